@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+)
+
+// Códigos de salida del comando. Cuando se procesan varios documentos se usa
+// el mayor de los códigos que corresponden a cada uno.
+const (
+	salidaOK         = 0 // Todo se procesó sin errores.
+	salidaCambios    = 1 // Con -check algún documento está fuera de orden; con -write se reescribió alguno; diff encontró diferencias.
+	salidaError      = 2 // Algún documento no es JSON válido, la entrada no se pudo leer o los argumentos son incorrectos.
+	salidaValidacion = 3 // Algún documento no cumple las validaciones configuradas.
+)
+
+// errorConCodigo asocia a un error el código de salida con que termina el comando.
+type errorConCodigo struct {
+	codigo int
+	err    error
+}
+
+func (e *errorConCodigo) Error() string { return e.err.Error() }
+func (e *errorConCodigo) Unwrap() error { return e.err }
+
+// codigoDeSalida devuelve el código de salida que corresponde a err.
+func codigoDeSalida(err error) int {
+	var conCodigo *errorConCodigo
+	switch {
+	case err == nil:
+		return salidaOK
+	case errors.As(err, &conCodigo):
+		return conCodigo.codigo
+	case errors.Is(err, errFueraDeOrden), errors.Is(err, errDocumentosDistintos):
+		return salidaCambios
+	case cuarentena.Clasificar(err) == cuarentena.TipoValidacion:
+		return salidaValidacion
+	}
+	return salidaError
+}
+
+// resumen cuenta el resultado de cada documento procesado; con -summary se
+// imprime al terminar. Los documentos se pueden registrar desde varias
+// goroutines; el resto de los métodos se usa cuando terminaron.
+type resumen struct {
+	mu           sync.Mutex
+	exigirOrden  bool // Los documentos modificados cuentan como salidaCambios (-check y -write).
+	procesados   int
+	modificados  int // Su forma canónica difiere del original.
+	jsonInvalido int
+	validacion   int
+	otros        int // Errores de lectura, escritura o de otro tipo.
+}
+
+// registrar suma el resultado de un documento: err es el error al procesarlo
+// y cambiado indica si su forma canónica difiere del original.
+func (r *resumen) registrar(cambiado bool, err error) {
+	if err != nil {
+		r.registrarFalla(cuarentena.Clasificar(err))
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.procesados++
+	if cambiado {
+		r.modificados++
+	}
+}
+
+// registrarFalla suma un documento que falló con el tipo indicado, según
+// cuarentena.Clasificar.
+func (r *resumen) registrarFalla(tipo string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.procesados++
+	switch tipo {
+	case cuarentena.TipoJSONInvalido:
+		r.jsonInvalido++
+	case cuarentena.TipoValidacion:
+		r.validacion++
+	default:
+		r.otros++
+	}
+}
+
+// fallidos devuelve la cantidad de documentos que no se pudieron procesar.
+func (r *resumen) fallidos() int {
+	return r.jsonInvalido + r.validacion + r.otros
+}
+
+// codigo devuelve el código de salida que corresponde a los documentos registrados.
+func (r *resumen) codigo() int {
+	switch {
+	case r.validacion > 0:
+		return salidaValidacion
+	case r.jsonInvalido > 0 || r.otros > 0:
+		return salidaError
+	case r.exigirOrden && r.modificados > 0:
+		return salidaCambios
+	}
+	return salidaOK
+}
+
+// escribir imprime el resumen en una línea de pares clave=valor, pensada para
+// procesarse desde scripts.
+func (r *resumen) escribir(w io.Writer) {
+	fmt.Fprintf(w, "procesados=%d sin-cambios=%d modificados=%d fallidos=%d json-invalido=%d validacion=%d otros=%d\n",
+		r.procesados, r.procesados-r.modificados-r.fallidos(), r.modificados, r.fallidos(), r.jsonInvalido, r.validacion, r.otros)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// errFueraDeOrden indica que al menos un documento no está en orden canónico.
+var errFueraDeOrden = errors.New("no está en orden canónico")
+
+// errDocumentosDistintos indica que diff encontró diferencias.
+var errDocumentosDistintos = errors.New("los documentos difieren")
+
+// ejecutarDiff implementa el subcomando "diff": compara las claves de primer
+// nivel de dos documentos e informa las que solo están en uno, las que
+// cambiaron de valor y las que cambiaron de posición. Devuelve
+// errDocumentosDistintos si encontró alguna diferencia.
+func ejecutarDiff(args []string, salida io.Writer) error {
+	if len(args) != 2 {
+		return fmt.Errorf("uso: ordena-json diff antes.json despues.json")
+	}
+	antes, err := leerDocumento(args[0])
+	if err != nil {
+		return err
+	}
+	despues, err := leerDocumento(args[1])
+	if err != nil {
+		return err
+	}
+
+	diferencias := 0
+	for _, clave := range antes.claves {
+		if _, ok := despues.valores[clave]; !ok {
+			fmt.Fprintf(salida, "- %s\n", clave)
+			diferencias++
+		}
+	}
+	for _, clave := range despues.claves {
+		valorAntes, ok := antes.valores[clave]
+		switch {
+		case !ok:
+			fmt.Fprintf(salida, "+ %s\n", clave)
+			diferencias++
+		case !reflect.DeepEqual(valorAntes, despues.valores[clave]):
+			fmt.Fprintf(salida, "~ %s\n", clave)
+			diferencias++
+		}
+	}
+	for _, m := range clavesMovidas(comunes(antes.claves, despues.valores), comunes(despues.claves, antes.valores)) {
+		fmt.Fprintf(salida, "> %s: posición %d -> %d\n", m.clave, m.desde+1, m.hasta+1)
+		diferencias++
+	}
+	if diferencias > 0 {
+		return errDocumentosDistintos
+	}
+	return nil
+}
+
+// revisarEntradas implementa -check: informa en salida cada documento que no
+// está en orden canónico, con las claves que se moverían, sin escribir nada.
+// Sin argumentos, o con "-", revisa la entrada estándar. El resultado de cada
+// documento se registra en res.
+func revisarEntradas(args []string, recursivo bool, entrada io.Reader, proc procesamiento, salida, errores io.Writer, res *resumen) error {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		documento, err := io.ReadAll(entrada)
+		if err != nil {
+			return err
+		}
+		err = revisarDocumento("<stdin>", documento, proc, salida)
+		res.registrar(errors.Is(err, errFueraDeOrden), ignorarFueraDeOrden(err))
+		if err != nil && !errors.Is(err, errFueraDeOrden) {
+			err = fmt.Errorf("<stdin>: %w", err)
+		}
+		return err
+	}
+
+	archivos, err := expandirEntradas(args, recursivo, "", proc)
+	if err != nil {
+		return err
+	}
+	for _, a := range archivos {
+		documento, err := os.ReadFile(a.ruta)
+		if err == nil {
+			err = revisarDocumento(a.ruta, documento, proc, salida)
+		}
+		res.registrar(errors.Is(err, errFueraDeOrden), ignorarFueraDeOrden(err))
+		if err != nil && !errors.Is(err, errFueraDeOrden) {
+			fmt.Fprintf(errores, "ordena-json: %s: %v\n", a.ruta, err)
+		}
+	}
+	if fallidos := res.fallidos(); fallidos > 0 {
+		return &errorConCodigo{res.codigo(), fmt.Errorf("%d de %d archivos con errores", fallidos, len(archivos))}
+	}
+	if res.modificados > 0 {
+		return fmt.Errorf("%w: %d de %d archivos", errFueraDeOrden, res.modificados, len(archivos))
+	}
+	return nil
+}
+
+// ignorarFueraDeOrden devuelve nil si err es errFueraDeOrden, que en -check
+// no es una falla del documento.
+func ignorarFueraDeOrden(err error) error {
+	if errors.Is(err, errFueraDeOrden) {
+		return nil
+	}
+	return err
+}
+
+// revisarDocumento compara un documento con su forma canónica. Si difieren,
+// informa en salida las claves que cambiarían de posición (o que solo difiere
+// el formato) y devuelve errFueraDeOrden.
+func revisarDocumento(nombre string, documento []byte, proc procesamiento, salida io.Writer) error {
+	canonico, err := ordenarDocumento(documento, proc)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(documento, canonico) {
+		return nil
+	}
+	actual, err := clavesEnOrden(documento)
+	if err != nil {
+		return err
+	}
+	esperado, err := clavesEnOrden(canonico)
+	if err != nil {
+		return err
+	}
+	movidas := clavesMovidas(actual, esperado)
+	if len(movidas) == 0 {
+		fmt.Fprintf(salida, "%s: el orden es correcto pero el formato difiere\n", nombre)
+	}
+	for _, m := range movidas {
+		fmt.Fprintf(salida, "%s: %s: posición %d -> %d\n", nombre, m.clave, m.desde+1, m.hasta+1)
+	}
+	return errFueraDeOrden
+}
+
+// documentoLeido es un documento con sus claves de primer nivel en orden.
+type documentoLeido struct {
+	claves  []string
+	valores map[string]interface{}
+}
+
+// leerDocumento lee un objeto JSON desde ruta.
+func leerDocumento(ruta string) (documentoLeido, error) {
+	contenido, err := os.ReadFile(ruta)
+	if err != nil {
+		return documentoLeido{}, err
+	}
+	var doc documentoLeido
+	if err := json.Unmarshal(contenido, &doc.valores); err != nil {
+		return documentoLeido{}, fmt.Errorf("%s: %w", ruta, err)
+	}
+	if doc.claves, err = clavesEnOrden(contenido); err != nil {
+		return documentoLeido{}, fmt.Errorf("%s: %w", ruta, err)
+	}
+	return doc, nil
+}
+
+// clavesEnOrden devuelve las claves de primer nivel de un objeto JSON en el
+// orden en que aparecen, sin repetir.
+func clavesEnOrden(documento []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(documento))
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, fmt.Errorf("se esperaba un objeto JSON")
+	}
+	var claves []string
+	vistas := make(map[string]bool)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		clave, _ := t.(string)
+		var valor json.RawMessage
+		if err := dec.Decode(&valor); err != nil {
+			return nil, err
+		}
+		if !vistas[clave] {
+			vistas[clave] = true
+			claves = append(claves, clave)
+		}
+	}
+	return claves, nil
+}
+
+// comunes filtra claves dejando solo las presentes en otro.
+func comunes(claves []string, otro map[string]interface{}) []string {
+	var resultado []string
+	for _, clave := range claves {
+		if _, ok := otro[clave]; ok {
+			resultado = append(resultado, clave)
+		}
+	}
+	return resultado
+}
+
+// movimiento describe una clave que cambia de posición.
+type movimiento struct {
+	clave        string
+	desde, hasta int // Posiciones, desde cero, en el orden actual y en el esperado.
+}
+
+// clavesMovidas compara dos órdenes de las mismas claves y devuelve el menor
+// conjunto de claves que hay que mover para pasar de actual a esperado: las
+// que no forman parte de la subsecuencia común más larga. Se informan en el
+// orden esperado.
+func clavesMovidas(actual, esperado []string) []movimiento {
+	// lcs[i][j] es la longitud de la subsecuencia común más larga de actual[i:] y esperado[j:].
+	lcs := make([][]int, len(actual)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(esperado)+1)
+	}
+	for i := len(actual) - 1; i >= 0; i-- {
+		for j := len(esperado) - 1; j >= 0; j-- {
+			if actual[i] == esperado[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	fijas := make(map[string]bool)
+	for i, j := 0, 0; i < len(actual) && j < len(esperado); {
+		switch {
+		case actual[i] == esperado[j]:
+			fijas[actual[i]] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	posicion := make(map[string]int, len(actual))
+	for i, clave := range actual {
+		posicion[clave] = i
+	}
+	var movidas []movimiento
+	for j, clave := range esperado {
+		if !fijas[clave] {
+			movidas = append(movidas, movimiento{clave: clave, desde: posicion[clave], hasta: j})
+		}
+	}
+	return movidas
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+)
+
+// errorCuarentena indica que el documento no se pudo ordenar y se guardó en la
+// cuarentena; el detalle queda en su reporte y no se repite en los logs.
+type errorCuarentena struct {
+	tipo string // Tipo de falla, según cuarentena.Clasificar.
+	err  error  // Error original.
+}
+
+func (e *errorCuarentena) Error() string { return "enviado a cuarentena: " + e.tipo }
+func (e *errorCuarentena) Unwrap() error { return e.err }
+
+// archivoEntrada es un archivo a procesar en modo lote.
+type archivoEntrada struct {
+	ruta     string // Ruta tal como se abre.
+	relativa string // Ruta relativa a la base del argumento; se conserva bajo -out.
+}
+
+// loteConfig agrupa los parámetros del modo lote.
+type loteConfig struct {
+	proc           procesamiento
+	dirSalida      string             // Directorio de salida; vacío escribe junto a cada original.
+	sufijo         string             // Sufijo para los resultados escritos junto al original.
+	enSitio        bool               // Reemplaza cada archivo por su versión ordenada (-write).
+	sufijoRespaldo string             // Con enSitio, sufijo de la copia del original; vacío no la guarda.
+	errores        io.Writer          // Destino de los errores por archivo.
+	cuarentena     cuarentena.Destino // Recibe los documentos que no se pudieron ordenar; nil los informa en errores.
+	resumen        *resumen           // Acumula el resultado de cada archivo; puede ser nil.
+	trabajadores   int                // Archivos que se procesan en paralelo; menos de 1 equivale a 1.
+}
+
+// validar revisa que la combinación de opciones de salida tenga sentido.
+func (cfg loteConfig) validar() error {
+	switch {
+	case cfg.enSitio && cfg.dirSalida != "":
+		return fmt.Errorf("-write y -out no se pueden usar juntos")
+	case cfg.enSitio && cfg.proc.formato.Extension() != cfg.proc.entrada.Extension():
+		return fmt.Errorf("-write exige que -format y -input-format coincidan: el archivo cambiaría de formato")
+	case !cfg.enSitio && cfg.sufijoRespaldo != "":
+		return fmt.Errorf("-backup-suffix requiere -write")
+	case !cfg.enSitio && cfg.dirSalida == "" && cfg.sufijo == "":
+		return fmt.Errorf("-suffix no puede ser vacío sin -out: se sobrescribirían los originales (usar -write)")
+	}
+	return nil
+}
+
+// sufijoResultados devuelve el sufijo que identifica los resultados escritos
+// junto a los originales, o "" si el modo de salida no los produce.
+func (cfg loteConfig) sufijoResultados() string {
+	if cfg.enSitio || cfg.dirSalida != "" {
+		return ""
+	}
+	return cfg.sufijo
+}
+
+// procesarLote ordena cada archivo y escribe el resultado en cfg.dirSalida,
+// conservando la ruta relativa, junto al original con cfg.sufijo antes de la
+// extensión o, con cfg.enSitio, sobre el propio original. Un archivo con
+// errores no detiene el lote: el error se informa en cfg.errores y al final se
+// devuelve un resumen con el código de salida que corresponde. Con
+// cfg.cuarentena, los documentos inválidos se guardan allí sin cambios y en
+// cfg.errores solo se indica que se enviaron. Con cfg.trabajadores mayor que 1
+// los archivos se procesan en paralelo y los errores se informan a medida que
+// ocurren, sin un orden fijo.
+func procesarLote(archivos []archivoEntrada, cfg loteConfig) error {
+	if cfg.resumen == nil {
+		cfg.resumen = &resumen{}
+	}
+	pendientes := make(chan archivoEntrada)
+	var (
+		wg           sync.WaitGroup
+		muErrores    sync.Mutex
+		trabajadores = min(max(cfg.trabajadores, 1), max(len(archivos), 1))
+	)
+	for range trabajadores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range pendientes {
+				if err := procesarArchivo(a, cfg); err != nil {
+					muErrores.Lock()
+					fmt.Fprintf(cfg.errores, "ordena-json: %s: %v\n", a.ruta, err)
+					muErrores.Unlock()
+				}
+			}
+		}()
+	}
+	for _, a := range archivos {
+		pendientes <- a
+	}
+	close(pendientes)
+	wg.Wait()
+
+	if fallidos := cfg.resumen.fallidos(); fallidos > 0 {
+		return &errorConCodigo{cfg.resumen.codigo(), fmt.Errorf("%d de %d archivos con errores", fallidos, len(archivos))}
+	}
+	if cfg.enSitio && cfg.resumen.modificados > 0 {
+		return &errorConCodigo{salidaCambios, fmt.Errorf("%d de %d archivos reescritos", cfg.resumen.modificados, len(archivos))}
+	}
+	return nil
+}
+
+// procesarArchivo ordena un archivo y escribe su resultado; si cfg.resumen no
+// es nil, registra allí el resultado.
+func procesarArchivo(a archivoEntrada, cfg loteConfig) error {
+	cambiado, err := ordenarArchivo(a, cfg)
+	if cfg.resumen != nil {
+		cfg.resumen.registrar(cambiado, err)
+	}
+	return err
+}
+
+// ordenarArchivo ordena un archivo, escribe su resultado e indica si la forma
+// canónica difiere del original.
+func ordenarArchivo(a archivoEntrada, cfg loteConfig) (bool, error) {
+	documento, err := os.ReadFile(a.ruta)
+	if err != nil {
+		return false, err
+	}
+	resultado, err := ordenarDocumento(documento, cfg.proc)
+	if err != nil {
+		if cfg.cuarentena == nil {
+			return false, err
+		}
+		// El reporte de validación se arma sobre el documento ya convertido a JSON.
+		enJSON, errEntrada := leerEntrada(documento, cfg.proc)
+		if errEntrada != nil {
+			enJSON = documento
+		}
+		registro := cuarentena.NuevoRegistro(a.relativa, enJSON, err, cfg.proc.ordenador)
+		if errGuardar := cfg.cuarentena.Guardar(documento, registro); errGuardar != nil {
+			return false, fmt.Errorf("%w (no se pudo enviar a cuarentena: %v)", err, errGuardar)
+		}
+		return false, &errorCuarentena{tipo: registro.Tipo, err: err}
+	}
+	cambiado := !bytes.Equal(documento, resultado)
+	if cfg.enSitio {
+		return cambiado, reemplazarArchivo(a.ruta, documento, resultado, cfg.sufijoRespaldo)
+	}
+	destino := cfg.destino(a)
+	if err := os.MkdirAll(filepath.Dir(destino), 0o755); err != nil {
+		return cambiado, err
+	}
+	return cambiado, os.WriteFile(destino, resultado, 0o644)
+}
+
+// destino calcula dónde se escribe el resultado de a. Si el formato de salida
+// difiere del de entrada, el resultado lleva la extensión del de salida.
+func (cfg loteConfig) destino(a archivoEntrada) string {
+	if cfg.enSitio {
+		return a.ruta
+	}
+	ruta := a.ruta
+	if cfg.dirSalida != "" {
+		ruta = filepath.Join(cfg.dirSalida, a.relativa)
+	}
+	ext := filepath.Ext(ruta)
+	base := strings.TrimSuffix(ruta, ext)
+	if cfg.proc.formato.Extension() != cfg.proc.entrada.Extension() {
+		ext = cfg.proc.formato.Extension()
+	}
+	if cfg.dirSalida != "" {
+		return base + ext
+	}
+	return base + cfg.sufijo + ext
+}
+
+// reemplazarArchivo escribe resultado sobre ruta. Si el archivo ya estaba
+// ordenado no se toca. El contenido nuevo se escribe primero en un archivo
+// temporal del mismo directorio que luego se renombra, de modo que una
+// interrupción nunca deja el original a medio escribir; se conservan sus
+// permisos. Con sufijoRespaldo, antes se guarda una copia del original en
+// ruta+sufijoRespaldo.
+func reemplazarArchivo(ruta string, original, resultado []byte, sufijoRespaldo string) error {
+	if bytes.Equal(original, resultado) {
+		return nil
+	}
+	info, err := os.Stat(ruta)
+	if err != nil {
+		return err
+	}
+	if sufijoRespaldo != "" {
+		if err := os.WriteFile(ruta+sufijoRespaldo, original, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	temporal, err := os.CreateTemp(filepath.Dir(ruta), "."+filepath.Base(ruta)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporal.Name())
+	if _, err := temporal.Write(resultado); err != nil {
+		temporal.Close()
+		return err
+	}
+	if err := temporal.Chmod(info.Mode().Perm()); err != nil {
+		temporal.Close()
+		return err
+	}
+	if err := temporal.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporal.Name(), ruta)
+}
+
+// expandirEntradas convierte los argumentos en la lista de archivos a procesar,
+// sin duplicados y en orden alfabético por argumento. Cada argumento puede ser
+// un archivo, un directorio (se toman sus archivos en el formato de entrada de
+// proc, ver esArchivoDeEntrada; con recursivo también los de sus subdirectorios) o un patrón glob, donde "**" coincide con cualquier
+// cantidad de directorios. Se omiten los resultados de ejecuciones anteriores,
+// es decir, los archivos cuyo nombre termina en sufijo más la extensión.
+func expandirEntradas(args []string, recursivo bool, sufijo string, proc procesamiento) ([]archivoEntrada, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no se indicaron archivos")
+	}
+	var archivos []archivoEntrada
+	vistos := make(map[string]bool)
+	agregar := func(ruta, base string) {
+		if vistos[ruta] || esResultadoPrevio(ruta, sufijo) {
+			return
+		}
+		vistos[ruta] = true
+		relativa, err := filepath.Rel(base, ruta)
+		if err != nil {
+			relativa = filepath.Base(ruta)
+		}
+		archivos = append(archivos, archivoEntrada{ruta: ruta, relativa: relativa})
+	}
+
+	for _, arg := range args {
+		var rutas []string
+		var base string
+		var err error
+		switch {
+		case tieneComodines(arg):
+			base = baseDePatron(arg)
+			rutas, err = expandirPatron(arg, base)
+		default:
+			var info os.FileInfo
+			if info, err = os.Stat(arg); err != nil {
+				return nil, err
+			}
+			if info.IsDir() {
+				base = arg
+				rutas, err = archivosDeDirectorio(arg, recursivo, proc)
+			} else {
+				base = filepath.Dir(arg)
+				rutas = []string{arg}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rutas) == 0 {
+			return nil, fmt.Errorf("%s: no coincide con ningún archivo", arg)
+		}
+		for _, ruta := range rutas {
+			agregar(ruta, base)
+		}
+	}
+	return archivos, nil
+}
+
+// esResultadoPrevio indica si ruta parece un resultado escrito junto a su original.
+func esResultadoPrevio(ruta, sufijo string) bool {
+	if sufijo == "" {
+		return false
+	}
+	ext := filepath.Ext(ruta)
+	return strings.HasSuffix(strings.TrimSuffix(ruta, ext), sufijo)
+}
+
+// archivosDeDirectorio devuelve los archivos de dir en el formato de entrada
+// de proc, ordenados.
+func archivosDeDirectorio(dir string, recursivo bool, proc procesamiento) ([]string, error) {
+	var rutas []string
+	err := filepath.WalkDir(dir, func(ruta string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if ruta != dir && !recursivo {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if esArchivoDeEntrada(ruta, proc) {
+			rutas = append(rutas, ruta)
+		}
+		return nil
+	})
+	return rutas, err
+}
+
+// tieneComodines indica si el argumento es un patrón glob.
+func tieneComodines(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// baseDePatron devuelve el directorio formado por los segmentos del patrón
+// anteriores al primer comodín.
+func baseDePatron(patron string) string {
+	segmentos := strings.Split(filepath.ToSlash(patron), "/")
+	fijos := segmentos[:0:0]
+	for _, s := range segmentos[:len(segmentos)-1] {
+		if tieneComodines(s) {
+			break
+		}
+		fijos = append(fijos, s)
+	}
+	if len(fijos) == 0 {
+		return "."
+	}
+	base := strings.Join(fijos, "/")
+	if base == "" {
+		base = "/"
+	}
+	return filepath.FromSlash(base)
+}
+
+// expandirPatron recorre base y devuelve, ordenados, los archivos cuya ruta
+// coincide con patron.
+func expandirPatron(patron, base string) ([]string, error) {
+	if _, err := path.Match(filepath.ToSlash(patron), ""); err != nil {
+		return nil, fmt.Errorf("%s: %w", patron, err)
+	}
+	if _, err := os.Stat(base); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	segmentos := strings.Split(filepath.ToSlash(filepath.Clean(patron)), "/")
+	var rutas []string
+	err := filepath.WalkDir(base, func(ruta string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if coincideSegmentos(segmentos, strings.Split(filepath.ToSlash(filepath.Clean(ruta)), "/")) {
+			rutas = append(rutas, ruta)
+		}
+		return nil
+	})
+	sort.Strings(rutas)
+	return rutas, err
+}
+
+// coincideSegmentos compara una ruta con un patrón segmento a segmento. Un
+// segmento "**" coincide con cero o más segmentos; el resto se compara con
+// path.Match.
+func coincideSegmentos(patron, ruta []string) bool {
+	for len(patron) > 0 {
+		if patron[0] == "**" {
+			for i := 0; i <= len(ruta); i++ {
+				if coincideSegmentos(patron[1:], ruta[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(ruta) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(patron[0], ruta[0]); !ok {
+			return false
+		}
+		patron, ruta = patron[1:], ruta[1:]
+	}
+	return len(ruta) == 0
+}
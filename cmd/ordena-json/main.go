@@ -0,0 +1,107 @@
+// Comando ordena-json expone las herramientas del paquete ordenJson en la línea de comandos.
+//
+// Sin subcomando, ordena el documento JSON recibido en un archivo o en la
+// entrada estándar y escribe el resultado en la salida estándar. Con varios
+// archivos, directorios o patrones glob los ordena en lote. El orden de campos
+// y las validaciones se pueden definir en un archivo YAML indicado con
+// -order-file o, si no, en el primer .ordenajson.yaml del directorio actual o
+// de sus ancestros. Con -input-format yaml los documentos de entrada son YAML
+// (en lote, los archivos .yaml y .yml). La salida es JSON salvo que -format
+// pida otro de los formatos del paquete formatos (yaml, toml, alfresco-xml,
+// msgpack, cbor, ...); en lote, los resultados llevan su extensión. Con
+// -stream la entrada es NDJSON o un arreglo JSON de documentos, que se
+// ordenan de a uno sin cargar el archivo completo en memoria; con una entrada
+// NDJSON y -format fluentd-forward o logstash, cada documento se escribe como
+// un evento de ese formato, para usar el comando dentro de un pipeline de
+// logs.
+//
+// El código de salida es 0 si todo se procesó correctamente, 1 si con -check
+// algún documento está fuera de orden, con -write se reescribió alguno o diff
+// encontró diferencias, 2 si algún documento no es JSON válido o no se pudo
+// leer y 3 si alguno no cumple las validaciones. Con varios documentos se usa
+// el mayor. -summary imprime además los totales en la salida de errores.
+//
+// Uso:
+//
+//	ordena-json [-compact] [-indent texto] [-input-format json|yaml] [-format formato] [archivo|-]
+//	ordena-json [-recursive] [-out dir] [-suffix sufijo] [-quarantine dir] [-workers n] [-summary] archivo|directorio|patrón...
+//	ordena-json -write [-backup-suffix sufijo] archivo|directorio|patrón...
+//	ordena-json -watch [-recursive] [-out dir] directorio...
+//	ordena-json -check [-recursive] [archivo|directorio|patrón|-]...
+//	ordena-json -stream [-format json|compact|fluentd-forward|logstash] [-fluentd-tag etiqueta] [archivo|-]
+//	ordena-json diff antes.json despues.json
+//	ordena-json plantilla [-order-file archivo.yaml]
+//	ordena-json reprocesar -out dir [flags] directorio-de-cuarentena
+//	ordena-json soak [flags]
+//	ordena-json --version
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func main() {
+	var err error
+	switch subcomando(os.Args) {
+	case "":
+		err = ejecutarOrdenar(os.Args[1:], os.Stdin, os.Stdout)
+	case "soak":
+		err = ejecutarSoak(os.Args[2:])
+	case "diff":
+		err = ejecutarDiff(os.Args[2:], os.Stdout)
+	case "plantilla":
+		err = ejecutarPlantilla(os.Args[2:], os.Stdout)
+	case "reprocesar":
+		err = ejecutarReprocesar(os.Args[2:], os.Stdout, os.Stderr)
+	case "version", "-version", "--version":
+		fmt.Println(ordenJson.Version())
+	case "help", "-h", "-help", "--help":
+		uso()
+		os.Exit(2)
+	default:
+		err = ejecutarOrdenar(os.Args[1:], os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ordena-json: %v\n", err)
+		os.Exit(codigoDeSalida(err))
+	}
+}
+
+// subcomando devuelve el primer argumento del comando, o "" si no hay ninguno.
+func subcomando(args []string) string {
+	if len(args) < 2 {
+		return ""
+	}
+	return args[1]
+}
+
+// uso imprime la ayuda general del comando.
+func uso() {
+	fmt.Fprintln(os.Stderr, `Uso:
+  ordena-json [-compact] [-indent texto] [-input-format json|yaml] [-format formato] [archivo|-]
+                             ordena un documento JSON (por defecto desde stdin)
+  ordena-json [-recursive] [-out dir] [-suffix sufijo] [-quarantine dir] [-workers n] [-summary] archivo|directorio|patrón...
+                             ordena en lote; sin -out escribe junto a cada original
+  ordena-json -write [-backup-suffix sufijo] archivo|directorio|patrón...
+                             reemplaza cada archivo por su versión ordenada
+  ordena-json -watch [-recursive] [-out dir] directorio...
+                             ordena cada archivo .json que llega a los directorios
+  ordena-json -check [-recursive] [archivo|directorio|patrón|-]...
+                             informa los archivos fuera de orden y falla si hay alguno
+  ordena-json -stream [-format json|compact|fluentd-forward|logstash] [-fluentd-tag etiqueta] [archivo|-]
+                             ordena de a uno los documentos NDJSON o de un arreglo JSON
+  ordena-json diff antes.json despues.json
+                             compara las claves de dos documentos
+  ordena-json plantilla [-order-file archivo.yaml]
+                             escribe un documento vacío con todas las claves del perfil
+  ordena-json reprocesar -out dir [flags] directorio-de-cuarentena
+                             reintenta los documentos enviados con -quarantine
+  ordena-json soak [flags]   prueba de resistencia con detección de fugas
+  ordena-json --version      muestra la versión y el perfil por defecto
+
+Códigos de salida: 0 correcto, 1 orden cambiado o necesario, 2 JSON inválido
+o error de lectura, 3 validación fallida.`)
+}
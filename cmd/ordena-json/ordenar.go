@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/configuracion"
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/formatos"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// procesamiento describe cómo se ordena y se escribe cada documento.
+type procesamiento struct {
+	ordenador *ordenJson.Ordenador // Aplica el perfil y las opciones de la configuración.
+	entrada   formatos.Formato     // Formato de los documentos leídos: formatos.JSON o formatos.YAML.
+	formato   formatos.Formato     // Formato de salida.
+	sangria   string               // Con formatos.JSON, texto de cada nivel de indentación.
+}
+
+// ejecutarOrdenar implementa el modo por defecto del comando. Con un solo
+// archivo (o ninguno, o "-", que indican la entrada estándar) escribe el
+// documento ordenado en salida. Con varios archivos, directorios, patrones glob
+// o -out, procesa cada archivo en lote; ver procesarLote. Con -write cada
+// archivo se reemplaza por su versión ordenada. Con -check solo se informa qué
+// archivos no están en orden canónico; ver revisarEntradas. Con -watch vigila los
+// directorios indicados; ver vigilar. Con -stream ordena los documentos de un
+// archivo NDJSON o de un arreglo JSON de a uno; ver ordenarFlujo. Con -quarantine los documentos que no se
+// pueden ordenar se guardan aparte en lugar de informarse en los logs. El
+// error devuelto determina el código de salida; ver codigoDeSalida.
+func ejecutarOrdenar(args []string, entrada io.Reader, salida io.Writer) error {
+	fs := flag.NewFlagSet("ordena-json", flag.ExitOnError)
+	nuevoProcesamiento := flagsProcesamiento(fs)
+	recursivo := fs.Bool("recursive", false, "recorre los subdirectorios de los directorios indicados")
+	dirSalida := fs.String("out", "", "directorio donde escribir los resultados del lote (conserva las rutas relativas)")
+	sufijo := fs.String("suffix", ".ordenado", "sufijo agregado antes de la extensión al escribir junto al original")
+	observar := fs.Bool("watch", false, "vigila los directorios indicados y ordena cada archivo .json que llega")
+	espera := fs.Duration("settle", 500*time.Millisecond, "con -watch, tiempo sin cambios antes de procesar un archivo")
+	enSitio := fs.Bool("write", false, "reemplaza cada archivo por su versión ordenada")
+	sufijoRespaldo := fs.String("backup-suffix", "", "con -write, conserva el original agregando este sufijo a su nombre")
+	dirCuarentena := fs.String("quarantine", "", "directorio donde guardar sin cambios, junto a su reporte de error, los documentos que no se pudieron ordenar")
+	conResumen := fs.Bool("summary", false, "al terminar imprime en stderr la cantidad de archivos procesados, modificados y fallidos")
+	trabajadores := fs.Int("workers", 1, "archivos del lote que se procesan en paralelo (0 = número de CPUs)")
+	revisar := fs.Bool("check", false, "no escribe nada: informa los archivos que no están en orden canónico y falla si hay alguno")
+	flujo := fs.Bool("stream", false, "la entrada es NDJSON o un arreglo JSON de documentos, que se ordenan uno a uno sin cargarla completa en memoria")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Uso: ordena-json [flags] [archivo|directorio|patrón|-]...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	proc, err := nuevoProcesamiento()
+	if err != nil {
+		return err
+	}
+	lote := loteConfig{
+		proc:           proc,
+		dirSalida:      *dirSalida,
+		sufijo:         *sufijo,
+		enSitio:        *enSitio,
+		sufijoRespaldo: *sufijoRespaldo,
+		errores:        os.Stderr,
+		resumen:        &resumen{exigirOrden: *enSitio || *revisar},
+		trabajadores:   *trabajadores,
+	}
+	if lote.trabajadores == 0 {
+		lote.trabajadores = runtime.NumCPU()
+	}
+	if *conResumen {
+		defer lote.resumen.escribir(os.Stderr)
+	}
+	if *dirCuarentena != "" {
+		lote.cuarentena = cuarentena.Directorio(*dirCuarentena)
+	}
+	if err := lote.validar(); err != nil {
+		return err
+	}
+
+	if *revisar {
+		if *enSitio || *dirSalida != "" || *observar || *dirCuarentena != "" || proc.formato.Extension() != formatos.JSON.Extension() || proc.entrada != formatos.JSON {
+			return fmt.Errorf("-check no se puede combinar con -write, -out, -watch, -quarantine, -input-format yaml ni un -format que no sea JSON")
+		}
+		return revisarEntradas(fs.Args(), *recursivo, entrada, proc, salida, os.Stderr, lote.resumen)
+	}
+
+	if *flujo {
+		if *enSitio || *dirSalida != "" || *observar || *dirCuarentena != "" || fs.NArg() > 1 || !formatoDeFlujo(proc.formato) || proc.entrada != formatos.JSON {
+			return fmt.Errorf("-stream admite un solo archivo o la entrada estándar, con -format json, compact, fluentd-forward o logstash, y no se puede combinar con -write, -out, -watch ni -quarantine")
+		}
+		return ordenarFlujo(fs.Arg(0), entrada, proc, salida, lote.resumen)
+	}
+
+	if *observar {
+		ctx, cancelar := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancelar()
+		return vigilar(ctx, fs.Args(), *recursivo, *espera, lote, salida)
+	}
+
+	if !*enSitio && *dirCuarentena == "" && esEntradaUnica(fs.Args(), *dirSalida) {
+		nombre := "<stdin>"
+		if fs.NArg() == 1 && fs.Arg(0) != "-" {
+			nombre = fs.Arg(0)
+			archivo, err := os.Open(nombre)
+			if err != nil {
+				return err
+			}
+			defer archivo.Close()
+			entrada = archivo
+		}
+		documento, err := io.ReadAll(entrada)
+		if err != nil {
+			return err
+		}
+		resultado, err := ordenarDocumento(documento, proc)
+		lote.resumen.registrar(!bytes.Equal(documento, resultado), err)
+		if err != nil {
+			return fmt.Errorf("%s: %w", nombre, err)
+		}
+		_, err = salida.Write(resultado)
+		return err
+	}
+
+	archivos, err := expandirEntradas(fs.Args(), *recursivo, lote.sufijoResultados(), proc)
+	if err != nil {
+		return err
+	}
+	return procesarLote(archivos, lote)
+}
+
+// ordenarFlujo implementa -stream: ordena con ordenJson.Ordenador.OrdenarFlujo
+// los documentos del archivo nombre (o de entrada, si nombre es "" o "-") y
+// los escribe en salida a medida que se procesan. Con un formato de salida
+// que no es JSON, la entrada debe ser NDJSON y cada documento se escribe como
+// un evento de ese formato; ver formatos.EscritorEventos.
+func ordenarFlujo(nombre string, entrada io.Reader, proc procesamiento, salida io.Writer, res *resumen) error {
+	if nombre == "" || nombre == "-" {
+		nombre = "<stdin>"
+	} else {
+		archivo, err := os.Open(nombre)
+		if err != nil {
+			return err
+		}
+		defer archivo.Close()
+		entrada = archivo
+	}
+	var eventos *formatos.EscritorEventos
+	if proc.formato != formatos.JSON {
+		lector := bufio.NewReader(entrada)
+		if esArreglo(lector) {
+			return fmt.Errorf("%s: -stream con -format %s requiere una entrada NDJSON", nombre, proc.formato)
+		}
+		eventos = formatos.NuevoEscritorEventos(salida, proc.formato)
+		entrada, salida = lector, eventos
+	}
+	n, err := proc.ordenador.OrdenarFlujo(entrada, salida)
+	if eventos != nil {
+		if errCierre := eventos.Close(); err == nil {
+			err = errCierre
+		}
+	}
+	for i := 0; i < n; i++ {
+		res.registrar(false, nil)
+	}
+	if err != nil {
+		res.registrar(false, err)
+		return fmt.Errorf("%s: %w", nombre, err)
+	}
+	return nil
+}
+
+// formatoDeFlujo indica si -stream admite el formato de salida: JSON, que
+// conserva la forma de la entrada, o un formato de un evento por documento.
+func formatoDeFlujo(formato formatos.Formato) bool {
+	switch formato {
+	case formatos.JSON, formatos.Compacto, formatos.Fluentd, formatos.Logstash:
+		return true
+	}
+	return false
+}
+
+// esArreglo indica si el primer carácter significativo de r abre un arreglo
+// JSON, sin consumirlo.
+func esArreglo(r *bufio.Reader) bool {
+	for i := 1; ; i++ {
+		inicio, err := r.Peek(i)
+		if err != nil {
+			return false
+		}
+		switch inicio[i-1] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return inicio[i-1] == '['
+	}
+}
+
+// flagsProcesamiento define en fs los flags que indican cómo se ordena y se
+// formatea cada documento. La función devuelta, que se llama después de
+// fs.Parse, arma el procesamiento correspondiente.
+func flagsProcesamiento(fs *flag.FlagSet) func() (procesamiento, error) {
+	nombreFormato := fs.String("format", string(formatos.JSON), fmt.Sprintf("formato de salida, uno de %v", formatos.Formatos))
+	nombreEntrada := fs.String("input-format", string(formatos.JSON), "formato de los documentos de entrada: json o yaml")
+	compacto := fs.Bool("compact", false, "equivale a -format compact")
+	sangria := fs.String("indent", "  ", "con -format json, texto usado para cada nivel de indentación")
+	etiqueta := fs.String("fluentd-tag", formatos.EtiquetaFluentd, "con -format fluentd-forward, etiqueta de los eventos")
+	archivoOrden := fs.String("order-file", "", "archivo YAML con el orden de campos y las opciones (por defecto se busca "+configuracion.ArchivoPorDefecto+")")
+	return func() (procesamiento, error) {
+		opts, err := configuracion.CargarOpciones(*archivoOrden)
+		if err != nil {
+			return procesamiento{}, err
+		}
+		formato, err := formatos.Parsear(*nombreFormato)
+		if err != nil {
+			return procesamiento{}, err
+		}
+		if *compacto {
+			formato = formatos.Compacto
+		}
+		formatos.EtiquetaFluentd = *etiqueta
+		entrada, err := formatos.ParsearEntrada(*nombreEntrada)
+		if err != nil {
+			return procesamiento{}, err
+		}
+		return procesamiento{ordenador: ordenJson.Nuevo(opts...), entrada: entrada, formato: formato, sangria: *sangria}, nil
+	}
+}
+
+// esEntradaUnica indica si los argumentos describen un único documento que se
+// escribe en la salida estándar: ninguno, "-" o la ruta de un archivo regular,
+// sin -out.
+func esEntradaUnica(args []string, dirSalida string) bool {
+	if dirSalida != "" || len(args) > 1 {
+		return false
+	}
+	if len(args) == 0 || args[0] == "-" {
+		return true
+	}
+	if tieneComodines(args[0]) {
+		return false
+	}
+	info, err := os.Stat(args[0])
+	// Si la ruta no existe se trata como archivo para informar el error de apertura.
+	return err != nil || !info.IsDir()
+}
+
+// ordenarDocumento ordena un documento en el formato de entrada de proc y lo
+// devuelve con el formato de salida, terminado en un salto de línea.
+func ordenarDocumento(documento []byte, proc procesamiento) ([]byte, error) {
+	documento, err := leerEntrada(documento, proc)
+	if err != nil {
+		return nil, err
+	}
+	ordenado, err := proc.ordenador.OrdenarJSON(string(documento))
+	if err != nil {
+		return nil, err
+	}
+	return presentar([]byte(ordenado), proc)
+}
+
+// leerEntrada devuelve el documento en JSON: sin cambios si ya lo está o
+// convertido con formatos.DesdeYAML si proc lee YAML.
+func leerEntrada(documento []byte, proc procesamiento) ([]byte, error) {
+	if proc.entrada != formatos.YAML {
+		return documento, nil
+	}
+	return formatos.DesdeYAML(documento)
+}
+
+// esArchivoDeEntrada indica si, por su extensión, ruta es un documento en el
+// formato de entrada de proc: .json o, al leer YAML, .yaml y .yml.
+func esArchivoDeEntrada(ruta string, proc procesamiento) bool {
+	ext := strings.ToLower(filepath.Ext(ruta))
+	if proc.entrada == formatos.YAML {
+		return ext == ".yaml" || ext == ".yml"
+	}
+	return ext == ".json"
+}
+
+// presentar da a un documento ya ordenado el formato indicado en proc,
+// terminado en un salto de línea salvo en los formatos binarios.
+func presentar(ordenado []byte, proc procesamiento) ([]byte, error) {
+	resultado, err := formatear(ordenado, proc.formato, proc.sangria)
+	if err != nil {
+		return nil, err
+	}
+	if !proc.formato.Binario() && !bytes.HasSuffix(resultado, []byte("\n")) {
+		resultado = append(resultado, '\n')
+	}
+	return resultado, nil
+}
+
+// formatear convierte el documento ya ordenado al formato de salida. El
+// paquete ordenJson siempre indenta con dos espacios, por lo que con
+// formatos.JSON y esa sangría se devuelve el documento sin cambios. Ninguna
+// conversión altera el orden de las claves.
+func formatear(ordenado []byte, formato formatos.Formato, sangria string) ([]byte, error) {
+	if formato != formatos.JSON || sangria == "  " {
+		return formatos.Convertir(ordenado, formato)
+	}
+	compacto, err := formatos.Compactar(ordenado)
+	if err != nil {
+		return nil, err
+	}
+	var indentado bytes.Buffer
+	if err := json.Indent(&indentado, compacto, "", sangria); err != nil {
+		return nil, err
+	}
+	return indentado.Bytes(), nil
+}
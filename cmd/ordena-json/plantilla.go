@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/samuel/prueba-orden/ordenJson/configuracion"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// ejecutarPlantilla implementa el subcomando "plantilla": escribe el
+// esqueleto JSON del perfil, con todas sus claves en orden canónico y
+// valores vacíos, para completar documentos a mano. El perfil es el del
+// archivo de configuración, como al ordenar.
+func ejecutarPlantilla(args []string, salida io.Writer) error {
+	fs := flag.NewFlagSet("ordena-json plantilla", flag.ExitOnError)
+	archivoOrden := fs.String("order-file", "", "archivo YAML con el orden de campos y las opciones (por defecto se busca "+configuracion.ArchivoPorDefecto+")")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		return fmt.Errorf("uso: ordena-json plantilla [-order-file archivo.yaml]")
+	}
+	opts, err := configuracion.CargarOpciones(*archivoOrden)
+	if err != nil {
+		return err
+	}
+	plantilla, err := ordenJson.Nuevo(opts...).GenerarPlantilla()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(salida, plantilla)
+	return err
+}
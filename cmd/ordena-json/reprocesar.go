@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/formatos"
+)
+
+// ejecutarReprocesar implementa el subcomando "reprocesar": vuelve a ordenar
+// los documentos de un directorio de cuarentena, normalmente después de
+// corregir el perfil o las validaciones. Los que ahora se ordenan se escriben
+// en -out con su ruta de origen y se quitan de la cuarentena; los que siguen
+// fallando se quedan con su reporte actualizado y se informan en errores.
+func ejecutarReprocesar(args []string, salida, errores io.Writer) error {
+	fs := flag.NewFlagSet("reprocesar", flag.ExitOnError)
+	nuevoProcesamiento := flagsProcesamiento(fs)
+	dirSalida := fs.String("out", "", "directorio donde escribir los documentos recuperados (obligatorio)")
+	conResumen := fs.Bool("summary", false, "al terminar imprime en stderr la cantidad de documentos recuperados y fallidos")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Uso: ordena-json reprocesar -out dir [flags] directorio-de-cuarentena")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || *dirSalida == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	proc, err := nuevoProcesamiento()
+	if err != nil {
+		return err
+	}
+	if proc.entrada == formatos.YAML {
+		return fmt.Errorf("reprocesar no admite -input-format yaml")
+	}
+	lote := loteConfig{proc: proc, dirSalida: *dirSalida}
+	res := &resumen{}
+	if *conResumen {
+		defer res.escribir(errores)
+	}
+
+	reproceso, err := cuarentena.Reprocesar(fs.Arg(0), proc.ordenador, func(origen string, ordenado []byte) error {
+		resultado, err := presentar(ordenado, proc)
+		if err != nil {
+			return err
+		}
+		destino := lote.destino(archivoEntrada{ruta: origen, relativa: origen})
+		if err := os.MkdirAll(filepath.Dir(destino), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(destino, resultado, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(salida, "%s -> %s\n", origen, destino)
+		return nil
+	})
+	for range reproceso.Recuperados {
+		res.registrar(true, nil)
+	}
+	for _, r := range reproceso.Pendientes {
+		res.registrarFalla(r.Tipo)
+		fmt.Fprintf(errores, "ordena-json: %s: %s (intento %d)\n", r.Origen, r.Error, r.Intentos)
+	}
+	if err != nil {
+		return err
+	}
+	if fallidos := res.fallidos(); fallidos > 0 {
+		return &errorConCodigo{res.codigo(), fmt.Errorf("%d de %d documentos siguen en cuarentena", fallidos, res.procesados)}
+	}
+	return nil
+}
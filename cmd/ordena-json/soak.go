@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/soak"
+)
+
+// ejecutarSoak implementa el subcomando "soak": ordena documentos generados
+// durante el tiempo indicado y falla si detecta crecimiento sostenido del heap
+// o de las goroutines.
+func ejecutarSoak(args []string) error {
+	fs := flag.NewFlagSet("soak", flag.ExitOnError)
+	duracion := fs.Duration("duracion", time.Hour, "duración total de la prueba")
+	intervalo := fs.Duration("intervalo", 10*time.Second, "intervalo entre muestras de memoria")
+	trabajadores := fs.Int("trabajadores", 0, "goroutines en paralelo (0 = número de CPUs)")
+	tendencia := fs.Int("tendencia", 10, "muestras consecutivas en aumento que se consideran fuga")
+	semilla := fs.Uint64("semilla", uint64(time.Now().UnixNano()), "semilla del generador de documentos")
+	fs.Parse(args)
+
+	ctx, cancelar := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelar()
+
+	reporte, err := soak.Ejecutar(ctx, soak.Config{
+		Duracion:          *duracion,
+		IntervaloMuestreo: *intervalo,
+		Trabajadores:      *trabajadores,
+		MuestrasTendencia: *tendencia,
+		Semilla:           *semilla,
+		Salida:            os.Stdout,
+	})
+	fmt.Printf("documentos=%d errores=%d muestras=%d\n", reporte.Documentos, reporte.Errores, len(reporte.Muestras))
+	return err
+}
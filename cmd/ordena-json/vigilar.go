@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// intervaloRevision es cada cuánto se revisan los archivos pendientes en vigilar.
+const intervaloRevision = 50 * time.Millisecond
+
+// vigilar observa los directorios indicados y ordena cada archivo en el
+// formato de entrada (ver esArchivoDeEntrada) que se crea o modifica en ellos,
+// hasta que ctx termina. Un archivo se procesa cuando pasa el tiempo espera
+// sin recibir cambios, para no leerlo mientras todavía se está copiando. Los resultados se escriben igual que en procesarLote; los
+// archivos que son resultados (los que terminan en el sufijo o los que están
+// dentro de cfg.dirSalida) se ignoran. Cada archivo procesado se informa en
+// registro y los errores en cfg.errores, sin detener la vigilancia.
+func vigilar(ctx context.Context, dirs []string, recursivo bool, espera time.Duration, cfg loteConfig, registro io.Writer) error {
+	if len(dirs) == 0 {
+		return fmt.Errorf("-watch requiere al menos un directorio")
+	}
+	observador, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer observador.Close()
+
+	dirSalida := ""
+	if cfg.dirSalida != "" {
+		if dirSalida, err = filepath.Abs(cfg.dirSalida); err != nil {
+			return err
+		}
+	}
+	esSalida := func(ruta string) bool {
+		if dirSalida == "" {
+			return false
+		}
+		abs, err := filepath.Abs(ruta)
+		return err == nil && (abs == dirSalida || strings.HasPrefix(abs, dirSalida+string(filepath.Separator)))
+	}
+
+	// raices asocia cada directorio observado con el argumento del que proviene,
+	// para calcular la ruta relativa que se conserva bajo -out.
+	raices := make(map[string]string)
+	observar := func(dir, raiz string) error {
+		return filepath.WalkDir(dir, func(ruta string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if ruta != dir && (!recursivo || esSalida(ruta)) {
+				return filepath.SkipDir
+			}
+			raices[ruta] = raiz
+			return observador.Add(ruta)
+		})
+	}
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s: -watch solo admite directorios", dir)
+		}
+		if err := observar(dir, dir); err != nil {
+			return err
+		}
+	}
+
+	pendientes := make(map[string]time.Time) // Último cambio de cada archivo por procesar.
+	revision := time.NewTicker(intervaloRevision)
+	defer revision.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-observador.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(cfg.errores, "ordena-json: %v\n", err)
+		case evento, ok := <-observador.Events:
+			if !ok {
+				return nil
+			}
+			ruta := evento.Name
+			switch {
+			case evento.Has(fsnotify.Remove) || evento.Has(fsnotify.Rename):
+				delete(pendientes, ruta)
+			case evento.Has(fsnotify.Create) || evento.Has(fsnotify.Write):
+				if esSalida(ruta) {
+					continue
+				}
+				if info, err := os.Stat(ruta); err == nil && info.IsDir() {
+					if recursivo && evento.Has(fsnotify.Create) {
+						if err := observar(ruta, raices[filepath.Dir(ruta)]); err != nil {
+							fmt.Fprintf(cfg.errores, "ordena-json: %s: %v\n", ruta, err)
+						}
+					}
+					continue
+				}
+				if esArchivoDeEntrada(ruta, cfg.proc) && !esResultadoPrevio(ruta, cfg.sufijoResultados()) {
+					pendientes[ruta] = time.Now()
+				}
+			}
+		case ahora := <-revision.C:
+			for ruta, ultimo := range pendientes {
+				if ahora.Sub(ultimo) < espera {
+					continue
+				}
+				delete(pendientes, ruta)
+				relativa, err := filepath.Rel(raices[filepath.Dir(ruta)], ruta)
+				if err != nil {
+					relativa = filepath.Base(ruta)
+				}
+				a := archivoEntrada{ruta: ruta, relativa: relativa}
+				if err := procesarArchivo(a, cfg); err != nil {
+					fmt.Fprintf(cfg.errores, "ordena-json: %s: %v\n", ruta, err)
+					continue
+				}
+				fmt.Fprintf(registro, "%s -> %s\n", ruta, cfg.destino(a))
+			}
+		}
+	}
+}
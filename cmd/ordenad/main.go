@@ -0,0 +1,117 @@
+// Comando ordenad ejecuta el servidor HTTP de ordenamiento (ver el paquete
+// servidor), para que los servicios que no están escritos en Go produzcan el
+// mismo orden canónico que ordena-json.
+//
+// Cada -perfil nombre=archivo.yaml agrega un perfil con el formato de
+// configuración de ordena-json, disponible en /ordenar/nombre, /flujo/nombre y
+// /validar/nombre. Sin -perfil se usa un único perfil tomado de -order-file o,
+// si no, del primer .ordenajson.yaml del directorio actual o sus ancestros.
+// Con -grpc-addr los mismos perfiles se atienden además por gRPC (ver el
+// paquete rpc).
+//
+// Uso:
+//
+//	ordenad [-addr :8080] [-grpc-addr :9090] [-max-bytes n] [-order-file archivo.yaml]
+//	ordenad [-addr :8080] -perfil nombre=archivo.yaml... [-default nombre]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/configuracion"
+	"github.com/samuel/prueba-orden/ordenJson/rpc"
+	"github.com/samuel/prueba-orden/ordenJson/servidor"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// esperaCierre es el tiempo que se espera a que terminen las solicitudes en
+// curso al recibir una interrupción.
+const esperaCierre = 10 * time.Second
+
+func main() {
+	if err := ejecutar(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "ordenad: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// ejecutar interpreta los flags y atiende solicitudes hasta recibir una interrupción.
+func ejecutar(args []string) error {
+	fs := flag.NewFlagSet("ordenad", flag.ExitOnError)
+	direccion := fs.String("addr", ":8080", "dirección donde escuchar")
+	direccionGRPC := fs.String("grpc-addr", "", "dirección donde atender gRPC; vacío no lo habilita")
+	tamanoMaximo := fs.Int64("max-bytes", servidor.TamanoMaximoPorDefecto, "tamaño máximo del cuerpo de cada solicitud")
+	archivoOrden := fs.String("order-file", "", "sin -perfil, archivo YAML del único perfil (por defecto se busca "+configuracion.ArchivoPorDefecto+")")
+	porDefecto := fs.String("default", "", "perfil usado cuando la solicitud no indica uno; obligatorio con más de un -perfil")
+	perfiles := make(map[string]*ordenJson.Ordenador)
+	fs.Func("perfil", "perfil `nombre=archivo.yaml`; se puede repetir", func(valor string) error {
+		nombre, ruta, ok := strings.Cut(valor, "=")
+		if !ok || nombre == "" || ruta == "" {
+			return fmt.Errorf("se esperaba nombre=archivo.yaml")
+		}
+		if _, repetido := perfiles[nombre]; repetido {
+			return fmt.Errorf("perfil %q repetido", nombre)
+		}
+		cfg, err := configuracion.Cargar(ruta)
+		if err != nil {
+			return err
+		}
+		perfiles[nombre] = ordenJson.Nuevo(cfg.Opciones()...)
+		return nil
+	})
+	fs.Parse(args)
+
+	if len(perfiles) == 0 {
+		opts, err := configuracion.CargarOpciones(*archivoOrden)
+		if err != nil {
+			return err
+		}
+		ordenador := ordenJson.Nuevo(opts...)
+		perfiles[ordenador.Perfil().Nombre()] = ordenador
+	}
+	s, err := servidor.Nuevo(servidor.Config{Perfiles: perfiles, PerfilPorDefecto: *porDefecto, TamanoMaximo: *tamanoMaximo})
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Addr: *direccion, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	ctx, cancelar := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelar()
+	errGRPC := make(chan error, 1)
+	if *direccionGRPC != "" {
+		escucha, err := net.Listen("tcp", *direccionGRPC)
+		if err != nil {
+			return err
+		}
+		srvGRPC := rpc.Registrar(s)
+		go func() { errGRPC <- srvGRPC.Serve(escucha) }()
+		defer srvGRPC.GracefulStop()
+		log.Printf("ordenad atendiendo gRPC en %s", *direccionGRPC)
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case err := <-errGRPC:
+			log.Printf("ordenad: gRPC: %v", err)
+		}
+		cierre, cancelarCierre := context.WithTimeout(context.Background(), esperaCierre)
+		defer cancelarCierre()
+		srv.Shutdown(cierre)
+	}()
+
+	log.Printf("ordenad %s escuchando en %s", ordenJson.Version().Version, *direccion)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 
-	"github.com/samuel/prueba-orden/ordenJson"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
 )
 
 func main() {
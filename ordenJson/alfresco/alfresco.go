@@ -0,0 +1,256 @@
+// Package alfresco conecta ordenJson con la API REST v1 de Alfresco: lee las
+// propiedades de un nodo, las ordena con un Ordenador y las vuelve a escribir
+// en el nodo, que es el flujo para el que se construyó ordenJson.
+//
+//	cliente, err := alfresco.Nuevo(alfresco.Config{
+//		URL:     "https://alfresco.ejemplo.cl",
+//		Usuario: "admin",
+//		Clave:   clave,
+//	})
+//	ordenadas, err := cliente.Ordenar(ctx, idNodo)
+//
+// Las solicitudes que fallan por errores de red o por respuestas 429, 502,
+// 503 o 504 se reintentan con espera exponencial; ver Config.Reintentos.
+package alfresco
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// RutaAPI es la ruta de la API REST v1 de Alfresco, relativa a Config.URL.
+const RutaAPI = "/alfresco/api/-default-/public/alfresco/versions/1"
+
+// Valores que usa Nuevo para los campos de Config en cero.
+const (
+	ReintentosPorDefecto    = 3
+	EsperaInicialPorDefecto = 500 * time.Millisecond
+)
+
+// Config describe el servidor Alfresco y cómo conectarse a él.
+type Config struct {
+	// URL es la dirección base del servidor, sin RutaAPI, por ejemplo
+	// "https://alfresco.ejemplo.cl".
+	URL string
+	// Usuario y Clave se envían con autenticación básica.
+	Usuario string
+	Clave   string
+	// Ticket, si no está vacío, se usa en lugar de Usuario y Clave. Es el
+	// ticket que devuelve la API de autenticación de Alfresco.
+	Ticket string
+	// Ordenador ordena las propiedades; nil usa ordenJson.Nuevo().
+	Ordenador *ordenJson.Ordenador
+	// Reintentos es la cantidad de veces que se repite una solicitud
+	// fallida; 0 usa ReintentosPorDefecto y un valor negativo no reintenta.
+	Reintentos int
+	// EsperaInicial es la espera antes del primer reintento, que se duplica
+	// en cada uno de los siguientes; 0 usa EsperaInicialPorDefecto. Si la
+	// respuesta trae Retry-After, se espera lo que indica.
+	EsperaInicial time.Duration
+	// HTTP es el cliente con que se hacen las solicitudes; nil usa
+	// http.DefaultClient.
+	HTTP *http.Client
+}
+
+// Cliente lee y escribe propiedades de nodos de Alfresco. Es seguro usarlo
+// desde varias goroutines a la vez.
+type Cliente struct {
+	cfg  Config
+	base string
+}
+
+// Nuevo crea un Cliente con la configuración recibida. Devuelve error si la
+// URL no es absoluta.
+func Nuevo(cfg Config) (*Cliente, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("URL de Alfresco inválida: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("URL de Alfresco inválida: %q no es absoluta", cfg.URL)
+	}
+	if cfg.Ordenador == nil {
+		cfg.Ordenador = ordenJson.Nuevo()
+	}
+	if cfg.Reintentos == 0 {
+		cfg.Reintentos = ReintentosPorDefecto
+	}
+	if cfg.EsperaInicial <= 0 {
+		cfg.EsperaInicial = EsperaInicialPorDefecto
+	}
+	if cfg.HTTP == nil {
+		cfg.HTTP = http.DefaultClient
+	}
+	return &Cliente{cfg: cfg, base: strings.TrimSuffix(cfg.URL, "/") + RutaAPI}, nil
+}
+
+// ErrorAlfresco es una respuesta de error de la API de Alfresco.
+type ErrorAlfresco struct {
+	Estado  int    // Código de estado HTTP.
+	Clave   string // errorKey de la respuesta, si la trae.
+	Mensaje string // briefSummary de la respuesta o, si no lo trae, el cuerpo.
+}
+
+func (e *ErrorAlfresco) Error() string {
+	return fmt.Sprintf("alfresco respondió %d: %s", e.Estado, e.Mensaje)
+}
+
+// Propiedades devuelve las propiedades del nodo tal como las envía Alfresco,
+// como un objeto JSON.
+func (c *Cliente) Propiedades(ctx context.Context, nodo string) (json.RawMessage, error) {
+	var respuesta struct {
+		Entry struct {
+			Properties json.RawMessage `json:"properties"`
+		} `json:"entry"`
+	}
+	if err := c.solicitar(ctx, http.MethodGet, nodo, nil, &respuesta); err != nil {
+		return nil, err
+	}
+	if len(respuesta.Entry.Properties) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+	return respuesta.Entry.Properties, nil
+}
+
+// ActualizarPropiedades escribe en el nodo las propiedades recibidas, que
+// deben ser un objeto JSON. La API v1 actualiza los nodos con PUT, que solo
+// modifica las propiedades incluidas.
+func (c *Cliente) ActualizarPropiedades(ctx context.Context, nodo string, propiedades string) error {
+	cuerpo := make([]byte, 0, len(propiedades)+len(`{"properties":}`))
+	cuerpo = append(cuerpo, `{"properties":`...)
+	cuerpo = append(cuerpo, propiedades...)
+	cuerpo = append(cuerpo, '}')
+	return c.solicitar(ctx, http.MethodPut, nodo, cuerpo, nil)
+}
+
+// Ordenar lee las propiedades del nodo, las ordena con Config.Ordenador y las
+// escribe de vuelta. Devuelve las propiedades ordenadas. Si no se pueden
+// ordenar, por ejemplo porque no cumplen las validaciones del perfil, el
+// nodo no se modifica y se devuelve el error de ordenJson.
+func (c *Cliente) Ordenar(ctx context.Context, nodo string) (string, error) {
+	propiedades, err := c.Propiedades(ctx, nodo)
+	if err != nil {
+		return "", err
+	}
+	ordenadas, err := c.cfg.Ordenador.OrdenarJSONCtx(ctx, string(propiedades))
+	if err != nil {
+		return "", fmt.Errorf("nodo %s: %w", nodo, err)
+	}
+	if err := c.ActualizarPropiedades(ctx, nodo, ordenadas); err != nil {
+		return "", err
+	}
+	return ordenadas, nil
+}
+
+// solicitar envía una solicitud al nodo, reintentando según la
+// configuración, y decodifica la respuesta en respuesta si no es nil.
+func (c *Cliente) solicitar(ctx context.Context, metodo, nodo string, cuerpo []byte, respuesta interface{}) error {
+	direccion := c.base + "/nodes/" + url.PathEscape(nodo)
+	espera := c.cfg.EsperaInicial
+	for intento := 0; ; intento++ {
+		datos, reintentable, retrasar, err := c.enviar(ctx, metodo, direccion, cuerpo)
+		if err == nil {
+			if respuesta == nil {
+				return nil
+			}
+			if err := json.Unmarshal(datos, respuesta); err != nil {
+				return fmt.Errorf("respuesta de alfresco inválida: %w", err)
+			}
+			return nil
+		}
+		if !reintentable || intento >= c.cfg.Reintentos {
+			return err
+		}
+		if retrasar > 0 {
+			espera = retrasar
+		}
+		temporizador := time.NewTimer(espera)
+		select {
+		case <-ctx.Done():
+			temporizador.Stop()
+			return ctx.Err()
+		case <-temporizador.C:
+		}
+		espera *= 2
+	}
+}
+
+// enviar hace un intento de la solicitud. Devuelve el cuerpo de la respuesta
+// si fue exitosa y, si no, si se puede reintentar y cuánto pide esperar el
+// servidor.
+func (c *Cliente) enviar(ctx context.Context, metodo, direccion string, cuerpo []byte) ([]byte, bool, time.Duration, error) {
+	var lector io.Reader
+	if cuerpo != nil {
+		lector = bytes.NewReader(cuerpo)
+	}
+	req, err := http.NewRequestWithContext(ctx, metodo, direccion, lector)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if cuerpo != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.Ticket != "" {
+		// Alfresco recibe el ticket como usuario de la autenticación básica, sin clave.
+		req.SetBasicAuth(c.cfg.Ticket, "")
+	} else if c.cfg.Usuario != "" {
+		req.SetBasicAuth(c.cfg.Usuario, c.cfg.Clave)
+	}
+
+	res, err := c.cfg.HTTP.Do(req)
+	if err != nil {
+		// Los errores de red se reintentan, salvo que haya terminado el contexto.
+		return nil, ctx.Err() == nil, 0, err
+	}
+	defer res.Body.Close()
+	datos, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, ctx.Err() == nil, 0, err
+	}
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return datos, false, 0, nil
+	}
+
+	errAlfresco := &ErrorAlfresco{Estado: res.StatusCode, Mensaje: strings.TrimSpace(string(datos))}
+	var detalle struct {
+		Error struct {
+			ErrorKey     string `json:"errorKey"`
+			BriefSummary string `json:"briefSummary"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(datos, &detalle) == nil && detalle.Error.BriefSummary != "" {
+		errAlfresco.Clave = detalle.Error.ErrorKey
+		errAlfresco.Mensaje = detalle.Error.BriefSummary
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil, true, retryAfter(res.Header.Get("Retry-After")), errAlfresco
+	}
+	return nil, false, 0, errAlfresco
+}
+
+// retryAfter interpreta el encabezado Retry-After, en segundos o como fecha.
+// Devuelve 0 si no está o no se entiende.
+func retryAfter(valor string) time.Duration {
+	if valor == "" {
+		return 0
+	}
+	if segundos, err := strconv.Atoi(valor); err == nil && segundos >= 0 {
+		return time.Duration(segundos) * time.Second
+	}
+	if fecha, err := http.ParseTime(valor); err == nil {
+		return max(time.Until(fecha), 0)
+	}
+	return 0
+}
@@ -0,0 +1,271 @@
+// Package bench reúne corpus representativos de documentos y funciones para
+// medirlos desde go test -bench, de modo que los cambios de rendimiento del
+// paquete ordenJson se puedan medir y vigilar. Un _test.go típico:
+//
+//	func BenchmarkOrdenar(b *testing.B) { bench.EjecutarTodos(b) }
+//
+//	func TestSinRegresiones(t *testing.T) {
+//		bench.VerificarReservas(t, bench.Pequeno(), 0)
+//	}
+//
+// Los corpus se generan con una semilla fija: el mismo corpus tiene siempre
+// los mismos documentos, por lo que las mediciones de distintas versiones son
+// comparables, por ejemplo con benchstat.
+package bench
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// documentosPorCorpus es la cantidad de documentos de cada corpus.
+const documentosPorCorpus = 64
+
+// Corpus es un conjunto de documentos de un mismo tipo.
+type Corpus struct {
+	Nombre     string
+	Documentos []string
+}
+
+// Bytes devuelve el tamaño total de los documentos del corpus.
+func (c Corpus) Bytes() int {
+	total := 0
+	for _, doc := range c.Documentos {
+		total += len(doc)
+	}
+	return total
+}
+
+// Pequeno devuelve documentos planos con los campos del perfil por defecto y
+// valores string, como la mayoría de los metadatos reales.
+func Pequeno() Corpus {
+	r := rand.New(rand.NewPCG(1, 1))
+	c := Corpus{Nombre: "pequeno"}
+	for i := 0; i < documentosPorCorpus; i++ {
+		campos := mezclar(r, ordenJson.OrdenCampos)
+		var sb strings.Builder
+		sb.WriteByte('{')
+		for j, campo := range campos[:8+r.IntN(len(campos)-8)] {
+			if j > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, "%q:%q", campo, palabra(r, 4+r.IntN(20)))
+		}
+		sb.WriteByte('}')
+		c.Documentos = append(c.Documentos, sb.String())
+	}
+	return c
+}
+
+// Grande devuelve documentos con miles de claves fuera del perfil y valores
+// de todos los tipos de JSON.
+func Grande() Corpus {
+	r := rand.New(rand.NewPCG(2, 2))
+	c := Corpus{Nombre: "grande"}
+	for i := 0; i < documentosPorCorpus/8; i++ {
+		var sb strings.Builder
+		sb.WriteByte('{')
+		for j := 0; j < 2000; j++ {
+			if j > 0 {
+				sb.WriteByte(',')
+			}
+			clave := fmt.Sprintf("campo-%d", r.IntN(1_000_000))
+			if j < len(ordenJson.OrdenCampos) {
+				clave = ordenJson.OrdenCampos[j]
+			}
+			fmt.Fprintf(&sb, "%q:%s", clave, valorEscalar(r))
+		}
+		sb.WriteByte('}')
+		c.Documentos = append(c.Documentos, sb.String())
+	}
+	return c
+}
+
+// Anidado devuelve documentos cuyos valores son objetos y arreglos de varios
+// niveles, que se ordenan alfabéticamente.
+func Anidado() Corpus {
+	r := rand.New(rand.NewPCG(3, 3))
+	c := Corpus{Nombre: "anidado"}
+	for i := 0; i < documentosPorCorpus; i++ {
+		var sb strings.Builder
+		sb.WriteByte('{')
+		for j, campo := range mezclar(r, ordenJson.OrdenCampos) {
+			if j > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, "%q:", campo)
+			escribirAnidado(&sb, r, 3)
+		}
+		sb.WriteByte('}')
+		c.Documentos = append(c.Documentos, sb.String())
+	}
+	return c
+}
+
+// Patologico devuelve documentos válidos pero costosos: cadenas largas llenas
+// de escapes, claves repetidas, anidamiento profundo y números extremos.
+func Patologico() Corpus {
+	r := rand.New(rand.NewPCG(4, 4))
+	c := Corpus{Nombre: "patologico"}
+	for i := 0; i < documentosPorCorpus/4; i++ {
+		var sb strings.Builder
+		sb.WriteByte('{')
+		// Claves repetidas: prevalece el último valor.
+		for j := 0; j < 50; j++ {
+			fmt.Fprintf(&sb, "%q:%q,", ordenJson.OrdenCampos[j%len(ordenJson.OrdenCampos)], palabra(r, 8))
+		}
+		// Escapes y caracteres que json.Marshal reescribe.
+		var escapes strings.Builder
+		for j := 0; j < 2000; j++ {
+			escapes.WriteString([]string{`\"`, `\\`, `\n`, `é`, "<", "&", "\u2028", `\/`}[r.IntN(8)])
+		}
+		fmt.Fprintf(&sb, `"escapes":"%s",`, escapes.String())
+		// Anidamiento profundo.
+		sb.WriteString(`"profundo":`)
+		sb.WriteString(strings.Repeat(`{"a":[`, 200))
+		sb.WriteString("1")
+		sb.WriteString(strings.Repeat(`]}`, 200))
+		// Números en los extremos del rango.
+		sb.WriteString(`,"numeros":[1e-300,1e300,-0,123456789012345678901234567890,5e-324]}`)
+		c.Documentos = append(c.Documentos, sb.String())
+	}
+	return c
+}
+
+// Todos devuelve todos los corpus.
+func Todos() []Corpus {
+	return []Corpus{Pequeno(), Grande(), Anidado(), Patologico()}
+}
+
+// Ejecutar mide el ordenamiento de los documentos del corpus con las
+// opciones recibidas. Cada iteración ordena un documento, recorriendo el
+// corpus en orden; los documentos se escriben con Ordenador.AgregarJSON en un
+// buffer reutilizado, por lo que las reservas informadas son las del propio
+// ordenamiento. Si algún documento no se puede ordenar, falla antes de medir.
+func Ejecutar(b *testing.B, c Corpus, opts ...ordenJson.Option) {
+	b.Helper()
+	ord := ordenJson.Nuevo(opts...)
+	docs := documentos(c)
+	var buf []byte
+	for i, doc := range docs {
+		var err error
+		if buf, err = ord.AgregarJSON(buf[:0], doc); err != nil {
+			b.Fatalf("%s: documento %d: %v", c.Nombre, i, err)
+		}
+	}
+	b.SetBytes(int64(c.Bytes() / len(docs)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = ord.AgregarJSON(buf[:0], docs[i%len(docs)])
+	}
+}
+
+// EjecutarTodos ejecuta Ejecutar con cada corpus de Todos como sub-benchmark.
+func EjecutarTodos(b *testing.B, opts ...ordenJson.Option) {
+	for _, c := range Todos() {
+		b.Run(c.Nombre, func(b *testing.B) { Ejecutar(b, c, opts...) })
+	}
+}
+
+// Reservas devuelve la cantidad promedio de reservas de memoria al ordenar
+// un documento del corpus, medida igual que en Ejecutar.
+func Reservas(c Corpus, opts ...ordenJson.Option) float64 {
+	ord := ordenJson.Nuevo(opts...)
+	docs := documentos(c)
+	var buf []byte
+	total := 0.0
+	for _, doc := range docs {
+		total += testing.AllocsPerRun(10, func() {
+			buf, _ = ord.AgregarJSON(buf[:0], doc)
+		})
+	}
+	return total / float64(len(docs))
+}
+
+// VerificarReservas falla t si ordenar un documento del corpus reserva en
+// promedio más de maximo veces memoria. A diferencia del tiempo, la cantidad
+// de reservas no depende de la máquina, por lo que sirve como prueba de
+// regresión en cualquier entorno.
+func VerificarReservas(t testing.TB, c Corpus, maximo float64, opts ...ordenJson.Option) {
+	t.Helper()
+	if reservas := Reservas(c, opts...); reservas > maximo {
+		t.Errorf("%s: %.1f reservas por documento, máximo %.1f", c.Nombre, reservas, maximo)
+	}
+}
+
+// documentos devuelve los documentos del corpus como inputs de AgregarJSON.
+// Se convierten una sola vez para que la conversión a interface{} no cuente
+// entre las reservas medidas.
+func documentos(c Corpus) []interface{} {
+	docs := make([]interface{}, len(c.Documentos))
+	for i, doc := range c.Documentos {
+		docs[i] = doc
+	}
+	return docs
+}
+
+// mezclar devuelve una copia de campos en orden aleatorio.
+func mezclar(r *rand.Rand, campos []string) []string {
+	copia := append([]string(nil), campos...)
+	r.Shuffle(len(copia), func(i, j int) { copia[i], copia[j] = copia[j], copia[i] })
+	return copia
+}
+
+// palabra devuelve un texto ASCII de n letras minúsculas.
+func palabra(r *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + r.IntN(26))
+	}
+	return string(b)
+}
+
+// valorEscalar devuelve un valor JSON que no es objeto ni arreglo.
+func valorEscalar(r *rand.Rand) string {
+	switch r.IntN(5) {
+	case 0:
+		return fmt.Sprintf("%q", palabra(r, 1+r.IntN(30)))
+	case 1:
+		return fmt.Sprint(r.IntN(1_000_000))
+	case 2:
+		return fmt.Sprint(r.NormFloat64() * 1e6)
+	case 3:
+		return []string{"true", "false"}[r.IntN(2)]
+	default:
+		return "null"
+	}
+}
+
+// escribirAnidado escribe en sb un objeto o arreglo de hasta profundidad niveles.
+func escribirAnidado(sb *strings.Builder, r *rand.Rand, profundidad int) {
+	if profundidad == 0 {
+		sb.WriteString(valorEscalar(r))
+		return
+	}
+	n := 1 + r.IntN(4)
+	if r.IntN(2) == 0 {
+		sb.WriteByte('[')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			escribirAnidado(sb, r, profundidad-1)
+		}
+		sb.WriteByte(']')
+		return
+	}
+	sb.WriteByte('{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(sb, "%q:", palabra(r, 3+r.IntN(6)))
+		escribirAnidado(sb, r, profundidad-1)
+	}
+	sb.WriteByte('}')
+}
@@ -0,0 +1,247 @@
+// Package cmis convierte entre los mapas de propiedades de CMIS, en el formato
+// JSON del browser binding, y los documentos planos que ordena ordenJson, para
+// que los equipos que usan CMIS en lugar de la API REST nativa apliquen el
+// mismo orden canónico.
+//
+//	props, err := cmis.LeerPropiedades(datos) // completo o "succinct"
+//	ordenado, err := cmis.Ordenar(ordenador, props)
+//	completo, err := cmis.EscribirPropiedades(ordenado, props)
+//
+// En el documento plano cada propiedad es una clave con su valor; las
+// propiedades multivaluadas son arreglos. Las de los tipos secundarios
+// (aspectos) se tratan igual que las del tipo principal, siempre que el
+// objeto declare esos tipos; ver VerificarTiposSecundarios.
+package cmis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Propiedades de CMIS que determinan qué otras propiedades admite el objeto.
+const (
+	ObjectTypeID           = "cmis:objectTypeId"
+	SecondaryObjectTypeIDs = "cmis:secondaryObjectTypeIds"
+)
+
+// Valores de Propiedad.Cardinalidad.
+const (
+	cardinalidadUnValor    = "single"
+	cardinalidadMultivalor = "multi"
+)
+
+// Propiedad es una propiedad de CMIS en el formato completo del browser
+// binding.
+type Propiedad struct {
+	ID           string      `json:"id"`
+	LocalName    string      `json:"localName,omitempty"`
+	DisplayName  string      `json:"displayName,omitempty"`
+	QueryName    string      `json:"queryName,omitempty"`
+	Tipo         string      `json:"type,omitempty"`        // id, string, boolean, integer, decimal, datetime, uri o html.
+	Cardinalidad string      `json:"cardinality,omitempty"` // single o multi.
+	Valor        interface{} `json:"value"`
+}
+
+// Propiedades es un mapa de propiedades de CMIS indexado por su id.
+type Propiedades map[string]Propiedad
+
+// LeerPropiedades interpreta un mapa de propiedades en formato completo, como
+// el campo "properties" de un objeto del browser binding, o en formato
+// "succinct", donde cada propiedad es directamente su valor. En el formato
+// succinct solo se conocen el id y el valor de cada propiedad.
+func LeerPropiedades(datos []byte) (Propiedades, error) {
+	var crudas map[string]json.RawMessage
+	if err := json.Unmarshal(datos, &crudas); err != nil {
+		return nil, fmt.Errorf("mapa de propiedades CMIS inválido: %w", err)
+	}
+	props := make(Propiedades, len(crudas))
+	completo := len(crudas) > 0
+	for _, cruda := range crudas {
+		if !esPropiedadCompleta(cruda) {
+			completo = false
+			break
+		}
+	}
+	for id, cruda := range crudas {
+		p := Propiedad{ID: id}
+		var err error
+		if completo {
+			err = json.Unmarshal(cruda, &p)
+			if p.ID == "" {
+				p.ID = id
+			}
+		} else {
+			err = json.Unmarshal(cruda, &p.Valor)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("propiedad %s: %w", id, err)
+		}
+		if p.ID != id {
+			return nil, fmt.Errorf("propiedad %s: el id %q no coincide con su clave", id, p.ID)
+		}
+		props[id] = p
+	}
+	return props, nil
+}
+
+// esPropiedadCompleta indica si cruda es un objeto con la forma de Propiedad.
+func esPropiedadCompleta(cruda json.RawMessage) bool {
+	var campos map[string]json.RawMessage
+	if json.Unmarshal(cruda, &campos) != nil {
+		return false
+	}
+	_, tieneValor := campos["value"]
+	_, tieneID := campos["id"]
+	return tieneValor && tieneID
+}
+
+// Documento devuelve las propiedades como documento plano: cada id con su
+// valor.
+func (props Propiedades) Documento() map[string]interface{} {
+	documento := make(map[string]interface{}, len(props))
+	for id, p := range props {
+		documento[id] = p.Valor
+	}
+	return documento
+}
+
+// TiposSecundarios devuelve los ids de los tipos secundarios del objeto,
+// según la propiedad cmis:secondaryObjectTypeIds.
+func (props Propiedades) TiposSecundarios() []string {
+	var tipos []string
+	switch v := props[SecondaryObjectTypeIDs].Valor.(type) {
+	case []interface{}:
+		for _, tipo := range v {
+			if s, ok := tipo.(string); ok {
+				tipos = append(tipos, s)
+			}
+		}
+	case string:
+		tipos = append(tipos, v)
+	}
+	return tipos
+}
+
+// Perfil devuelve un perfil con cmis:objectTypeId y
+// cmis:secondaryObjectTypeIds antes que los campos de base. Algunos
+// repositorios interpretan las propiedades en el orden recibido y necesitan
+// conocer los tipos del objeto antes que las propiedades que estos definen.
+func Perfil(base *ordenJson.Perfil) *ordenJson.Perfil {
+	campos := append([]string{ObjectTypeID, SecondaryObjectTypeIDs}, base.Campos()...)
+	return ordenJson.NuevoPerfil("cmis:"+base.Nombre(), campos)
+}
+
+// Ordenar ordena las propiedades con el Ordenador recibido y devuelve el
+// documento plano ordenado. Con un Ordenador creado con
+// ordenJson.WithPerfil(cmis.Perfil(...)), los tipos del objeto quedan antes
+// que el resto de las propiedades. Devuelve error si una propiedad de un tipo
+// secundario no tiene entre sus tipos el aspecto que la define; ver
+// VerificarTiposSecundarios.
+func Ordenar(o *ordenJson.Ordenador, props Propiedades) (string, error) {
+	if err := VerificarTiposSecundarios(props); err != nil {
+		return "", err
+	}
+	return o.OrdenarJSON(props.Documento())
+}
+
+// ErrorTipoSecundario indica que hay propiedades con el prefijo de un tipo
+// secundario que no está en cmis:secondaryObjectTypeIds.
+type ErrorTipoSecundario struct {
+	Propiedades []string // Ids de las propiedades sin su tipo, en orden alfabético.
+}
+
+func (e *ErrorTipoSecundario) Error() string {
+	return fmt.Sprintf("propiedades de tipos secundarios no declarados en %s: %s", SecondaryObjectTypeIDs, strings.Join(e.Propiedades, ", "))
+}
+
+// VerificarTiposSecundarios revisa, si el objeto declara
+// cmis:secondaryObjectTypeIds, que cada propiedad con prefijo pertenezca a un
+// tipo declarado: por convención de Alfresco, el aspecto "P:cm:titled" define
+// las propiedades "cm:...". Las propiedades "cmis:" y las del prefijo del
+// tipo principal siempre se admiten. Si el objeto no declara tipos
+// secundarios no se revisa nada.
+func VerificarTiposSecundarios(props Propiedades) error {
+	secundarios := props.TiposSecundarios()
+	if len(secundarios) == 0 {
+		return nil
+	}
+	prefijos := map[string]bool{"cmis": true}
+	if principal, ok := props[ObjectTypeID].Valor.(string); ok {
+		prefijos[prefijoTipo(principal)] = true
+	}
+	for _, tipo := range secundarios {
+		prefijos[prefijoTipo(tipo)] = true
+	}
+	var faltantes []string
+	for id := range props {
+		if prefijo, _, ok := strings.Cut(id, ":"); ok && !prefijos[prefijo] {
+			faltantes = append(faltantes, id)
+		}
+	}
+	if len(faltantes) == 0 {
+		return nil
+	}
+	sort.Strings(faltantes)
+	return &ErrorTipoSecundario{Propiedades: faltantes}
+}
+
+// prefijoTipo devuelve el prefijo de espacio de nombres del id de un tipo:
+// "cm" para "P:cm:titled" o "cm:content", y "cmis" para "cmis:document".
+func prefijoTipo(tipo string) string {
+	partes := strings.Split(tipo, ":")
+	if len(partes) >= 3 {
+		return partes[1]
+	}
+	return partes[0]
+}
+
+// EscribirPropiedades convierte un documento plano ordenado en un mapa de
+// propiedades en formato completo, con las propiedades en el mismo orden. Los
+// metadatos de cada propiedad (nombres, tipo) se toman de original, si la
+// tiene; si no, la cardinalidad se deduce del valor.
+func EscribirPropiedades(ordenado string, original Propiedades) ([]byte, error) {
+	dec := json.NewDecoder(strings.NewReader(ordenado))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, errors.New("el documento ordenado no es un objeto JSON")
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		id := token.(string)
+		var valor interface{}
+		if err := dec.Decode(&valor); err != nil {
+			return nil, err
+		}
+		p, ok := original[id]
+		if !ok {
+			p = Propiedad{ID: id, Cardinalidad: cardinalidadUnValor}
+			if _, multi := valor.([]interface{}); multi {
+				p.Cardinalidad = cardinalidadMultivalor
+			}
+		}
+		p.Valor = valor
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		clave, _ := json.Marshal(id)
+		codificada, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("propiedad %s: %w", id, err)
+		}
+		buf.Write(clave)
+		buf.WriteByte(':')
+		buf.Write(codificada)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
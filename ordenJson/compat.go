@@ -0,0 +1,124 @@
+package ordenJson
+
+import (
+	v2 "github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Este archivo reexporta la API que se agregó a este paquete antes de la
+// separación en v2, para que el código que ya la usa siga compilando.
+// Todos los nombres son alias de v2 y se comportan exactamente igual.
+
+// Deprecated: usar v2.Option.
+type Option = v2.Option
+
+// Deprecated: usar v2.Perfil.
+type Perfil = v2.Perfil
+
+// Deprecated: usar v2.Problema.
+type Problema = v2.Problema
+
+// Deprecated: usar v2.Severidad.
+type Severidad = v2.Severidad
+
+// Deprecated: usar v2.Reporte.
+type Reporte = v2.Reporte
+
+// Deprecated: usar v2.InfoVersion.
+type InfoVersion = v2.InfoVersion
+
+// Tipos de error; ver la documentación de cada uno en v2.
+type (
+	// Deprecated: usar v2.ErrorTiempoExcedido.
+	ErrorTiempoExcedido = v2.ErrorTiempoExcedido
+	// Deprecated: usar v2.ErrorValorNoPermitido.
+	ErrorValorNoPermitido = v2.ErrorValorNoPermitido
+	// Deprecated: usar v2.ErrorJSONInvalido.
+	ErrorJSONInvalido = v2.ErrorJSONInvalido
+	// Deprecated: usar v2.ErrorTipoNoSoportado.
+	ErrorTipoNoSoportado = v2.ErrorTipoNoSoportado
+	// Deprecated: usar v2.ErrorCamposFaltantes.
+	ErrorCamposFaltantes = v2.ErrorCamposFaltantes
+	// Deprecated: usar v2.ErrorClavesNoPermitidas.
+	ErrorClavesNoPermitidas = v2.ErrorClavesNoPermitidas
+	// Deprecated: usar v2.ErrorFechaInvalida.
+	ErrorFechaInvalida = v2.ErrorFechaInvalida
+	// Deprecated: usar v2.ErrorRegla.
+	ErrorRegla = v2.ErrorRegla
+	// Deprecated: usar v2.ErrorValorNoSerializable.
+	ErrorValorNoSerializable = v2.ErrorValorNoSerializable
+	// Deprecated: usar v2.ErrorEsquemaInvalido.
+	ErrorEsquemaInvalido = v2.ErrorEsquemaInvalido
+)
+
+// Constantes reexportadas de v2.
+const (
+	// Deprecated: usar v2.FormatoFechaCanonico.
+	FormatoFechaCanonico = v2.FormatoFechaCanonico
+	// Deprecated: usar v2.VersionPerfil.
+	VersionPerfil = v2.VersionPerfil
+
+	// Deprecated: usar v2.ReglaRequerido.
+	ReglaRequerido = v2.ReglaRequerido
+	// Deprecated: usar v2.ReglaClaveDesconocida.
+	ReglaClaveDesconocida = v2.ReglaClaveDesconocida
+	// Deprecated: usar v2.ReglaFecha.
+	ReglaFecha = v2.ReglaFecha
+	// Deprecated: usar v2.ReglaValorPermitido.
+	ReglaValorPermitido = v2.ReglaValorPermitido
+
+	// Deprecated: usar v2.SeveridadError.
+	SeveridadError = v2.SeveridadError
+	// Deprecated: usar v2.SeveridadAdvertencia.
+	SeveridadAdvertencia = v2.SeveridadAdvertencia
+)
+
+// Variables reexportadas de v2. Comparten los mismos arreglos y el mismo perfil.
+var (
+	// Deprecated: usar v2.PerfilPorDefecto.
+	PerfilPorDefecto = v2.PerfilPorDefecto
+	// Deprecated: usar v2.CamposFecha.
+	CamposFecha = v2.CamposFecha
+	// Deprecated: usar v2.FormatosFechaPorDefecto.
+	FormatosFechaPorDefecto = v2.FormatosFechaPorDefecto
+	// Deprecated: usar v2.ValoresEstadoVisado.
+	ValoresEstadoVisado = v2.ValoresEstadoVisado
+	// Deprecated: usar v2.ValoresEstadoVigencia.
+	ValoresEstadoVigencia = v2.ValoresEstadoVigencia
+)
+
+// Funciones reexportadas de v2.
+var (
+	// Deprecated: usar v2.NuevoPerfil.
+	NuevoPerfil = v2.NuevoPerfil
+	// Deprecated: usar v2.PerfilDesdeEsquema.
+	PerfilDesdeEsquema = v2.PerfilDesdeEsquema
+	// Deprecated: usar v2.OrdenarJSONConReporte.
+	OrdenarJSONConReporte = v2.OrdenarJSONConReporte
+	// Deprecated: usar v2.NuevoReporte.
+	NuevoReporte = v2.NuevoReporte
+	// Deprecated: usar v2.Version.
+	Version = v2.Version
+
+	// Deprecated: usar v2.WithPerfil.
+	WithPerfil = v2.WithPerfil
+	// Deprecated: usar v2.WithNormalizarFechas.
+	WithNormalizarFechas = v2.WithNormalizarFechas
+	// Deprecated: usar v2.WithCamposFecha.
+	WithCamposFecha = v2.WithCamposFecha
+	// Deprecated: usar v2.WithTamanoEsperado.
+	WithTamanoEsperado = v2.WithTamanoEsperado
+	// Deprecated: usar v2.WithPresupuesto.
+	WithPresupuesto = v2.WithPresupuesto
+	// Deprecated: usar v2.WithRequired.
+	WithRequired = v2.WithRequired
+	// Deprecated: usar v2.WithStrict.
+	WithStrict = v2.WithStrict
+	// Deprecated: usar v2.WithValoresPermitidos.
+	WithValoresPermitidos = v2.WithValoresPermitidos
+	// Deprecated: usar v2.WithValidarEstados.
+	WithValidarEstados = v2.WithValidarEstados
+	// Deprecated: usar v2.WithVacio.
+	WithVacio = v2.WithVacio
+	// Deprecated: usar v2.MarcadoresVacios.
+	MarcadoresVacios = v2.MarcadoresVacios
+)
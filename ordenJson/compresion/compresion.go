@@ -0,0 +1,193 @@
+// Package compresion comprime documentos ordenados con zstd usando un
+// diccionario compartido construido a partir del perfil de campos.
+//
+// Los documentos de metadatos son pequeños y repiten siempre las mismas claves
+// en el mismo orden, por lo que un compresor genérico apenas los reduce: cada
+// documento vuelve a pagar el costo de todas sus claves. Con un diccionario que
+// ya contiene esas claves, y los valores más frecuentes, cada documento
+// comprimido solo guarda lo que lo distingue.
+//
+// El diccionario se construye una vez con NuevoDiccionario, se guarda con
+// Bytes y se vuelve a cargar con Cargar; un documento solo se puede
+// descomprimir con el mismo diccionario con que se comprimió.
+package compresion
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// TamanoMaximo es el tamaño máximo, en bytes, del contenido de un diccionario.
+const TamanoMaximo = 64 << 10
+
+// frecuenciaMinima es la cantidad mínima de muestras en que debe aparecer una
+// línea para incluirla en el diccionario.
+const frecuenciaMinima = 2
+
+// ErrDiccionarioVacio se devuelve al cargar un diccionario sin contenido.
+var ErrDiccionarioVacio = errors.New("compresion: diccionario vacío")
+
+// Diccionario comprime y descomprime documentos con un diccionario zstd.
+// Es seguro usarlo desde varias goroutines a la vez.
+type Diccionario struct {
+	id        uint32
+	contenido []byte
+	enc       *zstd.Encoder
+	dec       *zstd.Decoder
+}
+
+// NuevoDiccionario construye un diccionario a partir de las claves de perfil
+// (ordenJson.PerfilPorDefecto si es nil) y de las líneas que más se repiten en
+// muestras, que deben ser documentos JSON representativos. Las muestras se
+// ordenan con el perfil antes de analizarlas, de modo que el diccionario
+// refleja la forma exacta de la salida de ordenJson.OrdenarJSON.
+func NuevoDiccionario(perfil *ordenJson.Perfil, muestras []string) (*Diccionario, error) {
+	if perfil == nil {
+		perfil = ordenJson.PerfilPorDefecto
+	}
+	ordenador := ordenJson.Nuevo(ordenJson.WithPerfil(perfil))
+
+	frecuencia := make(map[string]int)
+	for i, muestra := range muestras {
+		ordenado, err := ordenador.OrdenarJSON(muestra)
+		if err != nil {
+			return nil, fmt.Errorf("compresion: muestra %d: %w", i, err)
+		}
+		vistas := make(map[string]bool)
+		for _, linea := range bytes.Split([]byte(ordenado), []byte("\n")) {
+			linea = bytes.TrimSuffix(linea, []byte(","))
+			if len(linea) == 0 || vistas[string(linea)] {
+				continue
+			}
+			vistas[string(linea)] = true
+			frecuencia[string(linea)]++
+		}
+	}
+	return Cargar(0, construirContenido(perfil.Campos(), frecuencia))
+}
+
+// construirContenido arma el contenido del diccionario. zstd favorece las
+// coincidencias cercanas al final del diccionario, por lo que las líneas
+// frecuentes se ubican de menor a mayor frecuencia y al final va un documento
+// con todas las claves del perfil en su orden.
+func construirContenido(campos []string, frecuencia map[string]int) []byte {
+	var esqueleto bytes.Buffer
+	esqueleto.WriteString("{\n")
+	for i, campo := range campos {
+		clave, _ := json.Marshal(campo)
+		esqueleto.WriteString("  ")
+		esqueleto.Write(clave)
+		esqueleto.WriteString(`: ""`)
+		if i < len(campos)-1 {
+			esqueleto.WriteByte(',')
+		}
+		esqueleto.WriteByte('\n')
+	}
+	esqueleto.WriteString("}\n")
+
+	lineas := make([]string, 0, len(frecuencia))
+	for linea, n := range frecuencia {
+		if n >= frecuenciaMinima {
+			lineas = append(lineas, linea)
+		}
+	}
+	sort.Slice(lineas, func(i, j int) bool {
+		a, b := lineas[i], lineas[j]
+		if frecuencia[a] != frecuencia[b] {
+			return frecuencia[a] > frecuencia[b]
+		}
+		return a < b
+	})
+
+	// Se conservan las líneas más frecuentes que entran junto al esqueleto y
+	// luego se invierten para que las más frecuentes queden al final.
+	disponible := TamanoMaximo - esqueleto.Len()
+	n := 0
+	for _, linea := range lineas {
+		if disponible < len(linea)+2 {
+			break
+		}
+		disponible -= len(linea) + 2
+		n++
+	}
+	lineas = lineas[:n]
+
+	contenido := make([]byte, 0, TamanoMaximo-disponible)
+	for i := len(lineas) - 1; i >= 0; i-- {
+		contenido = append(contenido, lineas[i]...)
+		contenido = append(contenido, ',', '\n')
+	}
+	return append(contenido, esqueleto.Bytes()...)
+}
+
+// Cargar reconstruye un diccionario a partir del identificador y el contenido
+// devueltos por ID y Bytes. Si id es 0 se deriva del contenido.
+func Cargar(id uint32, contenido []byte) (*Diccionario, error) {
+	if len(contenido) == 0 {
+		return nil, ErrDiccionarioVacio
+	}
+	if id == 0 {
+		id = idDesdeContenido(contenido)
+	}
+	contenido = bytes.Clone(contenido)
+	enc, err := zstd.NewWriter(nil,
+		zstd.WithEncoderDictRaw(id, contenido),
+		zstd.WithEncoderCRC(false),
+		zstd.WithEncoderLevel(zstd.SpeedBetterCompression),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compresion: %w", err)
+	}
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderDictRaw(id, contenido))
+	if err != nil {
+		enc.Close()
+		return nil, fmt.Errorf("compresion: %w", err)
+	}
+	return &Diccionario{id: id, contenido: contenido, enc: enc, dec: dec}, nil
+}
+
+// idDesdeContenido deriva el identificador del diccionario de su contenido.
+// Los valores menores a 32768 están reservados por el formato zstd y los
+// mayores o iguales a 2^31 también, por lo que el resultado cae entre ambos.
+func idDesdeContenido(contenido []byte) uint32 {
+	return 1<<15 + crc32.ChecksumIEEE(contenido)%(1<<31-1<<15)
+}
+
+// ID devuelve el identificador del diccionario, que se guarda en cada documento comprimido.
+func (d *Diccionario) ID() uint32 {
+	return d.id
+}
+
+// Bytes devuelve el contenido del diccionario para guardarlo junto a los
+// documentos comprimidos. El resultado no se debe modificar.
+func (d *Diccionario) Bytes() []byte {
+	return d.contenido
+}
+
+// Comprimir comprime un documento con el diccionario.
+func (d *Diccionario) Comprimir(documento []byte) []byte {
+	return d.enc.EncodeAll(documento, nil)
+}
+
+// Descomprimir recupera un documento comprimido con Comprimir. Falla si el
+// documento se comprimió con otro diccionario.
+func (d *Diccionario) Descomprimir(comprimido []byte) ([]byte, error) {
+	documento, err := d.dec.DecodeAll(comprimido, nil)
+	if err != nil {
+		return nil, fmt.Errorf("compresion: %w", err)
+	}
+	return documento, nil
+}
+
+// Cerrar libera los recursos del compresor y del descompresor.
+func (d *Diccionario) Cerrar() {
+	d.enc.Close()
+	d.dec.Close()
+}
@@ -0,0 +1,217 @@
+// Package configuracion carga las reglas de ordenamiento desde archivos YAML,
+// para versionarlas junto a los datos y compartirlas entre el comando
+// ordena-json y el servidor ordenad.
+package configuracion
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ArchivoPorDefecto es el nombre del archivo de configuración que busca
+// Buscar en el directorio actual y en sus ancestros.
+const ArchivoPorDefecto = ".ordenajson.yaml"
+
+// Config es el contenido de un archivo de configuración. Permite
+// versionar las reglas de ordenamiento junto a los datos. Ejemplo:
+//
+//	nombre: contratos
+//	campos: [tanner:tipo-documento, tanner:rut-cliente, cm:title]
+//	requeridos: [tanner:rut-cliente]
+//	estricto: true
+//	tipos-documento: true
+//	claves-unicas: true
+//	normalizar-fechas: true
+//	formato-salida-fecha: "2006-01-02"
+//	valores-permitidos:
+//	  tanner:estado-visado: [aprobado, rechazado]
+//	vacios: ["-", "N/A"]
+//	campos-vacios: "null"
+//	renombres:
+//	  tanner:rut_cliente: tanner:rut-cliente
+//	  titulo: cm:title
+//	excluir: [tanner:observaciones]
+//	valores-por-defecto:
+//	  tanner:estado-vigencia: vigente
+//	ordenar-listas: [tanner:categorias]
+//	idioma: en
+type Config struct {
+	Nombre            string                 `yaml:"nombre"`               // Nombre del perfil; por defecto el nombre del archivo.
+	Campos            []string               `yaml:"campos"`               // Orden de los campos; vacío usa el perfil por defecto.
+	Requeridos        []string               `yaml:"requeridos"`           // Ver ordenJson.WithRequired.
+	Estricto          bool                   `yaml:"estricto"`             // Ver ordenJson.WithStrict.
+	TiposDocumento    bool                   `yaml:"tipos-documento"`      // Ver ordenJson.WithTiposDocumento.
+	ClavesUnicas      bool                   `yaml:"claves-unicas"`        // Ver ordenJson.WithClavesUnicas.
+	NormalizarFechas  bool                   `yaml:"normalizar-fechas"`    // Ver ordenJson.WithNormalizarFechas.
+	FormatosFecha     []string               `yaml:"formatos-fecha"`       // Layouts aceptados; implica normalizar-fechas.
+	FormatoSalida     string                 `yaml:"formato-salida-fecha"` // Layout de las fechas escritas; ver ordenJson.WithFormatoSalidaFecha.
+	CamposFecha       []string               `yaml:"campos-fecha"`         // Ver ordenJson.WithCamposFecha.
+	ValidarEstados    bool                   `yaml:"validar-estados"`      // Ver ordenJson.WithValidarEstados.
+	ValoresPermitidos map[string][]string    `yaml:"valores-permitidos"`   // Ver ordenJson.WithValoresPermitidos.
+	Vacios            []string               `yaml:"vacios"`               // Marcadores que cuentan como vacíos; ver ordenJson.WithVacio.
+	CamposVacios      string                 `yaml:"campos-vacios"`        // "omitir", "conservar", "null" u "omitempty"; ver ordenJson.WithCamposVacios.
+	Renombres         map[string]string      `yaml:"renombres"`            // Nombre actual de cada clave anterior; ver ordenJson.WithRenombres.
+	Solo              []string               `yaml:"solo"`                 // Campos que se escriben; ver ordenJson.WithOnly.
+	Excluir           []string               `yaml:"excluir"`              // Campos que se omiten; ver ordenJson.WithExclude.
+	ValoresPorDefecto map[string]interface{} `yaml:"valores-por-defecto"`  // Ver ordenJson.WithDefaults.
+	OrdenarListas     []string               `yaml:"ordenar-listas"`       // Campos cuyos arreglos se ordenan alfabéticamente; ver ordenJson.WithOrdenarLista.
+	Idioma            string                 `yaml:"idioma"`               // Idioma de los mensajes de error, "es" o "en"; ver ordenJson.WithIdioma.
+}
+
+// CargarOpciones devuelve las opciones de ordenamiento definidas en ruta o,
+// si ruta es vacía, en el primer ArchivoPorDefecto que se encuentre desde el
+// directorio actual hacia arriba. Sin archivo devuelve nil, es decir, las
+// opciones por defecto.
+func CargarOpciones(ruta string) ([]ordenJson.Option, error) {
+	if ruta == "" {
+		var err error
+		if ruta, err = Buscar(); ruta == "" || err != nil {
+			return nil, err
+		}
+	}
+	cfg, err := Cargar(ruta)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Opciones(), nil
+}
+
+// Cargar lee el archivo de configuración ruta. Las claves desconocidas y los
+// valores de campos-vacios distintos de omitir, conservar, null u omitempty
+// son un error; un null de YAML sin comillas equivale a "null". Si no indica
+// un nombre se usa el nombre del archivo.
+func Cargar(ruta string) (Config, error) {
+	contenido, err := os.ReadFile(ruta)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(contenido))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("%s: %w", ruta, err)
+	}
+	if err := cfg.leerCamposVacios(contenido); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", ruta, err)
+	}
+	if cfg.Nombre == "" {
+		cfg.Nombre = filepath.Base(ruta)
+	}
+	return cfg, nil
+}
+
+// leerCamposVacios valida CamposVacios. Como YAML lee campos-vacios: null
+// como la cadena vacía, vuelve a leer el nodo para distinguirlo de la clave
+// ausente.
+func (c *Config) leerCamposVacios(contenido []byte) error {
+	var nodos struct {
+		CamposVacios yaml.Node `yaml:"campos-vacios"`
+	}
+	if err := yaml.Unmarshal(contenido, &nodos); err != nil {
+		return err
+	}
+	if nodos.CamposVacios.Kind != 0 && nodos.CamposVacios.ShortTag() == "!!null" {
+		c.CamposVacios = string(ordenJson.CamposVaciosNull)
+	}
+	switch ordenJson.CamposVacios(c.CamposVacios) {
+	case "", ordenJson.CamposVaciosOmitir, ordenJson.CamposVaciosConservar, ordenJson.CamposVaciosNull, ordenJson.CamposVaciosOmitempty:
+		return nil
+	}
+	return fmt.Errorf("campos-vacios: %q no es omitir, conservar, null ni omitempty", c.CamposVacios)
+}
+
+// Buscar busca ArchivoPorDefecto en el directorio actual y en sus ancestros.
+// Devuelve "" si no lo encuentra.
+func Buscar() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		ruta := filepath.Join(dir, ArchivoPorDefecto)
+		if _, err := os.Stat(ruta); err == nil {
+			return ruta, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		padre := filepath.Dir(dir)
+		if padre == dir {
+			return "", nil
+		}
+		dir = padre
+	}
+}
+
+// Opciones traduce la configuración a opciones de ordenJson.
+func (c Config) Opciones() []ordenJson.Option {
+	var opts []ordenJson.Option
+	if len(c.Campos) > 0 {
+		opts = append(opts, ordenJson.WithPerfil(ordenJson.NuevoPerfil(c.Nombre, c.Campos)))
+	}
+	if len(c.Requeridos) > 0 {
+		opts = append(opts, ordenJson.WithRequired(c.Requeridos...))
+	}
+	if c.Estricto {
+		opts = append(opts, ordenJson.WithStrict())
+	}
+	if c.TiposDocumento {
+		opts = append(opts, ordenJson.WithTiposDocumento())
+	}
+	if c.ClavesUnicas {
+		opts = append(opts, ordenJson.WithClavesUnicas())
+	}
+	if c.NormalizarFechas || len(c.FormatosFecha) > 0 {
+		opts = append(opts, ordenJson.WithNormalizarFechas(c.FormatosFecha...))
+	}
+	if c.FormatoSalida != "" {
+		opts = append(opts, ordenJson.WithFormatoSalidaFecha(c.FormatoSalida))
+	}
+	if len(c.CamposFecha) > 0 {
+		opts = append(opts, ordenJson.WithCamposFecha(c.CamposFecha...))
+	}
+	if c.ValidarEstados {
+		opts = append(opts, ordenJson.WithValidarEstados())
+	}
+	campos := make([]string, 0, len(c.ValoresPermitidos))
+	for campo := range c.ValoresPermitidos {
+		campos = append(campos, campo)
+	}
+	sort.Strings(campos)
+	for _, campo := range campos {
+		opts = append(opts, ordenJson.WithValoresPermitidos(campo, c.ValoresPermitidos[campo]...))
+	}
+	if len(c.Vacios) > 0 {
+		opts = append(opts, ordenJson.WithVacio(ordenJson.MarcadoresVacios(c.Vacios...)))
+	}
+	if c.CamposVacios != "" {
+		opts = append(opts, ordenJson.WithCamposVacios(ordenJson.CamposVacios(c.CamposVacios)))
+	}
+	if len(c.Renombres) > 0 {
+		opts = append(opts, ordenJson.WithRenombres(c.Renombres))
+	}
+	if len(c.Solo) > 0 {
+		opts = append(opts, ordenJson.WithOnly(c.Solo...))
+	}
+	if len(c.Excluir) > 0 {
+		opts = append(opts, ordenJson.WithExclude(c.Excluir...))
+	}
+	if len(c.ValoresPorDefecto) > 0 {
+		opts = append(opts, ordenJson.WithDefaults(c.ValoresPorDefecto))
+	}
+	for _, campo := range c.OrdenarListas {
+		opts = append(opts, ordenJson.WithOrdenarLista(campo, nil))
+	}
+	if c.Idioma != "" {
+		opts = append(opts, ordenJson.WithIdioma(ordenJson.Idioma(c.Idioma)))
+	}
+	return opts
+}
@@ -0,0 +1,134 @@
+// Package cuarentena guarda, sin modificarlos, los documentos que no se
+// pudieron ordenar, junto con un reporte del error. Así los documentos
+// inválidos quedan separados para revisarlos o reprocesarlos en lugar de
+// mezclarse con los logs.
+//
+// Un Destino puede ser un directorio (ver Directorio) o cualquier otro
+// almacenamiento, como un prefijo en un bucket o una cola, adaptado con
+// DestinoFunc.
+package cuarentena
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Tipos de falla que se informan en Registro.Tipo.
+const (
+	TipoJSONInvalido = "json-invalido" // El documento no es un objeto JSON válido.
+	TipoValidacion   = "validacion"    // El documento no cumple las reglas configuradas.
+	TipoOtro         = "otro"          // Cualquier otro error, como un presupuesto de tiempo agotado.
+)
+
+// SufijoReporte se agrega al nombre del documento para nombrar su reporte en Directorio.
+const SufijoReporte = ".error.json"
+
+// Registro describe por qué un documento terminó en cuarentena.
+type Registro struct {
+	Origen    string               `json:"origen"`              // Archivo o posición en el flujo de donde provino el documento.
+	Fecha     time.Time            `json:"fecha"`               // Momento en que se envió a cuarentena.
+	Tipo      string               `json:"tipo"`                // Ver las constantes Tipo*.
+	Error     string               `json:"error"`               // Mensaje del error.
+	Problemas []ordenJson.Problema `json:"problemas,omitempty"` // Con TipoValidacion, todos los problemas encontrados.
+	Intentos  int                  `json:"intentos"`            // Veces que se intentó ordenar el documento; ver Reprocesar.
+}
+
+// Destino recibe los documentos en cuarentena.
+type Destino interface {
+	Guardar(documento []byte, registro Registro) error
+}
+
+// DestinoFunc adapta una función para usarla como Destino.
+type DestinoFunc func(documento []byte, registro Registro) error
+
+// Guardar llama a f.
+func (f DestinoFunc) Guardar(documento []byte, registro Registro) error {
+	return f(documento, registro)
+}
+
+// NuevoRegistro construye el registro de un documento que falló con err. Si
+// la falla es de validación y ordenador no es nil, el documento se vuelve a
+// revisar con OrdenarJSONConReporte para incluir todos los problemas y no solo
+// el primero.
+func NuevoRegistro(origen string, documento []byte, err error, ordenador *ordenJson.Ordenador) Registro {
+	r := Registro{Origen: origen, Fecha: time.Now().UTC(), Tipo: Clasificar(err), Error: err.Error(), Intentos: 1}
+	if r.Tipo == TipoValidacion && ordenador != nil {
+		if _, problemas, errReporte := ordenador.OrdenarJSONConReporte(string(documento)); errReporte == nil {
+			for _, p := range problemas {
+				if p.Severidad == ordenJson.SeveridadError {
+					r.Problemas = append(r.Problemas, p)
+				}
+			}
+		}
+	}
+	return r
+}
+
+// Clasificar devuelve el tipo de falla que corresponde a err.
+func Clasificar(err error) string {
+	var (
+		errJSON      *ordenJson.ErrorJSONInvalido
+		errTipo      *ordenJson.ErrorTipoNoSoportado
+		errFaltantes *ordenJson.ErrorCamposFaltantes
+		errClaves    *ordenJson.ErrorClavesNoPermitidas
+		errValor     *ordenJson.ErrorValorNoPermitido
+		errFecha     *ordenJson.ErrorFechaInvalida
+		errRegla     *ordenJson.ErrorRegla
+	)
+	switch {
+	case errors.As(err, &errJSON), errors.As(err, &errTipo):
+		return TipoJSONInvalido
+	case errors.As(err, &errFaltantes), errors.As(err, &errClaves), errors.As(err, &errValor),
+		errors.As(err, &errFecha), errors.As(err, &errRegla):
+		return TipoValidacion
+	}
+	return TipoOtro
+}
+
+// Directorio devuelve un Destino que escribe cada documento en dir, con el
+// nombre de su origen, y su registro al lado con SufijoReporte. Las rutas
+// relativas del origen se conservan; las absolutas y los ".." se descartan
+// para que nada se escriba fuera de dir.
+func Directorio(dir string) Destino {
+	return DestinoFunc(func(documento []byte, registro Registro) error {
+		ruta := filepath.Join(dir, rutaSegura(registro.Origen))
+		if err := os.MkdirAll(filepath.Dir(ruta), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(ruta, documento, 0o644); err != nil {
+			return err
+		}
+		return escribirRegistro(ruta+SufijoReporte, registro)
+	})
+}
+
+// escribirRegistro guarda registro como JSON indentado en ruta.
+func escribirRegistro(ruta string, registro Registro) error {
+	reporte, err := json.MarshalIndent(registro, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ruta, append(reporte, '\n'), 0o644)
+}
+
+// rutaSegura convierte un origen en una ruta relativa que no sale del directorio.
+func rutaSegura(origen string) string {
+	var partes []string
+	for _, parte := range strings.FieldsFunc(filepath.ToSlash(origen), func(r rune) bool { return r == '/' }) {
+		if parte == "." || parte == ".." {
+			continue
+		}
+		partes = append(partes, strings.ReplaceAll(parte, ":", "_"))
+	}
+	if len(partes) == 0 {
+		return fmt.Sprintf("documento-%d", time.Now().UnixNano())
+	}
+	return filepath.Join(partes...)
+}
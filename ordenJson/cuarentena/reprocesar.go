@@ -0,0 +1,91 @@
+package cuarentena
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Reproceso resume una ejecución de Reprocesar.
+type Reproceso struct {
+	Recuperados []string   // Orígenes que se ordenaron y se quitaron de la cuarentena.
+	Pendientes  []Registro // Registros actualizados de los documentos que siguen fallando.
+}
+
+// Continuar recibe un documento recuperado de la cuarentena, ya ordenado, junto
+// con su origen. Si devuelve un error el documento se mantiene en cuarentena.
+type Continuar func(origen string, ordenado []byte) error
+
+// Reprocesar vuelve a ordenar con ordenador cada documento guardado en dir por
+// Directorio, por ejemplo después de corregir el perfil o una regla de
+// validación. Los que ahora se ordenan se entregan a continuar y, si no
+// devuelve error, se eliminan de la cuarentena junto con su reporte. Los que
+// siguen fallando permanecen en dir con su reporte actualizado e Intentos
+// incrementado. Un error al leer o escribir la cuarentena detiene el proceso;
+// el Reproceso devuelto refleja lo hecho hasta ese momento.
+func Reprocesar(dir string, ordenador *ordenJson.Ordenador, continuar Continuar) (Reproceso, error) {
+	var reportes []string
+	err := filepath.WalkDir(dir, func(ruta string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(ruta, SufijoReporte) {
+			reportes = append(reportes, ruta)
+		}
+		return nil
+	})
+	if err != nil {
+		return Reproceso{}, err
+	}
+
+	var r Reproceso
+	for _, reporte := range reportes {
+		if err := reprocesarDocumento(reporte, ordenador, continuar, &r); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// reprocesarDocumento vuelve a ordenar el documento del reporte indicado y
+// registra el resultado en r.
+func reprocesarDocumento(reporte string, ordenador *ordenJson.Ordenador, continuar Continuar, r *Reproceso) error {
+	contenido, err := os.ReadFile(reporte)
+	if err != nil {
+		return err
+	}
+	var anterior Registro
+	if err := json.Unmarshal(contenido, &anterior); err != nil {
+		return fmt.Errorf("%s: %w", reporte, err)
+	}
+	ruta := strings.TrimSuffix(reporte, SufijoReporte)
+	documento, err := os.ReadFile(ruta)
+	if err != nil {
+		return err
+	}
+
+	ordenado, err := ordenador.OrdenarJSON(string(documento))
+	if err == nil {
+		err = continuar(anterior.Origen, []byte(ordenado))
+	}
+	if err != nil {
+		registro := NuevoRegistro(anterior.Origen, documento, err, ordenador)
+		registro.Intentos = anterior.Intentos + 1
+		r.Pendientes = append(r.Pendientes, registro)
+		return escribirRegistro(reporte, registro)
+	}
+
+	if err := os.Remove(ruta); err != nil {
+		return err
+	}
+	if err := os.Remove(reporte); err != nil {
+		return err
+	}
+	r.Recuperados = append(r.Recuperados, anterior.Origen)
+	return nil
+}
@@ -0,0 +1,105 @@
+package formatos
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SeparadorMultivalor une los valores de las propiedades multivaluadas en
+// AAlfrescoXML. Es el separador por defecto del importador masivo de Alfresco.
+const SeparadorMultivalor = ","
+
+// encabezadoAlfrescoXML es el inicio de un archivo de propiedades.
+const encabezadoAlfrescoXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE properties SYSTEM "http://java.sun.com/dtd/properties.dtd">
+<properties>
+`
+
+// AAlfrescoXML convierte un documento JSON ya ordenado al formato de
+// propiedades XML que lee el importador masivo de Alfresco (los archivos
+// *.metadata.properties.xml): un elemento entry por propiedad, en el mismo
+// orden que las claves del documento. Las claves "type" y "aspects", si están
+// en el documento, se escriben como cualquier otra propiedad.
+//
+// Los números y booleanos se escriben tal como aparecen en el JSON y los
+// arreglos de valores simples se unen con SeparadorMultivalor. Las propiedades
+// null se omiten. El documento debe ser un objeto y no puede contener objetos
+// anidados, que no tienen representación en ese formato.
+func AAlfrescoXML(documento []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(documento))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, fmt.Errorf("el documento debe ser un objeto JSON")
+	}
+	var buf bytes.Buffer
+	buf.WriteString(encabezadoAlfrescoXML)
+	for dec.More() {
+		clave, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var valor interface{}
+		if err := dec.Decode(&valor); err != nil {
+			return nil, err
+		}
+		texto, ok, err := valorPropiedad(valor)
+		if err != nil {
+			return nil, fmt.Errorf("propiedad %q: %w", clave, err)
+		}
+		if !ok {
+			continue
+		}
+		buf.WriteString(`  <entry key="`)
+		xml.EscapeText(&buf, []byte(clave.(string)))
+		buf.WriteString(`">`)
+		xml.EscapeText(&buf, []byte(texto))
+		buf.WriteString("</entry>\n")
+	}
+	if _, err := dec.Token(); err != nil { // '}'
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("contenido inesperado después del documento JSON")
+	}
+	buf.WriteString("</properties>\n")
+	return buf.Bytes(), nil
+}
+
+// valorPropiedad devuelve el texto de una propiedad, o false si se omite.
+func valorPropiedad(valor interface{}) (string, bool, error) {
+	switch v := valor.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case json.Number:
+		return v.String(), true, nil
+	case bool:
+		if v {
+			return "true", true, nil
+		}
+		return "false", true, nil
+	case []interface{}:
+		valores := make([]string, 0, len(v))
+		for _, elemento := range v {
+			if _, anidado := elemento.([]interface{}); anidado {
+				return "", false, fmt.Errorf("los arreglos anidados no tienen representación en XML de Alfresco")
+			}
+			texto, ok, err := valorPropiedad(elemento)
+			if err != nil {
+				return "", false, err
+			}
+			if ok {
+				valores = append(valores, texto)
+			}
+		}
+		return strings.Join(valores, SeparadorMultivalor), true, nil
+	}
+	return "", false, fmt.Errorf("los objetos anidados no tienen representación en XML de Alfresco")
+}
@@ -0,0 +1,78 @@
+package formatos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// objeto es un objeto JSON decodificado que conserva el orden de sus claves,
+// para los formatos binarios que necesitan conocer la cantidad de elementos
+// antes de escribirlos.
+type objeto []miembro
+
+// miembro es un par clave-valor de un objeto.
+type miembro struct {
+	clave string
+	valor interface{}
+}
+
+// leerArbol decodifica un documento JSON completo. Los objetos se devuelven
+// como objeto, los arreglos como []interface{}, los números enteros que caben
+// en int64 como int64 y el resto de los números como float64.
+func leerArbol(documento []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(documento))
+	dec.UseNumber()
+	valor, err := leerNodo(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("contenido inesperado después del documento JSON")
+	}
+	return valor, nil
+}
+
+// leerNodo lee el siguiente valor de dec.
+func leerNodo(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		if v == '{' {
+			o := objeto{}
+			for dec.More() {
+				clave, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				valor, err := leerNodo(dec)
+				if err != nil {
+					return nil, err
+				}
+				o = append(o, miembro{clave: clave.(string), valor: valor})
+			}
+			_, err := dec.Token() // '}'
+			return o, err
+		}
+		a := []interface{}{}
+		for dec.More() {
+			valor, err := leerNodo(dec)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, valor)
+		}
+		_, err := dec.Token() // ']'
+		return a, err
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	}
+	return t, nil // string, bool o nil
+}
@@ -0,0 +1,110 @@
+package formatos
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// modoCBOR codifica cada valor con la forma preferida de RFC 8949: enteros y
+// longitudes con la menor cantidad de bytes y cada float con la precisión más
+// corta que lo representa sin pérdida.
+var modoCBOR = func() cbor.EncMode {
+	modo, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return modo
+}()
+
+// OrdenarCBOR ordena input como ordenJson.OrdenarJSON, con las mismas
+// opciones, y lo devuelve codificado en CBOR con las claves en el orden
+// canónico del perfil. Ver ACBOR.
+func OrdenarCBOR(input interface{}, opts ...ordenJson.Option) ([]byte, error) {
+	ordenado, err := ordenJson.OrdenarJSON(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ACBOR([]byte(ordenado))
+}
+
+// ACBOR codifica un documento JSON en CBOR (RFC 8949) conservando el orden
+// de las claves de todos los objetos. Los números usan la forma preferida:
+// los enteros, la codificación más corta, y el resto, el float más corto que
+// los representa sin pérdida.
+func ACBOR(documento []byte) ([]byte, error) {
+	arbol, err := leerArbol(documento)
+	if err != nil {
+		return nil, err
+	}
+	return modoCBOR.Marshal(arbol)
+}
+
+// ACBORDeterministico codifica un documento JSON con la codificación
+// determinista de RFC 8949 (sección 4.2.1), que exigen algunos clientes para
+// comparar o firmar documentos byte a byte. Esa codificación ordena las claves
+// de cada mapa por sus bytes codificados, por lo que el resultado no conserva
+// el orden del perfil.
+func ACBORDeterministico(documento []byte) ([]byte, error) {
+	arbol, err := leerArbol(documento)
+	if err != nil {
+		return nil, err
+	}
+	return modoCBOR.Marshal(sinOrden(arbol))
+}
+
+// sinOrden reemplaza los objeto de valor, también los anidados, por mapas,
+// que modoCBOR escribe con las claves en orden determinista.
+func sinOrden(valor interface{}) interface{} {
+	switch v := valor.(type) {
+	case objeto:
+		mapa := make(map[string]interface{}, len(v))
+		for _, m := range v {
+			mapa[m.clave] = sinOrden(m.valor)
+		}
+		return mapa
+	case []interface{}:
+		for i, elemento := range v {
+			v[i] = sinOrden(elemento)
+		}
+	}
+	return valor
+}
+
+// MarshalCBOR implementa cbor.Marshaler: escribe el objeto como un mapa de
+// longitud definida con las claves en su orden.
+func (o objeto) MarshalCBOR() ([]byte, error) {
+	buf := cabeceraMapaCBOR(len(o))
+	for _, m := range o {
+		clave, err := modoCBOR.Marshal(m.clave)
+		if err != nil {
+			return nil, err
+		}
+		valor, err := modoCBOR.Marshal(m.valor)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(buf, clave...), valor...)
+	}
+	return buf, nil
+}
+
+// cabeceraMapaCBOR devuelve la cabecera de un mapa (tipo mayor 5) de n pares,
+// con el argumento en la forma más corta.
+func cabeceraMapaCBOR(n int) []byte {
+	const mapa = 5 << 5
+	switch {
+	case n < 24:
+		return []byte{mapa | byte(n)}
+	case n <= math.MaxUint8:
+		return []byte{mapa | 24, byte(n)}
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16([]byte{mapa | 25}, uint16(n))
+	case uint64(n) <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32([]byte{mapa | 26}, uint32(n))
+	}
+	return binary.BigEndian.AppendUint64([]byte{mapa | 27}, uint64(n))
+}
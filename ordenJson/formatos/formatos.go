@@ -0,0 +1,209 @@
+// Package formatos convierte los documentos que produce ordenJson a otros
+// formatos de salida sin alterar el orden de las claves.
+package formatos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formato identifica un formato de salida.
+type Formato string
+
+const (
+	JSON     Formato = "json"    // JSON indentado con dos espacios, como lo produce ordenJson.
+	Compacto Formato = "compact" // JSON en una sola línea.
+	YAML     Formato = "yaml"    // YAML con las claves en el mismo orden.
+
+	AlfrescoXML Formato = "alfresco-xml" // Propiedades XML del importador masivo de Alfresco; ver AAlfrescoXML.
+	MsgPack     Formato = "msgpack"      // MessagePack binario con las claves en el mismo orden; ver AMsgPack.
+
+	CBOR               Formato = "cbor"               // CBOR con las claves en el mismo orden; ver ACBOR.
+	CBORDeterministico Formato = "cbor-deterministic" // CBOR con la codificación determinista de RFC 8949; ver ACBORDeterministico.
+
+	TOML Formato = "toml" // TOML con las claves en el mismo orden; ver ATOML.
+
+	Fluentd  Formato = "fluentd-forward" // Evento del protocolo forward de Fluentd, con EtiquetaFluentd y el instante actual; ver AFluentd.
+	Logstash Formato = "logstash"        // Evento JSON de Logstash en una línea, con el instante actual; ver ALogstash.
+)
+
+// Formatos lista los formatos admitidos.
+var Formatos = []Formato{JSON, Compacto, YAML, AlfrescoXML, MsgPack, CBOR, CBORDeterministico, TOML, Fluentd, Logstash}
+
+// Parsear interpreta el nombre de un formato.
+func Parsear(nombre string) (Formato, error) {
+	for _, f := range Formatos {
+		if string(f) == nombre {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("formato desconocido %q: se esperaba uno de %v", nombre, Formatos)
+}
+
+// Extension devuelve la extensión de los archivos en el formato, con el punto.
+func (f Formato) Extension() string {
+	switch f {
+	case YAML:
+		return ".yaml"
+	case AlfrescoXML:
+		return ".xml"
+	case MsgPack, Fluentd:
+		return ".msgpack"
+	case CBOR, CBORDeterministico:
+		return ".cbor"
+	case TOML:
+		return ".toml"
+	}
+	return ".json"
+}
+
+// Binario indica si el formato no es texto. A los documentos binarios no se
+// les agrega un salto de línea final.
+func (f Formato) Binario() bool {
+	return f == MsgPack || f == CBOR || f == CBORDeterministico || f == Fluentd
+}
+
+// TipoContenido devuelve el Content-Type de los documentos en el formato.
+func (f Formato) TipoContenido() string {
+	switch f {
+	case YAML:
+		return "application/yaml; charset=utf-8"
+	case AlfrescoXML:
+		return "application/xml; charset=utf-8"
+	case MsgPack, Fluentd:
+		return "application/vnd.msgpack"
+	case CBOR, CBORDeterministico:
+		return "application/cbor"
+	case TOML:
+		return "application/toml; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// Convertir lleva un documento JSON al formato indicado. Con JSON devuelve el
+// documento sin cambios.
+func Convertir(documento []byte, formato Formato) ([]byte, error) {
+	switch formato {
+	case JSON:
+		return documento, nil
+	case Compacto:
+		return Compactar(documento)
+	case YAML:
+		return AYAML(documento)
+	case AlfrescoXML:
+		return AAlfrescoXML(documento)
+	case MsgPack:
+		return AMsgPack(documento)
+	case CBOR:
+		return ACBOR(documento)
+	case CBORDeterministico:
+		return ACBORDeterministico(documento)
+	case TOML:
+		return ATOML(documento)
+	case Fluentd:
+		return AFluentd(documento, EtiquetaFluentd, time.Now())
+	case Logstash:
+		return ALogstash(documento, time.Now())
+	}
+	return nil, fmt.Errorf("formato desconocido %q", formato)
+}
+
+// Compactar elimina los espacios no significativos del documento JSON.
+func Compactar(documento []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, documento); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AYAML convierte un documento JSON a YAML conservando el orden de las claves
+// de todos los objetos, también los anidados. Los números se escriben tal
+// como aparecen en el JSON y las cadenas que YAML interpretaría como otro
+// tipo (por ejemplo "true" o "10") se escriben entre comillas.
+func AYAML(documento []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(documento))
+	dec.UseNumber()
+	nodo, err := nodoYAML(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("contenido inesperado después del documento JSON")
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(nodo); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nodoYAML lee el siguiente valor de dec y lo convierte en un nodo YAML.
+func nodoYAML(dec *json.Decoder) (*yaml.Node, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			nodo := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				clave, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				valor, err := nodoYAML(dec)
+				if err != nil {
+					return nil, err
+				}
+				nodo.Content = append(nodo.Content, escalar("!!str", clave.(string)), valor)
+			}
+			_, err := dec.Token() // '}'
+			return nodo, err
+		case '[':
+			nodo := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				valor, err := nodoYAML(dec)
+				if err != nil {
+					return nil, err
+				}
+				nodo.Content = append(nodo.Content, valor)
+			}
+			_, err := dec.Token() // ']'
+			return nodo, err
+		}
+		return nil, fmt.Errorf("delimitador inesperado %v", v)
+	case string:
+		return escalar("!!str", v), nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return escalar("!!int", v.String()), nil
+		}
+		return escalar("!!float", v.String()), nil
+	case bool:
+		if v {
+			return escalar("!!bool", "true"), nil
+		}
+		return escalar("!!bool", "false"), nil
+	case nil:
+		return escalar("!!null", "null"), nil
+	}
+	return nil, fmt.Errorf("token inesperado %v", t)
+}
+
+// escalar construye un nodo escalar con la etiqueta indicada.
+func escalar(etiqueta, valor string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: etiqueta, Value: valor}
+}
@@ -0,0 +1,45 @@
+package formatos
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// OrdenarMsgPack ordena input como ordenJson.OrdenarJSON, con las mismas
+// opciones, y lo devuelve codificado en MessagePack. Ver AMsgPack.
+func OrdenarMsgPack(input interface{}, opts ...ordenJson.Option) ([]byte, error) {
+	ordenado, err := ordenJson.OrdenarJSON(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return AMsgPack([]byte(ordenado))
+}
+
+// AMsgPack codifica un documento JSON en MessagePack conservando el orden de
+// las claves de todos los objetos. Cada número usa la codificación entera
+// más corta en que cabe o, si no es entero, float64.
+func AMsgPack(documento []byte) ([]byte, error) {
+	arbol, err := leerArbol(documento)
+	if err != nil {
+		return nil, err
+	}
+	return msgpack.Marshal(arbol)
+}
+
+// EncodeMsgpack implementa msgpack.CustomEncoder: escribe el objeto como un
+// mapa con las claves en su orden.
+func (o objeto) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if err := enc.EncodeMapLen(len(o)); err != nil {
+		return err
+	}
+	for _, m := range o {
+		if err := enc.EncodeString(m.clave); err != nil {
+			return err
+		}
+		if err := enc.Encode(m.valor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
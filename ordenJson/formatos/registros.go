@@ -0,0 +1,170 @@
+package formatos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// EtiquetaFluentd es la etiqueta (tag) con la que Convertir escribe los
+// eventos en el formato Fluentd. Para elegirla por documento, ver AFluentd.
+var EtiquetaFluentd = "ordenjson"
+
+// extEventTime es el tipo de extensión de MessagePack con el que el
+// protocolo forward de Fluentd representa un instante con nanosegundos.
+const extEventTime = 0
+
+// Campos que Logstash agrega a cada evento.
+const (
+	campoTimestampLogstash = "@timestamp"
+	campoVersionLogstash   = "@version"
+)
+
+// AFluentd convierte un documento JSON ya ordenado en un evento del protocolo
+// forward de Fluentd en modo Message: un arreglo MessagePack [etiqueta,
+// instante, registro], con el instante como EventTime y el registro con las
+// claves en el mismo orden que el documento. Los eventos se pueden
+// concatenar y enviar tal cual a un in_forward. El documento debe ser un
+// objeto.
+func AFluentd(documento []byte, etiqueta string, instante time.Time) ([]byte, error) {
+	arbol, err := leerArbol(documento)
+	if err != nil {
+		return nil, err
+	}
+	registro, ok := arbol.(objeto)
+	if !ok {
+		return nil, fmt.Errorf("el documento debe ser un objeto JSON")
+	}
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	if err := enc.EncodeArrayLen(3); err != nil {
+		return nil, err
+	}
+	if err := enc.EncodeString(etiqueta); err != nil {
+		return nil, err
+	}
+	if err := enc.EncodeExtHeader(extEventTime, 8); err != nil {
+		return nil, err
+	}
+	var tiempo [8]byte
+	binary.BigEndian.PutUint32(tiempo[:4], uint32(instante.Unix()))
+	binary.BigEndian.PutUint32(tiempo[4:], uint32(instante.Nanosecond()))
+	buf.Write(tiempo[:])
+	if err := enc.Encode(registro); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ALogstash convierte un documento JSON ya ordenado en un evento de Logstash
+// en una línea, como lo lee el códec json_lines: los campos "@timestamp",
+// con instante en UTC y milisegundos, y "@version" primero, y después las
+// claves del documento en su orden. Si el documento ya tiene alguno de esos
+// campos, se conserva el suyo en su lugar. El documento debe ser un objeto.
+func ALogstash(documento []byte, instante time.Time) ([]byte, error) {
+	arbol, err := leerArbol(documento)
+	if err != nil {
+		return nil, err
+	}
+	registro, ok := arbol.(objeto)
+	if !ok {
+		return nil, fmt.Errorf("el documento debe ser un objeto JSON")
+	}
+	compacto, err := Compactar(documento)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if !registro.tiene(campoTimestampLogstash) {
+		fmt.Fprintf(&buf, `"%s":"%s",`, campoTimestampLogstash, instante.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	}
+	if !registro.tiene(campoVersionLogstash) {
+		fmt.Fprintf(&buf, `"%s":"1",`, campoVersionLogstash)
+	}
+	if len(registro) == 0 {
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.Write(compacto[1:])
+	return buf.Bytes(), nil
+}
+
+// tiene indica si el objeto tiene la clave.
+func (o objeto) tiene(clave string) bool {
+	for _, m := range o {
+		if m.clave == clave {
+			return true
+		}
+	}
+	return false
+}
+
+// EscritorEventos convierte a un formato de salida los documentos NDJSON que
+// recibe y los escribe en otro io.Writer, uno por evento, para ubicar el
+// ordenamiento en medio de un pipeline de logs:
+//
+//	ordenador.OrdenarFlujo(os.Stdin, formatos.NuevoEscritorEventos(os.Stdout, formatos.Logstash))
+//
+// Cada línea no vacía debe ser un documento JSON completo, como la salida de
+// ordenJson.Ordenador.OrdenarFlujo con una entrada NDJSON. Los formatos de
+// texto se escriben con un salto de línea final.
+type EscritorEventos struct {
+	w         io.Writer
+	formato   Formato
+	pendiente []byte
+}
+
+// NuevoEscritorEventos crea un EscritorEventos que escribe en w en el formato
+// indicado.
+func NuevoEscritorEventos(w io.Writer, formato Formato) *EscritorEventos {
+	return &EscritorEventos{w: w, formato: formato}
+}
+
+// Write convierte y escribe cada línea completa de p; el resto se guarda
+// hasta la próxima escritura o hasta Close.
+func (e *EscritorEventos) Write(p []byte) (int, error) {
+	e.pendiente = append(e.pendiente, p...)
+	for {
+		i := bytes.IndexByte(e.pendiente, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := e.escribir(e.pendiente[:i]); err != nil {
+			return len(p), err
+		}
+		e.pendiente = e.pendiente[i+1:]
+	}
+}
+
+// Close convierte y escribe la última línea, si no terminaba en un salto de
+// línea. No cierra el io.Writer subyacente.
+func (e *EscritorEventos) Close() error {
+	linea := e.pendiente
+	e.pendiente = nil
+	return e.escribir(linea)
+}
+
+// escribir convierte y escribe un documento.
+func (e *EscritorEventos) escribir(linea []byte) error {
+	linea = bytes.TrimSpace(linea)
+	if len(linea) == 0 {
+		return nil
+	}
+	if !json.Valid(linea) {
+		return fmt.Errorf("la línea no es un documento JSON: %.40q", linea)
+	}
+	evento, err := Convertir(linea, e.formato)
+	if err != nil {
+		return err
+	}
+	if !e.formato.Binario() && !bytes.HasSuffix(evento, []byte("\n")) {
+		evento = append(evento, '\n')
+	}
+	_, err = e.w.Write(evento)
+	return err
+}
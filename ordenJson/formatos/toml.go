@@ -0,0 +1,145 @@
+package formatos
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ATOML convierte un documento JSON ya ordenado a TOML conservando el orden
+// de las claves. Para no alterar ese orden, los objetos anidados se escriben
+// como tablas en línea (clave = { ... }) en lugar de secciones [tabla], que
+// TOML obliga a ubicar después de los valores simples. Las claves que no son
+// claves simples de TOML, como "tanner:tipo-documento", se escriben entre
+// comillas. TOML no tiene null: esas claves se omiten, y un null dentro de un
+// arreglo es un error. El documento debe ser un objeto.
+func ATOML(documento []byte) ([]byte, error) {
+	arbol, err := leerArbol(documento)
+	if err != nil {
+		return nil, err
+	}
+	raiz, ok := arbol.(objeto)
+	if !ok {
+		return nil, fmt.Errorf("el documento debe ser un objeto JSON")
+	}
+	var buf bytes.Buffer
+	for _, m := range raiz {
+		if m.valor == nil {
+			continue
+		}
+		escribirClaveTOML(&buf, m.clave)
+		buf.WriteString(" = ")
+		if err := escribirValorTOML(&buf, m.valor); err != nil {
+			return nil, fmt.Errorf("clave %q: %w", m.clave, err)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// escribirValorTOML escribe un valor de leerArbol en la sintaxis de TOML.
+func escribirValorTOML(buf *bytes.Buffer, valor interface{}) error {
+	switch v := valor.(type) {
+	case string:
+		escribirCadenaTOML(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		texto := strconv.FormatFloat(v, 'g', -1, 64)
+		buf.WriteString(texto)
+		if !strings.ContainsAny(texto, ".e") {
+			buf.WriteString(".0") // Sin punto ni exponente TOML lo leería como entero.
+		}
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elemento := range v {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if elemento == nil {
+				return fmt.Errorf("TOML no admite null en arreglos")
+			}
+			if err := escribirValorTOML(buf, elemento); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case objeto:
+		buf.WriteByte('{')
+		primero := true
+		for _, m := range v {
+			if m.valor == nil {
+				continue
+			}
+			if primero {
+				buf.WriteByte(' ')
+			} else {
+				buf.WriteString(", ")
+			}
+			primero = false
+			escribirClaveTOML(buf, m.clave)
+			buf.WriteString(" = ")
+			if err := escribirValorTOML(buf, m.valor); err != nil {
+				return fmt.Errorf("clave %q: %w", m.clave, err)
+			}
+		}
+		if !primero {
+			buf.WriteByte(' ')
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("valor inesperado %v", valor)
+	}
+	return nil
+}
+
+// escribirClaveTOML escribe una clave simple tal cual o, si tiene caracteres
+// que las claves simples no admiten, entre comillas.
+func escribirClaveTOML(buf *bytes.Buffer, clave string) {
+	simple := clave != ""
+	for _, r := range clave {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			simple = false
+			break
+		}
+	}
+	if simple {
+		buf.WriteString(clave)
+		return
+	}
+	escribirCadenaTOML(buf, clave)
+}
+
+// escribirCadenaTOML escribe s como cadena básica de TOML, con los caracteres
+// de control escapados.
+func escribirCadenaTOML(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
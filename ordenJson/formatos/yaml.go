@@ -0,0 +1,155 @@
+package formatos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Entradas lista los formatos que se pueden leer. Los documentos YAML se
+// convierten a JSON con DesdeYAML antes de ordenarlos.
+var Entradas = []Formato{JSON, YAML}
+
+// ParsearEntrada interpreta el nombre de un formato de entrada.
+func ParsearEntrada(nombre string) (Formato, error) {
+	for _, f := range Entradas {
+		if string(f) == nombre {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("formato de entrada desconocido %q: se esperaba uno de %v", nombre, Entradas)
+}
+
+// OrdenarYAML ordena un documento de metadatos en YAML con las opciones
+// recibidas, igual que ordenJson.OrdenarJSON, y lo devuelve en YAML. Para
+// obtener el resultado en JSON se usa DesdeYAML y luego ordenJson.OrdenarJSON.
+func OrdenarYAML(input string, opts ...ordenJson.Option) (string, error) {
+	documento, err := DesdeYAML([]byte(input))
+	if err != nil {
+		return "", err
+	}
+	ordenado, err := ordenJson.Nuevo(opts...).OrdenarJSON(string(documento))
+	if err != nil {
+		return "", err
+	}
+	resultado, err := AYAML([]byte(ordenado))
+	if err != nil {
+		return "", err
+	}
+	return string(resultado), nil
+}
+
+// DesdeYAML convierte un documento YAML a JSON compacto conservando el orden
+// de las claves. Los escalares sin comillas se interpretan según YAML (10 es
+// un número, true un booleano), salvo las fechas, que se conservan como
+// cadenas tal como están escritas para que las normalice ordenJson. Los
+// alias se expanden. El documento debe ser uno solo y no puede contener
+// valores sin equivalente en JSON, como .inf, ni claves que no sean escalares.
+func DesdeYAML(documento []byte) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(documento))
+	var raiz yaml.Node
+	if err := dec.Decode(&raiz); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("YAML vacío")
+		}
+		return nil, err
+	}
+	var otro yaml.Node
+	if err := dec.Decode(&otro); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("se esperaba un solo documento YAML")
+	}
+	var buf bytes.Buffer
+	if err := escribirJSON(&buf, &raiz); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escribirJSON escribe en buf el valor JSON equivalente al nodo.
+func escribirJSON(buf *bytes.Buffer, nodo *yaml.Node) error {
+	switch nodo.Kind {
+	case yaml.DocumentNode:
+		if len(nodo.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return escribirJSON(buf, nodo.Content[0])
+	case yaml.AliasNode:
+		return escribirJSON(buf, nodo.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(nodo.Content); i += 2 {
+			clave, valor := nodo.Content[i], nodo.Content[i+1]
+			if clave.Kind != yaml.ScalarNode || clave.Tag == "!!merge" {
+				return fmt.Errorf("línea %d: solo se admiten claves escalares", clave.Line)
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := escribirValor(buf, clave.Value); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := escribirJSON(buf, valor); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, elemento := range nodo.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := escribirJSON(buf, elemento); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.ScalarNode:
+		return escribirEscalar(buf, nodo)
+	}
+	return fmt.Errorf("línea %d: nodo YAML inesperado", nodo.Line)
+}
+
+// escribirEscalar escribe un escalar YAML según su etiqueta. Las cadenas, las
+// fechas y las etiquetas desconocidas se escriben como cadenas.
+func escribirEscalar(buf *bytes.Buffer, nodo *yaml.Node) error {
+	switch nodo.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool", "!!int", "!!float":
+		var valor interface{}
+		if err := nodo.Decode(&valor); err != nil {
+			return err
+		}
+		if f, ok := valor.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return fmt.Errorf("línea %d: %s no tiene equivalente en JSON", nodo.Line, nodo.Value)
+		}
+		return escribirValor(buf, valor)
+	}
+	return escribirValor(buf, nodo.Value)
+}
+
+// escribirValor escribe un valor simple codificado como JSON.
+func escribirValor(buf *bytes.Buffer, valor interface{}) error {
+	codificado, err := json.Marshal(valor)
+	if err != nil {
+		return err
+	}
+	buf.Write(codificado)
+	return nil
+}
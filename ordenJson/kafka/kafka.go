@@ -0,0 +1,176 @@
+// Package kafka implementa un puente que consume documentos JSON de un tópico
+// de Kafka, los ordena y los publica en un tópico de salida. Los documentos
+// que no se pueden ordenar (JSON inválido o que no cumplen las validaciones
+// del Ordenador) se publican en un tópico de mensajes muertos, con el error
+// en sus encabezados, para no detener el flujo.
+//
+//	puente, err := kafka.Nuevo(kafka.Config{
+//		Entrada:         kafkago.NewReader(kafkago.ReaderConfig{Brokers: brokers, GroupID: "ordenjson", Topic: "documentos"}),
+//		Salida:          &kafkago.Writer{Addr: kafkago.TCP(brokers...), Topic: "documentos-ordenados"},
+//		MensajesMuertos: &kafkago.Writer{Addr: kafkago.TCP(brokers...), Topic: "documentos-rechazados"},
+//	})
+//	err = puente.Ejecutar(ctx)
+//
+// Cada mensaje se confirma (commit) en el tópico de entrada solo después de
+// publicarlo en la salida o en los mensajes muertos, por lo que ante una
+// caída se reprocesa en lugar de perderse: la entrega es al menos una vez.
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Encabezados que se agregan a los mensajes muertos.
+const (
+	EncabezadoError  = "ordenjson-error"  // Mensaje del error.
+	EncabezadoTipo   = "ordenjson-tipo"   // Clasificación del error; ver cuarentena.Clasificar.
+	EncabezadoOrigen = "ordenjson-origen" // Tópico, partición y offset del mensaje original.
+)
+
+// Lector es la parte de *kafka.Reader que usa el puente. El lector debe
+// pertenecer a un grupo de consumidores para poder confirmar los mensajes.
+type Lector interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Escritor es la parte de *kafka.Writer que usa el puente.
+type Escritor interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Config describe los tópicos y el ordenamiento de un Puente.
+type Config struct {
+	// Entrada entrega los documentos a ordenar.
+	Entrada Lector
+	// Salida recibe los documentos ordenados. Los mensajes conservan la clave
+	// y los encabezados del original; el tópico lo define el Escritor.
+	Salida Escritor
+	// MensajesMuertos recibe los documentos que no se pudieron ordenar, sin
+	// cambios y con los encabezados EncabezadoError, EncabezadoTipo y
+	// EncabezadoOrigen. Si es nil, el primer documento inválido detiene el
+	// puente sin confirmarlo.
+	MensajesMuertos Escritor
+	// Ordenador ordena y, según sus opciones, valida los documentos; nil usa
+	// ordenJson.Nuevo().
+	Ordenador *ordenJson.Ordenador
+	// Indentado publica los documentos con la indentación de OrdenarJSON; por
+	// defecto se publican compactos, en una línea.
+	Indentado bool
+}
+
+// Resumen cuenta los mensajes que procesó un Puente.
+type Resumen struct {
+	Publicados int64 // Ordenados y publicados en Config.Salida.
+	Rechazados int64 // Publicados en Config.MensajesMuertos.
+}
+
+// Puente consume, ordena y republica mensajes. Ver Nuevo.
+type Puente struct {
+	cfg        Config
+	publicados atomic.Int64
+	rechazados atomic.Int64
+}
+
+// Nuevo crea un Puente con la configuración recibida. Devuelve error si falta
+// Entrada o Salida.
+func Nuevo(cfg Config) (*Puente, error) {
+	if cfg.Entrada == nil || cfg.Salida == nil {
+		return nil, errors.New("kafka: Config.Entrada y Config.Salida son obligatorios")
+	}
+	if cfg.Ordenador == nil {
+		cfg.Ordenador = ordenJson.Nuevo()
+	}
+	return &Puente{cfg: cfg}, nil
+}
+
+// Ejecutar procesa mensajes hasta que ctx termina, en cuyo caso devuelve nil,
+// o hasta el primer error al leer, publicar o confirmar, que devuelve. El
+// mensaje en curso al fallar no se confirma, por lo que se vuelve a recibir.
+func (p *Puente) Ejecutar(ctx context.Context) error {
+	for {
+		msg, err := p.cfg.Entrada.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("kafka: leyendo la entrada: %w", err)
+		}
+		if err := p.Procesar(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Procesar ordena un mensaje, lo publica en la salida o en los mensajes
+// muertos y lo confirma en la entrada.
+func (p *Puente) Procesar(ctx context.Context, msg kafkago.Message) error {
+	ordenado, err := p.cfg.Ordenador.OrdenarJSONCtx(ctx, string(msg.Value))
+	var errCancelado *ordenJson.ErrorCancelado
+	switch {
+	case errors.As(err, &errCancelado):
+		return err
+	case err != nil:
+		if p.cfg.MensajesMuertos == nil {
+			return fmt.Errorf("kafka: %s: %w", origen(msg), err)
+		}
+		if err := p.cfg.MensajesMuertos.WriteMessages(ctx, mensajeMuerto(msg, err)); err != nil {
+			return fmt.Errorf("kafka: publicando en los mensajes muertos: %w", err)
+		}
+		p.rechazados.Add(1)
+	default:
+		valor := []byte(ordenado)
+		if !p.cfg.Indentado {
+			var compacto bytes.Buffer
+			if err := json.Compact(&compacto, valor); err != nil {
+				return err
+			}
+			valor = compacto.Bytes()
+		}
+		salida := kafkago.Message{Key: msg.Key, Value: valor, Headers: msg.Headers}
+		if err := p.cfg.Salida.WriteMessages(ctx, salida); err != nil {
+			return fmt.Errorf("kafka: publicando en la salida: %w", err)
+		}
+		p.publicados.Add(1)
+	}
+	if err := p.cfg.Entrada.CommitMessages(ctx, msg); err != nil {
+		return fmt.Errorf("kafka: confirmando %s: %w", origen(msg), err)
+	}
+	return nil
+}
+
+// Resumen devuelve los mensajes procesados hasta ahora.
+func (p *Puente) Resumen() Resumen {
+	return Resumen{Publicados: p.publicados.Load(), Rechazados: p.rechazados.Load()}
+}
+
+// mensajeMuerto devuelve msg sin cambios en su contenido, con los
+// encabezados que describen err.
+func mensajeMuerto(msg kafkago.Message, err error) kafkago.Message {
+	encabezados := append([]kafkago.Header(nil), msg.Headers...)
+	encabezados = append(encabezados,
+		kafkago.Header{Key: EncabezadoError, Value: []byte(err.Error())},
+		kafkago.Header{Key: EncabezadoTipo, Value: []byte(cuarentena.Clasificar(err))},
+		kafkago.Header{Key: EncabezadoOrigen, Value: []byte(origen(msg))},
+	)
+	return kafkago.Message{Key: msg.Key, Value: msg.Value, Headers: encabezados}
+}
+
+// origen identifica msg como tópico/partición@offset.
+func origen(msg kafkago.Message) string {
+	return msg.Topic + "/" + strconv.Itoa(msg.Partition) + "@" + strconv.FormatInt(msg.Offset, 10)
+}
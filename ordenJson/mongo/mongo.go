@@ -0,0 +1,204 @@
+// Package mongo guarda documentos de ordenJson en MongoDB siempre en el orden
+// canónico. Los documentos se escriben como bson.D, que conserva el orden de
+// los campos, en lugar de pasar por un mapa o un struct:
+//
+//	_, err := mongo.InsertOrdenado(ctx, coleccion, metadata)
+//
+// Los documentos guardados antes sin ese cuidado se pueden reordenar en el
+// lugar con Migrar.
+package mongo
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	driver "go.mongodb.org/mongo-driver/v2/mongo"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenbson"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Documento ordena metadata con las opciones recibidas y lo devuelve como el
+// bson.D que escriben InsertOrdenado y ReplaceOrdenado. Los nombres de los
+// campos son los de NombresBSON; ver ordenbson.ADocumento.
+func Documento(metadata ordenJson.DocumentMetadata, opts ...ordenJson.Option) (bson.D, error) {
+	ordenado, err := ordenJson.Nuevo(opts...).OrdenarDocumentoMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return ordenbson.ADocumento([]byte(ordenado))
+}
+
+// InsertOrdenado inserta metadata en coll con los campos en el orden
+// canónico.
+func InsertOrdenado(ctx context.Context, coll *driver.Collection, metadata ordenJson.DocumentMetadata, opts ...ordenJson.Option) (*driver.InsertOneResult, error) {
+	documento, err := Documento(metadata, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return coll.InsertOne(ctx, documento)
+}
+
+// ReplaceOrdenado reemplaza el documento de coll que cumple filtro por
+// metadata, con los campos en el orden canónico.
+func ReplaceOrdenado(ctx context.Context, coll *driver.Collection, filtro interface{}, metadata ordenJson.DocumentMetadata, opts ...ordenJson.Option) (*driver.UpdateResult, error) {
+	documento, err := Documento(metadata, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return coll.ReplaceOne(ctx, filtro, documento)
+}
+
+// Migracion resume una ejecución de Migrar.
+type Migracion struct {
+	Revisados   int // Documentos que cumplían el filtro.
+	Reordenados int // Documentos que no estaban en el orden canónico y se reemplazaron.
+}
+
+// Migrar recorre los documentos de coll que cumplen filtro y reemplaza, por
+// su _id, los que no tienen los campos en el orden canónico del perfil de las
+// opciones. Los valores no cambian, solo su orden; ver Reordenar. Si otro
+// proceso modifica un documento entre su lectura y su reemplazo, el cambio se
+// pierde, por lo que conviene migrar sin escrituras concurrentes. Ante un
+// error se detiene y devuelve lo migrado hasta entonces.
+func Migrar(ctx context.Context, coll *driver.Collection, filtro interface{}, opts ...ordenJson.Option) (Migracion, error) {
+	var m Migracion
+	perfil := ordenJson.Nuevo(opts...).Perfil()
+	if filtro == nil {
+		filtro = bson.D{}
+	}
+	cursor, err := coll.Find(ctx, filtro)
+	if err != nil {
+		return m, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var original bson.D
+		if err := cursor.Decode(&original); err != nil {
+			return m, err
+		}
+		m.Revisados++
+		reordenado := Reordenar(original, perfil)
+		if mismoOrden(original, reordenado) {
+			continue
+		}
+		id, ok := valorCampo(original, "_id")
+		if !ok {
+			continue
+		}
+		if _, err := coll.ReplaceOne(ctx, bson.D{{Key: "_id", Value: id}}, reordenado); err != nil {
+			return m, err
+		}
+		m.Reordenados++
+	}
+	return m, cursor.Err()
+}
+
+// Reordenar devuelve una copia de d con los campos en el orden canónico de
+// perfil, el mismo que produce InsertOrdenado: _id primero, luego los campos
+// del perfil, que se buscan por su nombre en NombresBSON o por su clave JSON,
+// y al final el resto en orden alfabético. Los documentos anidados se ordenan
+// alfabéticamente, también dentro de los arreglos. Los valores no se
+// convierten, por lo que se conservan los tipos de BSON como ObjectID o las
+// fechas.
+func Reordenar(d bson.D, perfil *ordenJson.Perfil) bson.D {
+	posiciones := make(map[string]int, len(perfil.Campos()))
+	for i, campo := range perfil.Campos() {
+		posiciones[campo] = i
+		if nombre, ok := ordenbson.NombresBSON[campo]; ok {
+			posiciones[nombre] = i
+		}
+	}
+	posicion := func(clave string) int {
+		if clave == "_id" {
+			return -1
+		}
+		if p, ok := posiciones[clave]; ok {
+			return p
+		}
+		return len(posiciones)
+	}
+	copia := reordenarAnidado(d)
+	sort.SliceStable(copia, func(i, j int) bool { return posicion(copia[i].Key) < posicion(copia[j].Key) })
+	return copia
+}
+
+// reordenarAnidado devuelve una copia de d con sus campos en orden alfabético
+// y los valores anidados ordenados recursivamente.
+func reordenarAnidado(d bson.D) bson.D {
+	copia := make(bson.D, len(d))
+	for i, e := range d {
+		copia[i] = bson.E{Key: e.Key, Value: reordenarValor(e.Value)}
+	}
+	slices.SortStableFunc(copia, func(a, b bson.E) int {
+		switch {
+		case a.Key < b.Key:
+			return -1
+		case a.Key > b.Key:
+			return 1
+		}
+		return 0
+	})
+	return copia
+}
+
+// reordenarValor ordena los documentos anidados en v.
+func reordenarValor(v interface{}) interface{} {
+	switch v := v.(type) {
+	case bson.D:
+		return reordenarAnidado(v)
+	case bson.A:
+		copia := make(bson.A, len(v))
+		for i, elemento := range v {
+			copia[i] = reordenarValor(elemento)
+		}
+		return copia
+	}
+	return v
+}
+
+// mismoOrden indica si a y b tienen las mismas claves en el mismo orden, en
+// todos los niveles. b debe ser el resultado de Reordenar(a).
+func mismoOrden(a, b bson.D) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || !mismoOrdenValor(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// mismoOrdenValor es mismoOrden para valores anidados.
+func mismoOrdenValor(a, b interface{}) bool {
+	switch a := a.(type) {
+	case bson.D:
+		bd, ok := b.(bson.D)
+		return ok && mismoOrden(a, bd)
+	case bson.A:
+		ba, ok := b.(bson.A)
+		if !ok || len(a) != len(ba) {
+			return false
+		}
+		for i := range a {
+			if !mismoOrdenValor(a[i], ba[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// valorCampo devuelve el valor de la clave en d.
+func valorCampo(d bson.D, clave string) (interface{}, bool) {
+	for _, e := range d {
+		if e.Key == clave {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
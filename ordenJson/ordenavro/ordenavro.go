@@ -0,0 +1,134 @@
+// Package ordenavro codifica los metadatos de documentos en Avro, para
+// enviarlos a Kafka o guardarlos en el data lake con los campos en el orden
+// canónico de ordenJson.OrdenCampos.
+//
+// EncodeAvro produce un registro binario por documento, el formato de los
+// mensajes de Kafka; EscribirContenedor escribe un archivo contenedor de
+// Avro (.avro) con el esquema incluido.
+package ordenavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// NombreRegistro es el nombre completo del registro en el esquema, con el
+// mismo paquete que el mensaje protobuf equivalente.
+const NombreRegistro = "ordenjson.v1.DocumentMetadata"
+
+// EsquemaJSON es el esquema Avro de los metadatos: un registro con un campo
+// por clave de ordenJson.OrdenCampos, en ese orden. Avro no admite ':' ni '-'
+// en los nombres, así que cada campo se llama como su clave con esos
+// caracteres reemplazados por '_' (ver NombreCampo) y la clave original queda
+// en su "doc". Todos los campos son ["null", "string"] y los vacíos se
+// codifican como null.
+var EsquemaJSON = esquemaJSON()
+
+// Esquema es EsquemaJSON ya interpretado.
+var Esquema = avro.MustParse(EsquemaJSON)
+
+// campoEsquema es un campo de EsquemaJSON.
+type campoEsquema struct {
+	Nombre  string      `json:"name"`
+	Tipo    []string    `json:"type"`
+	Default interface{} `json:"default"`
+	Doc     string      `json:"doc"`
+}
+
+// esquemaJSON construye EsquemaJSON a partir de ordenJson.OrdenCampos.
+func esquemaJSON() string {
+	campos := make([]campoEsquema, len(ordenJson.OrdenCampos))
+	for i, clave := range ordenJson.OrdenCampos {
+		campos[i] = campoEsquema{Nombre: NombreCampo(clave), Tipo: []string{"null", "string"}, Doc: clave}
+	}
+	esquema, err := json.Marshal(struct {
+		Tipo   string         `json:"type"`
+		Nombre string         `json:"name"`
+		Campos []campoEsquema `json:"fields"`
+	}{"record", NombreRegistro, campos})
+	if err != nil {
+		panic(err)
+	}
+	return string(esquema)
+}
+
+// NombreCampo devuelve el nombre Avro del campo de una clave canónica:
+// "tanner:tipo-documento" pasa a ser "tanner_tipo_documento".
+func NombreCampo(clave string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, clave)
+}
+
+// EncodeAvro codifica cada documento como un registro binario de Esquema, sin
+// el esquema ni encabezados, que es lo que se publica en Kafka. Cada
+// documento pasa antes por ordenJson.OrdenarDocumentoMetadata con las
+// opciones recibidas, así que se aplican las mismas validaciones y
+// transformaciones; el primero que falla detiene la codificación y su error
+// se devuelve con la posición del documento.
+func EncodeAvro(docs []ordenJson.DocumentMetadata, opts ...ordenJson.Option) ([][]byte, error) {
+	ordenador := ordenJson.Nuevo(opts...)
+	registros := make([][]byte, len(docs))
+	for i, doc := range docs {
+		registro, err := nuevoRegistro(ordenador, doc)
+		if err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i, err)
+		}
+		if registros[i], err = avro.Marshal(Esquema, registro); err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i, err)
+		}
+	}
+	return registros, nil
+}
+
+// EscribirContenedor escribe los documentos en w como un archivo contenedor
+// de Avro con EsquemaJSON en su encabezado. Los documentos se preparan igual
+// que en EncodeAvro.
+func EscribirContenedor(w io.Writer, docs []ordenJson.DocumentMetadata, opts ...ordenJson.Option) error {
+	ordenador := ordenJson.Nuevo(opts...)
+	codificador, err := ocf.NewEncoder(EsquemaJSON, w)
+	if err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		registro, err := nuevoRegistro(ordenador, doc)
+		if err != nil {
+			return fmt.Errorf("documento %d: %w", i, err)
+		}
+		if err := codificador.Encode(registro); err != nil {
+			return fmt.Errorf("documento %d: %w", i, err)
+		}
+	}
+	return codificador.Close()
+}
+
+// nuevoRegistro ordena doc y lo convierte en el registro de Esquema.
+func nuevoRegistro(ordenador *ordenJson.Ordenador, doc ordenJson.DocumentMetadata) (map[string]interface{}, error) {
+	ordenado, err := ordenador.OrdenarDocumentoMetadata(doc)
+	if err != nil {
+		return nil, err
+	}
+	var valores map[string]interface{}
+	if err := json.Unmarshal([]byte(ordenado), &valores); err != nil {
+		return nil, err
+	}
+	registro := make(map[string]interface{}, len(ordenJson.OrdenCampos))
+	for _, clave := range ordenJson.OrdenCampos {
+		var valor interface{}
+		if texto, ok := valores[clave].(string); ok {
+			valor = texto
+		}
+		registro[NombreCampo(clave)] = valor
+	}
+	return registro, nil
+}
@@ -0,0 +1,126 @@
+// Package ordenbson integra ordenJson con MongoDB: convierte los documentos
+// ordenados en bson.D, que conserva el orden de los campos al insertarlos,
+// en lugar de pasar por un mapa sin orden.
+//
+//	documento, err := ordenbson.OrdenarBSON(entrada)
+//	coleccion.InsertOne(ctx, documento)
+package ordenbson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// NombresBSON asocia la clave JSON de cada campo de ordenJson.DocumentMetadata
+// con su nombre en las etiquetas bson del struct, que es el que tienen los
+// documentos guardados a partir de él.
+var NombresBSON = nombresBSON(reflect.TypeOf(ordenJson.DocumentMetadata{}))
+
+// nombresBSON lee las etiquetas json y bson de los campos de t.
+func nombresBSON(t reflect.Type) map[string]string {
+	nombres := make(map[string]string, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		campo := t.Field(i)
+		clave, _, _ := strings.Cut(campo.Tag.Get("json"), ",")
+		nombre, _, _ := strings.Cut(campo.Tag.Get("bson"), ",")
+		if clave != "" && nombre != "" {
+			nombres[clave] = strings.TrimSpace(nombre)
+		}
+	}
+	return nombres
+}
+
+// OrdenarBSON ordena input como ordenJson.OrdenarJSON, con las mismas
+// opciones, y devuelve el resultado como bson.D. Ver ADocumento.
+func OrdenarBSON(input interface{}, opts ...ordenJson.Option) (bson.D, error) {
+	ordenado, err := ordenJson.OrdenarJSON(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ADocumento([]byte(ordenado))
+}
+
+// ADocumento convierte un documento JSON ya ordenado en bson.D con los campos
+// en el mismo orden. Las claves de primer nivel que corresponden a un campo
+// de ordenJson.DocumentMetadata toman su nombre de NombresBSON; las demás, y
+// las de los objetos anidados, se conservan. Los objetos anidados pasan a ser
+// bson.D y los arreglos bson.A; los números enteros se guardan como int64 y
+// el resto como float64.
+func ADocumento(documento []byte) (bson.D, error) {
+	dec := json.NewDecoder(bytes.NewReader(documento))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, fmt.Errorf("el documento debe ser un objeto JSON")
+	}
+	d, err := leerObjeto(dec, NombresBSON)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("contenido inesperado después del documento JSON")
+	}
+	return d, nil
+}
+
+// leerObjeto lee los campos de un objeto cuya apertura ya se consumió. Las
+// claves que aparecen en nombres se renombran.
+func leerObjeto(dec *json.Decoder, nombres map[string]string) (bson.D, error) {
+	d := bson.D{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		clave := t.(string)
+		if nombre, ok := nombres[clave]; ok {
+			clave = nombre
+		}
+		valor, err := leerValor(dec)
+		if err != nil {
+			return nil, err
+		}
+		d = append(d, bson.E{Key: clave, Value: valor})
+	}
+	_, err := dec.Token() // '}'
+	return d, err
+}
+
+// leerValor lee el siguiente valor de dec y lo convierte a su tipo BSON.
+func leerValor(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		if v == '{' {
+			return leerObjeto(dec, nil)
+		}
+		a := bson.A{}
+		for dec.More() {
+			elemento, err := leerValor(dec)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, elemento)
+		}
+		_, err := dec.Token() // ']'
+		return a, err
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	}
+	return t, nil // string, bool o nil
+}
@@ -0,0 +1,32 @@
+// Package ordencodec provee implementaciones de ordenJson.Codec más rápidas
+// que encoding/json. Se pueden elegir en tiempo de ejecución con
+// ordenJson.WithCodec:
+//
+//	ord := ordenJson.Nuevo(ordenJson.WithCodec(ordencodec.Jsoniter))
+//
+// o en tiempo de compilación: al importar el paquete, su init establece con
+// ordenJson.EstablecerCodec el Codec que eligen las etiquetas de compilación,
+// y que usan todos los Ordenadores sin WithCodec:
+//
+//	import _ "github.com/samuel/prueba-orden/ordenJson/ordencodec"
+//
+//	go build -tags jsoniter   # jsoniter
+//	go build -tags sonic      # sonic; ver Sonic
+//
+// Sin etiquetas, el Codec por defecto sigue siendo ordenJson.CodecEstandar.
+// Todas las implementaciones producen la misma salida que encoding/json.
+package ordencodec
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Jsoniter es el Codec de github.com/json-iterator/go con la configuración
+// compatible con encoding/json.
+var Jsoniter ordenJson.Codec = jsoniter.ConfigCompatibleWithStandardLibrary
+
+func init() {
+	ordenJson.EstablecerCodec(predeterminado)
+}
@@ -0,0 +1,8 @@
+//go:build !jsoniter && !sonic
+
+package ordencodec
+
+import "github.com/samuel/prueba-orden/ordenJson/v2"
+
+// predeterminado es el Codec que establece init.
+var predeterminado = ordenJson.CodecEstandar
@@ -0,0 +1,6 @@
+//go:build jsoniter && !sonic
+
+package ordencodec
+
+// predeterminado es el Codec que establece init.
+var predeterminado = Jsoniter
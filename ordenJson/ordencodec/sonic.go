@@ -0,0 +1,18 @@
+//go:build sonic
+
+package ordencodec
+
+import (
+	"github.com/bytedance/sonic"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Sonic es el Codec de github.com/bytedance/sonic con la configuración
+// compatible con encoding/json. Solo está disponible con la etiqueta de
+// compilación sonic, porque sonic genera código de máquina y no compila en
+// todas las arquitecturas ni con todas las versiones de Go.
+var Sonic ordenJson.Codec = sonic.ConfigStd
+
+// predeterminado es el Codec que establece init.
+var predeterminado = Sonic
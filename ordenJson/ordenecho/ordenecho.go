@@ -0,0 +1,65 @@
+// Package ordenecho integra ordenJson con echo: un echo.JSONSerializer que
+// escribe las respuestas con las claves en orden canónico.
+//
+//	e := echo.New()
+//	e.JSONSerializer = ordenecho.Serializador{}
+package ordenecho
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Serializador implementa echo.JSONSerializer. Las respuestas de c.JSON,
+// c.JSONPretty y c.JSONBlob que pasan por él se ordenan; la lectura de las
+// solicitudes es la misma que la de echo.DefaultJSONSerializer.
+type Serializador struct {
+	Ordenador *ordenJson.Ordenador // Configuración con que se ordena; nil usa la por defecto.
+}
+
+// Serialize escribe i ordenado en la respuesta, con la sangría indicada por
+// echo (vacía para c.JSON) y terminado en un salto de línea.
+func (s Serializador) Serialize(c echo.Context, i interface{}, sangria string) error {
+	ordenador := s.Ordenador
+	if ordenador == nil {
+		ordenador = ordenJson.Nuevo()
+	}
+	datos, err := ordenador.Serializar(i)
+	if err != nil {
+		return err
+	}
+	var salida bytes.Buffer
+	if sangria == "" {
+		err = json.Compact(&salida, datos)
+	} else {
+		err = json.Indent(&salida, datos, "", sangria)
+	}
+	if err != nil {
+		return err
+	}
+	salida.WriteByte('\n')
+	_, err = c.Response().Write(salida.Bytes())
+	return err
+}
+
+// Deserialize lee el cuerpo de la solicitud en i. Los errores de sintaxis y de
+// tipos se devuelven como *echo.HTTPError con código 400.
+func (Serializador) Deserialize(c echo.Context, i interface{}) error {
+	err := json.NewDecoder(c.Request().Body).Decode(i)
+	var errTipo *json.UnmarshalTypeError
+	var errSintaxis *json.SyntaxError
+	switch {
+	case errors.As(err, &errTipo):
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", errTipo.Type, errTipo.Value, errTipo.Field, errTipo.Offset)).SetInternal(err)
+	case errors.As(err, &errSintaxis):
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", errSintaxis.Offset, errSintaxis.Error())).SetInternal(err)
+	}
+	return err
+}
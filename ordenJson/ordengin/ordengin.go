@@ -0,0 +1,69 @@
+// Package ordengin integra ordenJson con gin: las respuestas JSON se escriben
+// con las claves en orden canónico sin llamar a OrdenarJSON en cada handler.
+//
+//	ordengin.JSON(c, http.StatusOK, documento)
+//	c.Render(http.StatusOK, ordengin.Render{Valor: documento, Ordenador: contratos})
+package ordengin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// tipoJSON es el Content-Type de las respuestas, el mismo que usa c.JSON.
+var tipoJSON = []string{"application/json; charset=utf-8"}
+
+// JSON escribe v como respuesta JSON compacta, con el código indicado y las
+// claves en el orden de ordenJson.PerfilPorDefecto. Es el equivalente
+// ordenado de c.JSON.
+func JSON(c *gin.Context, codigo int, v interface{}) {
+	c.Render(codigo, Render{Valor: v})
+}
+
+// IndentedJSON es como JSON pero con la indentación de ordenJson.OrdenarJSON.
+func IndentedJSON(c *gin.Context, codigo int, v interface{}) {
+	c.Render(codigo, Render{Valor: v, Indentado: true})
+}
+
+// Render implementa render.Render de gin.
+type Render struct {
+	Valor     interface{}
+	Ordenador *ordenJson.Ordenador // Configuración con que se ordena; nil usa la por defecto.
+	Indentado bool                 // Sin indentación la salida es compacta, como la de c.JSON.
+}
+
+// Render escribe Valor ordenado en w. Si Valor no se puede ordenar, por
+// ejemplo porque no cumple las validaciones del Ordenador, devuelve el error
+// sin escribir nada; gin lo registra en el contexto.
+func (r Render) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	ordenador := r.Ordenador
+	if ordenador == nil {
+		ordenador = ordenJson.Nuevo()
+	}
+	datos, err := ordenador.Serializar(r.Valor)
+	if err != nil {
+		return err
+	}
+	if !r.Indentado {
+		var compacto bytes.Buffer
+		if err := json.Compact(&compacto, datos); err != nil {
+			return err
+		}
+		datos = compacto.Bytes()
+	}
+	_, err = w.Write(datos)
+	return err
+}
+
+// WriteContentType define el Content-Type de la respuesta.
+func (r Render) WriteContentType(w http.ResponseWriter) {
+	if encabezado := w.Header(); len(encabezado["Content-Type"]) == 0 {
+		encabezado["Content-Type"] = tipoJSON
+	}
+}
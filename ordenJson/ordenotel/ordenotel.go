@@ -0,0 +1,79 @@
+// Package ordenotel instrumenta ordenJson con trazas de OpenTelemetry:
+//
+//	ordenador := ordenJson.Nuevo(ordenotel.WithTrazas(nil))
+//	salida, err := ordenador.OrdenarJSONCtx(ctx, documento)
+//
+// Cada documento ordenado produce un span ordenjson.ordenar, hijo del span
+// que lleve el contexto, con un span ordenjson.validar dentro; OrdenarLote y
+// OrdenarFlujo agrupan los suyos bajo ordenjson.lote y ordenjson.flujo. Los
+// atributos (tamaño de entrada y salida, cantidad de claves, nombre del
+// perfil, ...) son los de las constantes Atributo de ordenJson.
+package ordenotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// NombreInstrumentacion es el nombre del tracer con el que se crean los spans.
+const NombreInstrumentacion = "github.com/samuel/prueba-orden/ordenJson"
+
+// WithTrazas es ordenJson.WithTrazador(Trazador(proveedor)).
+func WithTrazas(proveedor trace.TracerProvider) ordenJson.Option {
+	return ordenJson.WithTrazador(Trazador(proveedor))
+}
+
+// Trazador devuelve un ordenJson.Trazador que crea spans con el tracer
+// NombreInstrumentacion de proveedor. Con proveedor nil se usa el global,
+// otel.GetTracerProvider(), en cada span, por lo que se puede configurar
+// después de crear el Ordenador.
+func Trazador(proveedor trace.TracerProvider) ordenJson.Trazador {
+	return trazador{proveedor: proveedor}
+}
+
+// trazador implementa ordenJson.Trazador.
+type trazador struct {
+	proveedor trace.TracerProvider
+}
+
+// Iniciar crea un span interno hijo del que lleva ctx.
+func (t trazador) Iniciar(ctx context.Context, nombre string) (context.Context, ordenJson.Tramo) {
+	proveedor := t.proveedor
+	if proveedor == nil {
+		proveedor = otel.GetTracerProvider()
+	}
+	ctx, span := proveedor.Tracer(NombreInstrumentacion).Start(ctx, nombre, trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, tramo{span}
+}
+
+// tramo implementa ordenJson.Tramo sobre un span.
+type tramo struct {
+	span trace.Span
+}
+
+// Atributo agrega el atributo al span con su tipo.
+func (t tramo) Atributo(clave string, valor interface{}) {
+	switch v := valor.(type) {
+	case int:
+		t.span.SetAttributes(attribute.Int(clave, v))
+	case string:
+		t.span.SetAttributes(attribute.String(clave, v))
+	case bool:
+		t.span.SetAttributes(attribute.Bool(clave, v))
+	}
+}
+
+// Terminar registra err en el span, si no es nil, y lo cierra.
+func (t tramo) Terminar(err error) {
+	if err != nil {
+		t.span.RecordError(err)
+		t.span.SetStatus(codes.Error, err.Error())
+	}
+	t.span.End()
+}
@@ -0,0 +1,105 @@
+// Package ordenprom expone métricas de Prometheus del ordenamiento de
+// ordenJson, para alertar sobre picos de errores en los servicios que usan el
+// paquete:
+//
+//	metricas, err := ordenprom.NuevasMetricas(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//	ordenador := ordenJson.Nuevo(ordenprom.WithMetricas(metricas))
+//
+// Las métricas se alimentan de los Evento de ordenJson, por lo que cubren las
+// mismas operaciones que WithRegistroDeEventos: ordenar, reporte,
+// particionar y deduplicar, cada una en la etiqueta operacion.
+//
+//   - ordenjson_documentos_total{operacion, resultado}: documentos procesados,
+//     con resultado ok, con-problemas o error.
+//   - ordenjson_errores_total{operacion, tipo}: errores, por las constantes
+//     TipoError* de ordenJson (json-invalido, campos-faltantes, ...).
+//   - ordenjson_bytes_entrada{operacion}: histograma del tamaño de las
+//     entradas recibidas como texto.
+//   - ordenjson_duracion_segundos{operacion}: histograma de la duración de
+//     cada operación.
+package ordenprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Namespace es el prefijo del nombre de todas las métricas.
+const Namespace = "ordenjson"
+
+// Metricas agrupa los contadores e histogramas del ordenamiento. Implementa
+// ordenJson.RegistroDeEventos y es seguro usarla desde varias goroutines a la
+// vez; un mismo valor puede compartirse entre varios Ordenador.
+type Metricas struct {
+	documentos *prometheus.CounterVec
+	errores    *prometheus.CounterVec
+	bytes      *prometheus.HistogramVec
+	duracion   *prometheus.HistogramVec
+}
+
+// NuevasMetricas crea las métricas y las registra en registrador; con nil se
+// usa prometheus.DefaultRegisterer. Devuelve el error de Register si alguna
+// ya estaba registrada, por ejemplo al llamar dos veces con el mismo
+// registrador.
+func NuevasMetricas(registrador prometheus.Registerer) (*Metricas, error) {
+	if registrador == nil {
+		registrador = prometheus.DefaultRegisterer
+	}
+	m := &Metricas{
+		documentos: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "documentos_total",
+			Help:      "Documentos procesados, por operación y resultado.",
+		}, []string{"operacion", "resultado"}),
+		errores: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "errores_total",
+			Help:      "Errores al procesar documentos, por operación y tipo de error.",
+		}, []string{"operacion", "tipo"}),
+		bytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "bytes_entrada",
+			Help:      "Tamaño en bytes de los documentos recibidos como texto.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 9), // De 256 B a 16 MiB.
+		}, []string{"operacion"}),
+		duracion: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "duracion_segundos",
+			Help:      "Duración de cada operación sobre un documento.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 10), // De 10 µs a 2,6 s.
+		}, []string{"operacion"}),
+	}
+	for _, c := range []prometheus.Collector{m.documentos, m.errores, m.bytes, m.duracion} {
+		if err := registrador.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// WithMetricas es ordenJson.WithRegistroDeEventos(m). Como el Ordenador
+// admite un solo registro de eventos, para además escribirlos con
+// ordenJson.RegistroJSON se combinan ambos en un ordenJson.RegistroFunc.
+func WithMetricas(m *Metricas) ordenJson.Option {
+	return ordenJson.WithRegistroDeEventos(m)
+}
+
+// Registrar actualiza las métricas con el evento.
+func (m *Metricas) Registrar(e ordenJson.Evento) {
+	m.documentos.WithLabelValues(e.Operacion, e.Resultado).Inc()
+	if e.Resultado == ordenJson.ResultadoError {
+		tipo := e.TipoError
+		if tipo == "" {
+			tipo = ordenJson.TipoErrorOtro
+		}
+		m.errores.WithLabelValues(e.Operacion, tipo).Inc()
+	}
+	if e.BytesEntrada > 0 {
+		m.bytes.WithLabelValues(e.Operacion).Observe(float64(e.BytesEntrada))
+	}
+	m.duracion.WithLabelValues(e.Operacion).Observe(e.Duracion.Seconds())
+}
@@ -0,0 +1,136 @@
+// Package ordenxlsx exporta metadatos de documentos a planillas Excel (.xlsx)
+// con las columnas en el orden canónico, para entregarlas a usuarios de
+// negocio que las revisan en una planilla.
+package ordenxlsx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// NombreHoja es el nombre de la hoja con los metadatos.
+const NombreHoja = "Metadatos"
+
+// anchoMaximo limita el ancho, en caracteres, que se da a una columna.
+const anchoMaximo = 60
+
+// ExportarXLSX crea el archivo ruta (o lo reemplaza) con los documentos; ver
+// Escribir.
+func ExportarXLSX(docs []ordenJson.DocumentMetadata, ruta string, opts ...ordenJson.Option) error {
+	libro, err := nuevoLibro(docs, opts)
+	if err != nil {
+		return err
+	}
+	defer libro.Close()
+	return libro.SaveAs(ruta)
+}
+
+// Escribir escribe en w una planilla con la hoja NombreHoja: una fila de
+// encabezado con los campos del perfil, en su orden, y una fila por
+// documento. El encabezado va en negrita, queda fijo al desplazarse y tiene
+// filtros; cada columna toma el ancho de su contenido. Cada documento pasa
+// antes por ordenJson.OrdenarDocumentoMetadata con las opciones recibidas,
+// así que se aplican las mismas validaciones y transformaciones; el primero
+// que falla detiene la exportación y su error se devuelve con la posición del
+// documento.
+func Escribir(w io.Writer, docs []ordenJson.DocumentMetadata, opts ...ordenJson.Option) error {
+	libro, err := nuevoLibro(docs, opts)
+	if err != nil {
+		return err
+	}
+	defer libro.Close()
+	return libro.Write(w)
+}
+
+// nuevoLibro arma la planilla en memoria.
+func nuevoLibro(docs []ordenJson.DocumentMetadata, opts []ordenJson.Option) (*excelize.File, error) {
+	ordenador := ordenJson.Nuevo(opts...)
+	campos := ordenador.Perfil().Campos()
+	anchos := make([]int, len(campos))
+	filas := make([][]interface{}, 0, len(docs)+1)
+
+	encabezado := make([]interface{}, len(campos))
+	for j, campo := range campos {
+		encabezado[j] = campo
+		anchos[j] = utf8.RuneCountInString(campo)
+	}
+	filas = append(filas, encabezado)
+	for i, doc := range docs {
+		ordenado, err := ordenador.OrdenarDocumentoMetadata(doc)
+		if err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i, err)
+		}
+		var valores map[string]interface{}
+		if err := json.Unmarshal([]byte(ordenado), &valores); err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i, err)
+		}
+		fila := make([]interface{}, len(campos))
+		for j, campo := range campos {
+			if valor, ok := valores[campo]; ok && valor != nil {
+				texto := fmt.Sprint(valor)
+				fila[j] = texto
+				anchos[j] = max(anchos[j], utf8.RuneCountInString(texto))
+			}
+		}
+		filas = append(filas, fila)
+	}
+
+	libro := excelize.NewFile()
+	if err := escribirHoja(libro, filas, anchos); err != nil {
+		libro.Close()
+		return nil, err
+	}
+	return libro, nil
+}
+
+// escribirHoja vuelca las filas en NombreHoja y le da formato.
+func escribirHoja(libro *excelize.File, filas [][]interface{}, anchos []int) error {
+	if err := libro.SetSheetName(libro.GetSheetName(0), NombreHoja); err != nil {
+		return err
+	}
+	for i, fila := range filas {
+		celda, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := libro.SetSheetRow(NombreHoja, celda, &fila); err != nil {
+			return err
+		}
+	}
+	ultima, err := excelize.CoordinatesToCellName(len(anchos), 1)
+	if err != nil {
+		return err
+	}
+	negrita, err := libro.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	})
+	if err != nil {
+		return err
+	}
+	if err := libro.SetCellStyle(NombreHoja, "A1", ultima, negrita); err != nil {
+		return err
+	}
+	if err := libro.AutoFilter(NombreHoja, "A1:"+ultima, nil); err != nil {
+		return err
+	}
+	if err := libro.SetPanes(NombreHoja, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+	for j, ancho := range anchos {
+		columna, err := excelize.ColumnNumberToName(j + 1)
+		if err != nil {
+			return err
+		}
+		if err := libro.SetColWidth(NombreHoja, columna, columna, float64(min(ancho, anchoMaximo)+2)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
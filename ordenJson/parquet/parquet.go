@@ -0,0 +1,89 @@
+// Package parquet escribe lotes de metadatos de documentos en archivos
+// Parquet, con una columna por clave de ordenJson.OrdenCampos en ese orden,
+// para cargar exportaciones grandes directamente en el almacén analítico.
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	parquetgo "github.com/parquet-go/parquet-go"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// tipoFila es el struct de cada fila: un campo string opcional por clave de
+// ordenJson.OrdenCampos, en ese orden y con la clave como nombre de columna.
+// Se construye a partir de OrdenCampos para que el esquema no se desalinee.
+var tipoFila = func() reflect.Type {
+	campos := make([]reflect.StructField, len(ordenJson.OrdenCampos))
+	for i, clave := range ordenJson.OrdenCampos {
+		campos[i] = reflect.StructField{
+			Name: fmt.Sprintf("Campo%d", i),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(fmt.Sprintf(`parquet:%q`, clave+",optional")),
+		}
+	}
+	return reflect.StructOf(campos)
+}()
+
+// Esquema es el esquema de los archivos. Las columnas son opcionales: los
+// campos vacíos se escriben como null.
+var Esquema = parquetgo.SchemaOf(reflect.New(tipoFila).Interface())
+
+// EscribirArchivo crea el archivo ruta (o lo reemplaza) y escribe en él los
+// documentos; ver Escribir.
+func EscribirArchivo(ruta string, docs []ordenJson.DocumentMetadata, opts ...ordenJson.Option) error {
+	archivo, err := os.Create(ruta)
+	if err != nil {
+		return err
+	}
+	if err := Escribir(archivo, docs, opts...); err != nil {
+		archivo.Close()
+		return err
+	}
+	return archivo.Close()
+}
+
+// Escribir escribe los documentos en w como un archivo Parquet con Esquema,
+// una fila por documento. Cada documento pasa antes por
+// ordenJson.OrdenarDocumentoMetadata con las opciones recibidas, así que se
+// aplican las mismas validaciones y transformaciones; el primero que falla
+// detiene la escritura y su error se devuelve con la posición del documento.
+// Los documentos se comprimen con Snappy.
+func Escribir(w io.Writer, docs []ordenJson.DocumentMetadata, opts ...ordenJson.Option) error {
+	ordenador := ordenJson.Nuevo(opts...)
+	escritor := parquetgo.NewWriter(w, Esquema, parquetgo.Compression(&parquetgo.Snappy))
+	for i, doc := range docs {
+		fila, err := nuevaFila(ordenador, doc)
+		if err != nil {
+			return fmt.Errorf("documento %d: %w", i, err)
+		}
+		if err := escritor.Write(fila); err != nil {
+			return fmt.Errorf("documento %d: %w", i, err)
+		}
+	}
+	return escritor.Close()
+}
+
+// nuevaFila ordena doc y lo convierte en un valor de tipoFila.
+func nuevaFila(ordenador *ordenJson.Ordenador, doc ordenJson.DocumentMetadata) (interface{}, error) {
+	ordenado, err := ordenador.OrdenarDocumentoMetadata(doc)
+	if err != nil {
+		return nil, err
+	}
+	var valores map[string]interface{}
+	if err := json.Unmarshal([]byte(ordenado), &valores); err != nil {
+		return nil, err
+	}
+	fila := reflect.New(tipoFila)
+	for i, clave := range ordenJson.OrdenCampos {
+		if texto, ok := valores[clave].(string); ok {
+			fila.Elem().Field(i).SetString(texto)
+		}
+	}
+	return fila.Interface(), nil
+}
@@ -0,0 +1,204 @@
+// Package postgres normaliza documentos guardados en columnas jsonb de
+// PostgreSQL. PostgreSQL guarda jsonb en su propio orden de claves (primero
+// las más cortas), por lo que dos instantáneas con los mismos datos pueden
+// diferir solo en el orden. Este paquete lee cada documento, lo devuelve en el
+// orden canónico de ordenJson y genera los UPDATE que guardan esa forma de
+// texto en una columna json o text, que sí conservan el orden recibido. Las
+// claves fuera del perfil conservan el orden de jsonb, que no depende de cómo
+// se insertó el documento, por lo que el resultado es estable:
+//
+//	cfg := postgres.Config{Tabla: "public.documentos", Clave: "id", Columna: "metadata", Destino: "metadata_ordenada"}
+//	n, err := postgres.EscribirUpdates(ctx, db, cfg, archivo)
+//
+// Solo se usa database/sql, por lo que sirve con cualquier driver
+// (pgx/stdlib, lib/pq).
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Consultor es la parte de *sql.DB, *sql.Tx o *sql.Conn que usa Recorrer.
+type Consultor interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Ejecutor es la parte de *sql.DB, *sql.Tx o *sql.Conn que usa Actualizar.
+type Ejecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Config describe la tabla y las columnas a normalizar. Los nombres se citan
+// entre comillas dobles tal como se reciben, por lo que deben escribirse en
+// minúsculas si se crearon sin comillas.
+type Config struct {
+	// Tabla es el nombre de la tabla, opcionalmente con su esquema
+	// ("public.documentos").
+	Tabla string
+	// Clave es la columna que identifica cada fila, normalmente la clave
+	// primaria. Las filas se recorren en su orden.
+	Clave string
+	// Columna es la columna jsonb con los documentos. Las filas donde es NULL
+	// se omiten.
+	Columna string
+	// Destino es la columna json o text donde se guarda el documento ordenado.
+	// Es obligatoria para Actualizar, SentenciaUpdate y EscribirUpdates; no
+	// puede ser jsonb, que volvería a reordenar las claves.
+	Destino string
+	// Filtro, si no está vacío, es una condición SQL que se agrega al WHERE
+	// para limitar las filas, por ejemplo "creado > now() - interval '1 day'".
+	Filtro string
+	// Ordenador ordena los documentos; nil usa ordenJson.Nuevo().
+	Ordenador *ordenJson.Ordenador
+	// Indentado guarda los documentos con la indentación de OrdenarJSON; por
+	// defecto se guardan compactos, en una línea.
+	Indentado bool
+}
+
+// Fila es un documento leído por Recorrer.
+type Fila struct {
+	Clave    string // Valor de Config.Clave, como texto.
+	Ordenado string // Documento de Config.Columna en el orden canónico.
+	// Pendiente indica que Config.Destino no contiene Ordenado. Sin Destino
+	// siempre es true.
+	Pendiente bool
+}
+
+// Recorrer lee los documentos de cfg.Columna en el orden de cfg.Clave y llama
+// a f con cada uno ya ordenado. Se detiene ante el primer error de la
+// consulta, del ordenamiento o de f, y lo devuelve.
+func Recorrer(ctx context.Context, db Consultor, cfg Config, f func(Fila) error) error {
+	if err := cfg.validar(false); err != nil {
+		return err
+	}
+	o := cfg.Ordenador
+	if o == nil {
+		o = ordenJson.Nuevo()
+	}
+	filas, err := db.QueryContext(ctx, cfg.consulta())
+	if err != nil {
+		return fmt.Errorf("postgres: consultando %s: %w", cfg.Tabla, err)
+	}
+	defer filas.Close()
+	for filas.Next() {
+		var clave, documento string
+		var guardado sql.NullString
+		destinos := []interface{}{&clave, &documento}
+		if cfg.Destino != "" {
+			destinos = append(destinos, &guardado)
+		}
+		if err := filas.Scan(destinos...); err != nil {
+			return fmt.Errorf("postgres: leyendo %s: %w", cfg.Tabla, err)
+		}
+		ordenado, err := o.OrdenarJSONCtx(ctx, documento)
+		if err != nil {
+			return fmt.Errorf("postgres: fila %s: %w", clave, err)
+		}
+		if !cfg.Indentado {
+			var compacto bytes.Buffer
+			if err := json.Compact(&compacto, []byte(ordenado)); err != nil {
+				return fmt.Errorf("postgres: fila %s: %w", clave, err)
+			}
+			ordenado = compacto.String()
+		}
+		fila := Fila{Clave: clave, Ordenado: ordenado, Pendiente: !guardado.Valid || guardado.String != ordenado}
+		if err := f(fila); err != nil {
+			return err
+		}
+	}
+	return filas.Err()
+}
+
+// Actualizar guarda fila.Ordenado en cfg.Destino con un UPDATE parametrizado.
+// No debe llamarse sobre la misma conexión o transacción mientras Recorrer
+// tiene la consulta abierta; para eso, ver EscribirUpdates.
+func Actualizar(ctx context.Context, db Ejecutor, cfg Config, fila Fila) error {
+	if err := cfg.validar(true); err != nil {
+		return err
+	}
+	consulta := fmt.Sprintf("UPDATE %s SET %s = $1 WHERE %s = $2", identificador(cfg.Tabla), identificador(cfg.Destino), identificador(cfg.Clave))
+	if _, err := db.ExecContext(ctx, consulta, fila.Ordenado, fila.Clave); err != nil {
+		return fmt.Errorf("postgres: actualizando la fila %s: %w", fila.Clave, err)
+	}
+	return nil
+}
+
+// SentenciaUpdate devuelve el UPDATE que guarda fila.Ordenado en cfg.Destino,
+// con los valores como literales, listo para un script. La clave se escribe
+// como texto y PostgreSQL la convierte al tipo de la columna. cfg.Destino no
+// debe estar vacío.
+func SentenciaUpdate(cfg Config, fila Fila) string {
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s;", identificador(cfg.Tabla), identificador(cfg.Destino), literal(fila.Ordenado), identificador(cfg.Clave), literal(fila.Clave))
+}
+
+// EscribirUpdates escribe en w, uno por línea, los UPDATE de las filas
+// pendientes (ver Fila.Pendiente) y devuelve cuántos escribió. El script no
+// abre ni cierra una transacción.
+func EscribirUpdates(ctx context.Context, db Consultor, cfg Config, w io.Writer) (int, error) {
+	if err := cfg.validar(true); err != nil {
+		return 0, err
+	}
+	n := 0
+	err := Recorrer(ctx, db, cfg, func(fila Fila) error {
+		if !fila.Pendiente {
+			return nil
+		}
+		if _, err := io.WriteString(w, SentenciaUpdate(cfg, fila)+"\n"); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// validar revisa que cfg tenga las columnas obligatorias.
+func (cfg Config) validar(conDestino bool) error {
+	if cfg.Tabla == "" || cfg.Clave == "" || cfg.Columna == "" {
+		return errors.New("postgres: Config.Tabla, Config.Clave y Config.Columna son obligatorios")
+	}
+	if conDestino && cfg.Destino == "" {
+		return errors.New("postgres: Config.Destino es obligatorio para actualizar")
+	}
+	return nil
+}
+
+// consulta devuelve el SELECT de Recorrer.
+func (cfg Config) consulta() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SELECT %s::text, %s::text", identificador(cfg.Clave), identificador(cfg.Columna))
+	if cfg.Destino != "" {
+		fmt.Fprintf(&b, ", %s::text", identificador(cfg.Destino))
+	}
+	fmt.Fprintf(&b, " FROM %s WHERE %s IS NOT NULL", identificador(cfg.Tabla), identificador(cfg.Columna))
+	if cfg.Filtro != "" {
+		fmt.Fprintf(&b, " AND (%s)", cfg.Filtro)
+	}
+	fmt.Fprintf(&b, " ORDER BY %s", identificador(cfg.Clave))
+	return b.String()
+}
+
+// identificador cita un nombre, con su esquema si lo tiene: public.docs se
+// convierte en "public"."docs".
+func identificador(nombre string) string {
+	partes := strings.Split(nombre, ".")
+	for i, parte := range partes {
+		partes[i] = `"` + strings.ReplaceAll(parte, `"`, `""`) + `"`
+	}
+	return strings.Join(partes, ".")
+}
+
+// literal cita un texto como literal de SQL. Asume
+// standard_conforming_strings, activo por defecto desde PostgreSQL 9.1.
+func literal(texto string) string {
+	return "'" + strings.ReplaceAll(texto, "'", "''") + "'"
+}
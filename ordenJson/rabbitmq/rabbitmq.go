@@ -0,0 +1,254 @@
+// Package rabbitmq implementa un consumidor AMQP equivalente al puente de
+// ordenJson/kafka: consume documentos JSON de una cola, los ordena y los
+// publica en un exchange. Los documentos que no se pueden ordenar (JSON
+// inválido o que no cumplen las validaciones del Ordenador) se envían a una
+// cola de mensajes muertos, para no detener el consumo.
+//
+//	canal, _ := conexion.Channel()
+//	canal.Confirm(false) // Opcional: esperar la confirmación del broker.
+//	consumidor, err := rabbitmq.Nuevo(rabbitmq.Config{
+//		Canal:           canal,
+//		Cola:            "documentos",
+//		Exchange:        "documentos-ordenados",
+//		ExchangeMuertos: "documentos-rechazados",
+//	})
+//	err = consumidor.Ejecutar(ctx)
+//
+// Cada mensaje se confirma (ack) en la cola solo después de publicarlo, por
+// lo que ante una caída se vuelve a entregar en lugar de perderse: la entrega
+// es al menos una vez. Si el canal está en modo confirmación, además se espera
+// a que el broker acepte cada publicación.
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// Encabezados que se agregan a los mensajes muertos publicados en
+// Config.ExchangeMuertos. Son los mismos que usa ordenJson/kafka.
+const (
+	EncabezadoError  = "ordenjson-error"  // Mensaje del error.
+	EncabezadoTipo   = "ordenjson-tipo"   // Clasificación del error; ver cuarentena.Clasificar.
+	EncabezadoOrigen = "ordenjson-origen" // Exchange y clave de enrutamiento del mensaje original.
+)
+
+// Canal es la parte de *amqp.Channel que usa el consumidor.
+type Canal interface {
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (*amqp.DeferredConfirmation, error)
+}
+
+// Config describe la cola, los destinos y el ordenamiento de un Consumidor.
+type Config struct {
+	Canal Canal
+	// Cola es la cola de la que se consumen los documentos, sin confirmación
+	// automática.
+	Cola string
+	// Etiqueta identifica al consumidor en el broker; vacía deja que el broker
+	// genere una.
+	Etiqueta string
+	// Exchange recibe los documentos ordenados, con las propiedades y los
+	// encabezados del original. Vacío publica en el exchange por defecto, que
+	// enruta por nombre de cola.
+	Exchange string
+	// ClaveEnrutamiento es la clave con la que se publican los documentos
+	// ordenados; vacía conserva la del mensaje original.
+	ClaveEnrutamiento string
+	// ExchangeMuertos recibe los documentos que no se pudieron ordenar, sin
+	// cambios, con la clave de enrutamiento original y los encabezados
+	// EncabezadoError, EncabezadoTipo y EncabezadoOrigen. Si está vacío, esos
+	// mensajes se rechazan (nack) sin reencolar, para que los enrute el
+	// dead-letter exchange configurado en la cola (x-dead-letter-exchange).
+	ExchangeMuertos string
+	// Ordenador ordena y, según sus opciones, valida los documentos; nil usa
+	// ordenJson.Nuevo().
+	Ordenador *ordenJson.Ordenador
+	// Indentado publica los documentos con la indentación de OrdenarJSON; por
+	// defecto se publican compactos, en una línea.
+	Indentado bool
+}
+
+// Resumen cuenta los mensajes que procesó un Consumidor.
+type Resumen struct {
+	Publicados int64 // Ordenados y publicados en Config.Exchange.
+	Rechazados int64 // Enviados a los mensajes muertos.
+}
+
+// Consumidor consume, ordena y republica mensajes. Ver Nuevo.
+type Consumidor struct {
+	cfg        Config
+	publicados atomic.Int64
+	rechazados atomic.Int64
+}
+
+// Nuevo crea un Consumidor con la configuración recibida. Devuelve error si
+// falta Canal o Cola.
+func Nuevo(cfg Config) (*Consumidor, error) {
+	if cfg.Canal == nil || cfg.Cola == "" {
+		return nil, errors.New("rabbitmq: Config.Canal y Config.Cola son obligatorios")
+	}
+	if cfg.Ordenador == nil {
+		cfg.Ordenador = ordenJson.Nuevo()
+	}
+	return &Consumidor{cfg: cfg}, nil
+}
+
+// Ejecutar consume mensajes hasta que ctx termina, en cuyo caso devuelve nil,
+// o hasta el primer error al publicar o confirmar, o hasta que el canal se
+// cierra, que devuelve. El mensaje en curso al fallar se rechaza con
+// reencolado, por lo que se vuelve a entregar.
+func (c *Consumidor) Ejecutar(ctx context.Context) error {
+	entregas, err := c.cfg.Canal.Consume(c.cfg.Cola, c.cfg.Etiqueta, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("rabbitmq: consumiendo %s: %w", c.cfg.Cola, err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-entregas:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("rabbitmq: el canal de %s se cerró", c.cfg.Cola)
+			}
+			if err := c.Procesar(ctx, d); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+// Procesar ordena un mensaje, lo publica en el exchange o en los mensajes
+// muertos y lo confirma en la cola. Si no puede publicarlo, lo rechaza con
+// reencolado y devuelve el error. Si falla la confirmación o el rechazo en
+// sí, devuelve el error sin volver a intentarlo, de modo que cada mensaje se
+// confirma o se rechaza una sola vez.
+func (c *Consumidor) Procesar(ctx context.Context, d amqp.Delivery) error {
+	liquidado, err := c.procesar(ctx, d)
+	if err != nil && !liquidado {
+		d.Nack(false, true)
+	}
+	return err
+}
+
+// procesar hace el trabajo de Procesar. liquidado indica si ya se intentó
+// confirmar o rechazar d; si es false y hay error, d sigue pendiente.
+func (c *Consumidor) procesar(ctx context.Context, d amqp.Delivery) (liquidado bool, err error) {
+	ordenado, err := c.cfg.Ordenador.OrdenarJSONCtx(ctx, string(d.Body))
+	var errCancelado *ordenJson.ErrorCancelado
+	switch {
+	case errors.As(err, &errCancelado):
+		return false, err
+	case err != nil && c.cfg.ExchangeMuertos == "":
+		if err := d.Nack(false, false); err != nil {
+			return true, fmt.Errorf("rabbitmq: rechazando %s: %w", origen(d), err)
+		}
+		c.rechazados.Add(1)
+		return true, nil
+	case err != nil:
+		if err := c.publicar(ctx, c.cfg.ExchangeMuertos, d.RoutingKey, mensajeMuerto(d, err)); err != nil {
+			return false, fmt.Errorf("rabbitmq: publicando en los mensajes muertos: %w", err)
+		}
+		c.rechazados.Add(1)
+	default:
+		cuerpo := []byte(ordenado)
+		if !c.cfg.Indentado {
+			var compacto bytes.Buffer
+			if err := json.Compact(&compacto, cuerpo); err != nil {
+				return false, err
+			}
+			cuerpo = compacto.Bytes()
+		}
+		clave := c.cfg.ClaveEnrutamiento
+		if clave == "" {
+			clave = d.RoutingKey
+		}
+		salida := publicacion(d)
+		salida.ContentType = "application/json"
+		salida.Body = cuerpo
+		if err := c.publicar(ctx, c.cfg.Exchange, clave, salida); err != nil {
+			return false, fmt.Errorf("rabbitmq: publicando en %q: %w", c.cfg.Exchange, err)
+		}
+		c.publicados.Add(1)
+	}
+	if err := d.Ack(false); err != nil {
+		return true, fmt.Errorf("rabbitmq: confirmando %s: %w", origen(d), err)
+	}
+	return true, nil
+}
+
+// publicar publica msg y, si el canal está en modo confirmación, espera a que
+// el broker lo acepte.
+func (c *Consumidor) publicar(ctx context.Context, exchange, clave string, msg amqp.Publishing) error {
+	confirmacion, err := c.cfg.Canal.PublishWithDeferredConfirmWithContext(ctx, exchange, clave, false, false, msg)
+	if err != nil || confirmacion == nil {
+		return err
+	}
+	aceptado, err := confirmacion.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !aceptado {
+		return errors.New("el broker rechazó la publicación")
+	}
+	return nil
+}
+
+// Resumen devuelve los mensajes procesados hasta ahora.
+func (c *Consumidor) Resumen() Resumen {
+	return Resumen{Publicados: c.publicados.Load(), Rechazados: c.rechazados.Load()}
+}
+
+// publicacion copia las propiedades de d, sin el cuerpo.
+func publicacion(d amqp.Delivery) amqp.Publishing {
+	return amqp.Publishing{
+		Headers:         d.Headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    d.DeliveryMode,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		Expiration:      d.Expiration,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		UserId:          d.UserId,
+		AppId:           d.AppId,
+	}
+}
+
+// mensajeMuerto devuelve d sin cambios en su contenido, con los encabezados
+// que describen err.
+func mensajeMuerto(d amqp.Delivery, err error) amqp.Publishing {
+	msg := publicacion(d)
+	msg.Headers = amqp.Table{}
+	for clave, valor := range d.Headers {
+		msg.Headers[clave] = valor
+	}
+	msg.Headers[EncabezadoError] = err.Error()
+	msg.Headers[EncabezadoTipo] = cuarentena.Clasificar(err)
+	msg.Headers[EncabezadoOrigen] = origen(d)
+	msg.Body = d.Body
+	return msg
+}
+
+// origen identifica d como exchange/clave de enrutamiento.
+func origen(d amqp.Delivery) string {
+	return d.Exchange + "/" + d.RoutingKey
+}
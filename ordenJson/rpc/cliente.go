@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc"
+)
+
+// Error permite usar la falla de un documento de OrdenarLote como error.
+func (e *Error) Error() string {
+	return e.Tipo + ": " + e.Mensaje
+}
+
+// Cliente ordena documentos a través del servicio gRPC.
+type Cliente struct {
+	rpc    OrdenadorClient
+	perfil string
+}
+
+// NuevoCliente crea un cliente que usa la conexión conn y ordena con el
+// perfil indicado; vacío usa el perfil por defecto del servidor.
+func NuevoCliente(conn grpc.ClientConnInterface, perfil string) *Cliente {
+	return &Cliente{rpc: NewOrdenadorClient(conn), perfil: perfil}
+}
+
+// Ordenar ordena un documento. Los errores son errores de estado de gRPC;
+// ver status.Code.
+func (c *Cliente) Ordenar(ctx context.Context, documento []byte) ([]byte, error) {
+	resp, err := c.rpc.Ordenar(ctx, &OrdenarRequest{Documento: documento, Perfil: c.perfil})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Documento, nil
+}
+
+// Resultado es el resultado de un documento en OrdenarLote.
+type Resultado struct {
+	Documento []byte // Documento ordenado; nil si Err no es nil.
+	Err       error  // Falla del documento; si no es nil es un *Error.
+}
+
+// OrdenarLote ordena los documentos en un único flujo, enviándolos mientras
+// recibe las respuestas, y devuelve un Resultado por documento en el mismo
+// orden. Un documento que no se puede ordenar no interrumpe el lote; el error
+// devuelto indica que falló el flujo.
+func (c *Cliente) OrdenarLote(ctx context.Context, documentos [][]byte) ([]Resultado, error) {
+	ctx, cancelar := context.WithCancel(ctx)
+	defer cancelar()
+	flujo, err := c.rpc.OrdenarLote(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	errEnvio := make(chan error, 1)
+	go func() {
+		for i, documento := range documentos {
+			if err := flujo.Send(&OrdenarRequest{Documento: documento, Perfil: c.perfil, Id: strconv.Itoa(i)}); err != nil {
+				errEnvio <- err
+				return
+			}
+		}
+		errEnvio <- flujo.CloseSend()
+	}()
+
+	resultados := make([]Resultado, len(documentos))
+	for range documentos {
+		resp, err := flujo.Recv()
+		if err != nil {
+			return nil, err
+		}
+		i, err := strconv.Atoi(resp.Id)
+		if err != nil || i < 0 || i >= len(resultados) {
+			return nil, fmt.Errorf("respuesta con identificador inesperado %q", resp.Id)
+		}
+		if resp.Error != nil {
+			resultados[i].Err = resp.Error
+		} else {
+			resultados[i].Documento = resp.Documento
+		}
+	}
+	if err := <-errEnvio; err != nil {
+		return nil, err
+	}
+	return resultados, nil
+}
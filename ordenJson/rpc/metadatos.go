@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// DesdeMetadata convierte un ordenJson.DocumentMetadata en su mensaje
+// protobuf.
+func DesdeMetadata(m ordenJson.DocumentMetadata) *DocumentMetadata {
+	return &DocumentMetadata{
+		TipoDocumento:        m.TipoDocumento,
+		RazonSocialCliente:   m.RazonSocialCliente,
+		RutCliente:           m.RUTCliente,
+		EstadoVisado:         m.EstadoVisado,
+		EstadoVigencia:       m.EstadoVigencia,
+		FechaCarga:           m.FechaCarga,
+		NombreDoc:            m.NombreDoc,
+		Categorias:           m.Categorias,
+		SubCategorias:        m.SubCategorias,
+		Origen:               m.Origen,
+		Relacion:             m.Relacion,
+		FechaTerminoVigencia: m.FechaTerminoVigencia,
+		CmTitle:              m.CmTitle,
+		CmVersionType:        m.CmVersionType,
+		CmVersionLabel:       m.CmVersionLabel,
+		CmDescription:        m.CmDescription,
+		Observaciones:        m.Observaciones,
+	}
+}
+
+// Metadata convierte el mensaje en un ordenJson.DocumentMetadata. Un mensaje
+// nil produce un DocumentMetadata vacío.
+func (x *DocumentMetadata) Metadata() ordenJson.DocumentMetadata {
+	return ordenJson.DocumentMetadata{
+		TipoDocumento:        x.GetTipoDocumento(),
+		RazonSocialCliente:   x.GetRazonSocialCliente(),
+		RUTCliente:           x.GetRutCliente(),
+		EstadoVisado:         x.GetEstadoVisado(),
+		EstadoVigencia:       x.GetEstadoVigencia(),
+		FechaCarga:           x.GetFechaCarga(),
+		NombreDoc:            x.GetNombreDoc(),
+		Categorias:           x.GetCategorias(),
+		SubCategorias:        x.GetSubCategorias(),
+		Origen:               x.GetOrigen(),
+		Relacion:             x.GetRelacion(),
+		FechaTerminoVigencia: x.GetFechaTerminoVigencia(),
+		CmTitle:              x.GetCmTitle(),
+		CmVersionType:        x.GetCmVersionType(),
+		CmVersionLabel:       x.GetCmVersionLabel(),
+		CmDescription:        x.GetCmDescription(),
+		Observaciones:        x.GetObservaciones(),
+	}
+}
+
+// OrdenarMetadata devuelve el mensaje como JSON ordenado, igual que
+// ordenJson.OrdenarDocumentoMetadata con las opciones recibidas.
+func OrdenarMetadata(x *DocumentMetadata, opts ...ordenJson.Option) (string, error) {
+	return ordenJson.OrdenarDocumentoMetadata(x.Metadata(), opts...)
+}
+
+// MetadataDesdeJSON ordena input con las opciones recibidas, de modo que se
+// aplican las mismas validaciones y transformaciones que en
+// ordenJson.OrdenarJSON, y lo convierte en el mensaje. Las claves que no
+// corresponden a un campo de DocumentMetadata se descartan.
+func MetadataDesdeJSON(input interface{}, opts ...ordenJson.Option) (*DocumentMetadata, error) {
+	ordenado, err := ordenJson.OrdenarJSON(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var m ordenJson.DocumentMetadata
+	if err := json.Unmarshal([]byte(ordenado), &m); err != nil {
+		return nil, err
+	}
+	return DesdeMetadata(m), nil
+}
@@ -0,0 +1,309 @@
+// Contrato tipado de los metadatos de un documento, equivalente al struct
+// ordenJson.DocumentMetadata. Los números de campo siguen ordenJson.OrdenCampos
+// y cada json_name es la clave canónica, de modo que protojson escribe los
+// campos con los mismos nombres y en el mismo orden que ordenJson.
+//
+// Para regenerar metadatos.pb.go:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative metadatos.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        (unknown)
+// source: metadatos.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DocumentMetadata struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TipoDocumento        string                 `protobuf:"bytes,1,opt,name=tipo_documento,json=tanner:tipo-documento,proto3" json:"tipo_documento,omitempty"`
+	RazonSocialCliente   string                 `protobuf:"bytes,2,opt,name=razon_social_cliente,json=tanner:razon-social-cliente,proto3" json:"razon_social_cliente,omitempty"`
+	RutCliente           string                 `protobuf:"bytes,3,opt,name=rut_cliente,json=tanner:rut-cliente,proto3" json:"rut_cliente,omitempty"`
+	EstadoVisado         string                 `protobuf:"bytes,4,opt,name=estado_visado,json=tanner:estado-visado,proto3" json:"estado_visado,omitempty"`
+	EstadoVigencia       string                 `protobuf:"bytes,5,opt,name=estado_vigencia,json=tanner:estado-vigencia,proto3" json:"estado_vigencia,omitempty"`
+	FechaCarga           string                 `protobuf:"bytes,6,opt,name=fecha_carga,json=tanner:fecha-carga,proto3" json:"fecha_carga,omitempty"`
+	NombreDoc            string                 `protobuf:"bytes,7,opt,name=nombre_doc,json=tanner:nombre-doc,proto3" json:"nombre_doc,omitempty"`
+	Categorias           string                 `protobuf:"bytes,8,opt,name=categorias,json=tanner:categorias,proto3" json:"categorias,omitempty"`
+	SubCategorias        string                 `protobuf:"bytes,9,opt,name=sub_categorias,json=tanner:sub-categorias,proto3" json:"sub_categorias,omitempty"`
+	Origen               string                 `protobuf:"bytes,10,opt,name=origen,json=tanner:origen,proto3" json:"origen,omitempty"`
+	Relacion             string                 `protobuf:"bytes,11,opt,name=relacion,json=tanner:relacion,proto3" json:"relacion,omitempty"`
+	FechaTerminoVigencia string                 `protobuf:"bytes,12,opt,name=fecha_termino_vigencia,json=tanner:fecha-termino-vigencia,proto3" json:"fecha_termino_vigencia,omitempty"`
+	CmTitle              string                 `protobuf:"bytes,13,opt,name=cm_title,json=cm:title,proto3" json:"cm_title,omitempty"`
+	CmVersionType        string                 `protobuf:"bytes,14,opt,name=cm_version_type,json=cm:versionType,proto3" json:"cm_version_type,omitempty"`
+	CmVersionLabel       string                 `protobuf:"bytes,15,opt,name=cm_version_label,json=cm:versionLabel,proto3" json:"cm_version_label,omitempty"`
+	CmDescription        string                 `protobuf:"bytes,16,opt,name=cm_description,json=cm:description,proto3" json:"cm_description,omitempty"`
+	Observaciones        string                 `protobuf:"bytes,17,opt,name=observaciones,json=tanner:observaciones,proto3" json:"observaciones,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *DocumentMetadata) Reset() {
+	*x = DocumentMetadata{}
+	mi := &file_metadatos_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentMetadata) ProtoMessage() {}
+
+func (x *DocumentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_metadatos_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentMetadata.ProtoReflect.Descriptor instead.
+func (*DocumentMetadata) Descriptor() ([]byte, []int) {
+	return file_metadatos_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DocumentMetadata) GetTipoDocumento() string {
+	if x != nil {
+		return x.TipoDocumento
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetRazonSocialCliente() string {
+	if x != nil {
+		return x.RazonSocialCliente
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetRutCliente() string {
+	if x != nil {
+		return x.RutCliente
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetEstadoVisado() string {
+	if x != nil {
+		return x.EstadoVisado
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetEstadoVigencia() string {
+	if x != nil {
+		return x.EstadoVigencia
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetFechaCarga() string {
+	if x != nil {
+		return x.FechaCarga
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetNombreDoc() string {
+	if x != nil {
+		return x.NombreDoc
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetCategorias() string {
+	if x != nil {
+		return x.Categorias
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetSubCategorias() string {
+	if x != nil {
+		return x.SubCategorias
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetOrigen() string {
+	if x != nil {
+		return x.Origen
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetRelacion() string {
+	if x != nil {
+		return x.Relacion
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetFechaTerminoVigencia() string {
+	if x != nil {
+		return x.FechaTerminoVigencia
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetCmTitle() string {
+	if x != nil {
+		return x.CmTitle
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetCmVersionType() string {
+	if x != nil {
+		return x.CmVersionType
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetCmVersionLabel() string {
+	if x != nil {
+		return x.CmVersionLabel
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetCmDescription() string {
+	if x != nil {
+		return x.CmDescription
+	}
+	return ""
+}
+
+func (x *DocumentMetadata) GetObservaciones() string {
+	if x != nil {
+		return x.Observaciones
+	}
+	return ""
+}
+
+var File_metadatos_proto protoreflect.FileDescriptor
+
+var file_metadatos_proto_rawDesc = string([]byte{
+	0x0a, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x6f, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0c, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x22,
+	0xef, 0x05, 0x0a, 0x10, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x2d, 0x0a, 0x0e, 0x74, 0x69, 0x70, 0x6f, 0x5f, 0x64, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x74, 0x61,
+	0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x74, 0x69, 0x70, 0x6f, 0x2d, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x6f, 0x12, 0x39, 0x0a, 0x14, 0x72, 0x61, 0x7a, 0x6f, 0x6e, 0x5f, 0x73, 0x6f, 0x63,
+	0x69, 0x61, 0x6c, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x1b, 0x74, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x72, 0x61, 0x7a, 0x6f, 0x6e, 0x2d,
+	0x73, 0x6f, 0x63, 0x69, 0x61, 0x6c, 0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x65, 0x12, 0x27,
+	0x0a, 0x0b, 0x72, 0x75, 0x74, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x12, 0x74, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x72, 0x75, 0x74, 0x2d,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x0d, 0x65, 0x73, 0x74, 0x61, 0x64,
+	0x6f, 0x5f, 0x76, 0x69, 0x73, 0x61, 0x64, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14,
+	0x74, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x65, 0x73, 0x74, 0x61, 0x64, 0x6f, 0x2d, 0x76, 0x69,
+	0x73, 0x61, 0x64, 0x6f, 0x12, 0x2f, 0x0a, 0x0f, 0x65, 0x73, 0x74, 0x61, 0x64, 0x6f, 0x5f, 0x76,
+	0x69, 0x67, 0x65, 0x6e, 0x63, 0x69, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x74,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x65, 0x73, 0x74, 0x61, 0x64, 0x6f, 0x2d, 0x76, 0x69, 0x67,
+	0x65, 0x6e, 0x63, 0x69, 0x61, 0x12, 0x27, 0x0a, 0x0b, 0x66, 0x65, 0x63, 0x68, 0x61, 0x5f, 0x63,
+	0x61, 0x72, 0x67, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x74, 0x61, 0x6e, 0x6e,
+	0x65, 0x72, 0x3a, 0x66, 0x65, 0x63, 0x68, 0x61, 0x2d, 0x63, 0x61, 0x72, 0x67, 0x61, 0x12, 0x25,
+	0x0a, 0x0a, 0x6e, 0x6f, 0x6d, 0x62, 0x72, 0x65, 0x5f, 0x64, 0x6f, 0x63, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x11, 0x74, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x6e, 0x6f, 0x6d, 0x62, 0x72,
+	0x65, 0x2d, 0x64, 0x6f, 0x63, 0x12, 0x25, 0x0a, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x69, 0x61, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x74, 0x61, 0x6e, 0x6e, 0x65,
+	0x72, 0x3a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x61, 0x73, 0x12, 0x2d, 0x0a, 0x0e,
+	0x73, 0x75, 0x62, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x61, 0x73, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x74, 0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x73, 0x75, 0x62,
+	0x2d, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x61, 0x73, 0x12, 0x1d, 0x0a, 0x06, 0x6f,
+	0x72, 0x69, 0x67, 0x65, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x3a, 0x6f, 0x72, 0x69, 0x67, 0x65, 0x6e, 0x12, 0x21, 0x0a, 0x08, 0x72, 0x65,
+	0x6c, 0x61, 0x63, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x61,
+	0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x72, 0x65, 0x6c, 0x61, 0x63, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a,
+	0x16, 0x66, 0x65, 0x63, 0x68, 0x61, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x6f, 0x5f, 0x76,
+	0x69, 0x67, 0x65, 0x6e, 0x63, 0x69, 0x61, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1d, 0x74,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x3a, 0x66, 0x65, 0x63, 0x68, 0x61, 0x2d, 0x74, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x6f, 0x2d, 0x76, 0x69, 0x67, 0x65, 0x6e, 0x63, 0x69, 0x61, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x6d, 0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x6d, 0x3a, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6d, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x63, 0x6d, 0x3a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6d, 0x3a,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x26, 0x0a, 0x0e,
+	0x63, 0x6d, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6d, 0x3a, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x63,
+	0x69, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x74, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x3a, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x63, 0x69, 0x6f, 0x6e, 0x65,
+	0x73, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x61, 0x6d, 0x75, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x75, 0x65, 0x62, 0x61, 0x2d, 0x6f, 0x72,
+	0x64, 0x65, 0x6e, 0x2f, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x4a, 0x73, 0x6f, 0x6e, 0x2f, 0x72, 0x70,
+	0x63, 0x3b, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_metadatos_proto_rawDescOnce sync.Once
+	file_metadatos_proto_rawDescData []byte
+)
+
+func file_metadatos_proto_rawDescGZIP() []byte {
+	file_metadatos_proto_rawDescOnce.Do(func() {
+		file_metadatos_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_metadatos_proto_rawDesc), len(file_metadatos_proto_rawDesc)))
+	})
+	return file_metadatos_proto_rawDescData
+}
+
+var file_metadatos_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_metadatos_proto_goTypes = []any{
+	(*DocumentMetadata)(nil), // 0: ordenjson.v1.DocumentMetadata
+}
+var file_metadatos_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_metadatos_proto_init() }
+func file_metadatos_proto_init() {
+	if File_metadatos_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metadatos_proto_rawDesc), len(file_metadatos_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_metadatos_proto_goTypes,
+		DependencyIndexes: file_metadatos_proto_depIdxs,
+		MessageInfos:      file_metadatos_proto_msgTypes,
+	}.Build()
+	File_metadatos_proto = out.File
+	file_metadatos_proto_goTypes = nil
+	file_metadatos_proto_depIdxs = nil
+}
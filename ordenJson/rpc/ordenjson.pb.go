@@ -0,0 +1,301 @@
+// Servicio gRPC de ordenamiento. Las respuestas usan el mismo orden canónico
+// que ordena-json y el servidor HTTP ordenad.
+//
+// Para regenerar ordenjson.pb.go y ordenjson_grpc.pb.go:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	  --go-grpc_out=. --go-grpc_opt=paths=source_relative ordenjson.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        (unknown)
+// source: ordenjson.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OrdenarRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Documento JSON a ordenar.
+	Documento []byte `protobuf:"bytes,1,opt,name=documento,proto3" json:"documento,omitempty"`
+	// Perfil con que se ordena; vacío usa el perfil por defecto del servidor.
+	Perfil string `protobuf:"bytes,2,opt,name=perfil,proto3" json:"perfil,omitempty"`
+	// Identificador opcional que se devuelve en la respuesta.
+	Id            string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrdenarRequest) Reset() {
+	*x = OrdenarRequest{}
+	mi := &file_ordenjson_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrdenarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdenarRequest) ProtoMessage() {}
+
+func (x *OrdenarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ordenjson_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdenarRequest.ProtoReflect.Descriptor instead.
+func (*OrdenarRequest) Descriptor() ([]byte, []int) {
+	return file_ordenjson_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrdenarRequest) GetDocumento() []byte {
+	if x != nil {
+		return x.Documento
+	}
+	return nil
+}
+
+func (x *OrdenarRequest) GetPerfil() string {
+	if x != nil {
+		return x.Perfil
+	}
+	return ""
+}
+
+func (x *OrdenarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type OrdenarResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identificador recibido en la solicitud.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Documento ordenado; vacío si hubo un error.
+	Documento []byte `protobuf:"bytes,2,opt,name=documento,proto3" json:"documento,omitempty"`
+	// Solo en OrdenarLote, el error del documento.
+	Error         *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrdenarResponse) Reset() {
+	*x = OrdenarResponse{}
+	mi := &file_ordenjson_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrdenarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdenarResponse) ProtoMessage() {}
+
+func (x *OrdenarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ordenjson_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdenarResponse.ProtoReflect.Descriptor instead.
+func (*OrdenarResponse) Descriptor() ([]byte, []int) {
+	return file_ordenjson_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrdenarResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrdenarResponse) GetDocumento() []byte {
+	if x != nil {
+		return x.Documento
+	}
+	return nil
+}
+
+func (x *OrdenarResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tipo de falla: "json-invalido", "validacion", "perfil" u "otro".
+	Tipo          string `protobuf:"bytes,1,opt,name=tipo,proto3" json:"tipo,omitempty"`
+	Mensaje       string `protobuf:"bytes,2,opt,name=mensaje,proto3" json:"mensaje,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_ordenjson_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_ordenjson_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_ordenjson_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Error) GetTipo() string {
+	if x != nil {
+		return x.Tipo
+	}
+	return ""
+}
+
+func (x *Error) GetMensaje() string {
+	if x != nil {
+		return x.Mensaje
+	}
+	return ""
+}
+
+var File_ordenjson_proto protoreflect.FileDescriptor
+
+var file_ordenjson_proto_rawDesc = string([]byte{
+	0x0a, 0x0f, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0c, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x22,
+	0x56, 0x0a, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x6e, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x6f, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x6f, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x6a, 0x0a, 0x0f, 0x4f, 0x72, 0x64, 0x65, 0x6e,
+	0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x64,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x6f, 0x12, 0x29, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a,
+	0x73, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0x35, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x70, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x70, 0x6f,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x6e, 0x73, 0x61, 0x6a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x6e, 0x73, 0x61, 0x6a, 0x65, 0x32, 0xa3, 0x01, 0x0a, 0x09, 0x4f,
+	0x72, 0x64, 0x65, 0x6e, 0x61, 0x64, 0x6f, 0x72, 0x12, 0x46, 0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65,
+	0x6e, 0x61, 0x72, 0x12, 0x1c, 0x2e, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x6e, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x4f, 0x72, 0x64, 0x65, 0x6e, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x4e, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x6e, 0x61, 0x72, 0x4c, 0x6f, 0x74, 0x65, 0x12,
+	0x1c, 0x2e, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f,
+	0x72, 0x64, 0x65, 0x6e, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
+	0x6f, 0x72, 0x64, 0x65, 0x6e, 0x6a, 0x73, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x72, 0x64,
+	0x65, 0x6e, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01,
+	0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73,
+	0x61, 0x6d, 0x75, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x75, 0x65, 0x62, 0x61, 0x2d, 0x6f, 0x72, 0x64,
+	0x65, 0x6e, 0x2f, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x4a, 0x73, 0x6f, 0x6e, 0x2f, 0x72, 0x70, 0x63,
+	0x3b, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_ordenjson_proto_rawDescOnce sync.Once
+	file_ordenjson_proto_rawDescData []byte
+)
+
+func file_ordenjson_proto_rawDescGZIP() []byte {
+	file_ordenjson_proto_rawDescOnce.Do(func() {
+		file_ordenjson_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ordenjson_proto_rawDesc), len(file_ordenjson_proto_rawDesc)))
+	})
+	return file_ordenjson_proto_rawDescData
+}
+
+var file_ordenjson_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_ordenjson_proto_goTypes = []any{
+	(*OrdenarRequest)(nil),  // 0: ordenjson.v1.OrdenarRequest
+	(*OrdenarResponse)(nil), // 1: ordenjson.v1.OrdenarResponse
+	(*Error)(nil),           // 2: ordenjson.v1.Error
+}
+var file_ordenjson_proto_depIdxs = []int32{
+	2, // 0: ordenjson.v1.OrdenarResponse.error:type_name -> ordenjson.v1.Error
+	0, // 1: ordenjson.v1.Ordenador.Ordenar:input_type -> ordenjson.v1.OrdenarRequest
+	0, // 2: ordenjson.v1.Ordenador.OrdenarLote:input_type -> ordenjson.v1.OrdenarRequest
+	1, // 3: ordenjson.v1.Ordenador.Ordenar:output_type -> ordenjson.v1.OrdenarResponse
+	1, // 4: ordenjson.v1.Ordenador.OrdenarLote:output_type -> ordenjson.v1.OrdenarResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_ordenjson_proto_init() }
+func file_ordenjson_proto_init() {
+	if File_ordenjson_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ordenjson_proto_rawDesc), len(file_ordenjson_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ordenjson_proto_goTypes,
+		DependencyIndexes: file_ordenjson_proto_depIdxs,
+		MessageInfos:      file_ordenjson_proto_msgTypes,
+	}.Build()
+	File_ordenjson_proto = out.File
+	file_ordenjson_proto_goTypes = nil
+	file_ordenjson_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+// Servicio gRPC de ordenamiento. Las respuestas usan el mismo orden canónico
+// que ordena-json y el servidor HTTP ordenad.
+//
+// Para regenerar ordenjson.pb.go y ordenjson_grpc.pb.go:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	  --go-grpc_out=. --go-grpc_opt=paths=source_relative ordenjson.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: ordenjson.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Ordenador_Ordenar_FullMethodName     = "/ordenjson.v1.Ordenador/Ordenar"
+	Ordenador_OrdenarLote_FullMethodName = "/ordenjson.v1.Ordenador/OrdenarLote"
+)
+
+// OrdenadorClient is the client API for Ordenador service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrdenadorClient interface {
+	// Ordenar ordena un documento. Si no se puede ordenar devuelve un error
+	// INVALID_ARGUMENT (JSON inválido), FAILED_PRECONDITION (validación),
+	// NOT_FOUND (perfil desconocido) o DEADLINE_EXCEEDED (presupuesto agotado).
+	Ordenar(ctx context.Context, in *OrdenarRequest, opts ...grpc.CallOption) (*OrdenarResponse, error)
+	// OrdenarLote ordena un flujo de documentos. Cada respuesta corresponde, en
+	// el mismo orden, a una solicitud; un documento que no se puede ordenar se
+	// informa en OrdenarResponse.error sin cortar el flujo.
+	OrdenarLote(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[OrdenarRequest, OrdenarResponse], error)
+}
+
+type ordenadorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrdenadorClient(cc grpc.ClientConnInterface) OrdenadorClient {
+	return &ordenadorClient{cc}
+}
+
+func (c *ordenadorClient) Ordenar(ctx context.Context, in *OrdenarRequest, opts ...grpc.CallOption) (*OrdenarResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrdenarResponse)
+	err := c.cc.Invoke(ctx, Ordenador_Ordenar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordenadorClient) OrdenarLote(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[OrdenarRequest, OrdenarResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Ordenador_ServiceDesc.Streams[0], Ordenador_OrdenarLote_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[OrdenarRequest, OrdenarResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ordenador_OrdenarLoteClient = grpc.BidiStreamingClient[OrdenarRequest, OrdenarResponse]
+
+// OrdenadorServer is the server API for Ordenador service.
+// All implementations must embed UnimplementedOrdenadorServer
+// for forward compatibility.
+type OrdenadorServer interface {
+	// Ordenar ordena un documento. Si no se puede ordenar devuelve un error
+	// INVALID_ARGUMENT (JSON inválido), FAILED_PRECONDITION (validación),
+	// NOT_FOUND (perfil desconocido) o DEADLINE_EXCEEDED (presupuesto agotado).
+	Ordenar(context.Context, *OrdenarRequest) (*OrdenarResponse, error)
+	// OrdenarLote ordena un flujo de documentos. Cada respuesta corresponde, en
+	// el mismo orden, a una solicitud; un documento que no se puede ordenar se
+	// informa en OrdenarResponse.error sin cortar el flujo.
+	OrdenarLote(grpc.BidiStreamingServer[OrdenarRequest, OrdenarResponse]) error
+	mustEmbedUnimplementedOrdenadorServer()
+}
+
+// UnimplementedOrdenadorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrdenadorServer struct{}
+
+func (UnimplementedOrdenadorServer) Ordenar(context.Context, *OrdenarRequest) (*OrdenarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ordenar not implemented")
+}
+func (UnimplementedOrdenadorServer) OrdenarLote(grpc.BidiStreamingServer[OrdenarRequest, OrdenarResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method OrdenarLote not implemented")
+}
+func (UnimplementedOrdenadorServer) mustEmbedUnimplementedOrdenadorServer() {}
+func (UnimplementedOrdenadorServer) testEmbeddedByValue()                   {}
+
+// UnsafeOrdenadorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrdenadorServer will
+// result in compilation errors.
+type UnsafeOrdenadorServer interface {
+	mustEmbedUnimplementedOrdenadorServer()
+}
+
+func RegisterOrdenadorServer(s grpc.ServiceRegistrar, srv OrdenadorServer) {
+	// If the following call pancis, it indicates UnimplementedOrdenadorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Ordenador_ServiceDesc, srv)
+}
+
+func _Ordenador_Ordenar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrdenarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdenadorServer).Ordenar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ordenador_Ordenar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdenadorServer).Ordenar(ctx, req.(*OrdenarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ordenador_OrdenarLote_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrdenadorServer).OrdenarLote(&grpc.GenericServerStream[OrdenarRequest, OrdenarResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ordenador_OrdenarLoteServer = grpc.BidiStreamingServer[OrdenarRequest, OrdenarResponse]
+
+// Ordenador_ServiceDesc is the grpc.ServiceDesc for Ordenador service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Ordenador_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ordenjson.v1.Ordenador",
+	HandlerType: (*OrdenadorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ordenar",
+			Handler:    _Ordenador_Ordenar_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "OrdenarLote",
+			Handler:       _Ordenador_OrdenarLote_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ordenjson.proto",
+}
@@ -0,0 +1,106 @@
+// Package rpc expone el ordenamiento como servicio gRPC (ver ordenjson.proto)
+// con los mismos perfiles que el servidor HTTP, e incluye un cliente para
+// ordenar documentos sueltos o lotes grandes en un flujo.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/servidor"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// TipoPerfil es el tipo de Error de un documento que pidió un perfil que no
+// está configurado.
+const TipoPerfil = "perfil"
+
+// Servidor implementa OrdenadorServer con los perfiles de un servidor.Servidor.
+type Servidor struct {
+	UnimplementedOrdenadorServer
+	perfiles *servidor.Servidor
+}
+
+// NuevoServidor crea el servicio gRPC que atiende con los perfiles de perfiles.
+func NuevoServidor(perfiles *servidor.Servidor) *Servidor {
+	return &Servidor{perfiles: perfiles}
+}
+
+// Registrar crea un *grpc.Server con el servicio registrado y con el tamaño
+// máximo de mensaje ajustado al de perfiles.
+func Registrar(perfiles *servidor.Servidor, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.MaxRecvMsgSize(int(perfiles.TamanoMaximo()) + 1024)}, opts...)
+	s := grpc.NewServer(opts...)
+	RegisterOrdenadorServer(s, NuevoServidor(perfiles))
+	return s
+}
+
+// Ordenar implementa OrdenadorServer.
+func (s *Servidor) Ordenar(ctx context.Context, req *OrdenarRequest) (*OrdenarResponse, error) {
+	documento, falla, codigo := s.ordenar(ctx, req)
+	if falla != nil {
+		return nil, status.Error(codigo, falla.Mensaje)
+	}
+	return &OrdenarResponse{Id: req.Id, Documento: documento}, nil
+}
+
+// OrdenarLote implementa OrdenadorServer.
+func (s *Servidor) OrdenarLote(flujo grpc.BidiStreamingServer[OrdenarRequest, OrdenarResponse]) error {
+	for {
+		req, err := flujo.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		documento, falla, _ := s.ordenar(flujo.Context(), req)
+		if err := flujo.Send(&OrdenarResponse{Id: req.Id, Documento: documento, Error: falla}); err != nil {
+			return err
+		}
+	}
+}
+
+// ordenar ordena el documento de req con el perfil solicitado y el contexto
+// de la llamada. Si falla devuelve la descripción del error y el código gRPC
+// que le corresponde.
+func (s *Servidor) ordenar(ctx context.Context, req *OrdenarRequest) ([]byte, *Error, codes.Code) {
+	ordenador, ok := s.perfiles.Ordenador(req.Perfil)
+	if !ok {
+		return nil, &Error{Tipo: TipoPerfil, Mensaje: fmt.Sprintf("perfil %q no configurado", req.Perfil)}, codes.NotFound
+	}
+	if maximo := s.perfiles.TamanoMaximo(); int64(len(req.Documento)) > maximo {
+		return nil, &Error{Tipo: cuarentena.TipoOtro, Mensaje: fmt.Sprintf("el documento supera el máximo de %d bytes", maximo)}, codes.ResourceExhausted
+	}
+	ordenado, err := ordenador.OrdenarJSONCtx(ctx, string(req.Documento))
+	if err != nil {
+		return nil, &Error{Tipo: cuarentena.Clasificar(err), Mensaje: err.Error()}, codigoDeError(err)
+	}
+	return []byte(ordenado), nil, codes.OK
+}
+
+// codigoDeError devuelve el código gRPC que corresponde a un error de ordenamiento.
+func codigoDeError(err error) codes.Code {
+	var errTiempo *ordenJson.ErrorTiempoExcedido
+	var errLimite *ordenJson.ErrorLimiteExcedido
+	switch {
+	case errors.As(err, &errTiempo), errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.As(err, &errLimite):
+		return codes.ResourceExhausted
+	case cuarentena.Clasificar(err) == cuarentena.TipoJSONInvalido:
+		return codes.InvalidArgument
+	case cuarentena.Clasificar(err) == cuarentena.TipoValidacion:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
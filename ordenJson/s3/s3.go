@@ -0,0 +1,294 @@
+// Package s3 normaliza en lote los documentos JSON guardados en un bucket de
+// S3: lista los objetos de un prefijo, ordena cada uno y escribe el resultado
+// bajo otro prefijo o en el mismo objeto.
+//
+//	resumen, err := s3.Normalizar(ctx, s3.Config{
+//		Cliente: awss3.NewFromConfig(awsCfg),
+//		Bucket:  "documentos",
+//		Prefijo: "entrada/",
+//		Destino: "ordenados/",
+//		Progreso: func(ultima string) { guardarPuntoDeControl(ultima) },
+//	})
+//
+// Los objetos se procesan en paralelo. Para buckets con millones de objetos,
+// Config.Progreso informa la última clave hasta la cual todo está procesado;
+// si la ejecución se interrumpe, se retoma pasándola en Config.Desde.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// ConcurrenciaPorDefecto es la cantidad de objetos que se procesan a la vez
+// cuando Config.Concurrencia es cero.
+const ConcurrenciaPorDefecto = 16
+
+// objetosEntreProgresos es la cantidad de objetos procesados entre dos
+// llamadas a Config.Progreso.
+const objetosEntreProgresos = 1000
+
+// API es la parte de *s3.Client que usa Normalizar.
+type API interface {
+	ListObjectsV2(ctx context.Context, params *awss3.ListObjectsV2Input, optFns ...func(*awss3.Options)) (*awss3.ListObjectsV2Output, error)
+	GetObject(ctx context.Context, params *awss3.GetObjectInput, optFns ...func(*awss3.Options)) (*awss3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *awss3.PutObjectInput, optFns ...func(*awss3.Options)) (*awss3.PutObjectOutput, error)
+}
+
+// Config describe qué objetos normaliza Normalizar y dónde escribe el resultado.
+type Config struct {
+	Cliente API
+	Bucket  string
+	// Prefijo limita los objetos a los que comienzan con él. Solo se procesan
+	// las claves terminadas en ".json".
+	Prefijo string
+	// Destino es el prefijo bajo el que se escribe cada objeto ordenado, en
+	// lugar de Prefijo. Si está vacío, cada objeto se reescribe en el lugar,
+	// solo si cambió y solo si nadie lo modificó desde que se leyó (If-Match).
+	Destino string
+	// Ordenador ordena los documentos; nil usa ordenJson.Nuevo().
+	Ordenador *ordenJson.Ordenador
+	// Concurrencia es la cantidad de objetos que se procesan a la vez; 0 usa
+	// ConcurrenciaPorDefecto.
+	Concurrencia int
+	// Desde retoma una ejecución anterior: solo se procesan las claves
+	// posteriores a ella, en el orden de S3.
+	Desde string
+	// Progreso, si no es nil, recibe periódicamente y al terminar la última
+	// clave hasta la cual todos los objetos están procesados, para guardarla
+	// y usarla como Desde.
+	Progreso func(ultima string)
+	// AlFallar, si no es nil, recibe cada objeto que no se pudo ordenar. Esos
+	// objetos se cuentan en Resumen.Fallidos y no detienen la ejecución. Se
+	// llama desde varias goroutines a la vez.
+	AlFallar func(clave string, err error)
+}
+
+// Resumen cuenta los objetos que procesó Normalizar.
+type Resumen struct {
+	Escritos   int64  // Ordenados y escritos.
+	SinCambios int64  // Ya estaban ordenados; en el lugar no se reescriben.
+	Fallidos   int64  // No se pudieron ordenar; ver Config.AlFallar.
+	Omitidos   int64  // No terminan en ".json".
+	Ultima     string // Última clave hasta la cual todo está procesado.
+}
+
+// trabajo es un objeto listado, numerado en el orden de S3.
+type trabajo struct {
+	n     int
+	clave string
+}
+
+// resultado es el desenlace de procesar un trabajo.
+type resultado struct {
+	trabajo
+	estado int // Uno de los estados de abajo.
+	err    error
+}
+
+const (
+	estadoEscrito = iota
+	estadoSinCambios
+	estadoFallido
+	estadoOmitido
+)
+
+// Normalizar ordena los objetos descritos por cfg. Se detiene ante el primer
+// error al listar, leer o escribir en S3, o si ctx termina, y devuelve ese
+// error junto con lo procesado hasta entonces; Resumen.Ultima indica desde
+// dónde retomar.
+func Normalizar(ctx context.Context, cfg Config) (Resumen, error) {
+	if cfg.Cliente == nil || cfg.Bucket == "" {
+		return Resumen{}, errors.New("s3: Config.Cliente y Config.Bucket son obligatorios")
+	}
+	if cfg.Destino != "" && cfg.Destino != cfg.Prefijo && strings.HasPrefix(cfg.Destino, cfg.Prefijo) {
+		return Resumen{}, fmt.Errorf("s3: el destino %q está dentro del prefijo %q y se volvería a listar", cfg.Destino, cfg.Prefijo)
+	}
+	if cfg.Destino == cfg.Prefijo {
+		cfg.Destino = ""
+	}
+	if cfg.Ordenador == nil {
+		cfg.Ordenador = ordenJson.Nuevo()
+	}
+	if cfg.Concurrencia <= 0 {
+		cfg.Concurrencia = ConcurrenciaPorDefecto
+	}
+
+	padre := ctx
+	ctx, cancelar := context.WithCancel(ctx)
+	defer cancelar()
+	trabajos := make(chan trabajo, cfg.Concurrencia)
+	resultados := make(chan resultado, cfg.Concurrencia)
+
+	listado := make(chan error, 1)
+	go func() {
+		defer close(trabajos)
+		listado <- listar(ctx, cfg, trabajos)
+	}()
+
+	var grupo sync.WaitGroup
+	for i := 0; i < cfg.Concurrencia; i++ {
+		grupo.Add(1)
+		go func() {
+			defer grupo.Done()
+			for t := range trabajos {
+				resultados <- procesar(ctx, cfg, t)
+			}
+		}()
+	}
+	go func() {
+		grupo.Wait()
+		close(resultados)
+	}()
+
+	// Avanzar el punto de control solo cuando todos los objetos anteriores
+	// terminaron, aunque terminen fuera de orden.
+	var resumen Resumen
+	resumen.Ultima = cfg.Desde
+	claves := map[int]string{}
+	terminados := map[int]bool{}
+	siguiente, desdeProgreso := 0, 0
+	var primerError error
+	for r := range resultados {
+		switch r.estado {
+		case estadoEscrito:
+			resumen.Escritos++
+		case estadoSinCambios:
+			resumen.SinCambios++
+		case estadoOmitido:
+			resumen.Omitidos++
+		case estadoFallido:
+			if !errors.Is(r.err, errDocumento) {
+				// Error de S3: detener todo sin marcar el objeto como procesado.
+				if primerError == nil {
+					primerError = r.err
+					cancelar()
+				}
+				continue
+			}
+			resumen.Fallidos++
+		}
+		terminados[r.n] = true
+		claves[r.n] = r.clave
+		for terminados[siguiente] {
+			resumen.Ultima = claves[siguiente]
+			delete(terminados, siguiente)
+			delete(claves, siguiente)
+			siguiente++
+			desdeProgreso++
+		}
+		if cfg.Progreso != nil && desdeProgreso >= objetosEntreProgresos {
+			cfg.Progreso(resumen.Ultima)
+			desdeProgreso = 0
+		}
+	}
+	errListado := <-listado
+	if cfg.Progreso != nil {
+		cfg.Progreso(resumen.Ultima)
+	}
+	if primerError == nil {
+		primerError = errListado
+	}
+	if primerError == nil {
+		primerError = padre.Err()
+	}
+	return resumen, primerError
+}
+
+// errDocumento envuelve los errores de ordenamiento, que no detienen Normalizar.
+// Las cancelaciones no se envuelven: el objeto no se cuenta como procesado.
+var errDocumento = errors.New("documento no ordenable")
+
+// listar envía a trabajos, numeradas, las claves del prefijo posteriores a
+// cfg.Desde.
+func listar(ctx context.Context, cfg Config, trabajos chan<- trabajo) error {
+	entrada := &awss3.ListObjectsV2Input{Bucket: aws.String(cfg.Bucket), Prefix: aws.String(cfg.Prefijo)}
+	if cfg.Desde != "" {
+		entrada.StartAfter = aws.String(cfg.Desde)
+	}
+	n := 0
+	for {
+		pagina, err := cfg.Cliente.ListObjectsV2(ctx, entrada)
+		if err != nil {
+			return fmt.Errorf("s3: listando %s/%s: %w", cfg.Bucket, cfg.Prefijo, err)
+		}
+		for _, objeto := range pagina.Contents {
+			select {
+			case trabajos <- trabajo{n: n, clave: aws.ToString(objeto.Key)}:
+				n++
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if !aws.ToBool(pagina.IsTruncated) {
+			return nil
+		}
+		entrada.ContinuationToken = pagina.NextContinuationToken
+	}
+}
+
+// procesar ordena y escribe un objeto.
+func procesar(ctx context.Context, cfg Config, t trabajo) resultado {
+	r := resultado{trabajo: t, estado: estadoFallido}
+	if !strings.HasSuffix(strings.ToLower(t.clave), ".json") {
+		r.estado = estadoOmitido
+		return r
+	}
+	objeto, err := cfg.Cliente.GetObject(ctx, &awss3.GetObjectInput{Bucket: aws.String(cfg.Bucket), Key: aws.String(t.clave)})
+	if err != nil {
+		r.err = fmt.Errorf("s3: leyendo %s: %w", t.clave, err)
+		return r
+	}
+	original, err := io.ReadAll(objeto.Body)
+	objeto.Body.Close()
+	if err != nil {
+		r.err = fmt.Errorf("s3: leyendo %s: %w", t.clave, err)
+		return r
+	}
+
+	ordenado, err := cfg.Ordenador.OrdenarJSONCtx(ctx, string(original))
+	var errCancelado *ordenJson.ErrorCancelado
+	if errors.As(err, &errCancelado) || (err != nil && ctx.Err() != nil) {
+		// La ejecución se canceló: el objeto no quedó procesado.
+		r.err = fmt.Errorf("s3: ordenando %s: %w", t.clave, err)
+		return r
+	}
+	if err != nil {
+		if cfg.AlFallar != nil {
+			cfg.AlFallar(t.clave, err)
+		}
+		r.err = fmt.Errorf("%w: %w", errDocumento, err)
+		return r
+	}
+
+	salida := &awss3.PutObjectInput{
+		Bucket:      aws.String(cfg.Bucket),
+		Key:         aws.String(cfg.Destino + strings.TrimPrefix(t.clave, cfg.Prefijo)),
+		Body:        bytes.NewReader([]byte(ordenado)),
+		ContentType: aws.String("application/json"),
+	}
+	if cfg.Destino == "" {
+		if ordenado == string(original) {
+			r.estado = estadoSinCambios
+			return r
+		}
+		salida.Key = aws.String(t.clave)
+		salida.IfMatch = objeto.ETag
+	}
+	if _, err := cfg.Cliente.PutObject(ctx, salida); err != nil {
+		r.err = fmt.Errorf("s3: escribiendo %s: %w", aws.ToString(salida.Key), err)
+		return r
+	}
+	r.estado = estadoEscrito
+	return r
+}
@@ -0,0 +1,86 @@
+package servidor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// TipoFlujoNDJSON es el Content-Type de la respuesta de POST /flujo.
+const TipoFlujoNDJSON = "application/x-ndjson"
+
+// ResultadoFlujo es cada línea de la respuesta de POST /flujo.
+type ResultadoFlujo struct {
+	Indice    int             `json:"indice"`              // Posición del documento en la entrada, desde 0, sin contar las líneas vacías.
+	Documento json.RawMessage `json:"documento,omitempty"` // Documento ordenado y compacto.
+	Error     *RespuestaError `json:"error,omitempty"`
+}
+
+// atenderFlujo implementa POST /flujo. El cuerpo es una secuencia de
+// documentos JSON, uno por línea (NDJSON), y la respuesta, también NDJSON,
+// trae un ResultadoFlujo por documento en el mismo orden. Cada resultado se
+// envía en cuanto se procesa su documento, sin esperar al final de la
+// entrada, por lo que un cliente puede mantener la solicitud abierta e ir
+// enviando documentos a medida que llegan.
+//
+// Un documento inválido produce un resultado con Error y no interrumpe el
+// flujo. Config.TamanoMaximo limita cada línea, no el cuerpo completo; una
+// línea más larga termina el flujo con un último resultado de error.
+func (s *Servidor) atenderFlujo(w http.ResponseWriter, r *http.Request) {
+	ordenador, ok := s.perfilSolicitado(w, r)
+	if !ok {
+		return
+	}
+	control := http.NewResponseController(w)
+	// Con HTTP/1.x el servidor deja de leer el cuerpo al empezar a responder
+	// salvo que se habilite el modo full duplex; HTTP/2 no lo necesita.
+	control.EnableFullDuplex()
+	w.Header().Set("Content-Type", TipoFlujoNDJSON)
+	w.WriteHeader(http.StatusOK)
+	control.Flush()
+
+	maximo := int(s.cfg.TamanoMaximo)
+	lector := bufio.NewScanner(r.Body)
+	lector.Buffer(make([]byte, 0, min(64*1024, maximo)), maximo)
+	codificador := json.NewEncoder(w)
+	indice := 0
+	for lector.Scan() {
+		if r.Context().Err() != nil {
+			return // El cliente canceló la solicitud.
+		}
+		linea := bytes.TrimSpace(lector.Bytes())
+		if len(linea) == 0 {
+			continue
+		}
+		if err := codificador.Encode(ordenarLinea(r.Context(), ordenador, indice, linea)); err != nil {
+			return // El cliente cerró la conexión.
+		}
+		control.Flush()
+		indice++
+	}
+	if err := lector.Err(); err != nil {
+		mensaje := err.Error()
+		if errors.Is(err, bufio.ErrTooLong) {
+			mensaje = fmt.Sprintf("documento de más de %d bytes", s.cfg.TamanoMaximo)
+		}
+		codificador.Encode(ResultadoFlujo{Indice: indice, Error: &RespuestaError{Error: mensaje}})
+	}
+}
+
+// ordenarLinea ordena un documento del flujo con el contexto de la solicitud.
+// json.Encoder compacta el documento ordenado al escribirlo, sin alterar el
+// orden de las claves.
+func ordenarLinea(ctx context.Context, ordenador *ordenJson.Ordenador, indice int, linea []byte) ResultadoFlujo {
+	ordenado, err := ordenador.OrdenarJSONCtx(ctx, string(linea))
+	if err != nil {
+		return ResultadoFlujo{Indice: indice, Error: &RespuestaError{Error: err.Error(), Tipo: cuarentena.Clasificar(err)}}
+	}
+	return ResultadoFlujo{Indice: indice, Documento: json.RawMessage(ordenado)}
+}
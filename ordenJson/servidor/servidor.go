@@ -0,0 +1,307 @@
+// Package servidor implementa el modo servidor de ordenJson: un http.Handler
+// que expone los perfiles configurados a servicios que no están escritos en
+// Go, para que todos produzcan el mismo orden canónico. Rutas:
+//
+//	POST /ordenar[/{perfil}]   cuerpo JSON de entrada, documento ordenado de salida
+//	POST /validar[/{perfil}]   devuelve el ordenJson.Reporte del documento
+//	POST /flujo[/{perfil}]     documentos NDJSON de entrada, resultados NDJSON a medida que se procesan
+//	GET  /capacidades          ver Capacidades
+//	GET  /info                 versión y datos de compilación, ver ordenJson.Version
+//
+// Sin {perfil} se usa el del parámetro ?perfil= o, si no, Config.PerfilPorDefecto.
+// /ordenar acepta ?formato= con cualquiera de formatos.Formatos. Los errores se
+// responden como JSON con la forma de RespuestaError: 400 si el cuerpo no es
+// JSON válido, 404 si el perfil no existe, 413 si el cuerpo supera
+// Config.TamanoMaximo o el documento supera los límites de WithMaxBytes o
+// WithMaxDepth del perfil, 422 si el documento no cumple las validaciones del
+// perfil y 503 si se agotó el presupuesto de tiempo del perfil o terminó el
+// contexto de la solicitud, por ejemplo porque el cliente la canceló.
+//
+// GET /capacidades describe lo que admite el despliegue (formatos, perfiles,
+// validaciones y límites) para que los clientes se adapten sin tener que
+// conocerlo de antemano; ver Capacidades.
+package servidor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/formatos"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// TamanoMaximoPorDefecto es el tamaño máximo, en bytes, del cuerpo de una
+// solicitud cuando Config.TamanoMaximo es cero.
+const TamanoMaximoPorDefecto = 1 << 20
+
+// Config describe los perfiles y límites de un Servidor.
+type Config struct {
+	// Perfiles asocia a cada nombre el Ordenador con que se atiende. Si está
+	// vacío se usa un único perfil con el nombre y la configuración por
+	// defecto de ordenJson.
+	Perfiles map[string]*ordenJson.Ordenador
+	// PerfilPorDefecto es el perfil que se usa cuando la solicitud no indica
+	// uno. Puede omitirse si hay un solo perfil.
+	PerfilPorDefecto string
+	// TamanoMaximo limita el cuerpo de cada solicitud; 0 usa TamanoMaximoPorDefecto.
+	TamanoMaximo int64
+}
+
+// Servidor atiende las solicitudes del modo servidor. Es seguro usarlo desde
+// varias goroutines a la vez.
+type Servidor struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+// Nuevo crea un Servidor con la configuración recibida. Devuelve error si
+// PerfilPorDefecto no existe o, habiendo varios perfiles, no se indicó.
+func Nuevo(cfg Config) (*Servidor, error) {
+	if len(cfg.Perfiles) == 0 {
+		cfg.Perfiles = map[string]*ordenJson.Ordenador{ordenJson.PerfilPorDefecto.Nombre(): ordenJson.Nuevo()}
+	}
+	if cfg.PerfilPorDefecto == "" && len(cfg.Perfiles) == 1 {
+		for nombre := range cfg.Perfiles {
+			cfg.PerfilPorDefecto = nombre
+		}
+	}
+	if _, ok := cfg.Perfiles[cfg.PerfilPorDefecto]; !ok {
+		return nil, fmt.Errorf("perfil por defecto %q no configurado", cfg.PerfilPorDefecto)
+	}
+	if cfg.TamanoMaximo <= 0 {
+		cfg.TamanoMaximo = TamanoMaximoPorDefecto
+	}
+
+	s := &Servidor{cfg: cfg, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /ordenar", s.atenderOrdenar)
+	s.mux.HandleFunc("POST /ordenar/{perfil}", s.atenderOrdenar)
+	s.mux.HandleFunc("POST /validar", s.atenderValidar)
+	s.mux.HandleFunc("POST /validar/{perfil}", s.atenderValidar)
+	s.mux.HandleFunc("POST /flujo", s.atenderFlujo)
+	s.mux.HandleFunc("POST /flujo/{perfil}", s.atenderFlujo)
+	s.mux.HandleFunc("GET /capacidades", s.atenderCapacidades)
+	s.mux.HandleFunc("GET /info", s.atenderInfo)
+	return s, nil
+}
+
+// Ordenador devuelve el Ordenador del perfil nombre o, si nombre es vacío, el
+// del perfil por defecto. Permite atender otros protocolos con los mismos
+// perfiles.
+func (s *Servidor) Ordenador(nombre string) (*ordenJson.Ordenador, bool) {
+	if nombre == "" {
+		nombre = s.cfg.PerfilPorDefecto
+	}
+	ordenador, ok := s.cfg.Perfiles[nombre]
+	return ordenador, ok
+}
+
+// TamanoMaximo devuelve el tamaño máximo, en bytes, de un documento.
+func (s *Servidor) TamanoMaximo() int64 {
+	return s.cfg.TamanoMaximo
+}
+
+// ServeHTTP atiende una solicitud.
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// RespuestaError es el cuerpo de las respuestas de error.
+type RespuestaError struct {
+	Error string `json:"error"`
+	Tipo  string `json:"tipo,omitempty"` // Para errores del documento, ver las constantes cuarentena.Tipo*.
+}
+
+// atenderOrdenar implementa POST /ordenar.
+func (s *Servidor) atenderOrdenar(w http.ResponseWriter, r *http.Request) {
+	formato := formatos.JSON
+	if nombre := r.URL.Query().Get("formato"); nombre != "" {
+		var err error
+		if formato, err = formatos.Parsear(nombre); err != nil {
+			responderJSON(w, http.StatusBadRequest, RespuestaError{Error: err.Error()})
+			return
+		}
+	}
+	ordenador, documento, ok := s.leerSolicitud(w, r)
+	if !ok {
+		return
+	}
+	ordenado, err := ordenador.OrdenarJSONCtx(r.Context(), string(documento))
+	if err != nil {
+		responderErrorDocumento(w, err)
+		return
+	}
+	resultado, err := formatos.Convertir([]byte(ordenado), formato)
+	if err != nil {
+		responderJSON(w, http.StatusInternalServerError, RespuestaError{Error: err.Error()})
+		return
+	}
+	if !formato.Binario() && !bytes.HasSuffix(resultado, []byte("\n")) {
+		resultado = append(resultado, '\n')
+	}
+	w.Header().Set("Content-Type", formato.TipoContenido())
+	w.Write(resultado)
+}
+
+// atenderValidar implementa POST /validar. Un documento que no cumple las
+// validaciones no es un error de la solicitud: se responde 200 con el reporte.
+func (s *Servidor) atenderValidar(w http.ResponseWriter, r *http.Request) {
+	ordenador, documento, ok := s.leerSolicitud(w, r)
+	if !ok {
+		return
+	}
+	_, problemas, err := ordenador.OrdenarJSONConReporteCtx(r.Context(), string(documento))
+	if err != nil {
+		responderErrorDocumento(w, err)
+		return
+	}
+	responderJSON(w, http.StatusOK, ordenJson.NuevoReporte(problemas))
+}
+
+// leerSolicitud devuelve el Ordenador del perfil solicitado y el cuerpo de la
+// solicitud. Si falla, ya respondió con el error y devuelve false.
+func (s *Servidor) leerSolicitud(w http.ResponseWriter, r *http.Request) (*ordenJson.Ordenador, []byte, bool) {
+	ordenador, ok := s.perfilSolicitado(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	documento, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.TamanoMaximo))
+	if err != nil {
+		estado := http.StatusBadRequest
+		var errTamano *http.MaxBytesError
+		if errors.As(err, &errTamano) {
+			estado = http.StatusRequestEntityTooLarge
+		}
+		responderJSON(w, estado, RespuestaError{Error: err.Error()})
+		return nil, nil, false
+	}
+	return ordenador, documento, true
+}
+
+// perfilSolicitado devuelve el Ordenador del perfil indicado en la ruta o en
+// ?perfil=. Si no existe, ya respondió 404 y devuelve false.
+func (s *Servidor) perfilSolicitado(w http.ResponseWriter, r *http.Request) (*ordenJson.Ordenador, bool) {
+	nombre := r.PathValue("perfil")
+	if nombre == "" {
+		nombre = r.URL.Query().Get("perfil")
+	}
+	ordenador, ok := s.Ordenador(nombre)
+	if !ok {
+		responderJSON(w, http.StatusNotFound, RespuestaError{Error: fmt.Sprintf("perfil %q no configurado", nombre)})
+	}
+	return ordenador, ok
+}
+
+// responderErrorDocumento responde el error que produjo ordenar un documento,
+// con el código que corresponde a su tipo.
+func responderErrorDocumento(w http.ResponseWriter, err error) {
+	tipo := cuarentena.Clasificar(err)
+	estado := http.StatusInternalServerError
+	var errTiempo *ordenJson.ErrorTiempoExcedido
+	var errCancelado *ordenJson.ErrorCancelado
+	var errLimite *ordenJson.ErrorLimiteExcedido
+	switch {
+	case tipo == cuarentena.TipoJSONInvalido:
+		estado = http.StatusBadRequest
+	case tipo == cuarentena.TipoValidacion:
+		estado = http.StatusUnprocessableEntity
+	case errors.As(err, &errTiempo), errors.As(err, &errCancelado):
+		estado = http.StatusServiceUnavailable
+	case errors.As(err, &errLimite):
+		estado = http.StatusRequestEntityTooLarge
+	}
+	responderJSON(w, estado, RespuestaError{Error: err.Error(), Tipo: tipo})
+}
+
+// Capacidades es la respuesta de GET /capacidades.
+type Capacidades struct {
+	Version     ordenJson.InfoVersion `json:"version"`
+	Formatos    []formatos.Formato    `json:"formatos"`    // Valores admitidos en ?formato= de /ordenar.
+	Perfiles    []PerfilDisponible    `json:"perfiles"`    // Ordenados por nombre.
+	Validadores []string              `json:"validadores"` // Todas las reglas que el servidor sabe aplicar.
+	Limites     Limites               `json:"limites"`
+}
+
+// PerfilDisponible describe un perfil configurado en el servidor.
+type PerfilDisponible struct {
+	Nombre     string        `json:"nombre"`
+	PorDefecto bool          `json:"por_defecto"`
+	Campos     []string      `json:"campos"`  // Orden de campos que aplica.
+	Reglas     []string      `json:"reglas"`  // Validaciones activas; ver ordenJson.Ordenador.Reglas.
+	Limites    LimitesPerfil `json:"limites"` // Límites del Ordenador del perfil.
+}
+
+// Limites describe las restricciones que el servidor impone a cada solicitud,
+// sea cual sea el perfil; las de cada perfil están en PerfilDisponible.
+type Limites struct {
+	TamanoMaximo int64 `json:"tamano_maximo"` // Bytes admitidos en el cuerpo.
+}
+
+// LimitesPerfil describe las restricciones que el Ordenador de un perfil
+// impone a cada documento; los límites en cero no se aplican. Ver
+// ordenJson.Ordenador.Limites.
+type LimitesPerfil struct {
+	Bytes        int           `json:"bytes"`          // Ver ordenJson.WithMaxBytes.
+	Profundidad  int           `json:"profundidad"`    // Ver ordenJson.WithMaxDepth.
+	Presupuesto  time.Duration `json:"presupuesto_ns"` // Ver ordenJson.WithPresupuesto.
+	ClavesUnicas bool          `json:"claves_unicas"`  // Ver ordenJson.WithClavesUnicas.
+}
+
+// Capacidades describe lo que admite el servidor.
+func (s *Servidor) Capacidades() Capacidades {
+	c := Capacidades{
+		Version:     ordenJson.Version(),
+		Formatos:    formatos.Formatos,
+		Validadores: ordenJson.ReglasDisponibles,
+		Limites:     Limites{TamanoMaximo: s.cfg.TamanoMaximo},
+	}
+	for nombre, ordenador := range s.cfg.Perfiles {
+		limites := ordenador.Limites()
+		reglas := ordenador.Reglas()
+		if reglas == nil {
+			reglas = []string{}
+		}
+		c.Perfiles = append(c.Perfiles, PerfilDisponible{
+			Nombre:     nombre,
+			PorDefecto: nombre == s.cfg.PerfilPorDefecto,
+			Campos:     ordenador.Perfil().Campos(),
+			Reglas:     reglas,
+			Limites: LimitesPerfil{
+				Bytes:        limites.Bytes,
+				Profundidad:  limites.Profundidad,
+				Presupuesto:  limites.Presupuesto,
+				ClavesUnicas: limites.ClavesUnicas,
+			},
+		})
+	}
+	sort.Slice(c.Perfiles, func(i, j int) bool { return c.Perfiles[i].Nombre < c.Perfiles[j].Nombre })
+	return c
+}
+
+// atenderCapacidades implementa GET /capacidades.
+func (s *Servidor) atenderCapacidades(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, s.Capacidades())
+}
+
+// atenderInfo implementa GET /info.
+func (s *Servidor) atenderInfo(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, ordenJson.Version())
+}
+
+// responderJSON escribe valor como JSON indentado con el código indicado.
+func responderJSON(w http.ResponseWriter, estado int, valor interface{}) {
+	cuerpo, err := json.MarshalIndent(valor, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(estado)
+	w.Write(append(cuerpo, '\n'))
+}
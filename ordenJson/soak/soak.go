@@ -0,0 +1,216 @@
+// Package soak ejecuta pruebas de resistencia prolongadas sobre el ordenamiento
+// de documentos. Procesa documentos generados durante el tiempo indicado mientras
+// muestrea el heap y la cantidad de goroutines, y falla si alguno de ellos crece
+// de forma sostenida, lo que delata una fuga de memoria o de goroutines.
+package soak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// ErrFuga se devuelve (envuelto) cuando se detecta un crecimiento sostenido.
+var ErrFuga = errors.New("crecimiento sostenido detectado")
+
+// Config define los parámetros de una prueba de resistencia.
+type Config struct {
+	Duracion          time.Duration // Duración total de la prueba.
+	IntervaloMuestreo time.Duration // Cada cuánto se toma una muestra; por defecto 10s.
+	Trabajadores      int           // Goroutines que ordenan documentos en paralelo; por defecto runtime.NumCPU().
+	ClavesExtra       int           // Claves fuera del perfil que se agregan a cada documento; por defecto 5.
+	MuestrasTendencia int           // Muestras consecutivas en aumento que se consideran fuga; por defecto 10.
+	CrecimientoMinimo float64       // Crecimiento relativo mínimo en esa ventana para declarar fuga; por defecto 0.1 (10 %).
+	Semilla           uint64        // Semilla del generador de documentos.
+	Opciones          []ordenJson.Option
+	Salida            io.Writer // Si no es nil, recibe una línea por muestra.
+
+	// Ordenar procesa cada documento generado. Por defecto es ordenJson.OrdenarJSON
+	// con Opciones; se puede reemplazar para someter a prueba otra implementación.
+	Ordenar func(documento string) (string, error)
+}
+
+// Muestra es una medición tomada durante la prueba, después de forzar una recolección.
+type Muestra struct {
+	Transcurrido time.Duration
+	HeapEnUso    uint64 // Bytes ocupados por los objetos vivos del heap.
+	Goroutines   int
+	Documentos   uint64 // Documentos procesados hasta el momento.
+}
+
+// Reporte resume una prueba de resistencia.
+type Reporte struct {
+	Documentos uint64
+	Errores    uint64
+	Muestras   []Muestra
+}
+
+// Ejecutar corre la prueba hasta completar cfg.Duracion o hasta que ctx se cancele.
+// Devuelve el reporte junto con un error que envuelve ErrFuga si el heap o las
+// goroutines crecieron en MuestrasTendencia muestras consecutivas.
+func Ejecutar(ctx context.Context, cfg Config) (*Reporte, error) {
+	cfg = conValoresPorDefecto(cfg)
+	ctx, cancelar := context.WithTimeout(ctx, cfg.Duracion)
+	defer cancelar()
+
+	var documentos, errores atomic.Uint64
+	var wg sync.WaitGroup
+	// pausa detiene a los trabajadores mientras se toma una muestra, para medir
+	// solo la memoria retenida y no la de los documentos en proceso.
+	var pausa sync.RWMutex
+	for i := 0; i < cfg.Trabajadores; i++ {
+		wg.Add(1)
+		go func(semilla uint64) {
+			defer wg.Done()
+			gen := rand.New(rand.NewPCG(cfg.Semilla, semilla))
+			for ctx.Err() == nil {
+				pausa.RLock()
+				if _, err := cfg.Ordenar(GenerarDocumento(gen, cfg.ClavesExtra)); err != nil {
+					errores.Add(1)
+				}
+				pausa.RUnlock()
+				documentos.Add(1)
+			}
+		}(uint64(i))
+	}
+
+	reporte := &Reporte{}
+	inicio := time.Now()
+	ticker := time.NewTicker(cfg.IntervaloMuestreo)
+	defer ticker.Stop()
+
+	var errFuga error
+	for errFuga == nil {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			pausa.Lock()
+			m := tomarMuestra(time.Since(inicio), documentos.Load())
+			pausa.Unlock()
+			reporte.Muestras = append(reporte.Muestras, m)
+			if cfg.Salida != nil {
+				fmt.Fprintf(cfg.Salida, "%s heap=%d goroutines=%d documentos=%d\n", m.Transcurrido.Round(time.Millisecond), m.HeapEnUso, m.Goroutines, m.Documentos)
+			}
+			errFuga = detectarFuga(reporte.Muestras, cfg)
+			continue
+		}
+		break
+	}
+	cancelar()
+	wg.Wait()
+
+	reporte.Documentos = documentos.Load()
+	reporte.Errores = errores.Load()
+	return reporte, errFuga
+}
+
+// conValoresPorDefecto completa los campos de cfg que no se indicaron.
+func conValoresPorDefecto(cfg Config) Config {
+	if cfg.IntervaloMuestreo <= 0 {
+		cfg.IntervaloMuestreo = 10 * time.Second
+	}
+	if cfg.Trabajadores <= 0 {
+		cfg.Trabajadores = runtime.NumCPU()
+	}
+	if cfg.ClavesExtra < 0 {
+		cfg.ClavesExtra = 0
+	} else if cfg.ClavesExtra == 0 {
+		cfg.ClavesExtra = 5
+	}
+	if cfg.MuestrasTendencia <= 1 {
+		cfg.MuestrasTendencia = 10
+	}
+	if cfg.CrecimientoMinimo <= 0 {
+		cfg.CrecimientoMinimo = 0.1
+	}
+	if cfg.Ordenar == nil {
+		opts := cfg.Opciones
+		cfg.Ordenar = func(documento string) (string, error) {
+			return ordenJson.OrdenarJSON(documento, opts...)
+		}
+	}
+	return cfg
+}
+
+// tomarMuestra fuerza una recolección y mide el heap ocupado y las goroutines vivas.
+// Se llama con los trabajadores en pausa, de modo que no cuenta documentos en proceso.
+func tomarMuestra(transcurrido time.Duration, documentos uint64) Muestra {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return Muestra{
+		Transcurrido: transcurrido,
+		HeapEnUso:    mem.HeapAlloc,
+		Goroutines:   runtime.NumGoroutine(),
+		Documentos:   documentos,
+	}
+}
+
+// detectarFuga revisa las últimas MuestrasTendencia muestras y devuelve un error
+// si el heap o las goroutines aumentaron en cada una de ellas y, en total, más
+// que CrecimientoMinimo.
+func detectarFuga(muestras []Muestra, cfg Config) error {
+	if len(muestras) < cfg.MuestrasTendencia {
+		return nil
+	}
+	ventana := muestras[len(muestras)-cfg.MuestrasTendencia:]
+	heap := func(m Muestra) float64 { return float64(m.HeapEnUso) }
+	goroutines := func(m Muestra) float64 { return float64(m.Goroutines) }
+	if crecimientoSostenido(ventana, heap, cfg.CrecimientoMinimo) {
+		return fmt.Errorf("%w: heap de %d a %d bytes en %d muestras", ErrFuga, ventana[0].HeapEnUso, ventana[len(ventana)-1].HeapEnUso, len(ventana))
+	}
+	if crecimientoSostenido(ventana, goroutines, cfg.CrecimientoMinimo) {
+		return fmt.Errorf("%w: goroutines de %d a %d en %d muestras", ErrFuga, ventana[0].Goroutines, ventana[len(ventana)-1].Goroutines, len(ventana))
+	}
+	return nil
+}
+
+// crecimientoSostenido indica si la medida crece en cada muestra de la ventana
+// y el crecimiento total supera el mínimo relativo.
+func crecimientoSostenido(ventana []Muestra, medida func(Muestra) float64, minimo float64) bool {
+	for i := 1; i < len(ventana); i++ {
+		if medida(ventana[i]) <= medida(ventana[i-1]) {
+			return false
+		}
+	}
+	primera, ultima := medida(ventana[0]), medida(ventana[len(ventana)-1])
+	return primera > 0 && (ultima-primera)/primera >= minimo
+}
+
+// valoresGenerados son los valores que GenerarDocumento asigna a los campos.
+var valoresGenerados = []string{
+	`"contrato"`, `"12345678-9"`, `"aprobado"`, `"vigente"`, `"2023-10-01T00:00:00.000Z"`,
+	`"Descripción con \"comillas\" y ñ"`, `123`, `true`, `null`, `["legal", "contratos"]`,
+	`{"anidado": {"valor": 1.5}}`,
+}
+
+// GenerarDocumento construye un documento JSON con todas las claves de
+// ordenJson.OrdenCampos más extra claves desconocidas, en orden aleatorio y con
+// valores de distintos tipos.
+func GenerarDocumento(gen *rand.Rand, extra int) string {
+	claves := append([]string(nil), ordenJson.OrdenCampos...)
+	for i := 0; i < extra; i++ {
+		claves = append(claves, fmt.Sprintf("extra:campo-%d", gen.IntN(1000)))
+	}
+	gen.Shuffle(len(claves), func(i, j int) { claves[i], claves[j] = claves[j], claves[i] })
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, clave := range claves {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, "%q:%s", clave, valoresGenerados[gen.IntN(len(valoresGenerados))])
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
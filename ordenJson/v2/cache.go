@@ -0,0 +1,95 @@
+package ordenJson
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+)
+
+// WithCache guarda las últimas salidas del Ordenador, hasta capacidad
+// documentos, asociadas a un hash de la cadena de entrada. Si se vuelve a
+// ordenar una cadena idéntica (reintentos, el mismo mensaje enviado a varios
+// destinos), se devuelve la salida guardada sin decodificarla de nuevo. Al
+// llenarse se descarta la usada hace más tiempo. Un capacidad <= 0 desactiva
+// la caché.
+//
+// La caché pertenece al Ordenador creado con Nuevo, por lo que no tiene
+// efecto en las funciones del paquete, que crean uno por llamada. Solo se
+// guardan los documentos recibidos como cadena que se ordenan sin error; no
+// se usa en OrdenarJSONConReporte ni con Estadisticas, que necesitan revisar
+// cada documento.
+func WithCache(capacidad int) Option {
+	return func(cfg *configuracion) {
+		cfg.cache = nil
+		if capacidad > 0 {
+			cfg.cache = nuevaCache(capacidad)
+		}
+	}
+}
+
+// cacheResultados es una caché LRU de salidas indexada por el hash de la
+// entrada. Guarda también la entrada, sin copiarla, para que una colisión de
+// hashes no devuelva la salida de otro documento.
+type cacheResultados struct {
+	mu        sync.Mutex
+	semilla   maphash.Seed
+	capacidad int
+	entradas  map[uint64]*list.Element // Valores *entradaCache.
+	uso       list.List                // Del uso más reciente al más antiguo.
+}
+
+// entradaCache es un documento guardado en cacheResultados.
+type entradaCache struct {
+	hash    uint64
+	entrada string
+	salida  string
+}
+
+func nuevaCache(capacidad int) *cacheResultados {
+	return &cacheResultados{
+		semilla:   maphash.MakeSeed(),
+		capacidad: capacidad,
+		entradas:  make(map[uint64]*list.Element, capacidad),
+	}
+}
+
+// buscar devuelve el hash de entrada y, si está guardada, su salida.
+func (c *cacheResultados) buscar(entrada string) (uint64, string, bool) {
+	hash := maphash.String(c.semilla, entrada)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elemento, ok := c.entradas[hash]
+	if !ok || elemento.Value.(*entradaCache).entrada != entrada {
+		return hash, "", false
+	}
+	c.uso.MoveToFront(elemento)
+	return hash, elemento.Value.(*entradaCache).salida, true
+}
+
+// guardar asocia la salida a la entrada con el hash indicado, descartando la
+// entrada usada hace más tiempo si la caché está llena.
+func (c *cacheResultados) guardar(hash uint64, entrada, salida string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elemento, ok := c.entradas[hash]; ok {
+		*elemento.Value.(*entradaCache) = entradaCache{hash: hash, entrada: entrada, salida: salida}
+		c.uso.MoveToFront(elemento)
+		return
+	}
+	if c.uso.Len() >= c.capacidad {
+		antiguo := c.uso.Back()
+		delete(c.entradas, antiguo.Value.(*entradaCache).hash)
+		c.uso.Remove(antiguo)
+	}
+	c.entradas[hash] = c.uso.PushFront(&entradaCache{hash: hash, entrada: entrada, salida: salida})
+}
+
+// cacheable indica si la salida de input puede salir de la caché o
+// guardarse en ella, y devuelve input como cadena.
+func (cfg *configuracion) cacheable(input interface{}) (string, bool) {
+	if cfg.cache == nil || cfg.reporte || cfg.observar != nil || cfg.explicacion != nil || cfg.familia != nil {
+		return "", false
+	}
+	texto, ok := input.(string)
+	return texto, ok
+}
@@ -0,0 +1,60 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// maxClavesInternadas limita la cantidad de claves distintas que se guardan en la
+// tabla de internado, para que documentos con claves arbitrarias no la hagan crecer
+// sin control. Las claves que no caben se codifican en cada uso.
+const maxClavesInternadas = 4096
+
+// claveInternada es la entrada de la tabla de internado: una instancia compartida
+// de la clave y su forma JSON lista para escribir, incluidos los dos puntos.
+type claveInternada struct {
+	clave      string
+	codificada []byte // Por ejemplo: "tanner:rut-cliente":
+}
+
+// tablaClaves asocia cada clave ya vista con su entrada internada. En procesamiento
+// por lotes los documentos repiten las mismas claves, por lo que cada una se
+// codifica una sola vez y luego se reutiliza.
+var (
+	tablaClaves      sync.Map // string -> *claveInternada
+	totalClavesTabla atomic.Int64
+)
+
+// internarClave devuelve la entrada compartida para clave, creándola si es necesario.
+// Si la tabla está llena, devuelve una entrada nueva que no se guarda.
+func internarClave(clave string) (*claveInternada, error) {
+	if entrada, ok := tablaClaves.Load(clave); ok {
+		return entrada.(*claveInternada), nil
+	}
+
+	codificada, err := json.Marshal(clave)
+	if err != nil {
+		return nil, err
+	}
+	entrada := &claveInternada{clave: clave, codificada: append(codificada, ':')}
+
+	if totalClavesTabla.Load() >= maxClavesInternadas {
+		return entrada, nil
+	}
+	if existente, cargada := tablaClaves.LoadOrStore(clave, entrada); cargada {
+		return existente.(*claveInternada), nil
+	}
+	totalClavesTabla.Add(1)
+	return entrada, nil
+}
+
+// internarTexto devuelve la instancia compartida de clave si ya está en la tabla.
+// Se usa al decodificar para que millones de documentos compartan la misma
+// memoria para sus claves en lugar de retener una copia por documento.
+func internarTexto(clave string) string {
+	if entrada, ok := tablaClaves.Load(clave); ok {
+		return entrada.(*claveInternada).clave
+	}
+	return clave
+}
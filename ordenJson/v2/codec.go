@@ -0,0 +1,274 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// Codec codifica y decodifica valores JSON. Permite reemplazar encoding/json
+// por una implementación más rápida, como jsoniter o sonic (ver el paquete
+// ordencodec), sin que el paquete dependa de ella.
+//
+// Para que la salida no cambie al reemplazarlo, Marshal debe producir lo
+// mismo que json.Marshal (claves de mapas en orden alfabético y escape de
+// HTML incluidos) y Unmarshal debe decodificar en interface{} los mismos
+// tipos que json.Unmarshal: map[string]interface{}, []interface{}, string,
+// float64, bool y nil. Un Codec debe poder usarse desde varias goroutines.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(datos []byte, v interface{}) error
+}
+
+// CodecEstandar es el Codec de encoding/json, el que se usa por defecto.
+var CodecEstandar Codec = codecEstandar{}
+
+type codecEstandar struct{}
+
+func (codecEstandar) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (codecEstandar) Unmarshal(datos []byte, v interface{}) error { return json.Unmarshal(datos, v) }
+
+// codecPorDefecto guarda el Codec que establece EstablecerCodec.
+var codecPorDefecto atomic.Pointer[Codec]
+
+// EstablecerCodec reemplaza el Codec que usan los Ordenadores que se creen
+// a partir de ahora sin WithCodec; los ya creados no cambian. Un c nil
+// vuelve a CodecEstandar. Está pensada para llamarse una sola vez al iniciar
+// el programa, por ejemplo desde el init de ordencodec.
+func EstablecerCodec(c Codec) {
+	if c == nil {
+		c = CodecEstandar
+	}
+	codecPorDefecto.Store(&c)
+}
+
+// CodecActual devuelve el Codec establecido con EstablecerCodec.
+func CodecActual() Codec {
+	if c := codecPorDefecto.Load(); c != nil {
+		return *c
+	}
+	return CodecEstandar
+}
+
+// WithCodec indica el Codec con el que se decodifican los documentos recibidos
+// como cadena y se codifican los valores que no son de los tipos que produce
+// la decodificación. Un c nil usa CodecEstandar. Sin esta opción se usa el
+// establecido con EstablecerCodec.
+func WithCodec(c Codec) Option {
+	return func(cfg *configuracion) {
+		if c == nil {
+			c = CodecEstandar
+		}
+		cfg.codec = c
+	}
+}
+
+// codecAlternativo devuelve el Codec configurado, o nil si es CodecEstandar:
+// con encoding/json se usan las rutas que ya dependen de él directamente.
+func (cfg *configuracion) codecAlternativo() Codec {
+	if cfg.codec == CodecEstandar {
+		return nil
+	}
+	return cfg.codec
+}
+
+// marshal codifica v con el Codec configurado. Si la codificación entra en
+// pánico, por ejemplo en el método MarshalJSON de un campo, se devuelve como
+// error.
+func (cfg *configuracion) marshal(v interface{}) (datos []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			datos, err = nil, fmt.Errorf("pánico al codificar un valor %T: %v", v, r)
+		}
+	}()
+	if c := cfg.codecAlternativo(); c != nil {
+		return c.Marshal(v)
+	}
+	return codificarJSON(v)
+}
+
+// codificarJSON codifica v con json.Marshal. Los valores que llegan en mapas
+// o structs del llamador ejecutan código ajeno al codificarse, como sus
+// métodos MarshalJSON: si entra en pánico, se devuelve como error.
+func codificarJSON(v interface{}) (datos []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			datos, err = nil, fmt.Errorf("pánico al codificar un valor %T: %v", v, r)
+		}
+	}()
+	return json.Marshal(v)
+}
+
+// decodificarConCodec es equivalente a decodificarDesde, pero delega la
+// decodificación de cada valor en codec: el texto solo se recorre para ubicar
+// las claves y los límites de cada valor, y así conservar el orden de las
+// claves, que los decodificadores a mapa pierden. La validez del contenido de
+// cada valor la decide codec. Devuelve errPlazoVencido si vence limite.
+func decodificarConCodec(texto string, claves []string, codec Codec, limite plazo, unicas bool) (map[string]interface{}, []string, error) {
+	invalido := func(offset int, err error) error {
+		return &ErrorJSONInvalido{Offset: int64(offset), Err: err}
+	}
+	inesperado := func(i int) error {
+		if i >= len(texto) {
+			return invalido(i, errors.New("fin inesperado del JSON"))
+		}
+		return invalido(i, fmt.Errorf("carácter inesperado %q", texto[i]))
+	}
+
+	datos := make(map[string]interface{})
+	i := saltarEspacios(texto, 0)
+	switch {
+	case len(texto)-i >= 4 && texto[i:i+4] == "null":
+		// null equivale a un objeto vacío, igual que con json.Unmarshal.
+		i += 4
+	case i < len(texto) && texto[i] == '{':
+		i = saltarEspacios(texto, i+1)
+		if i < len(texto) && texto[i] == '}' {
+			i++
+			break
+		}
+		for n := 1; ; n++ {
+			if n%clavesEntreRevisiones == 0 && limite.vencido() {
+				return nil, nil, errPlazoVencido
+			}
+			fin := finDeValor(texto, i)
+			if fin < 0 || texto[i] != '"' {
+				return nil, nil, inesperado(i)
+			}
+			inicioClave := i
+			clave := texto[i+1 : fin-1]
+			if !cadenaLiteral(clave) {
+				// La clave tiene escapes: que la decodifique el codec.
+				if err := decodificarValorCodec(codec, texto[i:fin], &clave); err != nil {
+					return nil, nil, invalido(i, err)
+				}
+			}
+			clave = internarTexto(clave)
+
+			i = saltarEspacios(texto, fin)
+			if i >= len(texto) || texto[i] != ':' {
+				return nil, nil, inesperado(i)
+			}
+			i = saltarEspacios(texto, i+1)
+			if fin = finDeValor(texto, i); fin < 0 {
+				return nil, nil, inesperado(i)
+			}
+			var valor interface{}
+			if err := decodificarValorCodec(codec, texto[i:fin], &valor); err != nil {
+				return nil, nil, invalido(i, err)
+			}
+			if _, repetida := datos[clave]; !repetida {
+				claves = append(claves, clave)
+			} else if unicas {
+				return nil, nil, invalido(inicioClave, &ErrorClaveDuplicada{Clave: clave})
+			}
+			datos[clave] = valor
+
+			i = saltarEspacios(texto, fin)
+			if i < len(texto) && texto[i] == ',' {
+				i = saltarEspacios(texto, i+1)
+				continue
+			}
+			if i < len(texto) && texto[i] == '}' {
+				i++
+				break
+			}
+			return nil, nil, inesperado(i)
+		}
+	default:
+		if i >= len(texto) {
+			return nil, nil, inesperado(i)
+		}
+		return nil, nil, invalido(i, errors.New("el JSON no es un objeto"))
+	}
+	// No se admite contenido después del objeto.
+	if i = saltarEspacios(texto, i); i != len(texto) {
+		return nil, nil, invalido(i, errors.New("contenido inesperado después del objeto JSON"))
+	}
+	return datos, claves, nil
+}
+
+// finDeValor devuelve la posición siguiente al valor JSON que comienza en
+// texto[i], o -1 si en i no comienza un valor. Solo reconoce los límites del
+// valor (comillas, llaves y corchetes balanceados); no valida su contenido.
+func finDeValor(texto string, i int) int {
+	if i >= len(texto) {
+		return -1
+	}
+	switch texto[i] {
+	case '"':
+		return finDeCadena(texto, i)
+	case '{', '[':
+		profundidad := 0
+		for i < len(texto) {
+			switch texto[i] {
+			case '"':
+				if i = finDeCadena(texto, i); i < 0 {
+					return -1
+				}
+				continue
+			case '{', '[':
+				profundidad++
+			case '}', ']':
+				if profundidad--; profundidad == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return -1
+	case ',', ':', '}', ']', ' ', '\t', '\n', '\r':
+		return -1
+	default:
+		// Número o literal: termina en el siguiente delimitador.
+		for i < len(texto) {
+			switch texto[i] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return i
+			}
+			i++
+		}
+		return i
+	}
+}
+
+// cadenaLiteral indica si el contenido de una cadena JSON, sin comillas, es
+// igual a su valor decodificado: no tiene escapes, caracteres de control ni
+// UTF-8 inválido.
+func cadenaLiteral(contenido string) bool {
+	for i := 0; i < len(contenido); i++ {
+		if c := contenido[i]; c < ' ' || c == '\\' {
+			return false
+		}
+	}
+	return utf8.ValidString(contenido)
+}
+
+// finDeCadena devuelve la posición siguiente a la comilla que cierra la
+// cadena que comienza en texto[i], o -1 si no se cierra.
+func finDeCadena(texto string, i int) int {
+	for i++; i < len(texto); i++ {
+		switch texto[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// decodificarValorCodec decodifica un valor con codec. Un codec de terceros
+// puede entrar en pánico con entradas malformadas: el pánico se devuelve como
+// error, que quien llama informa como JSON inválido en la posición del valor.
+func decodificarValorCodec(codec Codec, texto string, v interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pánico del codec: %v", r)
+		}
+	}()
+	return codec.Unmarshal([]byte(texto), v)
+}
@@ -0,0 +1,51 @@
+package ordenJson
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ExportarCSV escribe los documentos en w como CSV, con las columnas en el
+// orden de OrdenCampos. Ver Ordenador.ExportarCSV.
+func ExportarCSV(docs []DocumentMetadata, w io.Writer, opts ...Option) error {
+	return Nuevo(opts...).ExportarCSV(docs, w)
+}
+
+// ExportarCSV escribe en w una fila de encabezado con los campos del perfil,
+// en su orden, y una fila por documento. Cada documento pasa por
+// OrdenarDocumentoMetadata, así que se aplican las mismas validaciones y
+// transformaciones (por ejemplo WithNormalizarFechas); el primero que falla
+// detiene la exportación y su error se devuelve con la posición del
+// documento. Los campos vacíos quedan como celdas vacías y los que no están en
+// el perfil no se exportan.
+func (o *Ordenador) ExportarCSV(docs []DocumentMetadata, w io.Writer) error {
+	campos := o.cfg.perfil.Campos()
+	escritor := csv.NewWriter(w)
+	if err := escritor.Write(campos); err != nil {
+		return err
+	}
+	fila := make([]string, len(campos))
+	for i, doc := range docs {
+		ordenado, err := o.OrdenarDocumentoMetadata(doc)
+		if err != nil {
+			return fmt.Errorf("documento %d: %w", i, err)
+		}
+		var valores map[string]interface{}
+		if err := json.Unmarshal([]byte(ordenado), &valores); err != nil {
+			return fmt.Errorf("documento %d: %w", i, err)
+		}
+		for j, campo := range campos {
+			fila[j] = ""
+			if valor, ok := valores[campo]; ok && valor != nil {
+				fila[j] = fmt.Sprint(valor)
+			}
+		}
+		if err := escritor.Write(fila); err != nil {
+			return err
+		}
+	}
+	escritor.Flush()
+	return escritor.Error()
+}
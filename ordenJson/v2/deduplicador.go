@@ -0,0 +1,199 @@
+package ordenJson
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// Deduplicador detecta documentos repetidos en un flujo usando un filtro de
+// Bloom sobre el contenido canónico de cada documento (ver formaCanonica):
+// dos documentos que solo difieren en el orden de sus claves, también las
+// que están fuera del perfil, o en el formato se consideran el mismo. Ocupa
+// memoria fija, proporcional a la capacidad, sin importar cuántos documentos
+// se revisen.
+//
+// Un filtro de Bloom puede dar falsos positivos (informar como repetido un
+// documento nuevo) con la tasa configurada, pero nunca falsos negativos. La
+// tasa se cumple mientras no se superen los documentos indicados en la
+// capacidad; ver Cantidad y Reiniciar. Es seguro usarlo desde varias
+// goroutines a la vez.
+type Deduplicador struct {
+	ordenador *Ordenador
+	hashes    uint64 // Cantidad de posiciones que se marcan por documento.
+
+	mu       sync.Mutex
+	bits     []uint64
+	cantidad int // Documentos distintos agregados desde el último Reiniciar.
+}
+
+// NuevoDeduplicador crea un deduplicador dimensionado para capacidad
+// documentos distintos con la tasa de falsos positivos indicada, que debe
+// estar entre 0 y 1 (por ejemplo 0.001). Las opciones se aplican al ordenar
+// cada documento para obtener su forma canónica.
+func NuevoDeduplicador(capacidad int, tasaFalsosPositivos float64, opts ...Option) (*Deduplicador, error) {
+	if capacidad <= 0 {
+		return nil, fmt.Errorf("la capacidad del deduplicador debe ser positiva: %d", capacidad)
+	}
+	if !(tasaFalsosPositivos > 0 && tasaFalsosPositivos < 1) {
+		return nil, fmt.Errorf("la tasa de falsos positivos debe estar entre 0 y 1: %v", tasaFalsosPositivos)
+	}
+	// Tamaño óptimo del filtro: m = -n·ln(p)/ln(2)² bits y k = m/n·ln(2) hashes.
+	m := math.Ceil(-float64(capacidad) * math.Log(tasaFalsosPositivos) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(capacidad)*math.Ln2))
+	return &Deduplicador{
+		ordenador: Nuevo(opts...),
+		hashes:    uint64(k),
+		bits:      make([]uint64, (uint64(m)+63)/64),
+	}, nil
+}
+
+// Visto informa si el documento ya pasó por el deduplicador y, si no, lo
+// registra. Devuelve error si el documento no se puede ordenar.
+func (d *Deduplicador) Visto(doc interface{}) (bool, error) {
+	inicio := time.Now()
+	canonico, _, err := ordenar(doc, &d.ordenador.cfg)
+	d.ordenador.cfg.registrarEvento(OperacionDeduplicar, inicio, doc, canonico, nil, err)
+	if err != nil {
+		return false, err
+	}
+	h := fnv.New128a()
+	h.Write(formaCanonica([]byte(canonico)))
+	suma := h.Sum(nil)
+	h1 := binary.BigEndian.Uint64(suma[:8])
+	h2 := binary.BigEndian.Uint64(suma[8:]) | 1 // Impar, para recorrer todas las posiciones.
+
+	total := uint64(len(d.bits)) * 64
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	visto := true
+	for i := uint64(0); i < d.hashes; i++ {
+		pos := (h1 + i*h2) % total
+		palabra, bit := pos/64, uint64(1)<<(pos%64)
+		if d.bits[palabra]&bit == 0 {
+			visto = false
+			d.bits[palabra] |= bit
+		}
+	}
+	if !visto {
+		d.cantidad++
+	}
+	return visto, nil
+}
+
+// Cantidad devuelve cuántos documentos distintos se registraron desde la
+// creación o el último Reiniciar. Cuando supera la capacidad, la tasa de
+// falsos positivos crece y conviene reiniciar el deduplicador.
+func (d *Deduplicador) Cantidad() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cantidad
+}
+
+// Reiniciar olvida todos los documentos registrados.
+func (d *Deduplicador) Reiniciar() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	clear(d.bits)
+	d.cantidad = 0
+}
+
+// Duplicado describe un documento de Deduplicar cuyo contenido canónico
+// repite el de uno anterior. Se serializa a JSON con las claves indice,
+// original y hash.
+type Duplicado struct {
+	Indice   int    `json:"indice"`   // Posición del documento repetido en la entrada, desde 0.
+	Original int    `json:"original"` // Posición de su primera aparición.
+	Hash     string `json:"hash"`     // SHA-256 en hexadecimal de la forma canónica del documento.
+}
+
+// Deduplicacion es el resultado de Deduplicar y DeduplicarFlujo.
+type Deduplicacion struct {
+	Unicos     []string    `json:"-"`          // Documentos ordenados sin repetir, en el orden de su primera aparición; nil en DeduplicarFlujo.
+	Documentos int         `json:"documentos"` // Documentos leídos.
+	Distintos  int         `json:"distintos"`  // Documentos con contenido canónico distinto.
+	Duplicados []Duplicado `json:"duplicados"` // Repetidos, en el orden de la entrada; nunca es null.
+}
+
+// Deduplicar ordena los documentos con las opciones recibidas y descarta los
+// repetidos. Ver Ordenador.Deduplicar.
+func Deduplicar(docs []string, opts ...Option) (*Deduplicacion, error) {
+	return Nuevo(opts...).Deduplicar(docs)
+}
+
+// Deduplicar ordena cada documento y devuelve los que tienen contenido
+// canónico distinto, junto con el reporte de los repetidos: dos documentos
+// que solo difieren en el orden de sus claves, también las que están fuera
+// del perfil, o en el formato son el mismo, igual que en Iguales. Se
+// devuelve la salida ordenada de la primera aparición. A diferencia de
+// Deduplicador, la comparación es exacta, por un hash SHA-256 de la forma
+// canónica del documento, y la memoria crece con la cantidad de
+// documentos distintos. Al primer documento que no se puede ordenar se
+// detiene y devuelve el error indicando su posición (desde 0).
+func (o *Ordenador) Deduplicar(docs []string) (*Deduplicacion, error) {
+	r := nuevoRegistroDuplicados()
+	var ordenado []byte
+	for i, doc := range docs {
+		var err error
+		if ordenado, err = o.AgregarJSON(ordenado[:0], doc); err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i, err)
+		}
+		if !r.repetido(i, ordenado) {
+			r.resultado.Unicos = append(r.resultado.Unicos, string(ordenado))
+		}
+	}
+	return r.resultado, nil
+}
+
+// DeduplicarFlujo ordena los documentos con las opciones recibidas y escribe
+// los que no se repiten. Ver Ordenador.DeduplicarFlujo.
+func DeduplicarFlujo(r io.Reader, w io.Writer, opts ...Option) (*Deduplicacion, error) {
+	return Nuevo(opts...).DeduplicarFlujo(r, w)
+}
+
+// DeduplicarFlujo es como OrdenarFlujo, pero solo escribe en w la primera
+// aparición de cada contenido canónico, como Deduplicar. Los documentos no
+// se retienen, por lo que Unicos queda en nil; solo se guarda el hash de
+// cada documento distinto. Si un documento no se puede ordenar devuelve el
+// error de OrdenarFlujo junto con el reporte de los documentos anteriores.
+func (o *Ordenador) DeduplicarFlujo(r io.Reader, w io.Writer) (*Deduplicacion, error) {
+	cfg := o.cfg
+	registro := nuevoRegistroDuplicados()
+	_, err := cfg.ordenarFlujo(r, w, registro.repetido)
+	return registro.resultado, err
+}
+
+// registroDuplicados recuerda la primera aparición de cada contenido
+// canónico de Deduplicar y DeduplicarFlujo.
+type registroDuplicados struct {
+	primeros  map[[sha256.Size]byte]int
+	resultado *Deduplicacion
+}
+
+// nuevoRegistroDuplicados crea un registro vacío.
+func nuevoRegistroDuplicados() *registroDuplicados {
+	return &registroDuplicados{
+		primeros:  make(map[[sha256.Size]byte]int),
+		resultado: &Deduplicacion{Duplicados: []Duplicado{}},
+	}
+}
+
+// repetido registra el documento ordenado en la posición i y devuelve true
+// si su contenido ya había aparecido.
+func (r *registroDuplicados) repetido(i int, ordenado []byte) bool {
+	r.resultado.Documentos++
+	suma := sha256.Sum256(formaCanonica(ordenado))
+	if original, ok := r.primeros[suma]; ok {
+		r.resultado.Duplicados = append(r.resultado.Duplicados, Duplicado{Indice: i, Original: original, Hash: hex.EncodeToString(suma[:])})
+		return true
+	}
+	r.primeros[suma] = i
+	r.resultado.Distintos++
+	return false
+}
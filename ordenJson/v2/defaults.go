@@ -0,0 +1,51 @@
+package ordenJson
+
+import "sort"
+
+// WithDefaults completa los campos de primer nivel que faltan en el documento
+// con los valores indicados, durante el ordenamiento:
+//
+//	ordenJson.WithDefaults(map[string]interface{}{"tanner:estado-vigencia": "vigente"})
+//
+// Un campo falta si el documento no lo trae, si su valor es null o si se
+// omitió por vacío (ver WithVacio); en OrdenarDocumentoMetadata, los campos
+// vacíos faltan siempre. Los valores por defecto se agregan después de
+// WithRenombres y WithTransform y antes de validar, por lo que cuentan para
+// WithRequired y WithValoresPermitidos, y se escriben en el orden canónico.
+// Varias llamadas se combinan; si un campo se repite, prevalece el último
+// valor. El mapa se copia.
+func WithDefaults(valores map[string]interface{}) Option {
+	return func(cfg *configuracion) {
+		if len(valores) == 0 {
+			return
+		}
+		if cfg.defaults == nil {
+			cfg.defaults = make(map[string]interface{}, len(valores))
+		}
+		for campo, valor := range valores {
+			cfg.defaults[campo] = valor
+		}
+		cfg.camposDefaults = cfg.camposDefaults[:0:0]
+		for campo := range cfg.defaults {
+			cfg.camposDefaults = append(cfg.camposDefaults, campo)
+		}
+		sort.Strings(cfg.camposDefaults)
+	}
+}
+
+// completarDefaults agrega a datos los valores por defecto de los campos que
+// faltan y devuelve las claves con las nuevas al final, en orden alfabético.
+// Modifica datos.
+func (cfg *configuracion) completarDefaults(datos map[string]interface{}, claves []string) []string {
+	for _, campo := range cfg.camposDefaults {
+		valor, ok := datos[campo]
+		if ok && valor != nil {
+			continue
+		}
+		if !ok {
+			claves = append(claves, campo)
+		}
+		datos[campo] = cfg.defaults[campo]
+	}
+	return claves
+}
@@ -0,0 +1,291 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TipoCambio indica qué le pasó a un campo entre dos documentos comparados con Diff.
+type TipoCambio string
+
+const (
+	CambioAgregado   TipoCambio = "agregado"   // El campo solo está en el segundo documento.
+	CambioEliminado  TipoCambio = "eliminado"  // El campo solo está en el primer documento.
+	CambioModificado TipoCambio = "modificado" // El campo está en ambos con valores distintos.
+)
+
+// Cambio describe una diferencia entre dos documentos. Se serializa a JSON
+// con las claves ruta, tipo, antes y despues.
+type Cambio struct {
+	Ruta    string      `json:"ruta"`              // JSON Pointer (RFC 6901) del campo, como "/cm:title" o "/lista/0/id".
+	Tipo    TipoCambio  `json:"tipo"`              // Ver las constantes Cambio*.
+	Antes   interface{} `json:"antes,omitempty"`   // Valor en el primer documento; nil si se agregó.
+	Despues interface{} `json:"despues,omitempty"` // Valor en el segundo documento; nil si se eliminó.
+}
+
+// Diferencias es el resultado de comparar dos documentos con Diff.
+type Diferencias struct {
+	Cambios []Cambio `json:"cambios"` // Cambios en orden canónico; nunca es null.
+}
+
+// Iguales indica si los documentos comparados no tienen diferencias.
+func (d *Diferencias) Iguales() bool {
+	return len(d.Cambios) == 0
+}
+
+// String devuelve un cambio por línea, con el valor codificado en JSON:
+// "+ ruta: valor" si se agregó, "- ruta: valor" si se eliminó y
+// "~ ruta: antes -> despues" si se modificó.
+func (d *Diferencias) String() string {
+	var b strings.Builder
+	for _, c := range d.Cambios {
+		switch c.Tipo {
+		case CambioAgregado:
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Ruta, valorDiff(c.Despues))
+		case CambioEliminado:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Ruta, valorDiff(c.Antes))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Ruta, valorDiff(c.Antes), valorDiff(c.Despues))
+		}
+	}
+	return b.String()
+}
+
+// valorDiff codifica un valor de un Cambio en JSON compacto.
+func valorDiff(v interface{}) string {
+	texto, err := codificarJSON(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(texto)
+}
+
+// Diff compara dos documentos JSON con las opciones recibidas. Ver Ordenador.Diff.
+func Diff(a, b string, opts ...Option) (*Diferencias, error) {
+	return Nuevo(opts...).Diff(a, b)
+}
+
+// Diff compara dos documentos JSON sin tener en cuenta el orden de las claves
+// ni los espacios, y devuelve los campos agregados, eliminados y modificados
+// para pasar de a a b. Los objetos anidados se comparan campo por campo y los
+// arreglos posición por posición; los números se comparan por su valor con
+// precisión exacta, como en Iguales, por lo que 1 y 1.0 son iguales.
+//
+// Los cambios se informan en orden canónico: los campos de primer nivel en el
+// orden del perfil, como los escribe OrdenarJSON, con los que no están en él
+// en el orden en que aparecen en a y luego en b; dentro de los objetos
+// anidados, en orden alfabético. No se aplican las validaciones del
+// Ordenador: solo se usa su perfil. Si alguno no es un objeto JSON válido se
+// devuelve un *ErrorJSONInvalido.
+func (o *Ordenador) Diff(a, b string) (*Diferencias, error) {
+	datosA, clavesA, err := decodificarConNumeros(a)
+	if err != nil {
+		return nil, fmt.Errorf("primer documento: %w", err)
+	}
+	datosB, clavesB, err := decodificarConNumeros(b)
+	if err != nil {
+		return nil, fmt.Errorf("segundo documento: %w", err)
+	}
+
+	claves := clavesA
+	for _, clave := range clavesB {
+		if _, ok := datosA[clave]; !ok {
+			claves = append(claves, clave)
+		}
+	}
+	perfil := o.cfg.perfil
+	sort.SliceStable(claves, func(i, j int) bool {
+		return perfil.posicion(claves[i]) < perfil.posicion(claves[j])
+	})
+
+	d := &Diferencias{Cambios: []Cambio{}}
+	for _, clave := range claves {
+		d.compararCampo(rutaHija("", clave), datosA, datosB, clave)
+	}
+	return d, nil
+}
+
+// decodificarUbicado decodifica un objeto JSON y ubica el error de
+// sintaxis, si lo hay, en su línea y columna.
+func decodificarUbicado(texto string) (map[string]interface{}, []string, error) {
+	datos, claves, err := decodificarObjeto(texto)
+	if err != nil {
+		var errJSON *ErrorJSONInvalido
+		if errors.As(err, &errJSON) {
+			errJSON.ubicar(texto)
+		}
+		return nil, nil, err
+	}
+	return datos, claves, nil
+}
+
+// decodificarConNumeros es equivalente a decodificarUbicado pero deja los
+// números como json.Number, para compararlos sin pasar por float64.
+func decodificarConNumeros(texto string) (map[string]interface{}, []string, error) {
+	dec := json.NewDecoder(strings.NewReader(texto))
+	dec.UseNumber()
+	datos, claves, err := decodificarConDecoder(dec, nil, false)
+	if err != nil {
+		var errJSON *ErrorJSONInvalido
+		if errors.As(err, &errJSON) {
+			errJSON.ubicar(texto)
+		}
+		return nil, nil, err
+	}
+	return datos, claves, nil
+}
+
+// compararCampo registra la diferencia de la clave entre los objetos a y b.
+func (d *Diferencias) compararCampo(ruta string, a, b map[string]interface{}, clave string) {
+	antes, enA := a[clave]
+	despues, enB := b[clave]
+	switch {
+	case !enB:
+		d.Cambios = append(d.Cambios, Cambio{Ruta: ruta, Tipo: CambioEliminado, Antes: antes})
+	case !enA:
+		d.Cambios = append(d.Cambios, Cambio{Ruta: ruta, Tipo: CambioAgregado, Despues: despues})
+	default:
+		d.compararValores(ruta, antes, despues)
+	}
+}
+
+// compararValores registra las diferencias entre dos valores que están en
+// ambos documentos, entrando en los objetos y arreglos.
+func (d *Diferencias) compararValores(ruta string, antes, despues interface{}) {
+	switch a := antes.(type) {
+	case map[string]interface{}:
+		if b, ok := despues.(map[string]interface{}); ok {
+			claves := make([]string, 0, len(a)+len(b))
+			for clave := range a {
+				claves = append(claves, clave)
+			}
+			for clave := range b {
+				if _, ok := a[clave]; !ok {
+					claves = append(claves, clave)
+				}
+			}
+			sort.Strings(claves)
+			for _, clave := range claves {
+				d.compararCampo(rutaHija(ruta, clave), a, b, clave)
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := despues.([]interface{}); ok {
+			for i := 0; i < max(len(a), len(b)); i++ {
+				hija := rutaHija(ruta, strconv.Itoa(i))
+				switch {
+				case i >= len(b):
+					d.Cambios = append(d.Cambios, Cambio{Ruta: hija, Tipo: CambioEliminado, Antes: a[i]})
+				case i >= len(a):
+					d.Cambios = append(d.Cambios, Cambio{Ruta: hija, Tipo: CambioAgregado, Despues: b[i]})
+				default:
+					d.compararValores(hija, a[i], b[i])
+				}
+			}
+			return
+		}
+	}
+	if !valoresIguales(antes, despues) {
+		d.Cambios = append(d.Cambios, Cambio{Ruta: ruta, Tipo: CambioModificado, Antes: antes, Despues: despues})
+	}
+}
+
+// rutaHija agrega a un JSON Pointer el segmento de la clave, escapando "~" y
+// "/" como indica el RFC 6901.
+func rutaHija(ruta, clave string) string {
+	clave = strings.ReplaceAll(clave, "~", "~0")
+	clave = strings.ReplaceAll(clave, "/", "~1")
+	return ruta + "/" + clave
+}
+
+// Iguales indica si a y b son el mismo documento JSON sin tener en cuenta el
+// orden de las claves, los espacios ni la forma de escribir los textos y los
+// números: "\u00e1" y "á" son iguales, igual que 1, 1.0 y 1e0. Los números
+// se comparan con precisión exacta, sin pasar por float64. A diferencia de
+// Diff, los documentos pueden ser cualquier valor JSON, no solo objetos. Si
+// alguno no es JSON válido se devuelve un *ErrorJSONInvalido.
+func Iguales(a, b string) (bool, error) {
+	valorA, err := decodificarValor(a)
+	if err != nil {
+		return false, fmt.Errorf("primer documento: %w", err)
+	}
+	valorB, err := decodificarValor(b)
+	if err != nil {
+		return false, fmt.Errorf("segundo documento: %w", err)
+	}
+	return valoresIguales(valorA, valorB), nil
+}
+
+// decodificarValor decodifica un documento JSON con los números como
+// json.Number.
+func decodificarValor(texto string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(texto))
+	dec.UseNumber()
+	var valor interface{}
+	err := dec.Decode(&valor)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			return valor, nil
+		} else if err == nil {
+			err = errors.New("contenido inesperado después del documento JSON")
+		}
+	}
+	errJSON := &ErrorJSONInvalido{Offset: dec.InputOffset(), Err: err}
+	var errSintaxis *json.SyntaxError
+	if errors.As(err, &errSintaxis) {
+		errJSON.Offset = errSintaxis.Offset
+	}
+	errJSON.ubicar(texto)
+	return nil, errJSON
+}
+
+// valoresIguales compara dos valores decodificados con los números como
+// json.Number, por decodificarValor o decodificarConNumeros.
+func valoresIguales(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for clave, valor := range x {
+			otro, ok := y[clave]
+			if !ok || !valoresIguales(valor, otro) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !valoresIguales(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		var rx, ry big.Rat
+		_, okX := rx.SetString(string(x))
+		_, okY := ry.SetString(string(y))
+		return okX && okY && rx.Cmp(&ry) == 0
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,25 @@
+// Package ordenJson ordena documentos JSON de metadatos según un orden de
+// campos predefinido, para que todos los sistemas produzcan y comparen los
+// mismos documentos con las claves en la misma posición.
+//
+// El orden se describe con un Perfil; PerfilPorDefecto se construye a partir
+// de OrdenCampos. Las funciones OrdenarJSON, OrdenarDocumentoMetadata y
+// OrdenarJSONConReporte aceptan opciones (WithPerfil, WithRequired, WithStrict,
+// WithNormalizarFechas, ...) que agregan validaciones y transformaciones.
+// Para ordenar muchos documentos con la misma configuración se crea un
+// Ordenador con Nuevo y se reutiliza.
+//
+// Ninguna entrada hace entrar en pánico al paquete: un documento malformado o
+// adversario se rechaza con un error, y los pánicos del código ajeno que se
+// ejecuta al ordenar, como los métodos MarshalJSON de los valores de un mapa
+// o un Codec de terceros, se devuelven como ErrorValorNoSerializable o
+// ErrorJSONInvalido. Los objetivos de fuzzing FuzzOrdenarJSON y
+// FuzzOrdenarFlujo del directorio test comprueban la garantía, y su corpus en
+// test/testdata/fuzz guarda las entradas problemáticas conocidas, que se
+// verifican en cada go test. Para seguir buscando:
+//
+//	go test ./test -run '^$' -fuzz FuzzOrdenarJSON
+//
+// Esta es la versión 2 del paquete. La ruta github.com/samuel/prueba-orden/ordenJson
+// se mantiene como una capa de compatibilidad que delega en este paquete.
+package ordenJson
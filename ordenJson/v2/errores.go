@@ -0,0 +1,419 @@
+package ordenJson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Errores centinela de cada clase de falla. Cada tipo Error* del paquete es
+// equivalente a su centinela con errors.Is, también cuando viene envuelto con
+// %w, por lo que se puede distinguir la clase de falla sin depender del
+// mensaje:
+//
+//	if errors.Is(err, ordenJson.ErrCamposFaltantes) { ... }
+//
+// Para acceder a los detalles, como los campos que faltan, se usa errors.As
+// con el tipo correspondiente.
+var (
+	ErrTiempoExcedido           = errors.New("presupuesto de tiempo excedido")   // *ErrorTiempoExcedido.
+	ErrCancelado                = errors.New("ordenamiento cancelado")           // *ErrorCancelado.
+	ErrLimiteExcedido           = errors.New("límite excedido")                  // *ErrorLimiteExcedido.
+	ErrValorNoPermitido         = errors.New("valor no permitido")               // *ErrorValorNoPermitido.
+	ErrJSONInvalido             = errors.New("JSON inválido")                    // *ErrorJSONInvalido.
+	ErrClaveDuplicada           = errors.New("clave duplicada")                  // *ErrorClaveDuplicada; ver WithClavesUnicas.
+	ErrTipoNoSoportado          = errors.New("tipo de entrada no soportado")     // *ErrorTipoNoSoportado.
+	ErrCamposFaltantes          = errors.New("faltan campos obligatorios")       // *ErrorCamposFaltantes.
+	ErrClavesNoPermitidas       = errors.New("claves no permitidas")             // *ErrorClavesNoPermitidas.
+	ErrFechaInvalida            = errors.New("fecha inválida")                   // *ErrorFechaInvalida.
+	ErrRegla                    = errors.New("regla de validación no cumplida")  // *ErrorRegla.
+	ErrValorNoSerializable      = errors.New("valor no serializable")            // *ErrorValorNoSerializable.
+	ErrEsquemaInvalido          = errors.New("esquema inválido")                 // *ErrorEsquemaInvalido.
+	ErrPatch                    = errors.New("operación de patch no aplicable")  // *ErrorPatch.
+	ErrPerfilDesconocido        = errors.New("perfil desconocido")               // *ErrorPerfilDesconocido.
+	ErrConflictos               = errors.New("fragmentos con valores distintos") // *ErrorConflictos.
+	ErrTipoDocumentoDesconocido = errors.New("tipo de documento no registrado")  // *ErrorTipoDocumentoDesconocido.
+)
+
+// ErrorTiempoExcedido indica que el ordenamiento de un documento superó el
+// presupuesto de tiempo configurado con WithPresupuesto.
+type ErrorTiempoExcedido struct {
+	localizable
+
+	Presupuesto  time.Duration // Presupuesto configurado.
+	Transcurrido time.Duration // Tiempo transcurrido al momento de abortar.
+	Etapa        string        // Etapa en la que se abortó (decodificación, validación, serialización).
+}
+
+func (e *ErrorTiempoExcedido) Error() string {
+	return e.mensaje("tiempo-excedido", e.Presupuesto, e.etapa(e.Etapa), e.Transcurrido)
+}
+
+// Is hace que el error sea equivalente a ErrTiempoExcedido.
+func (e *ErrorTiempoExcedido) Is(target error) bool {
+	return target == ErrTiempoExcedido
+}
+
+// Timeout permite tratar el error como cualquier otro error de tiempo agotado,
+// igual que los errores de red de la biblioteca estándar.
+func (e *ErrorTiempoExcedido) Timeout() bool {
+	return true
+}
+
+// ErrorCancelado indica que el contexto recibido en una variante Ctx, como
+// OrdenarJSONCtx, terminó antes de que se ordenara el documento. Err es el
+// error del contexto, por lo que errors.Is(err, context.Canceled) o
+// errors.Is(err, context.DeadlineExceeded) permiten distinguir la causa.
+type ErrorCancelado struct {
+	localizable
+
+	Etapa string // Etapa en la que se abortó (decodificación, validación, serialización).
+	Err   error
+}
+
+func (e *ErrorCancelado) Error() string {
+	return e.mensaje("cancelado", e.etapa(e.Etapa), e.Err)
+}
+
+// Is hace que el error sea equivalente a ErrCancelado.
+func (e *ErrorCancelado) Is(target error) bool {
+	return target == ErrCancelado
+}
+
+func (e *ErrorCancelado) Unwrap() error {
+	return e.Err
+}
+
+// ErrorLimiteExcedido indica que un documento supera un límite configurado
+// con WithMaxBytes o WithMaxDepth, por lo que se rechazó sin ordenarlo.
+type ErrorLimiteExcedido struct {
+	localizable
+
+	Limite string // LimiteBytes o LimiteProfundidad.
+	Maximo int    // Valor configurado del límite.
+	Valor  int    // Tamaño del documento en bytes; 0 si no se conoce o el límite es de profundidad.
+}
+
+func (e *ErrorLimiteExcedido) Error() string {
+	if e.Limite == LimiteProfundidad {
+		return e.mensaje("limite-profundidad", e.Maximo)
+	}
+	if e.Valor > 0 {
+		return e.mensaje("limite-bytes-valor", e.Maximo, e.Valor)
+	}
+	return e.mensaje("limite-bytes", e.Maximo)
+}
+
+// Is hace que el error sea equivalente a ErrLimiteExcedido.
+func (e *ErrorLimiteExcedido) Is(target error) bool {
+	return target == ErrLimiteExcedido
+}
+
+// ErrorValorNoPermitido indica que un campo tiene un valor fuera del conjunto
+// de valores admitidos, configurado con WithValoresPermitidos o con "enum" en un esquema.
+type ErrorValorNoPermitido struct {
+	localizable
+
+	Campo      string        // Campo cuyo valor no está permitido.
+	Valor      interface{}   // Valor recibido.
+	Permitidos []interface{} // Valores admitidos para el campo.
+}
+
+func (e *ErrorValorNoPermitido) Error() string {
+	return e.mensaje("valor-no-permitido", e.Campo, e.Valor, e.Permitidos)
+}
+
+// Is hace que el error sea equivalente a ErrValorNoPermitido.
+func (e *ErrorValorNoPermitido) Is(target error) bool {
+	return target == ErrValorNoPermitido
+}
+
+// ErrorJSONInvalido indica que la entrada no es un objeto JSON válido.
+type ErrorJSONInvalido struct {
+	localizable
+
+	Offset  int64 // Posición en bytes, desde el inicio de la entrada, donde se detectó el problema.
+	Linea   int   // Línea correspondiente a Offset, comenzando en 1.
+	Columna int   // Columna en bytes correspondiente a Offset, comenzando en 1.
+	Err     error // Error original del decodificador.
+}
+
+func (e *ErrorJSONInvalido) Error() string {
+	return e.mensaje("json-invalido", e.Linea, e.Columna, e.Err)
+}
+
+// Is hace que el error sea equivalente a ErrJSONInvalido.
+func (e *ErrorJSONInvalido) Is(target error) bool {
+	return target == ErrJSONInvalido
+}
+
+func (e *ErrorJSONInvalido) Unwrap() error {
+	return e.Err
+}
+
+// ubicar completa Linea y Columna a partir del texto de entrada.
+func (e *ErrorJSONInvalido) ubicar(texto string) {
+	offset := int(min(max(e.Offset, 0), int64(len(texto))))
+	previo := texto[:offset]
+	e.Linea = strings.Count(previo, "\n") + 1
+	e.Columna = offset - strings.LastIndexByte(previo, '\n')
+}
+
+// ErrorClaveDuplicada indica que, con WithClavesUnicas, el documento repite
+// una clave de primer nivel. Se devuelve como Err de un *ErrorJSONInvalido
+// que indica dónde aparece la repetición.
+type ErrorClaveDuplicada struct {
+	localizable
+
+	Clave string
+}
+
+func (e *ErrorClaveDuplicada) Error() string {
+	return e.mensaje("clave-duplicada", e.Clave)
+}
+
+// Is hace que el error sea equivalente a ErrClaveDuplicada.
+func (e *ErrorClaveDuplicada) Is(target error) bool {
+	return target == ErrClaveDuplicada
+}
+
+// ErrorTipoNoSoportado indica que se recibió una entrada de un tipo que las
+// funciones de ordenamiento no saben procesar.
+type ErrorTipoNoSoportado struct {
+	localizable
+
+	Tipo reflect.Type // Tipo recibido; nil si la entrada era nil.
+}
+
+func (e *ErrorTipoNoSoportado) Error() string {
+	return e.mensaje("tipo-no-soportado", e.Tipo)
+}
+
+// Is hace que el error sea equivalente a ErrTipoNoSoportado.
+func (e *ErrorTipoNoSoportado) Is(target error) bool {
+	return target == ErrTipoNoSoportado
+}
+
+// ErrorCamposFaltantes indica que faltan campos marcados como obligatorios.
+type ErrorCamposFaltantes struct {
+	localizable
+
+	Campos []string // Campos obligatorios sin valor, en el orden en que se declararon.
+}
+
+func (e *ErrorCamposFaltantes) Error() string {
+	return e.mensaje("campos-faltantes", strings.Join(e.Campos, ", "))
+}
+
+// Is hace que el error sea equivalente a ErrCamposFaltantes.
+func (e *ErrorCamposFaltantes) Is(target error) bool {
+	return target == ErrCamposFaltantes
+}
+
+// ErrorClavesNoPermitidas indica que, en modo estricto, el documento contiene
+// claves que no pertenecen al perfil.
+type ErrorClavesNoPermitidas struct {
+	localizable
+
+	Claves []string // Claves desconocidas, en el orden en que aparecen en el documento.
+}
+
+func (e *ErrorClavesNoPermitidas) Error() string {
+	return e.mensaje("claves-no-permitidas", strings.Join(e.Claves, ", "))
+}
+
+// Is hace que el error sea equivalente a ErrClavesNoPermitidas.
+func (e *ErrorClavesNoPermitidas) Is(target error) bool {
+	return target == ErrClavesNoPermitidas
+}
+
+// ErrorFechaInvalida indica que un campo de fecha no se pudo interpretar con
+// ninguno de los formatos aceptados.
+type ErrorFechaInvalida struct {
+	localizable
+
+	Campo    string
+	Valor    interface{}
+	Formatos []string // Formatos que se probaron.
+}
+
+func (e *ErrorFechaInvalida) Error() string {
+	if _, ok := e.Valor.(string); !ok {
+		return e.mensaje("fecha-no-texto", e.Campo, e.Valor)
+	}
+	return e.mensaje("fecha-invalida", e.Campo, e.Valor)
+}
+
+// Is hace que el error sea equivalente a ErrFechaInvalida.
+func (e *ErrorFechaInvalida) Is(target error) bool {
+	return target == ErrFechaInvalida
+}
+
+// ErrorRegla indica que un campo no cumple una regla de validación del perfil,
+// como las derivadas de un esquema.
+type ErrorRegla struct {
+	localizable
+
+	Campo   string
+	Regla   string // Nombre de la regla: "type", "pattern", "format", "minLength" o "maxLength".
+	Detalle string // Descripción del incumplimiento, siempre en español.
+
+	datos []interface{} // Argumentos del mensaje de la regla en el catálogo; nil usa Detalle.
+}
+
+// nuevoErrorRegla crea el error de una regla con el detalle del catálogo
+// armado con datos, en español.
+func nuevoErrorRegla(campo, regla string, datos ...interface{}) *ErrorRegla {
+	e := &ErrorRegla{Campo: campo, Regla: regla, datos: datos}
+	e.Detalle = fmt.Sprintf(catalogoMensajes[IdiomaEspanol]["regla "+regla], datos...)
+	return e
+}
+
+func (e *ErrorRegla) Error() string {
+	detalle := e.Detalle
+	if e.datos != nil {
+		detalle = e.mensaje("regla "+e.Regla, e.datos...)
+	}
+	return e.mensaje("regla", e.Campo, detalle)
+}
+
+// Is hace que el error sea equivalente a ErrRegla.
+func (e *ErrorRegla) Is(target error) bool {
+	return target == ErrRegla
+}
+
+// ErrorValorNoSerializable indica que el valor de un campo no se puede
+// representar en JSON, por ejemplo un canal o un número NaN dentro de un mapa.
+type ErrorValorNoSerializable struct {
+	localizable
+
+	Campo string
+	Err   error
+}
+
+func (e *ErrorValorNoSerializable) Error() string {
+	return e.mensaje("valor-no-serializable", e.Campo, e.Err)
+}
+
+// Is hace que el error sea equivalente a ErrValorNoSerializable.
+func (e *ErrorValorNoSerializable) Is(target error) bool {
+	return target == ErrValorNoSerializable
+}
+
+func (e *ErrorValorNoSerializable) Unwrap() error {
+	return e.Err
+}
+
+// ErrorEsquemaInvalido indica que un JSON Schema no se pudo convertir en perfil.
+type ErrorEsquemaInvalido struct {
+	localizable
+
+	Propiedad string // Propiedad con la declaración inválida; vacía si el problema es general.
+	Err       error
+}
+
+func (e *ErrorEsquemaInvalido) Error() string {
+	if e.Propiedad != "" {
+		return e.mensaje("esquema-invalido-campo", e.Propiedad, e.Err)
+	}
+	return e.mensaje("esquema-invalido", e.Err)
+}
+
+// Is hace que el error sea equivalente a ErrEsquemaInvalido.
+func (e *ErrorEsquemaInvalido) Is(target error) bool {
+	return target == ErrEsquemaInvalido
+}
+
+func (e *ErrorEsquemaInvalido) Unwrap() error {
+	return e.Err
+}
+
+// ErrorPatch indica que una operación de un JSON Patch no se pudo aplicar
+// con AplicarPatch, por lo que el documento no se modificó.
+type ErrorPatch struct {
+	localizable
+
+	Indice int    // Posición de la operación en el patch, comenzando en 0.
+	Op     string // Operación: add, remove, replace, move, copy o test.
+	Ruta   string // Valor de "path" de la operación.
+	Err    error
+}
+
+func (e *ErrorPatch) Error() string {
+	return e.mensaje("patch", e.Indice, e.Op, e.Ruta, e.Err)
+}
+
+// Is hace que el error sea equivalente a ErrPatch.
+func (e *ErrorPatch) Is(target error) bool {
+	return target == ErrPatch
+}
+
+func (e *ErrorPatch) Unwrap() error {
+	return e.Err
+}
+
+// ErrorPerfilDesconocido indica que no hay un perfil registrado con el nombre
+// pedido; ver RegistrarPerfil.
+type ErrorPerfilDesconocido struct {
+	localizable
+
+	Nombre string
+}
+
+func (e *ErrorPerfilDesconocido) Error() string {
+	return e.mensaje("perfil-desconocido", e.Nombre)
+}
+
+// Is hace que el error sea equivalente a ErrPerfilDesconocido.
+func (e *ErrorPerfilDesconocido) Is(target error) bool {
+	return target == ErrPerfilDesconocido
+}
+
+// ErrorTipoDocumentoDesconocido indica que no hay una familia registrada
+// para el valor de tanner:tipo-documento de un documento; ver
+// RegisterTipoDocumento.
+type ErrorTipoDocumentoDesconocido struct {
+	localizable
+
+	Tipo string // Vacío si el documento no tiene tipo.
+}
+
+func (e *ErrorTipoDocumentoDesconocido) Error() string {
+	return e.mensaje("tipo-documento-desconocido", e.Tipo)
+}
+
+// Is hace que el error sea equivalente a ErrTipoDocumentoDesconocido.
+func (e *ErrorTipoDocumentoDesconocido) Is(target error) bool {
+	return target == ErrTipoDocumentoDesconocido
+}
+
+// Conflicto describe un campo al que dos fragmentos de Componer le dan
+// valores distintos.
+type Conflicto struct {
+	Ruta       string         // JSON Pointer del campo, como "/cm:title" o "/cliente/rut".
+	Fragmentos [2]int         // Posiciones de los dos fragmentos, desde 0.
+	Valores    [2]interface{} // Valor de cada fragmento, en el mismo orden.
+}
+
+// ErrorConflictos indica que los fragmentos de Componer no se pueden unir
+// porque tienen valores distintos para los mismos campos.
+type ErrorConflictos struct {
+	localizable
+
+	Conflictos []Conflicto // En orden canónico de los campos.
+}
+
+func (e *ErrorConflictos) Error() string {
+	detalles := make([]string, len(e.Conflictos))
+	for i, c := range e.Conflictos {
+		detalles[i] = e.mensaje("conflicto", c.Ruta, c.Fragmentos[0], c.Fragmentos[1])
+	}
+	return e.mensaje("conflictos", strings.Join(detalles, ", "))
+}
+
+// Is hace que el error sea equivalente a ErrConflictos.
+func (e *ErrorConflictos) Is(target error) bool {
+	return target == ErrConflictos
+}
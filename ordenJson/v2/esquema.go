@@ -0,0 +1,215 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// reglaCampo agrupa las restricciones declaradas para un campo del perfil.
+type reglaCampo struct {
+	tipos    []string      // Tipos JSON admitidos ("string", "integer", ...); vacío admite cualquiera.
+	valores  []interface{} // Valores admitidos (enum); vacío admite cualquiera.
+	patron   *regexp.Regexp
+	formato  string // "date-time" o "date".
+	minLargo int    // Largo mínimo de una cadena; -1 si no aplica.
+	maxLargo int    // Largo máximo de una cadena; -1 si no aplica.
+}
+
+// esquemaJSON es el subconjunto de JSON Schema que se utiliza para construir perfiles.
+type esquemaJSON struct {
+	Titulo                 string          `json:"title"`
+	ID                     string          `json:"$id"`
+	Propiedades            json.RawMessage `json:"properties"`
+	Requeridos             []string        `json:"required"`
+	PropiedadesAdicionales json.RawMessage `json:"additionalProperties"`
+}
+
+// propiedadEsquema describe las restricciones de una propiedad en el esquema.
+type propiedadEsquema struct {
+	Tipo      json.RawMessage `json:"type"`
+	Enum      []interface{}   `json:"enum"`
+	Patron    string          `json:"pattern"`
+	Formato   string          `json:"format"`
+	MinLength *int            `json:"minLength"`
+	MaxLength *int            `json:"maxLength"`
+}
+
+// PerfilDesdeEsquema construye un perfil a partir de un JSON Schema de tipo objeto.
+// El orden de los campos es el orden en que se declaran las propiedades en
+// "properties", y las reglas de validación se derivan de las palabras clave
+// soportadas: "required", "additionalProperties": false (equivale a WithStrict)
+// y, por propiedad, "type", "enum", "pattern", "format" ("date-time" o "date"),
+// "minLength" y "maxLength". Las demás palabras clave se ignoran.
+// El nombre del perfil se toma de "title" o, en su defecto, de "$id".
+func PerfilDesdeEsquema(esquema []byte) (*Perfil, error) {
+	var e esquemaJSON
+	if err := json.Unmarshal(esquema, &e); err != nil {
+		return nil, &ErrorEsquemaInvalido{Err: err}
+	}
+	if len(e.Propiedades) == 0 {
+		return nil, &ErrorEsquemaInvalido{Err: errors.New("no declara properties")}
+	}
+
+	// Obtener las propiedades en el orden en que se declaran.
+	_, campos, err := decodificarObjeto(string(e.Propiedades))
+	if err != nil {
+		return nil, &ErrorEsquemaInvalido{Err: err}
+	}
+	var propiedades map[string]propiedadEsquema
+	if err := json.Unmarshal(e.Propiedades, &propiedades); err != nil {
+		return nil, &ErrorEsquemaInvalido{Err: err}
+	}
+
+	nombre := e.Titulo
+	if nombre == "" {
+		nombre = e.ID
+	}
+	p := NuevoPerfil(nombre, campos)
+	p.requeridos = e.Requeridos
+	p.estricto = string(e.PropiedadesAdicionales) == "false"
+	p.reglas = make(map[string]*reglaCampo, len(propiedades))
+	for _, campo := range campos {
+		regla, err := nuevaReglaCampo(propiedades[campo])
+		if err != nil {
+			return nil, &ErrorEsquemaInvalido{Propiedad: campo, Err: err}
+		}
+		p.reglas[campo] = regla
+	}
+	return p, nil
+}
+
+// nuevaReglaCampo convierte la declaración de una propiedad en su regla de validación.
+func nuevaReglaCampo(prop propiedadEsquema) (*reglaCampo, error) {
+	regla := &reglaCampo{valores: prop.Enum, formato: prop.Formato, minLargo: -1, maxLargo: -1}
+
+	// "type" puede ser una cadena o una lista de cadenas.
+	if len(prop.Tipo) > 0 {
+		var tipo string
+		if err := json.Unmarshal(prop.Tipo, &tipo); err == nil {
+			regla.tipos = []string{tipo}
+		} else if err := json.Unmarshal(prop.Tipo, &regla.tipos); err != nil {
+			return nil, errors.New("type debe ser una cadena o una lista de cadenas")
+		}
+	}
+	if prop.Patron != "" {
+		patron, err := regexp.Compile(prop.Patron)
+		if err != nil {
+			return nil, fmt.Errorf("pattern inválido: %w", err)
+		}
+		regla.patron = patron
+	}
+	if prop.MinLength != nil {
+		regla.minLargo = *prop.MinLength
+	}
+	if prop.MaxLength != nil {
+		regla.maxLargo = *prop.MaxLength
+	}
+	return regla, nil
+}
+
+// validarValor comprueba el valor de campo contra la regla y devuelve un error
+// que describe la primera infracción.
+func (r *reglaCampo) validarValor(campo string, valor interface{}) error {
+	if len(r.tipos) > 0 && !tipoAdmitido(valor, r.tipos) {
+		return nuevoErrorRegla(campo, "type", tipoJSON(valor), r.tipos)
+	}
+	if len(r.valores) > 0 && !valorAdmitido(valor, r.valores) {
+		return &ErrorValorNoPermitido{Campo: campo, Valor: valor, Permitidos: r.valores}
+	}
+
+	texto, ok := valor.(string)
+	if !ok {
+		return nil
+	}
+	largo := utf8.RuneCountInString(texto)
+	if r.minLargo >= 0 && largo < r.minLargo {
+		return nuevoErrorRegla(campo, "minLength", largo, r.minLargo)
+	}
+	if r.maxLargo >= 0 && largo > r.maxLargo {
+		return nuevoErrorRegla(campo, "maxLength", largo, r.maxLargo)
+	}
+	if r.patron != nil && !r.patron.MatchString(texto) {
+		return nuevoErrorRegla(campo, "pattern", texto, r.patron)
+	}
+	switch r.formato {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, texto); err != nil {
+			return nuevoErrorRegla(campo, "format", texto, "date-time")
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", texto); err != nil {
+			return nuevoErrorRegla(campo, "format", texto, "date")
+		}
+	}
+	return nil
+}
+
+// tipoAdmitido indica si el tipo JSON del valor está entre los admitidos.
+// Un número entero también satisface el tipo "number".
+func tipoAdmitido(valor interface{}, tipos []string) bool {
+	tipo := tipoJSON(valor)
+	for _, admitido := range tipos {
+		if admitido == tipo || (admitido == "number" && tipo == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// tipoJSON devuelve el nombre del tipo JSON Schema que corresponde al valor.
+func tipoJSON(valor interface{}) string {
+	if valor == nil {
+		return "null"
+	}
+	v := reflect.ValueOf(valor)
+	switch v.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return v.Kind().String()
+}
+
+// valorAdmitido indica si el valor es igual a alguno de los admitidos.
+// Los números se comparan por valor, sin importar su tipo Go.
+func valorAdmitido(valor interface{}, admitidos []interface{}) bool {
+	for _, admitido := range admitidos {
+		if reflect.DeepEqual(normalizarNumero(valor), normalizarNumero(admitido)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizarNumero convierte cualquier número a float64 para poder compararlo.
+func normalizarNumero(valor interface{}) interface{} {
+	v := reflect.ValueOf(valor)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return valor
+}
@@ -0,0 +1,204 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+)
+
+// bitsRegistrosHLL define la cantidad de registros (2^bitsRegistrosHLL) del
+// estimador de valores distintos de cada campo. Con 12 bits el error típico
+// es de alrededor de 1,6 % y cada campo ocupa 4 KiB.
+const bitsRegistrosHLL = 12
+
+// Estadisticas ordena documentos igual que un Ordenador y, al mismo tiempo,
+// acumula estadísticas por campo sobre todo el corpus procesado: cuántos
+// documentos lo traen y con valor, cuántos valores distintos tiene
+// (aproximado) y, para los campos de fecha, la fecha mínima y máxima. La
+// memoria usada depende de la cantidad de campos, no de la de documentos.
+// Los campos que WithOnly y WithExclude omiten de la salida también se
+// cuentan. Es seguro usarlo desde varias goroutines a la vez.
+type Estadisticas struct {
+	ordenador     *Ordenador
+	formatosFecha []string // El formato de salida, para las fechas ya normalizadas, y los configurados.
+
+	mu         sync.Mutex
+	documentos int
+	campos     map[string]*estadisticaCampo
+}
+
+// estadisticaCampo acumula los datos de un campo.
+type estadisticaCampo struct {
+	presentes int
+	llenos    int
+	distintos [1 << bitsRegistrosHLL]uint8 // Registros de HyperLogLog.
+	fechaMin  time.Time
+	fechaMax  time.Time
+	fechas    int // Valores interpretados como fecha.
+}
+
+// ResumenEstadisticas es el documento que resume las estadísticas acumuladas.
+type ResumenEstadisticas struct {
+	Documentos int            `json:"documentos"`
+	Campos     []ResumenCampo `json:"campos"` // En el orden del perfil; los campos fuera del perfil al final, alfabéticamente.
+}
+
+// ResumenCampo resume las estadísticas de un campo.
+type ResumenCampo struct {
+	Campo            string  `json:"campo"`
+	Presentes        int     `json:"presentes"`              // Documentos que traen la clave.
+	Llenos           int     `json:"llenos"`                 // Documentos en que la clave tiene un valor no vacío.
+	TasaLlenado      float64 `json:"tasa-llenado"`           // Llenos sobre el total de documentos.
+	ValoresDistintos int     `json:"valores-distintos"`      // Estimación de la cantidad de valores distintos no vacíos.
+	FechaMinima      string  `json:"fecha-minima,omitempty"` // Solo en campos de fecha, en FormatoFechaCanonico.
+	FechaMaxima      string  `json:"fecha-maxima,omitempty"`
+}
+
+// NuevasEstadisticas crea un acumulador que ordena con las opciones
+// recibidas. Los campos de fecha y sus formatos son los configurados con
+// WithCamposFecha y WithNormalizarFechas (o los valores por defecto).
+func NuevasEstadisticas(opts ...Option) *Estadisticas {
+	e := &Estadisticas{ordenador: Nuevo(opts...), campos: make(map[string]*estadisticaCampo)}
+	e.formatosFecha = append([]string{e.ordenador.cfg.formatoFecha()}, e.ordenador.cfg.formatosFecha...)
+	e.ordenador.cfg.observar = e.registrar
+	return e
+}
+
+// OrdenarJSON ordena el documento como Ordenador.OrdenarJSON y, si es válido,
+// lo incorpora a las estadísticas. Los documentos que no se pueden ordenar no
+// se cuentan.
+func (e *Estadisticas) OrdenarJSON(input interface{}) (string, error) {
+	return e.ordenador.OrdenarJSON(input)
+}
+
+// registrar incorpora un documento a las estadísticas.
+func (e *Estadisticas) registrar(datos map[string]interface{}, claves []string) {
+	cfg := &e.ordenador.cfg
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.documentos++
+	for _, clave := range claves {
+		est := e.campos[clave]
+		if est == nil {
+			est = &estadisticaCampo{}
+			e.campos[clave] = est
+		}
+		est.presentes++
+		valor := datos[clave]
+		if texto, ok := valor.(string); (ok && cfg.esVacio(texto)) || valor == nil {
+			continue
+		}
+		est.llenos++
+		est.agregarDistinto(valor)
+	}
+	for _, campo := range cfg.camposFecha {
+		texto, ok := datos[campo].(string)
+		if !ok || texto == "" {
+			continue
+		}
+		t, ok := interpretarFecha(texto, e.formatosFecha)
+		if !ok {
+			continue
+		}
+		est := e.campos[campo]
+		if est == nil {
+			continue
+		}
+		if est.fechas == 0 || t.Before(est.fechaMin) {
+			est.fechaMin = t
+		}
+		if est.fechas == 0 || t.After(est.fechaMax) {
+			est.fechaMax = t
+		}
+		est.fechas++
+	}
+}
+
+// agregarDistinto incorpora un valor al estimador de valores distintos.
+func (est *estadisticaCampo) agregarDistinto(valor interface{}) {
+	h := fnv.New64a()
+	if texto, ok := valor.(string); ok {
+		h.Write([]byte(texto))
+	} else {
+		codificado, _ := json.Marshal(valor)
+		h.Write(codificado)
+	}
+	x := mezclar(h.Sum64())
+	registro := x >> (64 - bitsRegistrosHLL)
+	rango := uint8(bits.LeadingZeros64(x<<bitsRegistrosHLL|1<<(bitsRegistrosHLL-1))) + 1
+	if rango > est.distintos[registro] {
+		est.distintos[registro] = rango
+	}
+}
+
+// estimarDistintos aplica el estimador de HyperLogLog, con la corrección para
+// cardinalidades pequeñas.
+func (est *estadisticaCampo) estimarDistintos() int {
+	const m = 1 << bitsRegistrosHLL
+	suma, ceros := 0.0, 0
+	for _, r := range est.distintos {
+		suma += math.Ldexp(1, -int(r))
+		if r == 0 {
+			ceros++
+		}
+	}
+	alfa := 0.7213 / (1 + 1.079/m)
+	estimado := alfa * m * m / suma
+	if estimado <= 2.5*m && ceros > 0 {
+		estimado = m * math.Log(float64(m)/float64(ceros))
+	}
+	return int(math.Round(estimado))
+}
+
+// mezclar dispersa los bits de un hash (finalizador de splitmix64) para que
+// los bits altos, que usa HyperLogLog, sean uniformes.
+func mezclar(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Resumen devuelve las estadísticas acumuladas hasta el momento.
+func (e *Estadisticas) Resumen() ResumenEstadisticas {
+	perfil := e.ordenador.cfg.perfil
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	resumen := ResumenEstadisticas{Documentos: e.documentos, Campos: make([]ResumenCampo, 0, len(e.campos))}
+	for campo, est := range e.campos {
+		r := ResumenCampo{
+			Campo:            campo,
+			Presentes:        est.presentes,
+			Llenos:           est.llenos,
+			ValoresDistintos: est.estimarDistintos(),
+		}
+		if e.documentos > 0 {
+			r.TasaLlenado = float64(est.llenos) / float64(e.documentos)
+		}
+		if est.fechas > 0 {
+			r.FechaMinima = est.fechaMin.Format(FormatoFechaCanonico)
+			r.FechaMaxima = est.fechaMax.Format(FormatoFechaCanonico)
+		}
+		resumen.Campos = append(resumen.Campos, r)
+	}
+	sort.Slice(resumen.Campos, func(i, j int) bool {
+		a, b := resumen.Campos[i].Campo, resumen.Campos[j].Campo
+		if pa, pb := perfil.posicion(a), perfil.posicion(b); pa != pb {
+			return pa < pb
+		}
+		return a < b
+	})
+	return resumen
+}
+
+// JSON devuelve el resumen serializado e indentado con dos espacios.
+func (r ResumenEstadisticas) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
@@ -0,0 +1,108 @@
+package ordenJson
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// entradaStruct es el input con que OrdenarStruct llama a ordenar: un struct
+// que se lee con el plan de su tipo, como entradaMetadata.
+type entradaStruct struct {
+	plan *planStruct
+	base unsafe.Pointer // Inicio del struct.
+	// raiz es el puntero recibido, si se recibió uno, para detectar los
+	// campos que vuelven a apuntar al struct.
+	raiz visita
+}
+
+// OrdenarStruct ordena los campos no vacíos del struct v. Ver
+// Ordenador.OrdenarStruct.
+func OrdenarStruct(v interface{}, opts ...Option) (string, error) {
+	return Nuevo(opts...).OrdenarStruct(v)
+}
+
+// OrdenarStruct ordena los campos no vacíos de v, un struct o un puntero a
+// struct, igual que OrdenarDocumentoMetadata: se usan los campos con
+// etiqueta json, con la clave de la etiqueta.
+//
+// Además de cadenas, los campos pueden ser números, booleanos, time.Time
+// (que se escriben con FormatoFechaCanonico o el layout de
+// WithFormatoSalidaFecha), slices y arreglos de esos tipos, y structs
+// anidados, que se escriben como objetos con sus campos de etiqueta json.
+// Los tipos que implementan json.Marshaler o fmt.Stringer, como un RUT o una
+// versión, se escriben con su propia representación: el JSON de MarshalJSON
+// o, si no lo implementan, el texto de String. Los campos de otros tipos,
+// como mapas, se ignoran. Los campos con el valor cero de su tipo se omiten,
+// igual que las cadenas vacías, salvo que WithCamposVacios indique otra
+// cosa. Un puntero nil cuenta como campo no informado y uno no nil se
+// escribe con el valor al que apunta, aunque sea vacío, por lo que *string o
+// *time.Time distinguen un dato informado vacío de uno no informado.
+//
+// Los campos de los structs embebidos sin nombre en la etiqueta json se
+// escriben como campos del struct que los embebe, como en encoding/json, lo
+// que permite armar los tipos de metadatos a partir de structs comunes. Si
+// un struct embebido por puntero es nil, sus campos no están informados.
+//
+// La prioridad de un campo se puede declarar junto a él con la etiqueta
+// orden:
+//
+//	type CamposCm struct {
+//		Titulo string `json:"cm:title"`
+//	}
+//
+//	type Factura struct {
+//		CamposCm
+//		Folio int `json:"folio" orden:"1"`
+//	}
+//
+// Los campos con etiqueta orden van primero, de menor a mayor valor (con el
+// mismo valor, en el orden de declaración), y el resto sigue el orden del
+// perfil. Para WithStrict, los campos con etiqueta orden forman parte del
+// perfil.
+//
+// Si v no es un struct devuelve un *ErrorTipoNoSoportado, si una etiqueta
+// orden no es un entero, un error que la indica, y si un campo se contiene a
+// sí mismo a través de punteros, un *ErrorValorNoSerializable.
+func (o *Ordenador) OrdenarStruct(v interface{}) (string, error) {
+	inicio := time.Now()
+	entrada, perfil, err := o.entradaStruct(v)
+	if err != nil {
+		localizarError(err, o.cfg.idioma)
+		o.cfg.registrarEvento(OperacionOrdenar, inicio, v, "", nil, err)
+		return "", err
+	}
+	cfg := o.cfg
+	cfg.perfil = perfil
+	salida, _, err := ordenar(entrada, &cfg)
+	cfg.registrarEvento(OperacionOrdenar, inicio, v, salida, nil, err)
+	return salida, err
+}
+
+// entradaStruct prepara v para ordenar y devuelve el perfil con que se
+// ordena, el del Ordenador con los campos de etiqueta orden antepuestos.
+func (o *Ordenador) entradaStruct(v interface{}) (*entradaStruct, *Perfil, error) {
+	valor := reflect.ValueOf(v)
+	var puntero reflect.Value
+	for valor.Kind() == reflect.Pointer && !valor.IsNil() {
+		puntero, valor = valor, valor.Elem()
+	}
+	if valor.Kind() != reflect.Struct {
+		return nil, nil, &ErrorTipoNoSoportado{Tipo: reflect.TypeOf(v)}
+	}
+	plan := planDe(valor.Type())
+	if plan.err != nil {
+		return nil, nil, plan.err
+	}
+	entrada := &entradaStruct{plan: plan}
+	if puntero.IsValid() {
+		entrada.base = puntero.UnsafePointer()
+		entrada.raiz = visita{direccion: entrada.base, tipo: puntero.Type()}
+	} else {
+		// Un struct recibido por valor no es direccionable: se lee una copia.
+		copia := reflect.New(valor.Type())
+		copia.Elem().Set(valor)
+		entrada.base = copia.UnsafePointer()
+	}
+	return entrada, plan.perfil(o.cfg.perfil), nil
+}
@@ -0,0 +1,222 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+)
+
+// EsquemaEventos es la versión del formato de Evento. Se incrementa solo si
+// un cambio deja de ser compatible: quitar o renombrar un campo, o cambiar su
+// tipo o significado. Agregar campos nuevos no cambia la versión.
+const EsquemaEventos = 1
+
+// Operaciones que se informan en Evento.Operacion.
+const (
+	OperacionOrdenar     = "ordenar"     // OrdenarJSON y OrdenarDocumentoMetadata.
+	OperacionReporte     = "reporte"     // OrdenarJSONConReporte.
+	OperacionParticionar = "particionar" // Particionar.
+	OperacionDeduplicar  = "deduplicar"  // Deduplicador.Visto.
+)
+
+// Resultados que se informan en Evento.Resultado.
+const (
+	ResultadoOK        = "ok"            // La operación terminó sin errores.
+	ResultadoProblemas = "con-problemas" // OrdenarJSONConReporte encontró problemas con SeveridadError.
+	ResultadoError     = "error"         // La operación devolvió un error.
+)
+
+// Tipos de error que se informan en Evento.TipoError; ver TipoDeError.
+const (
+	TipoErrorJSONInvalido        = "json-invalido"         // *ErrorJSONInvalido.
+	TipoErrorCamposFaltantes     = "campos-faltantes"      // *ErrorCamposFaltantes.
+	TipoErrorClavesNoPermitidas  = "claves-no-permitidas"  // *ErrorClavesNoPermitidas.
+	TipoErrorValorNoPermitido    = "valor-no-permitido"    // *ErrorValorNoPermitido.
+	TipoErrorFechaInvalida       = "fecha-invalida"        // *ErrorFechaInvalida.
+	TipoErrorRegla               = "regla"                 // *ErrorRegla.
+	TipoErrorLimiteExcedido      = "limite-excedido"       // *ErrorLimiteExcedido.
+	TipoErrorTiempoExcedido      = "tiempo-excedido"       // *ErrorTiempoExcedido.
+	TipoErrorCancelado           = "cancelado"             // *ErrorCancelado.
+	TipoErrorTipoNoSoportado     = "tipo-no-soportado"     // *ErrorTipoNoSoportado.
+	TipoErrorValorNoSerializable = "valor-no-serializable" // *ErrorValorNoSerializable.
+	TipoErrorOtro                = "otro"                  // Cualquier otro error.
+)
+
+// Evento describe una operación sobre un documento. Su serialización JSON, un
+// objeto por línea en RegistroJSON, es el formato estable de los registros de
+// eventos:
+//
+//	{
+//	  "esquema": 1,
+//	  "fecha": "2024-05-02T13:04:05.123456789Z",
+//	  "operacion": "ordenar",
+//	  "hash": "9f1c2a3b4d5e6f70",
+//	  "resultado": "error",
+//	  "duracion_ns": 41250,
+//	  "bytes": 512,
+//	  "problemas": 2,
+//	  "error": "faltan campos requeridos: [tanner:rut-cliente]"
+//	}
+//
+// hash es el FNV-64a, en hexadecimal, de la salida canónica cuando la
+// operación la produjo, de modo que un mismo documento con las claves en otro
+// orden tiene el mismo hash; si no, es el de la entrada tal como se recibió, y
+// se omite si la entrada no era texto. bytes, problemas y error se omiten
+// cuando no corresponden. bytes_entrada y tipo_error, agregados después, se
+// omiten además cuando la entrada no era texto o no hubo error.
+type Evento struct {
+	Esquema   int           `json:"esquema"`
+	Fecha     time.Time     `json:"fecha"`
+	Operacion string        `json:"operacion"`
+	Hash      string        `json:"hash,omitempty"`
+	Resultado string        `json:"resultado"`
+	Duracion  time.Duration `json:"duracion_ns"`
+	Bytes     int           `json:"bytes,omitempty"`     // Tamaño de la salida.
+	Problemas int           `json:"problemas,omitempty"` // Problemas informados por OrdenarJSONConReporte.
+	Error     string        `json:"error,omitempty"`
+
+	BytesEntrada int    `json:"bytes_entrada,omitempty"` // Tamaño de la entrada, si era texto.
+	TipoError    string `json:"tipo_error,omitempty"`    // Ver las constantes TipoError*.
+}
+
+// RegistroDeEventos recibe un Evento por cada operación. Registrar se llama
+// de forma sincrónica y puede llamarse desde varias goroutines a la vez.
+type RegistroDeEventos interface {
+	Registrar(Evento)
+}
+
+// RegistroFunc adapta una función para usarla como RegistroDeEventos.
+type RegistroFunc func(Evento)
+
+// Registrar llama a f.
+func (f RegistroFunc) Registrar(e Evento) {
+	f(e)
+}
+
+// WithRegistroDeEventos envía a r un Evento por cada documento que se ordena,
+// reporta, particiona o deduplica con la configuración.
+func WithRegistroDeEventos(r RegistroDeEventos) Option {
+	return func(cfg *configuracion) {
+		cfg.eventos = r
+	}
+}
+
+// RegistroJSON escribe cada Evento como un objeto JSON en una línea.
+type RegistroJSON struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NuevoRegistroJSON crea un registro que escribe en w.
+func NuevoRegistroJSON(w io.Writer) *RegistroJSON {
+	return &RegistroJSON{w: w}
+}
+
+// Registrar escribe e. Después del primer error de escritura se descartan
+// los eventos siguientes; ver Err.
+func (r *RegistroJSON) Registrar(e Evento) {
+	linea, err := json.Marshal(e)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err == nil {
+		_, err = r.w.Write(append(linea, '\n'))
+	}
+	r.err = err
+}
+
+// Err devuelve el primer error al escribir un evento, o nil.
+func (r *RegistroJSON) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// registrarEvento envía a cfg.eventos, si está configurado, el evento de una
+// operación que comenzó en inicio.
+func (cfg *configuracion) registrarEvento(operacion string, inicio time.Time, input interface{}, salida string, problemas []Problema, err error) {
+	if cfg.eventos == nil {
+		return
+	}
+	e := Evento{
+		Esquema:   EsquemaEventos,
+		Fecha:     inicio.UTC(),
+		Operacion: operacion,
+		Resultado: ResultadoOK,
+		Duracion:  time.Since(inicio),
+		Bytes:     len(salida),
+		Problemas: len(problemas),
+	}
+	texto, esTexto := input.(string)
+	if esTexto {
+		e.BytesEntrada = len(texto)
+	}
+	switch {
+	case salida != "":
+		e.Hash = hashEvento(salida)
+	case esTexto:
+		e.Hash = hashEvento(texto)
+	}
+	if err != nil {
+		e.Resultado, e.Error, e.TipoError = ResultadoError, err.Error(), TipoDeError(err)
+	} else if NuevoReporte(problemas).Errores > 0 {
+		e.Resultado = ResultadoProblemas
+	}
+	cfg.eventos.Registrar(e)
+}
+
+// hashEvento devuelve el FNV-64a de texto en hexadecimal.
+func hashEvento(texto string) string {
+	h := fnv.New64a()
+	io.WriteString(h, texto)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// TipoDeError clasifica un error devuelto por el paquete con una de las
+// constantes TipoError*, por ejemplo para agrupar errores en métricas sin
+// depender de su mensaje. Los errores envueltos, como los de OrdenarLote y
+// OrdenarFlujo que indican la posición del documento, se clasifican por el
+// error de ordenamiento que contienen. Devuelve "" si err es nil.
+func TipoDeError(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, tipo := range tiposDeError {
+		if tipo.es(err) {
+			return tipo.nombre
+		}
+	}
+	return TipoErrorOtro
+}
+
+// tiposDeError asocia cada constante TipoError* con su tipo de error, de los
+// que envuelven a otros a los que no.
+var tiposDeError = []struct {
+	nombre string
+	es     func(error) bool
+}{
+	{TipoErrorCancelado, esError[*ErrorCancelado]},
+	{TipoErrorTiempoExcedido, esError[*ErrorTiempoExcedido]},
+	{TipoErrorLimiteExcedido, esError[*ErrorLimiteExcedido]},
+	{TipoErrorJSONInvalido, esError[*ErrorJSONInvalido]},
+	{TipoErrorValorNoSerializable, esError[*ErrorValorNoSerializable]},
+	{TipoErrorTipoNoSoportado, esError[*ErrorTipoNoSoportado]},
+	{TipoErrorCamposFaltantes, esError[*ErrorCamposFaltantes]},
+	{TipoErrorClavesNoPermitidas, esError[*ErrorClavesNoPermitidas]},
+	{TipoErrorValorNoPermitido, esError[*ErrorValorNoPermitido]},
+	{TipoErrorFechaInvalida, esError[*ErrorFechaInvalida]},
+	{TipoErrorRegla, esError[*ErrorRegla]},
+}
+
+// esError indica si la cadena de err contiene un error de tipo T.
+func esError[T error](err error) bool {
+	var objetivo T
+	return errors.As(err, &objetivo)
+}
@@ -0,0 +1,115 @@
+package ordenJson
+
+import "time"
+
+// Criterios que se informan en DecisionClave.Criterio.
+const (
+	// CriterioPerfil: la clave es un campo del perfil y se ubica según su
+	// posición en él.
+	CriterioPerfil = "perfil"
+	// CriterioEntrada: la clave no está en el perfil, por lo que va después de
+	// los campos del perfil y conserva su orden relativo en la entrada (el
+	// alfabético si la entrada era un mapa).
+	CriterioEntrada = "entrada"
+	// CriterioOmitida: la clave estaba en la entrada pero no se escribió, por
+	// WithVacio, WithOnly o WithExclude, o porque WithRenombres la descartó
+	// en favor de la clave con el nombre actual.
+	CriterioOmitida = "omitida"
+)
+
+// DecisionClave explica dónde terminó una clave de primer nivel al ordenar un
+// documento con OrdenarJSONExplicado. Se serializa a JSON con las claves
+// clave, clave-original, posicion-original, criterio, posicion-perfil y
+// posicion-final. Las posiciones comienzan en 0.
+type DecisionClave struct {
+	Clave            string `json:"clave"`                    // Clave con la que se escribe, después de WithRenombres.
+	ClaveOriginal    string `json:"clave-original,omitempty"` // Clave en la entrada si WithRenombres la renombró.
+	PosicionOriginal int    `json:"posicion-original"`        // Posición en la entrada; -1 si la agregó WithDefaults.
+	Criterio         string `json:"criterio"`                 // Ver las constantes Criterio*.
+	PosicionPerfil   int    `json:"posicion-perfil"`          // Posición del campo en el perfil; -1 si no está en él.
+	PosicionFinal    int    `json:"posicion-final"`           // Posición en la salida; -1 si se omitió.
+}
+
+// trazaOrden guarda las claves de primer nivel de un documento al entrar al
+// ordenamiento y al salir de él.
+type trazaOrden struct {
+	originales []string // En el orden de la entrada, antes de WithRenombres.
+	finales    []string // En el orden de la salida.
+	perfil     *Perfil  // Perfil con que se ordenó, que WithTiposDocumento puede cambiar.
+}
+
+// OrdenarJSONExplicado ordena el documento con las opciones recibidas y
+// explica la posición de cada clave. Ver Ordenador.OrdenarJSONExplicado.
+func OrdenarJSONExplicado(input interface{}, opts ...Option) (string, []DecisionClave, error) {
+	return Nuevo(opts...).OrdenarJSONExplicado(input)
+}
+
+// OrdenarJSONExplicado ordena el documento igual que OrdenarJSON y devuelve,
+// junto con la salida, una DecisionClave por cada clave de primer nivel con
+// su posición en la entrada, el criterio que decidió su lugar y su posición
+// final, para depurar por qué un consumidor ve las claves en cierto orden.
+// Las decisiones siguen el orden de la salida, seguidas de las claves
+// omitidas en el orden de la entrada. Si hay un error no se devuelven
+// decisiones. La explicación desactiva la caché de WithCache y la ruta
+// rápida de los documentos planos, por lo que es más lento que OrdenarJSON.
+func (o *Ordenador) OrdenarJSONExplicado(input interface{}) (string, []DecisionClave, error) {
+	inicio := time.Now()
+	cfg := o.cfg
+	cfg.explicacion = &trazaOrden{}
+	salida, _, err := ordenar(input, &cfg)
+	cfg.registrarEvento(OperacionOrdenar, inicio, input, salida, nil, err)
+	if err != nil {
+		return "", nil, err
+	}
+	return salida, cfg.explicacion.decisiones(cfg.explicacion.perfil, cfg.renombres), nil
+}
+
+// decisiones arma la explicación de la traza con el perfil y los renombres
+// con que se ordenó.
+func (t *trazaOrden) decisiones(perfil *Perfil, renombres map[string]string) []DecisionClave {
+	posiciones := make(map[string]int, len(t.originales))
+	for i, clave := range t.originales {
+		posiciones[clave] = i
+	}
+	usadas := make(map[string]struct{}, len(t.finales))
+	decisiones := make([]DecisionClave, 0, len(t.originales)+len(t.finales))
+	for i, clave := range t.finales {
+		d := DecisionClave{Clave: clave, PosicionOriginal: -1, Criterio: CriterioEntrada, PosicionPerfil: posicionEnPerfil(perfil, clave), PosicionFinal: i}
+		// Una clave que el documento traía pero que se renombró, como en un
+		// intercambio, viene de otra clave anterior.
+		_, renombrada := renombres[clave]
+		if posicion, ok := posiciones[clave]; ok && !renombrada {
+			d.PosicionOriginal = posicion
+			usadas[clave] = struct{}{}
+		} else {
+			// La primera clave anterior que se renombró a esta, como en
+			// renombrarClaves.
+			for j, original := range t.originales {
+				if renombres[original] == clave {
+					d.ClaveOriginal, d.PosicionOriginal = original, j
+					usadas[original] = struct{}{}
+					break
+				}
+			}
+		}
+		if d.PosicionPerfil >= 0 {
+			d.Criterio = CriterioPerfil
+		}
+		decisiones = append(decisiones, d)
+	}
+	for i, clave := range t.originales {
+		if _, ok := usadas[clave]; !ok {
+			decisiones = append(decisiones, DecisionClave{Clave: clave, PosicionOriginal: i, Criterio: CriterioOmitida, PosicionPerfil: posicionEnPerfil(perfil, clave), PosicionFinal: -1})
+		}
+	}
+	return decisiones
+}
+
+// posicionEnPerfil devuelve la posición de la clave en el perfil, o -1 si no
+// es uno de sus campos.
+func posicionEnPerfil(perfil *Perfil, clave string) int {
+	if posicion := perfil.posicion(clave); posicion < len(perfil.campos) {
+		return posicion
+	}
+	return -1
+}
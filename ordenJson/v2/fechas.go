@@ -0,0 +1,120 @@
+package ordenJson
+
+import "time"
+
+// FormatoFechaCanonico es el layout al que se normalizan los campos de fecha.
+const FormatoFechaCanonico = "2006-01-02T15:04:05.000Z07:00"
+
+// CamposFecha lista los campos que contienen fechas y que se normalizan
+// cuando se utiliza WithNormalizarFechas.
+var CamposFecha = []string{
+	"tanner:fecha-carga",
+	"tanner:fecha-termino-vigencia",
+}
+
+// FormatosFechaPorDefecto son los layouts aceptados cuando WithNormalizarFechas
+// no recibe ninguno. Las fechas sin zona horaria se interpretan en UTC.
+var FormatosFechaPorDefecto = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02-01-2006 15:04:05",
+	"02/01/2006 15:04:05",
+	"02-01-2006",
+	"02/01/2006",
+}
+
+// WithNormalizarFechas activa la normalización de los campos de fecha al formato
+// FormatoFechaCanonico. Cada valor se interpreta probando los layouts recibidos
+// en orden; si no se indica ninguno se usan FormatosFechaPorDefecto.
+// Un valor que no coincide con ningún layout produce un *ErrorFechaInvalida.
+func WithNormalizarFechas(formatos ...string) Option {
+	return func(cfg *configuracion) {
+		cfg.normalizarFechas = true
+		if len(formatos) > 0 {
+			cfg.formatosFecha = formatos
+		}
+	}
+}
+
+// WithFormatoSalidaFecha indica el layout con que se escriben las fechas: los
+// campos time.Time de los structs que se ordenan con OrdenarStruct y los
+// campos que normaliza WithNormalizarFechas. Por defecto se usa
+// FormatoFechaCanonico, ISO 8601 con milisegundos. Al normalizar, las fechas
+// que ya tienen el layout se aceptan aunque no esté entre los formatos de
+// entrada, por lo que normalizar de nuevo la salida no la cambia. Un layout
+// vacío restablece el formato por defecto.
+func WithFormatoSalidaFecha(layout string) Option {
+	return func(cfg *configuracion) {
+		cfg.layoutFecha = layout
+	}
+}
+
+// formatoFecha devuelve el layout de WithFormatoSalidaFecha o, si no hay uno,
+// FormatoFechaCanonico.
+func (cfg *configuracion) formatoFecha() string {
+	if cfg.layoutFecha != "" {
+		return cfg.layoutFecha
+	}
+	return FormatoFechaCanonico
+}
+
+// WithCamposFecha reemplaza la lista de campos que se tratan como fechas.
+func WithCamposFecha(campos ...string) Option {
+	return func(cfg *configuracion) {
+		cfg.camposFecha = campos
+	}
+}
+
+// normalizarFechas reescribe en datos los campos de fecha configurados y
+// devuelve un error por cada campo que no se pudo interpretar; esos campos
+// quedan sin cambios. Los valores nulos o vacíos se mantienen sin cambios.
+func normalizarFechas(datos map[string]interface{}, cfg *configuracion) []error {
+	var errs []error
+	for _, campo := range cfg.camposFecha {
+		valor, ok := datos[campo]
+		if !ok || valor == nil {
+			continue
+		}
+		texto, ok := valor.(string)
+		if !ok {
+			errs = append(errs, &ErrorFechaInvalida{Campo: campo, Valor: valor, Formatos: cfg.formatosFecha})
+			continue
+		}
+		if texto == "" {
+			continue
+		}
+		normalizada, ok := cfg.normalizarFecha(texto)
+		if !ok {
+			errs = append(errs, &ErrorFechaInvalida{Campo: campo, Valor: valor, Formatos: cfg.formatosFecha})
+			continue
+		}
+		datos[campo] = normalizada
+	}
+	return errs
+}
+
+// normalizarFecha interpreta valor con el primer formato de entrada que
+// coincida o, si ninguno coincide, con el de WithFormatoSalidaFecha, y lo
+// devuelve con el formato de salida. Si ninguno coincide devuelve false.
+func (cfg *configuracion) normalizarFecha(valor string) (string, bool) {
+	t, ok := interpretarFecha(valor, cfg.formatosFecha)
+	if !ok && cfg.layoutFecha != "" {
+		t, ok = interpretarFecha(valor, []string{cfg.layoutFecha})
+	}
+	if !ok {
+		return "", false
+	}
+	return t.Format(cfg.formatoFecha()), true
+}
+
+// interpretarFecha interpreta valor con el primer layout que coincida.
+func interpretarFecha(valor string, formatos []string) (time.Time, bool) {
+	for _, formato := range formatos {
+		if t, err := time.Parse(formato, valor); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
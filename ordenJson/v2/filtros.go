@@ -0,0 +1,57 @@
+package ordenJson
+
+// WithOnly limita la salida a los campos de primer nivel indicados, en su
+// orden canónico, por ejemplo para una vista pública de los metadatos. Se
+// puede usar varias veces para agregar campos; sin campos no tiene efecto.
+//
+// El filtro solo afecta a la salida: el documento se valida completo, por lo
+// que WithRequired sigue exigiendo los campos que no se escriben. Ver
+// también WithExclude.
+func WithOnly(campos ...string) Option {
+	return func(cfg *configuracion) {
+		if len(campos) == 0 {
+			return
+		}
+		if cfg.solo == nil {
+			cfg.solo = make(map[string]struct{}, len(campos))
+		}
+		for _, campo := range campos {
+			cfg.solo[campo] = struct{}{}
+		}
+	}
+}
+
+// WithExclude omite de la salida los campos de primer nivel indicados, como
+// las observaciones internas, y deja los demás en su orden canónico. Se
+// puede usar varias veces para agregar campos y se combina con WithOnly: se
+// omiten los campos excluidos aunque estén en la lista de WithOnly. Igual
+// que WithOnly, solo afecta a la salida.
+func WithExclude(campos ...string) Option {
+	return func(cfg *configuracion) {
+		if len(campos) == 0 {
+			return
+		}
+		if cfg.excluir == nil {
+			cfg.excluir = make(map[string]struct{}, len(campos))
+		}
+		for _, campo := range campos {
+			cfg.excluir[campo] = struct{}{}
+		}
+	}
+}
+
+// filtrarClaves devuelve, en el mismo orden, las claves que WithOnly y
+// WithExclude dejan en la salida.
+func (cfg *configuracion) filtrarClaves(claves []string) []string {
+	restantes := claves[:0]
+	for _, clave := range claves {
+		if _, ok := cfg.solo[clave]; cfg.solo != nil && !ok {
+			continue
+		}
+		if _, ok := cfg.excluir[clave]; ok {
+			continue
+		}
+		restantes = append(restantes, clave)
+	}
+	return restantes
+}
@@ -0,0 +1,187 @@
+package ordenJson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// OrdenarFlujo ordena los documentos que lee de r y los escribe en w. Ver
+// Ordenador.OrdenarFlujo.
+func OrdenarFlujo(r io.Reader, w io.Writer, opts ...Option) (int, error) {
+	return Nuevo(opts...).OrdenarFlujo(r, w)
+}
+
+// OrdenarFlujoCtx es como OrdenarFlujo, pero se detiene si ctx termina. Ver
+// Ordenador.OrdenarFlujoCtx.
+func OrdenarFlujoCtx(ctx context.Context, r io.Reader, w io.Writer, opts ...Option) (int, error) {
+	return Nuevo(opts...).OrdenarFlujoCtx(ctx, r, w)
+}
+
+// OrdenarFlujo ordena uno a uno los documentos que lee de r y los escribe en
+// w, sin retener en memoria más de un documento a la vez, por lo que sirve
+// para archivos de varios gigabytes. La entrada puede ser NDJSON (objetos
+// separados por saltos de línea o espacios) o un arreglo JSON de objetos; se
+// distingue por su primer carácter. La salida tiene la misma forma: NDJSON
+// con cada documento compacto en su línea, o un arreglo con cada documento
+// indentado igual que en OrdenarJSON.
+//
+// La salida se escribe a medida que se ordena, con un buffer de tamaño fijo:
+// si w es más lento que r, la lectura espera a que w reciba lo anterior.
+// Devuelve la cantidad de documentos escritos; al primer documento que no se
+// puede ordenar se detiene y devuelve el error indicando su posición (desde
+// 0), después de escribir los documentos anteriores.
+func (o *Ordenador) OrdenarFlujo(r io.Reader, w io.Writer) (int, error) {
+	return o.OrdenarFlujoCtx(context.Background(), r, w)
+}
+
+// OrdenarFlujoCtx es como OrdenarFlujo, pero cada documento se ordena con
+// OrdenarJSONCtx: si ctx termina, el documento en curso se aborta y se
+// devuelve su *ErrorCancelado, después de escribir los anteriores.
+func (o *Ordenador) OrdenarFlujoCtx(ctx context.Context, r io.Reader, w io.Writer) (int, error) {
+	cfg := o.cfg
+	cfg.ctx = ctx
+	if cfg.trazador == nil {
+		return cfg.ordenarFlujo(r, w, nil)
+	}
+	var tramo Tramo
+	cfg.ctx, tramo = cfg.tramoEn(ctx, TramoFlujo)
+	n, err := cfg.ordenarFlujo(r, w, nil)
+	tramo.Atributo(AtributoDocumentos, n)
+	tramo.Terminar(err)
+	return n, err
+}
+
+// ordenarFlujo implementa OrdenarFlujoCtx con la configuración cfg. Si
+// omitir no es nil, recibe la posición en la entrada y la salida ordenada de
+// cada documento, y los que omitir descarta no se escriben.
+func (cfg *configuracion) ordenarFlujo(r io.Reader, w io.Writer, omitir func(i int, ordenado []byte) bool) (int, error) {
+	entrada := bufio.NewReader(r)
+	arreglo, err := esArregloJSON(entrada)
+	if err != nil {
+		return 0, err
+	}
+	salida := bufio.NewWriter(w)
+	n, leidos := 0, 0
+	terminar := func(err error) (int, error) {
+		if errEscritura := salida.Flush(); err == nil {
+			err = errEscritura
+		}
+		return n, err
+	}
+
+	// Con WithMaxBytes, la lectura de cada documento se corta poco después
+	// del límite, en lugar de acumularlo entero antes de rechazarlo.
+	var fuente io.Reader = entrada
+	var acotado *lectorAcotado
+	if cfg.maxBytes > 0 {
+		acotado = &lectorAcotado{r: entrada, restantes: cfg.maxBytes + holguraLectura}
+		fuente = acotado
+	}
+	dec := json.NewDecoder(fuente)
+	if arreglo {
+		// Consumir el corchete de apertura.
+		if _, err := dec.Token(); err != nil {
+			return 0, &ErrorJSONInvalido{Offset: dec.InputOffset(), Err: err}
+		}
+		salida.WriteByte('[')
+	}
+	var documento json.RawMessage
+	var ordenado []byte
+	var compacto bytes.Buffer
+	for !arreglo || dec.More() {
+		if err := dec.Decode(&documento); err != nil {
+			if err == io.EOF && !arreglo {
+				break
+			}
+			if errors.Is(err, errDocumentoExcedido) {
+				return terminar(fmt.Errorf("documento %d: %w", leidos, &ErrorLimiteExcedido{Limite: LimiteBytes, Maximo: cfg.maxBytes}))
+			}
+			return terminar(fmt.Errorf("documento %d: %w", leidos, &ErrorJSONInvalido{Offset: dec.InputOffset(), Err: err}))
+		}
+		if acotado != nil {
+			acotado.restantes = cfg.maxBytes + holguraLectura
+		}
+		if ordenado, err = cfg.agregarJSON(ordenado[:0], string(documento)); err != nil {
+			return terminar(fmt.Errorf("documento %d: %w", leidos, err))
+		}
+		leidos++
+		if omitir != nil && omitir(leidos-1, ordenado) {
+			continue
+		}
+		if arreglo {
+			if n > 0 {
+				salida.WriteByte(',')
+			}
+			err = escribirIndentado(salida, ordenado)
+		} else {
+			compacto.Reset()
+			json.Compact(&compacto, ordenado)
+			compacto.WriteByte('\n')
+			_, err = salida.Write(compacto.Bytes())
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	if arreglo {
+		// Consumir el corchete de cierre y verificar que no siga nada más.
+		if _, err := dec.Token(); err != nil {
+			return terminar(&ErrorJSONInvalido{Offset: dec.InputOffset(), Err: err})
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			if err == nil {
+				err = errors.New("contenido inesperado después del arreglo JSON")
+			}
+			return terminar(&ErrorJSONInvalido{Offset: dec.InputOffset(), Err: err})
+		}
+		if n > 0 {
+			salida.WriteByte('\n')
+		}
+		salida.WriteString("]\n")
+	}
+	return terminar(nil)
+}
+
+// escribirIndentado escribe en w el documento ordenado un nivel más adentro,
+// como elemento de un arreglo. Los saltos de línea de la salida ordenada
+// siempre separan tokens, nunca están dentro de una cadena. Como los errores
+// de bufio.Writer persisten, el de la última escritura incluye los anteriores.
+func escribirIndentado(w *bufio.Writer, ordenado []byte) error {
+	linea := indentadorPorDefecto.linea(1)
+	w.Write(linea)
+	for {
+		i := bytes.IndexByte(ordenado, '\n')
+		if i < 0 {
+			_, err := w.Write(ordenado)
+			return err
+		}
+		w.Write(ordenado[:i])
+		w.Write(linea)
+		ordenado = ordenado[i+1:]
+	}
+}
+
+// esArregloJSON indica si el primer carácter de r que no es un espacio en
+// blanco abre un arreglo, sin consumirlo. Una entrada vacía se trata como
+// NDJSON sin documentos.
+func esArregloJSON(r *bufio.Reader) (bool, error) {
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			continue
+		}
+		return c == '[', r.UnreadByte()
+	}
+}
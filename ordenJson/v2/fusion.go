@@ -0,0 +1,239 @@
+package ordenJson
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Precedencia decide, para un campo, qué valor conserva Merge cuando la base
+// y el overlay lo traen.
+type Precedencia int
+
+const (
+	// PrecedenciaOverlay toma el valor del overlay si lo tiene y, si no, el de
+	// la base. Es la precedencia por defecto.
+	PrecedenciaOverlay Precedencia = iota
+	// PrecedenciaBase toma el valor de la base si lo tiene y, si no, el del
+	// overlay: el overlay solo completa los campos que faltan.
+	PrecedenciaBase
+	// PrecedenciaReemplazar toma siempre el valor del overlay, aunque no lo
+	// tenga: el campo queda vacío si el overlay no lo trae.
+	PrecedenciaReemplazar
+	// PrecedenciaConservar toma siempre el valor de la base e ignora el del
+	// overlay, para campos que una fuente secundaria no debe cambiar.
+	PrecedenciaConservar
+)
+
+// MergePolicy define la precedencia de cada campo al combinar metadatos con
+// Merge o MergeJSON.
+type MergePolicy struct {
+	// PorDefecto es la precedencia de los campos que no están en Campos.
+	PorDefecto Precedencia
+	// Campos asigna una precedencia propia a algunos campos, por su clave
+	// JSON, como "tanner:rut-cliente".
+	Campos map[string]Precedencia
+}
+
+// precedencia devuelve la precedencia que la política asigna al campo.
+func (p MergePolicy) precedencia(campo string) Precedencia {
+	if precedencia, ok := p.Campos[campo]; ok {
+		return precedencia
+	}
+	return p.PorDefecto
+}
+
+// elegir indica si el valor combinado del campo se toma del overlay, según
+// la precedencia y si cada fuente trae el campo.
+func (p MergePolicy) elegir(campo string, enBase, enOverlay bool) bool {
+	switch p.precedencia(campo) {
+	case PrecedenciaBase:
+		return !enBase
+	case PrecedenciaReemplazar:
+		return true
+	case PrecedenciaConservar:
+		return false
+	default:
+		return enOverlay
+	}
+}
+
+// Merge combina los metadatos de base con los de overlay campo por campo
+// según la política, y devuelve el resultado sin modificar ninguno de los
+// dos. Un campo vacío se considera ausente. Para combinar más de dos fuentes
+// se aplica Merge sucesivamente, de la de menor a la de mayor prioridad. El
+// resultado se ordena como cualquier DocumentMetadata, con
+// OrdenarDocumentoMetadata.
+func Merge(base, overlay DocumentMetadata, policy MergePolicy) DocumentMetadata {
+	resultado := base
+	destino := reflect.ValueOf(&resultado).Elem()
+	fuente := reflect.ValueOf(overlay)
+	for i := 0; i < tipoDocumentMetadata.NumField(); i++ {
+		campo := tipoDocumentMetadata.Field(i)
+		nombre, _, _ := strings.Cut(campo.Tag.Get("json"), ",")
+		if campo.Type.Kind() != reflect.String || nombre == "" || nombre == "-" {
+			// Hoy todos los campos de DocumentMetadata son string con
+			// etiqueta json.
+			continue
+		}
+		actual, valor := destino.Field(i), fuente.Field(i).String()
+		if policy.elegir(nombre, actual.String() != "", valor != "") {
+			actual.SetString(valor)
+		}
+	}
+	return resultado
+}
+
+// MergeJSON combina dos documentos JSON con las opciones recibidas. Ver
+// Ordenador.MergeJSON.
+func MergeJSON(base, overlay string, policy MergePolicy, opts ...Option) (string, error) {
+	return Nuevo(opts...).MergeJSON(base, overlay, policy)
+}
+
+// MergeJSON combina las claves de primer nivel de dos documentos JSON según
+// la política, igual que Merge, y devuelve el resultado ordenado con
+// OrdenarJSON, por lo que también se valida. Una clave ausente o con valor
+// null se considera ausente; los objetos anidados se toman completos de una
+// u otra fuente. Las claves fuera del perfil conservan el orden en que
+// aparecen en base, seguidas de las que solo trae overlay. Si alguno no es un
+// objeto JSON válido se devuelve un *ErrorJSONInvalido.
+func (o *Ordenador) MergeJSON(base, overlay string, policy MergePolicy) (string, error) {
+	datosBase, clavesBase, err := decodificarUbicado(base)
+	if err != nil {
+		return "", fmt.Errorf("documento base: %w", err)
+	}
+	datosOverlay, clavesOverlay, err := decodificarUbicado(overlay)
+	if err != nil {
+		return "", fmt.Errorf("documento overlay: %w", err)
+	}
+
+	claves := clavesBase
+	for _, clave := range clavesOverlay {
+		if _, ok := datosBase[clave]; !ok {
+			claves = append(claves, clave)
+		}
+	}
+
+	datos := make(map[string]interface{}, len(claves))
+	combinadas := claves[:0]
+	for _, clave := range claves {
+		valor, enBase := datosBase[clave]
+		enBase = enBase && valor != nil
+		if candidato, ok := datosOverlay[clave]; policy.elegir(clave, enBase, ok && candidato != nil) {
+			valor = candidato
+		}
+		if valor != nil {
+			datos[clave] = valor
+			combinadas = append(combinadas, clave)
+		}
+	}
+	texto, err := codificarObjeto(datos, combinadas)
+	if err != nil {
+		return "", err
+	}
+	return o.OrdenarJSON(texto)
+}
+
+// Componer une varios fragmentos JSON en un documento ordenado con las
+// opciones por defecto. Ver Ordenador.Componer.
+func Componer(fragmentos ...string) (string, error) {
+	return Nuevo().Componer(fragmentos...)
+}
+
+// Componer une varios fragmentos parciales de un mismo documento, como uno
+// del escáner y otro del CRM, en un solo documento ordenado con OrdenarJSON,
+// por lo que el resultado también se valida. Los objetos anidados se unen
+// campo por campo y un campo con valor null no aporta nada. Si dos fragmentos
+// dan valores distintos a un mismo campo se devuelve un *ErrorConflictos con
+// todos los conflictos; valores iguales, como 1 y 1.0, no son conflicto. Las
+// claves fuera del perfil quedan en el orden de su primera aparición. Si un
+// fragmento no es un objeto JSON válido se devuelve un *ErrorJSONInvalido con
+// su posición (desde 0). Para resolver las diferencias con una precedencia
+// por campo, ver MergeJSON.
+func (o *Ordenador) Componer(fragmentos ...string) (string, error) {
+	datos := make(map[string]interface{})
+	var claves []string
+	origen := make(map[string]int) // Fragmento del que se tomó el valor de cada ruta.
+	var conflictos []Conflicto
+	for i, fragmento := range fragmentos {
+		parte, clavesParte, err := decodificarUbicado(fragmento)
+		if err != nil {
+			return "", fmt.Errorf("fragmento %d: %w", i, err)
+		}
+		for _, clave := range clavesParte {
+			if _, ok := datos[clave]; !ok && parte[clave] != nil {
+				claves = append(claves, clave)
+			}
+		}
+		conflictos = componerObjeto(datos, parte, "", i, origen, conflictos)
+	}
+	if len(conflictos) > 0 {
+		perfil := o.cfg.perfil
+		sort.SliceStable(conflictos, func(i, j int) bool {
+			return perfil.posicion(campoDeRuta(conflictos[i].Ruta)) < perfil.posicion(campoDeRuta(conflictos[j].Ruta))
+		})
+		return "", &ErrorConflictos{Conflictos: conflictos}
+	}
+	texto, err := codificarObjeto(datos, claves)
+	if err != nil {
+		return "", err
+	}
+	return o.OrdenarJSON(texto)
+}
+
+// componerObjeto agrega a destino los campos de parte, que viene del
+// fragmento i, y devuelve conflictos con los que encontró. Los campos se
+// recorren en orden alfabético para que los conflictos tengan un orden fijo.
+func componerObjeto(destino, parte map[string]interface{}, ruta string, i int, origen map[string]int, conflictos []Conflicto) []Conflicto {
+	claves := make([]string, 0, len(parte))
+	for clave := range parte {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	for _, clave := range claves {
+		valor := parte[clave]
+		if valor == nil {
+			continue
+		}
+		hija := rutaHija(ruta, clave)
+		actual, ok := destino[clave]
+		if !ok {
+			destino[clave] = valor
+			origen[hija] = i
+			continue
+		}
+		objetoActual, esObjeto := actual.(map[string]interface{})
+		if objetoParte, ok := valor.(map[string]interface{}); ok && esObjeto {
+			conflictos = componerObjeto(objetoActual, objetoParte, hija, i, origen, conflictos)
+			continue
+		}
+		if !reflect.DeepEqual(actual, valor) {
+			conflictos = append(conflictos, Conflicto{
+				Ruta:       hija,
+				Fragmentos: [2]int{origenDe(origen, hija), i},
+				Valores:    [2]interface{}{actual, valor},
+			})
+		}
+	}
+	return conflictos
+}
+
+// origenDe devuelve el fragmento del que se tomó el valor de la ruta, que
+// puede haber llegado dentro de un objeto tomado completo.
+func origenDe(origen map[string]int, ruta string) int {
+	for ruta != "" {
+		if i, ok := origen[ruta]; ok {
+			return i
+		}
+		ruta = ruta[:strings.LastIndexByte(ruta, '/')]
+	}
+	return 0
+}
+
+// campoDeRuta devuelve la clave de primer nivel de un JSON Pointer.
+func campoDeRuta(ruta string) string {
+	campo, _, _ := strings.Cut(strings.TrimPrefix(ruta, "/"), "/")
+	campo = strings.ReplaceAll(campo, "~1", "/")
+	return strings.ReplaceAll(campo, "~0", "~")
+}
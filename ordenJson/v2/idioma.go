@@ -0,0 +1,170 @@
+package ordenJson
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// Idioma selecciona el idioma de los mensajes de los errores tipados del
+// paquete (los tipos Error*). Los centinelas, los tipos y sus campos no
+// cambian con el idioma, por lo que errors.Is y errors.As funcionan igual.
+type Idioma string
+
+const (
+	IdiomaEspanol Idioma = "es" // Idioma por defecto.
+	IdiomaIngles  Idioma = "en"
+)
+
+// idiomaPaquete guarda el Idioma de EstablecerIdioma.
+var idiomaPaquete atomic.Value
+
+// EstablecerIdioma cambia el idioma de los mensajes de error de todo el
+// paquete; un idioma sin catálogo equivale a IdiomaEspanol. Afecta a los
+// errores que no recibieron un idioma con WithIdioma, también a los ya
+// creados, porque el mensaje se arma en cada llamada a Error. Conviene
+// llamarla una sola vez, al iniciar el programa.
+func EstablecerIdioma(idioma Idioma) {
+	idiomaPaquete.Store(idioma)
+}
+
+// IdiomaActual devuelve el idioma configurado con EstablecerIdioma.
+func IdiomaActual() Idioma {
+	if idioma, ok := idiomaPaquete.Load().(Idioma); ok {
+		return idioma
+	}
+	return IdiomaEspanol
+}
+
+// WithIdioma fija el idioma de los mensajes de los errores que devuelven las
+// operaciones de ordenamiento con esta configuración, sin cambiar el del
+// resto del paquete. El contexto que agregan algunas operaciones al envolver
+// un error, como la posición del documento en un lote, y los mensajes de
+// Problema no se traducen.
+func WithIdioma(idioma Idioma) Option {
+	return func(cfg *configuracion) {
+		cfg.idioma = idioma
+	}
+}
+
+// localizable es la parte común de los errores tipados que guarda el idioma
+// fijado con WithIdioma.
+type localizable struct {
+	idioma Idioma // Vacío usa IdiomaActual.
+}
+
+// fijarIdioma guarda el idioma del error.
+func (l *localizable) fijarIdioma(idioma Idioma) {
+	l.idioma = idioma
+}
+
+// mensaje arma el mensaje con la clave del catálogo en el idioma del error.
+func (l *localizable) mensaje(clave string, args ...interface{}) string {
+	idioma := l.idioma
+	if idioma == "" {
+		idioma = IdiomaActual()
+	}
+	formato, ok := catalogoMensajes[idioma][clave]
+	if !ok {
+		formato = catalogoMensajes[IdiomaEspanol][clave]
+	}
+	return fmt.Sprintf(formato, args...)
+}
+
+// etapa traduce el nombre de una etapa del ordenamiento, que los errores
+// guardan en español.
+func (l *localizable) etapa(etapa string) string {
+	if traducida := l.mensaje("etapa " + etapa); traducida != "" {
+		return traducida
+	}
+	return etapa
+}
+
+// localizarError fija el idioma en los errores tipados de la cadena de err.
+// No hace nada si idioma es vacío.
+func localizarError(err error, idioma Idioma) {
+	if idioma == "" {
+		return
+	}
+	for err != nil {
+		if l, ok := err.(interface{ fijarIdioma(Idioma) }); ok {
+			l.fijarIdioma(idioma)
+		}
+		if multiple, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multiple.Unwrap() {
+				localizarError(e, idioma)
+			}
+			return
+		}
+		err = errors.Unwrap(err)
+	}
+}
+
+// catalogoMensajes contiene el formato de cada mensaje de error por idioma.
+// Cada idioma debe tener las mismas claves y los mismos verbos de formato.
+var catalogoMensajes = map[Idioma]map[string]string{
+	IdiomaEspanol: {
+		"tiempo-excedido":            "se excedió el presupuesto de %s durante la %s (transcurrido: %s)",
+		"cancelado":                  "ordenamiento cancelado durante la %s: %v",
+		"limite-profundidad":         "el documento supera la profundidad máxima de %d niveles",
+		"limite-bytes-valor":         "el documento supera el tamaño máximo de %d bytes (tiene %d)",
+		"limite-bytes":               "el documento supera el tamaño máximo de %d bytes",
+		"valor-no-permitido":         "campo %s: valor %v no permitido, se esperaba uno de %v",
+		"json-invalido":              "JSON inválido en la línea %d, columna %d: %v",
+		"clave-duplicada":            "la clave %q está repetida",
+		"tipo-no-soportado":          "tipo de entrada no soportado: %v",
+		"campos-faltantes":           "faltan campos obligatorios: %s",
+		"claves-no-permitidas":       "claves no permitidas en modo estricto: %s",
+		"fecha-no-texto":             "el campo %s debe ser una cadena de fecha, se recibió %T",
+		"fecha-invalida":             "campo %s: fecha %q no coincide con ningún formato aceptado",
+		"regla":                      "campo %s: %s",
+		"regla type":                 "tipo %s no admitido, se esperaba %v",
+		"regla minLength":            "largo %d menor que el mínimo %d",
+		"regla maxLength":            "largo %d mayor que el máximo %d",
+		"regla pattern":              "valor %q no coincide con el patrón %s",
+		"regla format":               "valor %q no tiene formato %s",
+		"valor-no-serializable":      "campo %s: no se puede serializar el valor: %v",
+		"esquema-invalido":           "esquema inválido: %v",
+		"esquema-invalido-campo":     "esquema inválido: propiedad %s: %v",
+		"patch":                      "operación %d del patch (%s %s): %v",
+		"perfil-desconocido":         "perfil desconocido: %q",
+		"tipo-documento-desconocido": "tipo de documento no registrado: %q",
+		"conflicto":                  "%s (fragmentos %d y %d)",
+		"conflictos":                 "los fragmentos tienen valores distintos en: %s",
+		"etapa decodificación":       "decodificación",
+		"etapa validación":           "validación",
+		"etapa serialización":        "serialización",
+	},
+	IdiomaIngles: {
+		"tiempo-excedido":            "time budget of %s exceeded during %s (elapsed: %s)",
+		"cancelado":                  "ordering canceled during %s: %v",
+		"limite-profundidad":         "the document exceeds the maximum depth of %d levels",
+		"limite-bytes-valor":         "the document exceeds the maximum size of %d bytes (it has %d)",
+		"limite-bytes":               "the document exceeds the maximum size of %d bytes",
+		"valor-no-permitido":         "field %s: value %v is not allowed, expected one of %v",
+		"json-invalido":              "invalid JSON at line %d, column %d: %v",
+		"clave-duplicada":            "the key %q is repeated",
+		"tipo-no-soportado":          "unsupported input type: %v",
+		"campos-faltantes":           "missing required fields: %s",
+		"claves-no-permitidas":       "keys not allowed in strict mode: %s",
+		"fecha-no-texto":             "the field %s must be a date string, got %T",
+		"fecha-invalida":             "field %s: date %q does not match any accepted format",
+		"regla":                      "field %s: %s",
+		"regla type":                 "type %s is not allowed, expected %v",
+		"regla minLength":            "length %d is less than the minimum %d",
+		"regla maxLength":            "length %d is greater than the maximum %d",
+		"regla pattern":              "value %q does not match the pattern %s",
+		"regla format":               "value %q is not in %s format",
+		"valor-no-serializable":      "field %s: the value cannot be serialized: %v",
+		"esquema-invalido":           "invalid schema: %v",
+		"esquema-invalido-campo":     "invalid schema: property %s: %v",
+		"patch":                      "patch operation %d (%s %s): %v",
+		"perfil-desconocido":         "unknown profile: %q",
+		"tipo-documento-desconocido": "unregistered document type: %q",
+		"conflicto":                  "%s (fragments %d and %d)",
+		"conflictos":                 "the fragments have different values at: %s",
+		"etapa decodificación":       "decoding",
+		"etapa validación":           "validation",
+		"etapa serialización":        "serialization",
+	},
+}
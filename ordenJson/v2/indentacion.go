@@ -0,0 +1,291 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// profundidadPrecalculada es la cantidad de niveles cuya sangría se calcula de
+// antemano. Los niveles más profundos se construyen al momento de usarse.
+const profundidadPrecalculada = 32
+
+// profundidadMaximaDirecta es la profundidad a partir de la cual escribirValor
+// delega en json.Marshal, que detecta los mapas que se contienen a sí mismos.
+const profundidadMaximaDirecta = 1000
+
+// indentador escribe JSON con saltos de línea y sangría. Los valores que
+// produce la decodificación se escriben ya indentados en una sola pasada (ver
+// escribirValor); el resto se codifica con json.Marshal y se reescribe con
+// indentar. A diferencia de json.Indent, que escribe la sangría byte a byte,
+// guarda para cada profundidad el salto de línea y la sangría completos y los
+// copia de una sola vez.
+type indentador struct {
+	sangria string
+	lineas  [][]byte // lineas[d] es "\n" seguido de d repeticiones de sangria.
+}
+
+// indentadorPorDefecto usa dos espacios, igual que la salida histórica del paquete.
+var indentadorPorDefecto = nuevoIndentador("  ")
+
+// estructural marca los bytes que obligan a interrumpir la copia en bloque.
+var estructural = [256]bool{'"': true, '{': true, '[': true, '}': true, ']': true, ',': true, ':': true}
+
+// finDeTramoEnCadena marca los bytes que pueden terminar el recorrido de una cadena.
+var finDeTramoEnCadena = [256]bool{'"': true, '\\': true}
+
+// nuevoIndentador precalcula las líneas de sangría para la cadena recibida.
+func nuevoIndentador(sangria string) *indentador {
+	ind := &indentador{sangria: sangria, lineas: make([][]byte, profundidadPrecalculada)}
+	linea := []byte{'\n'}
+	for d := range ind.lineas {
+		ind.lineas[d] = linea
+		linea = append(append([]byte(nil), linea...), sangria...)
+	}
+	return ind
+}
+
+// linea devuelve el salto de línea con la sangría de la profundidad indicada.
+func (ind *indentador) linea(profundidad int) []byte {
+	if profundidad < len(ind.lineas) {
+		return ind.lineas[profundidad]
+	}
+	linea := append([]byte(nil), ind.lineas[len(ind.lineas)-1]...)
+	for d := len(ind.lineas) - 1; d < profundidad; d++ {
+		linea = append(linea, ind.sangria...)
+	}
+	return linea
+}
+
+// indentar agrega a dst el JSON compacto src con el mismo formato que
+// json.Indent sin prefijo, como si src comenzara en la profundidad indicada, y
+// devuelve el slice extendido. src debe ser JSON válido y sin espacios fuera de
+// las cadenas, como el producido por json.Marshal.
+func (ind *indentador) indentar(dst, src []byte, profundidad int) []byte {
+	inicio := 0 // Comienzo del tramo pendiente de copiar.
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if !estructural[c] {
+			continue
+		}
+		switch c {
+		case '"':
+			// Saltar la cadena completa; su contenido se copia junto con el tramo.
+			for i++; i < len(src); i++ {
+				if finDeTramoEnCadena[src[i]] {
+					if src[i] == '"' {
+						break
+					}
+					i++ // Saltar el carácter escapado.
+				}
+			}
+		case '{', '[':
+			// Los objetos y arrays vacíos se escriben compactos, igual que json.Indent.
+			if i+1 < len(src) && (src[i+1] == '}' || src[i+1] == ']') {
+				i++
+				continue
+			}
+			profundidad++
+			dst = append(dst, src[inicio:i+1]...)
+			dst = append(dst, ind.linea(profundidad)...)
+			inicio = i + 1
+		case '}', ']':
+			profundidad--
+			dst = append(dst, src[inicio:i]...)
+			dst = append(dst, ind.linea(profundidad)...)
+			dst = append(dst, c)
+			inicio = i + 1
+		case ',':
+			dst = append(dst, src[inicio:i+1]...)
+			dst = append(dst, ind.linea(profundidad)...)
+			inicio = i + 1
+		case ':':
+			dst = append(dst, src[inicio:i+1]...)
+			dst = append(dst, ' ')
+			inicio = i + 1
+		}
+	}
+	return append(dst, src[inicio:]...)
+}
+
+// escribirValor agrega a dst el valor v, ubicado en la profundidad indicada,
+// con el mismo resultado que json.Marshal seguido de indentar. Los tipos que
+// produce la decodificación (mapas, slices, cadenas, float64, bool y nil) se
+// escriben directamente, sin codificar primero un JSON compacto; las claves de
+// los objetos anidados se ordenan alfabéticamente, igual que en json.Marshal.
+func (ind *indentador) escribirValor(dst []byte, v interface{}, profundidad int, codec Codec) ([]byte, error) {
+	if profundidad >= profundidadMaximaDirecta {
+		return ind.escribirCodificado(dst, v, profundidad, codec)
+	}
+	switch v := v.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case bool:
+		return strconv.AppendBool(dst, v), nil
+	case string:
+		return agregarCadena(dst, v), nil
+	case float64:
+		return agregarNumero(dst, v)
+	case map[string]interface{}:
+		if v == nil {
+			return append(dst, "null"...), nil
+		}
+		if len(v) == 0 {
+			return append(dst, "{}"...), nil
+		}
+		claves := make([]string, 0, len(v))
+		for clave := range v {
+			claves = append(claves, clave)
+		}
+		sort.Strings(claves)
+		dst = append(dst, '{')
+		linea := ind.linea(profundidad + 1)
+		for i, clave := range claves {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = append(dst, linea...)
+			dst = agregarCadena(dst, clave)
+			dst = append(dst, ':', ' ')
+			var err error
+			if dst, err = ind.escribirValor(dst, v[clave], profundidad+1, codec); err != nil {
+				return nil, err
+			}
+		}
+		dst = append(dst, ind.linea(profundidad)...)
+		return append(dst, '}'), nil
+	case []interface{}:
+		if v == nil {
+			return append(dst, "null"...), nil
+		}
+		if len(v) == 0 {
+			return append(dst, "[]"...), nil
+		}
+		dst = append(dst, '[')
+		linea := ind.linea(profundidad + 1)
+		for i, elemento := range v {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = append(dst, linea...)
+			var err error
+			if dst, err = ind.escribirValor(dst, elemento, profundidad+1, codec); err != nil {
+				return nil, err
+			}
+		}
+		dst = append(dst, ind.linea(profundidad)...)
+		return append(dst, ']'), nil
+	}
+	return ind.escribirCodificado(dst, v, profundidad, codec)
+}
+
+// escribirCodificado agrega a dst el valor v codificado con codec, o con
+// json.Marshal si es nil, e indentado a partir de la profundidad indicada.
+// Codificar por reflexión ejecuta código ajeno, como los métodos MarshalJSON
+// de los valores de un mapa: si entra en pánico, se devuelve como error.
+func (ind *indentador) escribirCodificado(dst []byte, v interface{}, profundidad int, codec Codec) (resultado []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			resultado, err = nil, fmt.Errorf("pánico al codificar un valor %T: %v", v, r)
+		}
+	}()
+	var compacto []byte
+	if codec != nil {
+		compacto, err = codec.Marshal(v)
+	} else {
+		compacto, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ind.indentar(dst, compacto, profundidad), nil
+}
+
+// agregarNumero agrega a dst el número f con el mismo formato que json.Marshal.
+func agregarNumero(dst []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, &json.UnsupportedValueError{Value: reflect.ValueOf(f), Str: strconv.FormatFloat(f, 'g', -1, 64)}
+	}
+	formato := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		formato = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, formato, -1, 64)
+	if formato == 'e' {
+		// Convertir e-09 en e-9, como json.Marshal.
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
+
+// cadenaSegura marca los bytes ASCII que json.Marshal escribe sin escapar
+// dentro de una cadena; '<', '>' y '&' se escapan como en su modo HTML.
+var cadenaSegura = func() (seguros [utf8.RuneSelf]bool) {
+	for c := ' '; c < utf8.RuneSelf; c++ {
+		seguros[c] = c != '"' && c != '\\' && c != '<' && c != '>' && c != '&'
+	}
+	return seguros
+}()
+
+const digitosHex = "0123456789abcdef"
+
+// agregarCadena agrega a dst la cadena s entre comillas con los mismos escapes
+// que json.Marshal. Las cadenas con UTF-8 inválido, poco frecuentes, se
+// codifican con json.Marshal para conservar su forma de reemplazar esos bytes.
+func agregarCadena(dst []byte, s string) []byte {
+	base := len(dst)
+	dst = append(dst, '"')
+	inicio := 0 // Comienzo del tramo pendiente de copiar.
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if cadenaSegura[c] {
+				i++
+				continue
+			}
+			dst = append(dst, s[inicio:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', digitosHex[c>>4], digitosHex[c&0xF])
+			}
+			i++
+			inicio = i
+			continue
+		}
+		r, tamano := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && tamano == 1 {
+			// json.Marshal no falla al codificar un string.
+			codificada, _ := json.Marshal(s)
+			return append(dst[:base], codificada...)
+		}
+		// U+2028 y U+2029 se escapan para que la salida sea válida en JavaScript.
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[inicio:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', digitosHex[r&0xF])
+			i += tamano
+			inicio = i
+			continue
+		}
+		i += tamano
+	}
+	dst = append(dst, s[inicio:]...)
+	return append(dst, '"')
+}
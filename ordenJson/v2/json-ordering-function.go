@@ -0,0 +1,533 @@
+package ordenJson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// DocumentMetadata representa la estructura de metadatos del documento.
+// Cada campo tiene etiquetas JSON y BSON para facilitar la serialización y deserialización.
+type DocumentMetadata struct {
+	TipoDocumento        string `json:"tanner:tipo-documento" bson:"_tanner:tipo-documento, omitempty"` // Tipo de documento (ej: contrato, factura)
+	RazonSocialCliente   string `json:"tanner:razon-social-cliente" bson:"_tanner:razon-social-cliente, omitempty"` // Razón social del cliente
+	RUTCliente           string `json:"tanner:rut-cliente" bson:"_tanner:rut-cliente, omitempty"` // RUT del cliente
+	EstadoVisado         string `json:"tanner:estado-visado" bson:"_tanner:estado-visado, omitempty"` // Estado de visado (ej: aprobado, rechazado)
+	EstadoVigencia       string `json:"tanner:estado-vigencia" bson:"_tanner:estado-vigencia, omitempty"` // Estado de vigencia (ej: vigente, vencido)
+	FechaCarga           string `json:"tanner:fecha-carga" bson:"_tanner:fecha-carga, omitempty" validate:"datetime=2006-01-02T15:04:05.999Z07:00"` // Fecha de carga del documento
+	NombreDoc            string `json:"tanner:nombre-doc" bson:"_tanner:nombre-doc, omitempty"` // Nombre del documento
+	Categorias           string `json:"tanner:categorias" bson:"_tanner:categorias, omitempty"` // Categoría del documento
+	SubCategorias        string `json:"tanner:sub-categorias" bson:"_tanner:sub-categorias, omitempty"` // Subcategoría del documento
+	Origen               string `json:"tanner:origen" bson:"_tanner:origen, omitempty"` // Origen del documento (ej: departamento legal)
+	Relacion             string `json:"tanner:relacion" bson:"_tanner:relacion, omitempty"` // Relación del documento (ej: cliente, proveedor)
+	FechaTerminoVigencia string `json:"tanner:fecha-termino-vigencia" bson:"_tanner:fecha-termino-vigencia, omitempty"` // Fecha de término de vigencia
+	CmTitle              string `json:"cm:title" bson:"_cm:title, omitempty"` // Título del documento
+	CmVersionType        string `json:"cm:versionType" bson:"_cm:versionType, omitempty"` // Tipo de versión del documento
+	CmVersionLabel       string `json:"cm:versionLabel" bson:"_cm:versionLabel, omitempty"` // Etiqueta de versión del documento
+	CmDescription        string `json:"cm:description" bson:"_cm:description, omitempty"` // Descripción del documento
+	Observaciones        string `json:"tanner:observaciones" bson:"_tanner:observaciones,omitempty"` // Observaciones adicionales
+}
+
+// OrdenCampos define el orden deseado de los campos en el JSON.
+// El índice en el slice representa la prioridad (menor índice = mayor prioridad).
+var OrdenCampos = []string{
+	"tanner:tipo-documento",
+	"tanner:razon-social-cliente",
+	"tanner:rut-cliente",
+	"tanner:estado-visado",
+	"tanner:estado-vigencia",
+	"tanner:fecha-carga",
+	"tanner:nombre-doc",
+	"tanner:categorias",
+	"tanner:sub-categorias",
+	"tanner:origen",
+	"tanner:relacion",
+	"tanner:fecha-termino-vigencia",
+	"cm:title",
+	"cm:versionType",
+	"cm:versionLabel",
+	"cm:description",
+	"tanner:observaciones",
+}
+
+// init construye PerfilPorDefecto a partir de OrdenCampos y lo registra.
+// Esto precalcula la posición y la forma codificada de cada campo para acelerar la ordenación.
+func init() {
+	PerfilPorDefecto = NuevoPerfil("por-defecto", OrdenCampos)
+	RegistrarPerfil(PerfilPorDefecto)
+	RegisterTipoDocumento("contrato", DocumentMetadata{}, PerfilPorDefecto)
+	RegisterTipoDocumento("factura", DocumentMetadata{}, PerfilPorDefecto)
+}
+
+// OrdenarDocumentoMetadata recibe un DocumentMetadata y devuelve un JSON ordenado.
+// Filtra los campos vacíos (ver WithCamposVacios) y ordena los campos según el orden predefinido.
+// Las opciones recibidas se aplican igual que en OrdenarJSON.
+func OrdenarDocumentoMetadata(metadata DocumentMetadata, opts ...Option) (string, error) {
+	return Nuevo(opts...).OrdenarDocumentoMetadata(metadata)
+}
+
+// entradaMetadata es el input con que OrdenarDocumentoMetadata llama a
+// ordenar: un DocumentMetadata que se lee con su plan en lugar de convertirse
+// antes en mapa. Es un tipo propio para que OrdenarJSON siga rechazando los
+// DocumentMetadata.
+type entradaMetadata DocumentMetadata
+
+// datosDeMetadata convierte un DocumentMetadata en un mapa con los campos
+// no vacíos, o con todos según WithCamposVacios, y agrega sus claves, en
+// orden alfabético, a claves. Los campos se leen con el plan del tipo, que se
+// calcula una sola vez.
+func datosDeMetadata(metadata *DocumentMetadata, claves []string, cfg *configuracion) (map[string]interface{}, []string) {
+	plan := planDe(tipoDocumentMetadata)
+	datos := make(map[string]interface{}, len(plan.campos))
+	// Los campos de DocumentMetadata son string, que se leen sin error.
+	claves, _, _ = plan.leer(unsafe.Pointer(metadata), datos, claves, &lectura{cfg: cfg})
+	return datos, claves
+}
+
+// mapaTipado convierte input, si es un mapa con claves de clase cadena o
+// entero y valores de un tipo que se lee como un campo de OrdenarStruct,
+// otro mapa de esos tipos o una interfaz (map[string]string,
+// map[string]json.RawMessage, map[string]int, ...), en un
+// map[string]interface{}. Devuelve false si input no es un mapa de esos
+// tipos.
+func mapaTipado(input interface{}, cfg *configuracion) (map[string]interface{}, bool, error) {
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.Map || !tipoLegible(v.Type(), true) {
+		return nil, false, nil
+	}
+	mapa, err := (&lectura{cfg: cfg}).mapaDe(v)
+	return mapa, true, err
+}
+
+// OrdenarJSON recibe un JSON desordenado (como cadena o mapa) y lo devuelve ordenado según el orden predefinido.
+// Si el input es una cadena, se convierte a un mapa antes de ordenar.
+// Además de map[string]interface{}, se aceptan mapas tipados como
+// map[string]string, map[string]json.RawMessage o map[string]int, cuyos
+// valores se escriben como los campos de OrdenarStruct, aunque se conservan
+// los vacíos.
+// Las opciones permiten activar transformaciones adicionales, como WithNormalizarFechas.
+// El mapa recibido como input nunca se modifica.
+// Para ordenar muchos documentos con las mismas opciones conviene crear un Ordenador.
+func OrdenarJSON(input interface{}, opts ...Option) (string, error) {
+	return Nuevo(opts...).OrdenarJSON(input)
+}
+
+// OrdenarJSONCtx es como OrdenarJSON, pero se aborta con un *ErrorCancelado
+// si ctx termina antes de completar el documento. Ver Ordenador.OrdenarJSONCtx.
+func OrdenarJSONCtx(ctx context.Context, input interface{}, opts ...Option) (string, error) {
+	return Nuevo(opts...).OrdenarJSONCtx(ctx, input)
+}
+
+// AgregarJSON ordena input igual que OrdenarJSON y agrega el resultado a dst.
+// Ver Ordenador.AgregarJSON.
+func AgregarJSON(dst []byte, input interface{}, opts ...Option) ([]byte, error) {
+	return Nuevo(opts...).AgregarJSON(dst, input)
+}
+
+// ordenar implementa OrdenarJSON y OrdenarJSONConReporte. Si cfg.reporte está
+// activo, los problemas de validación se acumulan en lugar de abortar. El
+// documento se escribe en un buffer del pool y solo se copia al string final.
+// Con WithCache, las entradas repetidas se responden desde la caché.
+func ordenar(input interface{}, cfg *configuracion) (string, []Problema, error) {
+	cfg, tramo := cfg.iniciarTramo(TramoOrdenar)
+	if tramo != nil {
+		cfg.atributosEntrada(input)
+	}
+	entrada, cacheable := cfg.cacheable(input)
+	var hash uint64
+	if cacheable {
+		var guardada string
+		var ok bool
+		if hash, guardada, ok = cfg.cache.buscar(entrada); ok {
+			cfg.atributoTramo(AtributoCache, true)
+			terminarTramo(tramo, len(guardada), nil)
+			return guardada, nil, nil
+		}
+	}
+	salida := tomarSalida()
+	resultado, problemas, err := ordenarEn(*salida, input, cfg)
+	var texto string
+	if err != nil {
+		localizarError(err, cfg.idioma)
+	} else {
+		texto = string(resultado)
+		if cacheable {
+			cfg.cache.guardar(hash, entrada, texto)
+		}
+	}
+	devolverSalida(salida, resultado)
+	terminarTramo(tramo, len(texto), err)
+	return texto, problemas, err
+}
+
+// ordenarEn implementa ordenar agregando el documento ordenado a dst. Si hay
+// un error, el contenido agregado a dst no está definido.
+func ordenarEn(dst []byte, input interface{}, cfg *configuracion) ([]byte, []Problema, error) {
+	limite := nuevoPlazo(cfg)
+	if err := limite.revisar("decodificación"); err != nil {
+		return dst, nil, err
+	}
+	if err := cfg.revisarTamano(input); err != nil {
+		return dst, nil, err
+	}
+
+	// Los documentos planos se ordenan sin decodificarlos, si ninguna opción
+	// necesita el mapa.
+	if texto, ok := input.(string); ok && cfg.admiteRutaPlana() {
+		if resultado, ok := ordenarPlano(dst, texto, cfg); ok {
+			return resultado, nil, nil
+		}
+	}
+	// Un documento plano tiene un solo nivel, por lo que la profundidad solo
+	// se revisa en la ruta general.
+	if err := cfg.revisarProfundidad(input); err != nil {
+		return dst, nil, err
+	}
+
+	var datos map[string]interface{}
+	deStruct := false    // Si datos se leyó de un struct, con WithCamposVacios.
+	var plan *planStruct // Plan del struct de OrdenarStruct, con WithTiposDocumento.
+	reutilizables := tomarClaves()
+	claves := *reutilizables
+	defer func() { devolverClaves(reutilizables, claves) }()
+
+	// Convertir el input a un mapa.
+	switch v := input.(type) {
+	case string:
+		// Si el input es una cadena, convertirla a un mapa conservando el orden original de las claves.
+		// Con un Codec alternativo se le delega cada valor; con encoding/json y
+		// presupuesto de tiempo, la lectura revisa el plazo mientras se decodifica.
+		var err error
+		if codec := cfg.codecAlternativo(); codec != nil {
+			datos, claves, err = decodificarConCodec(v, claves, codec, limite, cfg.clavesUnicas)
+		} else {
+			var r io.Reader = strings.NewReader(v)
+			if limite.activo() {
+				r = &lectorConPlazo{r: r, limite: limite}
+			}
+			datos, claves, err = decodificarDesde(r, claves, cfg.clavesUnicas)
+		}
+		if err != nil {
+			if errors.Is(err, errPlazoVencido) {
+				return dst, nil, limite.revisar("decodificación")
+			}
+			var errJSON *ErrorJSONInvalido
+			if errors.As(err, &errJSON) {
+				errJSON.ubicar(v)
+			}
+			return dst, nil, err
+		}
+	case map[string]interface{}:
+		// Si el input ya es un mapa, usarlo directamente.
+		// Si hay que transformar u omitir valores se trabaja sobre una copia.
+		datos = v
+		if cfg.modificaDatos() {
+			datos = copiarMapa(v)
+		}
+		// Un mapa no tiene orden propio; se parte del orden alfabético para que la salida sea determinista.
+		claves = slices.Grow(claves, len(datos))
+		for clave := range datos {
+			claves = append(claves, clave)
+		}
+		sort.Strings(claves)
+	case *entradaMetadata:
+		// El mapa es propio, por lo que se puede transformar sin copiarlo, y
+		// las claves ya vienen en orden alfabético.
+		datos, claves = datosDeMetadata((*DocumentMetadata)(v), claves, cfg)
+		deStruct = true
+	case *entradaStruct:
+		// Igual que un DocumentMetadata, con el plan del tipo del struct.
+		datos = make(map[string]interface{}, len(v.plan.campos))
+		l := &lectura{cfg: cfg}
+		if v.raiz.direccion != nil {
+			l.camino = map[visita]struct{}{v.raiz: {}}
+		}
+		leidas, _, err := v.plan.leer(v.base, datos, claves, l)
+		if err != nil {
+			return dst, nil, err
+		}
+		claves, deStruct, plan = leidas, true, v.plan
+	default:
+		// Un mapa tipado se convierte en un mapa propio, que se ordena igual
+		// que un map[string]interface{}.
+		mapa, ok, err := mapaTipado(input, cfg)
+		if !ok {
+			// Si el tipo de entrada no es soportado, retornar un error.
+			return dst, nil, &ErrorTipoNoSoportado{Tipo: reflect.TypeOf(input)}
+		}
+		if err != nil {
+			return dst, nil, err
+		}
+		if err := cfg.revisarProfundidad(mapa); err != nil {
+			return dst, nil, err
+		}
+		datos = mapa
+		claves = slices.Grow(claves, len(datos))
+		for clave := range datos {
+			claves = append(claves, clave)
+		}
+		sort.Strings(claves)
+	}
+
+	if cfg.explicacion != nil {
+		cfg.explicacion.originales = slices.Clone(claves)
+	}
+
+	// Llevar las claves de WithRenombres a su nombre actual.
+	if len(cfg.renombres) > 0 {
+		claves = renombrarClaves(datos, claves, cfg.renombres)
+	}
+
+	// Aplicar las transformaciones de WithTransform.
+	if len(cfg.transformaciones) > 0 {
+		transformar(datos, cfg.transformaciones)
+	}
+
+	// Omitir los valores que el criterio de WithVacio considera vacíos, salvo
+	// en los structs cuyos campos vacíos WithCamposVacios pide conservar.
+	if cfg.vacio != nil && (!deStruct || cfg.omiteCamposVacios()) {
+		claves = omitirVacios(datos, claves, cfg.vacio)
+	}
+
+	// Completar los campos que faltan con los valores de WithDefaults.
+	if cfg.defaults != nil {
+		claves = cfg.completarDefaults(datos, claves)
+	}
+	cfg.atributoTramo(AtributoClaves, len(claves))
+
+	// Con WithTiposDocumento, ordenar y validar con el perfil de la familia
+	// del documento.
+	if cfg.tiposDocumento {
+		familia, ok := buscarTipoDocumento(datos)
+		if cfg.familia != nil {
+			cfg.familia.tipo, _ = datos[claveTipoDocumento].(string)
+			cfg.familia.registro = familia
+		}
+		if ok {
+			copia := *cfg
+			copia.perfil = familia.perfil
+			if plan != nil {
+				copia.perfil = plan.perfil(familia.perfil)
+			}
+			cfg = &copia
+		}
+	}
+
+	// Normalizar las fechas si la opción está activa.
+	var problemas []Problema
+	if cfg.normalizarFechas {
+		for _, err := range normalizarFechas(datos, cfg) {
+			if !cfg.reporte {
+				return dst, nil, err
+			}
+			problemas = append(problemas, problemaDesdeError(ReglaFecha, err))
+		}
+	}
+
+	// Validar el documento según las reglas configuradas.
+	if err := limite.revisar("validación"); err != nil {
+		return dst, nil, err
+	}
+	encontrados, err := validarDocumento(datos, claves, cfg)
+	if err != nil {
+		return dst, nil, err
+	}
+	problemas = append(problemas, encontrados...)
+
+	// Dejar en la salida solo los campos de WithOnly y WithExclude. observar
+	// recibe todas las claves, por lo que se filtra una copia.
+	observadas := claves
+	if cfg.solo != nil || cfg.excluir != nil {
+		if cfg.observar != nil {
+			claves = slices.Clone(claves)
+		}
+		claves = cfg.filtrarClaves(claves)
+	}
+
+	// Ordenar las claves según el orden predefinido.
+	// La ordenación es estable: las claves fuera de OrdenCampos mantienen su orden relativo.
+	perfil := cfg.perfil
+	sort.SliceStable(claves, func(i, j int) bool {
+		return perfil.posicion(claves[i]) < perfil.posicion(claves[j])
+	})
+	if cfg.explicacion != nil {
+		cfg.explicacion.finales = slices.Clone(claves)
+		cfg.explicacion.perfil = perfil
+	}
+
+	// Escribir el JSON ordenado e indentado en una sola pasada: cada valor se
+	// codifica directamente con su sangría, sin armar antes un JSON compacto.
+	// Se reserva espacio una sola vez con el tamaño esperado de la salida.
+	tamanoEsperado := cfg.tamanoEsperado
+	if tamanoEsperado <= 0 {
+		tamanoEsperado = perfil.tamanoEstimado()
+	}
+	ind := indentadorPorDefecto
+	inicio := len(dst)
+	resultado := append(slices.Grow(dst, tamanoEsperado), '{')
+	for i, clave := range claves {
+		if i > 0 {
+			resultado = append(resultado, ',')
+		}
+		// Revisar periódicamente el presupuesto de tiempo.
+		if i%clavesEntreRevisiones == 0 {
+			if err := limite.revisar("serialización"); err != nil {
+				return dst, nil, err
+			}
+		}
+		// Escribir la clave ya codificada que provee el perfil.
+		claveJSON, err := perfil.claveCodificada(clave)
+		if err != nil {
+			return dst, nil, err
+		}
+		resultado = append(resultado, ind.linea(1)...)
+		resultado = append(resultado, claveJSON...)
+		resultado = append(resultado, ' ')
+		// Codificar el valor, redactado si WithMask lo indica.
+		valor := datos[clave]
+		if m, ok := cfg.mascaras[clave]; ok {
+			valor = enmascarar(valor, m)
+		}
+		if resultado, err = ind.escribirValor(resultado, valor, 1, cfg.codecAlternativo()); err != nil {
+			return dst, nil, &ErrorValorNoSerializable{Campo: clave, Err: err}
+		}
+	}
+	if len(claves) > 0 {
+		resultado = append(resultado, ind.linea(0)...)
+	}
+	resultado = append(resultado, '}')
+	perfil.registrarTamano(len(resultado) - inicio)
+	if cfg.observar != nil {
+		cfg.observar(datos, observadas)
+	}
+	return resultado, problemas, nil
+}
+
+// OrdenarMapaComoDocumentoMetadata convierte un mapa a JSON y luego lo ordena.
+// Es un wrapper alrededor de OrdenarJSON para facilitar su uso con mapas.
+func OrdenarMapaComoDocumentoMetadata(mapa map[string]interface{}, opts ...Option) (string, error) {
+	return OrdenarJSON(mapa, opts...)
+}
+
+// decodificarObjeto convierte un objeto JSON en un mapa y devuelve además sus claves
+// en el orden en que aparecen en el texto. Si una clave se repite, prevalece el último
+// valor, igual que con json.Unmarshal. Un literal null se interpreta como objeto vacío.
+func decodificarObjeto(texto string) (map[string]interface{}, []string, error) {
+	return decodificarDesde(strings.NewReader(texto), nil, false)
+}
+
+// decodificarDesde es equivalente a decodificarObjeto pero lee el objeto desde r
+// y agrega las claves a claves, que puede ser un slice reutilizado. Si unicas
+// es true, una clave repetida es un error en lugar de prevalecer el último valor.
+// Los errores de sintaxis se devuelven como *ErrorJSONInvalido con el offset
+// donde se detectaron; la línea y la columna las completa quien conoce el texto.
+func decodificarDesde(r io.Reader, claves []string, unicas bool) (map[string]interface{}, []string, error) {
+	return decodificarConDecoder(json.NewDecoder(r), claves, unicas)
+}
+
+// decodificarConDecoder es equivalente a decodificarDesde pero lee con dec,
+// para que quien lo llame pueda configurarlo, por ejemplo con UseNumber.
+func decodificarConDecoder(dec *json.Decoder, claves []string, unicas bool) (map[string]interface{}, []string, error) {
+	invalido := func(err error) error {
+		if errors.Is(err, errPlazoVencido) {
+			return err
+		}
+		offset := dec.InputOffset()
+		var errSintaxis *json.SyntaxError
+		if errors.As(err, &errSintaxis) {
+			offset = errSintaxis.Offset
+		}
+		return &ErrorJSONInvalido{Offset: offset, Err: err}
+	}
+	token, err := dec.Token()
+	if err != nil {
+		return nil, nil, invalido(err)
+	}
+	datos := make(map[string]interface{})
+	switch token {
+	case nil:
+		// null equivale a un objeto vacío, igual que con json.Unmarshal.
+	case json.Delim('{'):
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return nil, nil, invalido(err)
+			}
+			clave := internarTexto(token.(string))
+			var valor interface{}
+			if err := dec.Decode(&valor); err != nil {
+				return nil, nil, invalido(err)
+			}
+			if _, repetida := datos[clave]; !repetida {
+				claves = append(claves, clave)
+			} else if unicas {
+				return nil, nil, &ErrorJSONInvalido{Offset: dec.InputOffset(), Err: &ErrorClaveDuplicada{Clave: clave}}
+			}
+			datos[clave] = valor
+		}
+		// Consumir la llave de cierre.
+		if _, err := dec.Token(); err != nil {
+			return nil, nil, invalido(err)
+		}
+	default:
+		return nil, nil, invalido(&json.UnmarshalTypeError{Value: fmt.Sprint(token), Type: reflect.TypeOf(datos), Offset: dec.InputOffset()})
+	}
+	// No se admite contenido después del objeto.
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("contenido inesperado después del objeto JSON")
+		}
+		return nil, nil, invalido(err)
+	}
+	return datos, claves, nil
+}
+
+// codificarObjeto escribe datos como un objeto JSON compacto con las claves
+// en el orden de claves, para ordenarlo después con OrdenarJSON sin perder
+// el orden de las que no están en el perfil.
+func codificarObjeto(datos map[string]interface{}, claves []string) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, clave := range claves {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		codificada, err := json.Marshal(clave)
+		if err != nil {
+			return "", err
+		}
+		buf.Write(codificada)
+		buf.WriteByte(':')
+		codificado, err := codificarJSON(datos[clave])
+		if err != nil {
+			return "", err
+		}
+		buf.Write(codificado)
+	}
+	buf.WriteByte('}')
+	return buf.String(), nil
+}
+
+// modificaDatos indica si alguna opción modifica el mapa del documento antes
+// de escribirlo, por lo que un mapa recibido como entrada se debe copiar.
+func (cfg *configuracion) modificaDatos() bool {
+	return cfg.normalizarFechas || cfg.vacio != nil || len(cfg.renombres) > 0 ||
+		len(cfg.transformaciones) > 0 || cfg.defaults != nil
+}
+
+// copiarMapa devuelve una copia superficial del mapa recibido.
+func copiarMapa(mapa map[string]interface{}) map[string]interface{} {
+	copia := make(map[string]interface{}, len(mapa))
+	for clave, valor := range mapa {
+		copia[clave] = valor
+	}
+	return copia
+}
\ No newline at end of file
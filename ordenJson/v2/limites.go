@@ -0,0 +1,165 @@
+package ordenJson
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Nombres de los límites que informa ErrorLimiteExcedido.
+const (
+	LimiteBytes       = "bytes"
+	LimiteProfundidad = "profundidad"
+)
+
+// WithMaxBytes rechaza con un *ErrorLimiteExcedido los documentos recibidos
+// como cadena de más de n bytes, antes de decodificarlos. En OrdenarFlujo el
+// límite se aplica a cada documento, y la lectura se detiene sin retener en
+// memoria más que unos pocos kilobytes por encima de n. Un n <= 0 no limita
+// el tamaño.
+func WithMaxBytes(n int) Option {
+	return func(cfg *configuracion) {
+		cfg.maxBytes = max(n, 0)
+	}
+}
+
+// WithMaxDepth rechaza con un *ErrorLimiteExcedido los documentos con más de
+// n niveles de objetos y arreglos anidados; el objeto del documento es el
+// nivel 1. En las cadenas se revisa antes de decodificarlas, para que un
+// documento absurdamente anidado no llegue a ocupar memoria. Un n <= 0 no
+// limita la profundidad, aparte del límite propio de encoding/json.
+func WithMaxDepth(n int) Option {
+	return func(cfg *configuracion) {
+		cfg.maxProfundidad = max(n, 0)
+	}
+}
+
+// Limites describe las restricciones que un Ordenador impone a cada
+// documento; un cero indica que no hay límite. Ver Ordenador.Limites.
+type Limites struct {
+	Bytes        int           // Ver WithMaxBytes.
+	Profundidad  int           // Ver WithMaxDepth.
+	Presupuesto  time.Duration // Ver WithPresupuesto.
+	ClavesUnicas bool          // Las claves repetidas se rechazan; ver WithClavesUnicas.
+}
+
+// Limites devuelve los límites configurados en el Ordenador, para informarlos
+// a quien le envía documentos.
+func (o *Ordenador) Limites() Limites {
+	return Limites{
+		Bytes:        o.cfg.maxBytes,
+		Profundidad:  o.cfg.maxProfundidad,
+		Presupuesto:  max(o.cfg.presupuesto, 0),
+		ClavesUnicas: o.cfg.clavesUnicas,
+	}
+}
+
+// revisarTamano devuelve un *ErrorLimiteExcedido si input es una cadena más
+// larga que WithMaxBytes.
+func (cfg *configuracion) revisarTamano(input interface{}) error {
+	if texto, ok := input.(string); ok && cfg.maxBytes > 0 && len(texto) > cfg.maxBytes {
+		return &ErrorLimiteExcedido{Limite: LimiteBytes, Maximo: cfg.maxBytes, Valor: len(texto)}
+	}
+	return nil
+}
+
+// revisarProfundidad devuelve un *ErrorLimiteExcedido si input tiene más
+// niveles que WithMaxDepth. En las cadenas solo se cuentan las llaves y los
+// corchetes fuera de las cadenas JSON, sin validar el resto del texto.
+func (cfg *configuracion) revisarProfundidad(input interface{}) error {
+	if cfg.maxProfundidad <= 0 {
+		return nil
+	}
+	excedido := false
+	switch v := input.(type) {
+	case string:
+		excedido = profundidadTexto(v, cfg.maxProfundidad) > cfg.maxProfundidad
+	case map[string]interface{}:
+		excedido = profundidadValor(v, cfg.maxProfundidad) > cfg.maxProfundidad
+	}
+	if excedido {
+		return &ErrorLimiteExcedido{Limite: LimiteProfundidad, Maximo: cfg.maxProfundidad}
+	}
+	return nil
+}
+
+// profundidadTexto devuelve la cantidad máxima de llaves y corchetes abiertos
+// a la vez en texto, o un valor mayor que maximo en cuanto lo supera.
+func profundidadTexto(texto string, maximo int) int {
+	profundidad, mayor := 0, 0
+	for i := 0; i < len(texto); i++ {
+		switch texto[i] {
+		case '"':
+			if fin := finDeCadena(texto, i); fin > 0 {
+				i = fin - 1
+			} else {
+				i = len(texto)
+			}
+		case '{', '[':
+			if profundidad++; profundidad > mayor {
+				if mayor = profundidad; mayor > maximo {
+					return mayor
+				}
+			}
+		case '}', ']':
+			profundidad--
+		}
+	}
+	return mayor
+}
+
+// profundidadValor devuelve los niveles de objetos y arreglos de v, o un
+// valor mayor que maximo en cuanto lo supera.
+func profundidadValor(v interface{}, maximo int) int {
+	mayor := 0
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if maximo <= 0 {
+			return 1
+		}
+		for _, elemento := range v {
+			if mayor = max(mayor, profundidadValor(elemento, maximo-1)); mayor > maximo-1 {
+				break
+			}
+		}
+	case []interface{}:
+		if maximo <= 0 {
+			return 1
+		}
+		for _, elemento := range v {
+			if mayor = max(mayor, profundidadValor(elemento, maximo-1)); mayor > maximo-1 {
+				break
+			}
+		}
+	default:
+		return 0
+	}
+	return mayor + 1
+}
+
+// holguraLectura es lo que lectorAcotado deja leer por encima del límite de
+// un documento, para que el decodificador pueda ver dónde termina.
+const holguraLectura = 4096
+
+// errDocumentoExcedido lo devuelve lectorAcotado cuando un documento del
+// flujo supera WithMaxBytes.
+var errDocumentoExcedido = errors.New("documento demasiado grande")
+
+// lectorAcotado limita lo que se lee de r mientras se decodifica un
+// documento: restantes se renueva antes de cada uno.
+type lectorAcotado struct {
+	r         io.Reader
+	restantes int
+}
+
+func (l *lectorAcotado) Read(b []byte) (int, error) {
+	if l.restantes <= 0 {
+		return 0, errDocumentoExcedido
+	}
+	if len(b) > l.restantes {
+		b = b[:l.restantes]
+	}
+	n, err := l.r.Read(b)
+	l.restantes -= n
+	return n, err
+}
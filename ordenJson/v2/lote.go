@@ -0,0 +1,89 @@
+package ordenJson
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Resultado es el resultado de ordenar uno de los documentos de OrdenarLote.
+type Resultado struct {
+	Salida string // Documento ordenado; vacío si hubo un error.
+	Err    error  // Error al ordenar el documento, o el del contexto si no se llegó a procesar.
+}
+
+// WithTrabajadores indica cuántas goroutines ordenan documentos en paralelo
+// en OrdenarLote. Con n <= 0, el valor por defecto, se usa runtime.GOMAXPROCS(0).
+func WithTrabajadores(n int) Option {
+	return func(cfg *configuracion) {
+		cfg.trabajadores = n
+	}
+}
+
+// OrdenarLote ordena en paralelo los documentos recibidos. Ver Ordenador.OrdenarLote.
+func OrdenarLote(ctx context.Context, inputs []string, opts ...Option) ([]Resultado, error) {
+	return Nuevo(opts...).OrdenarLote(ctx, inputs)
+}
+
+// OrdenarLote reparte los documentos entre los trabajadores indicados con
+// WithTrabajadores y devuelve un Resultado por documento, en el mismo orden
+// que inputs. Que un documento no se pueda ordenar no detiene el lote: su
+// error queda en su Resultado. Cada documento se ordena con OrdenarJSONCtx:
+// si ctx termina, los documentos en curso se abortan con un *ErrorCancelado,
+// los que no se llegaron a procesar reciben ctx.Err() como error y
+// OrdenarLote devuelve también ctx.Err() junto con los resultados.
+func (o *Ordenador) OrdenarLote(ctx context.Context, inputs []string) ([]Resultado, error) {
+	resultados := make([]Resultado, len(inputs))
+	trabajadores := o.cfg.trabajadores
+	if trabajadores <= 0 {
+		trabajadores = runtime.GOMAXPROCS(0)
+	}
+	trabajadores = min(trabajadores, len(inputs))
+	ctx, tramo := o.cfg.tramoEn(ctx, TramoLote)
+	if tramo != nil {
+		tramo.Atributo(AtributoDocumentos, len(inputs))
+		tramo.Atributo(AtributoTrabajadores, trabajadores)
+		defer func() { terminarTramoLote(tramo, resultados, ctx.Err()) }()
+	}
+
+	// Cada trabajador toma el siguiente documento sin procesar, de modo que
+	// los documentos costosos no dejan a otros trabajadores sin tarea.
+	var siguiente atomic.Int64
+	var wg sync.WaitGroup
+	for t := 0; t < trabajadores; t++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(siguiente.Add(1) - 1)
+				if i >= len(inputs) {
+					return
+				}
+				resultados[i].Salida, resultados[i].Err = o.OrdenarJSONCtx(ctx, inputs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := min(int(siguiente.Load()), len(inputs)); i < len(inputs); i++ {
+			resultados[i].Err = err
+		}
+		return resultados, err
+	}
+	return resultados, nil
+}
+
+// terminarTramoLote cierra el tramo de OrdenarLote con la cantidad de
+// documentos con error.
+func terminarTramoLote(tramo Tramo, resultados []Resultado, err error) {
+	fallidos := 0
+	for _, r := range resultados {
+		if r.Err != nil {
+			fallidos++
+		}
+	}
+	tramo.Atributo(AtributoFallidos, fallidos)
+	tramo.Terminar(err)
+}
@@ -0,0 +1,87 @@
+package ordenJson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mascara devuelve la versión redactada de un valor de texto. Ver WithMask.
+type Mascara func(texto string) string
+
+// caracterMascara reemplaza a cada carácter oculto por las máscaras del paquete.
+const caracterMascara = "*"
+
+// WithMask redacta con m el valor del campo de primer nivel indicado al
+// escribir la salida, para que los documentos ordenados que van a logs o a
+// ambientes no productivos no expongan datos sensibles:
+//
+//	ordenJson.WithMask("tanner:rut-cliente", ordenJson.MaskAllButLast4)
+//
+// La estructura y el orden no cambian: si el valor es un objeto o un arreglo,
+// se redacta cada texto que contiene; los números se redactan como texto y
+// los booleanos y null quedan sin cambios. La validación, WithTransform y
+// Estadisticas ven el valor original. Con varias máscaras para el mismo
+// campo prevalece la última.
+func WithMask(campo string, m Mascara) Option {
+	return func(cfg *configuracion) {
+		if cfg.mascaras == nil {
+			cfg.mascaras = make(map[string]Mascara)
+		}
+		if m == nil {
+			delete(cfg.mascaras, campo)
+			return
+		}
+		cfg.mascaras[campo] = m
+	}
+}
+
+// MaskAll reemplaza cada carácter del texto por un asterisco.
+func MaskAll(texto string) string {
+	return strings.Repeat(caracterMascara, utf8.RuneCountInString(texto))
+}
+
+// MaskAllButLast4 reemplaza por asteriscos todos los caracteres del texto
+// salvo los cuatro últimos, como "******78-K" para "12345678-K". Los textos
+// de cuatro caracteres o menos se ocultan completos.
+func MaskAllButLast4(texto string) string {
+	n := utf8.RuneCountInString(texto)
+	if n <= 4 {
+		return MaskAll(texto)
+	}
+	ultimos := texto
+	for i := 0; i < n-4; i++ {
+		_, tamano := utf8.DecodeRuneInString(ultimos)
+		ultimos = ultimos[tamano:]
+	}
+	return strings.Repeat(caracterMascara, n-4) + ultimos
+}
+
+// enmascarar aplica m a los textos de valor sin modificarlo y devuelve el
+// valor redactado.
+func enmascarar(valor interface{}, m Mascara) interface{} {
+	switch v := valor.(type) {
+	case string:
+		return m(v)
+	case float64:
+		return m(strconv.FormatFloat(v, 'f', -1, 64))
+	case map[string]interface{}:
+		copia := make(map[string]interface{}, len(v))
+		for clave, hijo := range v {
+			copia[clave] = enmascarar(hijo, m)
+		}
+		return copia
+	case []interface{}:
+		copia := make([]interface{}, len(v))
+		for i, hijo := range v {
+			copia[i] = enmascarar(hijo, m)
+		}
+		return copia
+	case bool, nil:
+		return valor
+	default:
+		// Números de un Codec alternativo, como json.Number o int64.
+		return m(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,135 @@
+package ordenJson
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Middleware envuelve next para que las respuestas application/json (o con
+// un tipo +json) se devuelvan con las claves en el orden canónico de
+// PerfilPorDefecto. Ver Ordenador.Middleware.
+func Middleware(next http.Handler) http.Handler {
+	return Nuevo().Middleware(next)
+}
+
+// Middleware envuelve next para que las respuestas JSON se devuelvan ordenadas
+// con la configuración del Ordenador. El cuerpo de esas respuestas se acumula
+// hasta que next termina, se ordena y se envía con el Content-Length
+// corregido; mientras tanto Flush no envía nada. Las respuestas en las que
+// next no escribe el cuerpo, como las de HEAD, conservan el Content-Length
+// que haya definido next. Las respuestas que no son JSON, las comprimidas (con
+// Content-Encoding) y las que no tienen cuerpo pasan sin cambios; un cuerpo
+// JSON que no se puede ordenar, como un arreglo o un documento que no cumple
+// las validaciones, se envía tal como lo escribió next.
+func (o *Ordenador) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respuesta := &respuestaOrdenada{ResponseWriter: w, ordenador: o, head: r.Method == http.MethodHead}
+		next.ServeHTTP(respuesta, r)
+		respuesta.terminar()
+	})
+}
+
+// respuestaOrdenada retiene el cuerpo de una respuesta JSON para ordenarlo
+// cuando el handler termina.
+type respuestaOrdenada struct {
+	http.ResponseWriter
+	ordenador *Ordenador
+	head      bool // La petición es HEAD.
+	estado    int  // Código de estado; 0 hasta que se decide.
+	retener   bool // El cuerpo se acumula en cuerpo en lugar de enviarse.
+	cuerpo    bytes.Buffer
+}
+
+// WriteHeader decide, con los encabezados ya definidos, si el cuerpo se
+// ordena. Los códigos informativos (1xx) se envían sin más.
+func (r *respuestaOrdenada) WriteHeader(estado int) {
+	if estado >= 100 && estado < 200 {
+		r.ResponseWriter.WriteHeader(estado)
+		return
+	}
+	if r.estado != 0 {
+		return
+	}
+	r.estado = estado
+	r.retener = esTipoJSON(r.Header().Get("Content-Type")) &&
+		r.Header().Get("Content-Encoding") == "" &&
+		estado != http.StatusNoContent && estado != http.StatusNotModified
+	if !r.retener {
+		r.ResponseWriter.WriteHeader(estado)
+	}
+}
+
+// Write acumula el cuerpo si se va a ordenar o lo envía directamente.
+func (r *respuestaOrdenada) Write(p []byte) (int, error) {
+	if r.estado == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.retener {
+		return r.ResponseWriter.Write(p)
+	}
+	return r.cuerpo.Write(p)
+}
+
+// Flush envía lo escrito hasta ahora si el cuerpo no se ordena. Si se
+// ordena no hace nada: el cuerpo se envía completo cuando next termina.
+func (r *respuestaOrdenada) Flush() {
+	r.FlushError()
+}
+
+// FlushError es equivalente a Flush pero devuelve el error de la respuesta
+// original; es el método que usa http.ResponseController. No se expone
+// Unwrap para que http.ResponseController no omita el cuerpo retenido.
+func (r *respuestaOrdenada) FlushError() error {
+	if r.estado == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.retener {
+		return nil
+	}
+	return http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// terminar ordena y envía el cuerpo retenido.
+func (r *respuestaOrdenada) terminar() {
+	if !r.retener {
+		return
+	}
+	cuerpo := r.cuerpo.Bytes()
+	if r.head && len(cuerpo) == 0 {
+		// No hay cuerpo que medir: se conserva el Content-Length de next.
+		r.ResponseWriter.WriteHeader(r.estado)
+		return
+	}
+	// Solo se ordenan objetos: OrdenarJSON convertiría un null en {}.
+	if !bytes.HasPrefix(bytes.TrimSpace(cuerpo), []byte("{")) {
+		r.enviar(cuerpo)
+		return
+	}
+	if ordenado, err := r.ordenador.OrdenarJSON(string(cuerpo)); err == nil {
+		// Se conserva el salto de línea final que agrega json.Encoder.
+		if bytes.HasSuffix(cuerpo, []byte("\n")) {
+			ordenado += "\n"
+		}
+		cuerpo = []byte(ordenado)
+	}
+	r.enviar(cuerpo)
+}
+
+// enviar escribe el código de estado y el cuerpo con su Content-Length.
+func (r *respuestaOrdenada) enviar(cuerpo []byte) {
+	r.Header().Set("Content-Length", strconv.Itoa(len(cuerpo)))
+	r.ResponseWriter.WriteHeader(r.estado)
+	r.ResponseWriter.Write(cuerpo)
+}
+
+// esTipoJSON indica si el Content-Type corresponde a un documento JSON.
+func esTipoJSON(contentType string) bool {
+	tipo, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return tipo == "application/json" || strings.HasSuffix(tipo, "+json")
+}
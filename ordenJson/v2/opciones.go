@@ -0,0 +1,82 @@
+package ordenJson
+
+import (
+	"context"
+	"time"
+)
+
+// Option modifica la configuración utilizada por las funciones de ordenamiento.
+// Las opciones se aplican en el orden recibido, por lo que una opción posterior
+// puede sobrescribir el efecto de una anterior.
+type Option func(*configuracion)
+
+// configuracion agrupa los parámetros que controlan una llamada de ordenamiento.
+type configuracion struct {
+	perfil            *Perfil                     // Perfil cuyo orden de campos se aplica.
+	normalizarFechas  bool                        // Indica si los campos de fecha se deben normalizar.
+	formatosFecha     []string                    // Layouts aceptados al interpretar las fechas de entrada.
+	layoutFecha       string                      // Layout de las fechas que se escriben; vacío usa FormatoFechaCanonico.
+	camposFecha       []string                    // Campos que se tratan como fechas.
+	requeridos        []string                    // Campos que deben tener valor.
+	estricto          bool                        // Indica si se rechazan las claves que no están en el perfil.
+	clavesUnicas      bool                        // Indica si se rechazan las claves repetidas; ver WithClavesUnicas.
+	idioma            Idioma                      // Idioma de los errores de WithIdioma; vacío usa IdiomaActual.
+	tamanoEsperado    int                         // Tamaño esperado de la salida en bytes; 0 usa la estimación del perfil.
+	presupuesto       time.Duration               // Tiempo máximo para ordenar un documento; 0 sin límite.
+	valoresPermitidos map[string][]interface{}    // Valores admitidos por campo.
+	vacio             func(string) bool           // Criterio de valor vacío de WithVacio; nil usa la cadena vacía.
+	camposVacios      CamposVacios                // Qué hacer con los campos vacíos de un struct; ver WithCamposVacios.
+	tiposDocumento    bool                        // Elegir el perfil según tanner:tipo-documento; ver WithTiposDocumento.
+	claveParticion    string                      // Campo que determina la partición en Particionar; vacío usa el documento completo.
+	eventos           RegistroDeEventos           // Recibe un Evento por operación; nil no registra nada.
+	trabajadores      int                         // Goroutines de OrdenarLote; 0 usa runtime.GOMAXPROCS(0).
+	codec             Codec                       // Decodifica las cadenas y codifica los valores; ver WithCodec.
+	cache             *cacheResultados            // Salidas ya calculadas; nil sin WithCache.
+	maxBytes          int                         // Tamaño máximo de las cadenas de entrada; 0 sin límite.
+	maxProfundidad    int                         // Niveles máximos de anidamiento; 0 sin límite.
+	trazador          Trazador                    // Crea los tramos de WithTrazador; nil no traza.
+	renombres         map[string]string           // Nombre actual de cada clave anterior; ver WithRenombres.
+	transformaciones  map[string][]Transformacion // Transformaciones de WithTransform por campo.
+	mascaras          map[string]Mascara          // Máscara de WithMask por campo.
+	solo              map[string]struct{}         // Campos de WithOnly; nil escribe todos.
+	excluir           map[string]struct{}         // Campos de WithExclude.
+	defaults          map[string]interface{}      // Valores de WithDefaults por campo.
+	camposDefaults    []string                    // Claves de defaults en orden alfabético.
+
+	reporte     bool              // Acumula los problemas de validación en lugar de abortar (OrdenarJSONConReporte).
+	ctx         context.Context   // Contexto de las variantes Ctx; nil en las demás.
+	tramo       Tramo             // Tramo de la operación en curso; nil si no se traza.
+	explicacion *trazaOrden       // Recibe el orden de las claves (OrdenarJSONExplicado); nil en las demás.
+	familia     *familiaDocumento // Recibe la familia del documento (DecodificarDocumento); nil en las demás.
+
+	// observar, si no es nil, recibe cada documento ordenado con éxito
+	// (Estadisticas), con todas sus claves, también las que WithOnly y
+	// WithExclude omiten de la salida. No debe modificar datos ni claves.
+	observar func(datos map[string]interface{}, claves []string)
+}
+
+// nuevaConfiguracion construye la configuración por defecto y le aplica las opciones recibidas.
+func nuevaConfiguracion(opts []Option) *configuracion {
+	cfg := &configuracion{
+		perfil:        PerfilPorDefecto,
+		formatosFecha: FormatosFechaPorDefecto,
+		camposFecha:   CamposFecha,
+		codec:         CodecActual(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	return cfg
+}
+
+// WithTamanoEsperado indica el tamaño aproximado, en bytes, de la salida esperada.
+// Los buffers internos se reservan con ese tamaño desde el inicio en lugar de
+// crecer varias veces al procesar documentos grandes. Sin esta opción se usa la
+// estimación que cada perfil aprende de los documentos que ya ordenó.
+func WithTamanoEsperado(bytes int) Option {
+	return func(cfg *configuracion) {
+		cfg.tamanoEsperado = bytes
+	}
+}
@@ -0,0 +1,109 @@
+package ordenJson
+
+import (
+	"context"
+	"time"
+)
+
+// Ordenador aplica un conjunto fijo de opciones a cada documento que ordena.
+// Las opciones se resuelven una sola vez en Nuevo, por lo que conviene crear
+// un Ordenador y reutilizarlo cuando se ordenan muchos documentos con la misma
+// configuración. Es seguro usarlo desde varias goroutines a la vez. Los
+// buffers que usa para cada documento se reutilizan entre llamadas; con
+// AgregarJSON el resultado se escribe además en un buffer del llamador.
+type Ordenador struct {
+	cfg configuracion
+}
+
+// Nuevo crea un Ordenador con las opciones recibidas.
+func Nuevo(opts ...Option) *Ordenador {
+	return &Ordenador{cfg: *nuevaConfiguracion(opts)}
+}
+
+// Perfil devuelve el perfil cuyo orden de campos aplica el Ordenador.
+func (o *Ordenador) Perfil() *Perfil {
+	return o.cfg.perfil
+}
+
+// OrdenarJSON ordena un documento JSON recibido como cadena o como
+// map[string]interface{}. Ver la función OrdenarJSON del paquete.
+func (o *Ordenador) OrdenarJSON(input interface{}) (string, error) {
+	inicio := time.Now()
+	salida, _, err := ordenar(input, &o.cfg)
+	o.cfg.registrarEvento(OperacionOrdenar, inicio, input, salida, nil, err)
+	return salida, err
+}
+
+// AgregarJSON ordena input igual que OrdenarJSON y agrega el resultado a dst,
+// que se devuelve extendido. Reutilizando dst entre documentos, por ejemplo
+// con buf = o.AgregarJSON(buf[:0], doc), ordenar no reserva memoria para la
+// salida. Si hay un error, se devuelve dst sin cambios.
+func (o *Ordenador) AgregarJSON(dst []byte, input interface{}) ([]byte, error) {
+	return o.cfg.agregarJSON(dst, input)
+}
+
+// agregarJSON implementa AgregarJSON con la configuración cfg.
+func (cfg *configuracion) agregarJSON(dst []byte, input interface{}) ([]byte, error) {
+	inicio := time.Now()
+	cfg, tramo := cfg.iniciarTramo(TramoOrdenar)
+	if tramo != nil {
+		cfg.atributosEntrada(input)
+	}
+	var resultado []byte
+	var err error
+	entrada, cacheable := cfg.cacheable(input)
+	if !cacheable {
+		resultado, _, err = ordenarEn(dst, input, cfg)
+	} else if hash, guardada, ok := cfg.cache.buscar(entrada); ok {
+		resultado = append(dst, guardada...)
+		cfg.atributoTramo(AtributoCache, true)
+	} else if resultado, _, err = ordenarEn(dst, input, cfg); err == nil {
+		cfg.cache.guardar(hash, entrada, string(resultado[len(dst):]))
+	}
+	if err != nil {
+		resultado = dst
+		localizarError(err, cfg.idioma)
+	}
+	terminarTramo(tramo, len(resultado)-len(dst), err)
+	if cfg.eventos != nil {
+		cfg.registrarEvento(OperacionOrdenar, inicio, input, string(resultado[len(dst):]), nil, err)
+	}
+	return resultado, err
+}
+
+// OrdenarJSONCtx ordena el documento igual que OrdenarJSON, pero revisa ctx
+// en los mismos puntos que WithPresupuesto: durante la decodificación, entre
+// las etapas y durante la serialización. Si ctx termina antes de completar el
+// documento, devuelve un *ErrorCancelado.
+func (o *Ordenador) OrdenarJSONCtx(ctx context.Context, input interface{}) (string, error) {
+	inicio := time.Now()
+	cfg := o.cfg
+	cfg.ctx = ctx
+	salida, _, err := ordenar(input, &cfg)
+	cfg.registrarEvento(OperacionOrdenar, inicio, input, salida, nil, err)
+	return salida, err
+}
+
+// OrdenarJSONConReporte ordena el documento acumulando los problemas de
+// validación. Ver la función OrdenarJSONConReporte del paquete.
+func (o *Ordenador) OrdenarJSONConReporte(input interface{}) (string, []Problema, error) {
+	return o.OrdenarJSONConReporteCtx(context.Background(), input)
+}
+
+// OrdenarJSONConReporteCtx es como OrdenarJSONConReporte, pero se aborta con
+// un *ErrorCancelado si ctx termina; ver OrdenarJSONCtx.
+func (o *Ordenador) OrdenarJSONConReporteCtx(ctx context.Context, input interface{}) (string, []Problema, error) {
+	inicio := time.Now()
+	cfg := o.cfg
+	cfg.reporte = true
+	cfg.ctx = ctx
+	salida, problemas, err := ordenar(input, &cfg)
+	cfg.registrarEvento(OperacionReporte, inicio, input, salida, problemas, err)
+	return salida, problemas, err
+}
+
+// OrdenarDocumentoMetadata ordena los campos no vacíos de metadata.
+// Ver la función OrdenarDocumentoMetadata del paquete.
+func (o *Ordenador) OrdenarDocumentoMetadata(metadata DocumentMetadata) (string, error) {
+	return o.OrdenarJSON((*entradaMetadata)(&metadata))
+}
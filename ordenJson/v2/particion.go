@@ -0,0 +1,160 @@
+package ordenJson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WithClaveParticion hace que Particionar use solo el valor del campo indicado
+// (por ejemplo "tanner:rut-cliente") en lugar del documento completo, para que
+// todos los documentos de un mismo cliente caigan en la misma partición.
+func WithClaveParticion(campo string) Option {
+	return func(cfg *configuracion) {
+		cfg.claveParticion = campo
+	}
+}
+
+// Particionar asigna el documento a una de las particiones, numeradas de 0 a
+// particiones-1. La asignación es estable: depende solo del contenido canónico
+// del documento (su salida ordenada con las opciones recibidas, con todas las
+// claves en orden alfabético; ver formaCanonica), por lo que el orden de las
+// claves o el formato de la entrada no la alteran, y es la misma
+// en cualquier proceso o máquina. Con WithClaveParticion depende solo del
+// valor de ese campo; si el campo falta se devuelve *ErrorCamposFaltantes.
+//
+// Se usa hash consistente: al aumentar la cantidad de particiones de n a n+1
+// solo se mueve aproximadamente 1/(n+1) de los documentos.
+func Particionar(doc interface{}, particiones int, opts ...Option) (int, error) {
+	return Nuevo(opts...).Particionar(doc, particiones)
+}
+
+// Particionar asigna el documento a una partición. Ver la función Particionar del paquete.
+func (o *Ordenador) Particionar(doc interface{}, particiones int) (int, error) {
+	if particiones <= 0 {
+		return 0, fmt.Errorf("la cantidad de particiones debe ser positiva: %d", particiones)
+	}
+	inicio := time.Now()
+	canonico, err := o.bytesParticion(doc)
+	if err != nil {
+		o.cfg.registrarEvento(OperacionParticionar, inicio, doc, "", nil, err)
+		return 0, err
+	}
+	var salida string
+	if o.cfg.claveParticion == "" {
+		salida = string(canonico)
+	}
+	o.cfg.registrarEvento(OperacionParticionar, inicio, doc, salida, nil, nil)
+	h := fnv.New64a()
+	if o.cfg.claveParticion == "" {
+		canonico = formaCanonica(canonico)
+	}
+	h.Write(canonico)
+	return hashConsistente(h.Sum64(), particiones), nil
+}
+
+// bytesParticion devuelve los bytes a partir de los cuales se calcula la partición.
+func (o *Ordenador) bytesParticion(doc interface{}) ([]byte, error) {
+	if o.cfg.claveParticion == "" {
+		salida, _, err := ordenar(doc, &o.cfg)
+		return []byte(salida), err
+	}
+
+	var datos map[string]interface{}
+	switch v := doc.(type) {
+	case string:
+		var err error
+		if datos, _, err = decodificarObjeto(v); err != nil {
+			var errJSON *ErrorJSONInvalido
+			if errors.As(err, &errJSON) {
+				errJSON.ubicar(v)
+			}
+			return nil, err
+		}
+	case map[string]interface{}:
+		datos = v
+	default:
+		mapa, ok, err := mapaTipado(doc, &o.cfg)
+		if !ok {
+			return nil, &ErrorTipoNoSoportado{Tipo: reflect.TypeOf(doc)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		datos = mapa
+	}
+	valor := datos[o.cfg.claveParticion]
+	if estaVacio(valor) {
+		return nil, &ErrorCamposFaltantes{Campos: []string{o.cfg.claveParticion}}
+	}
+	if texto, ok := valor.(string); ok {
+		return []byte(texto), nil
+	}
+	codificado, err := codificarJSON(valor)
+	if err != nil {
+		return nil, &ErrorValorNoSerializable{Campo: o.cfg.claveParticion, Err: err}
+	}
+	return codificado, nil
+}
+
+// formaCanonica devuelve ordenado, la salida de ordenar, con sus claves de
+// primer nivel en orden alfabético y sin espacios. La salida ordenada
+// conserva el orden de la entrada para las claves fuera del perfil, por lo
+// que dos documentos que solo difieren en ese orden tienen la misma forma
+// canónica; los valores ya no dependen del orden de la entrada, porque los
+// objetos anidados se escriben con las claves en orden alfabético.
+func formaCanonica(ordenado []byte) []byte {
+	type miembro struct {
+		clave string
+		valor json.RawMessage
+	}
+	var miembros []miembro
+	dec := json.NewDecoder(bytes.NewReader(ordenado))
+	// La salida de ordenar siempre es un objeto válido.
+	if _, err := dec.Token(); err != nil {
+		return ordenado
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		clave, ok := token.(string)
+		var valor json.RawMessage
+		if err != nil || !ok || dec.Decode(&valor) != nil {
+			return ordenado
+		}
+		miembros = append(miembros, miembro{clave: clave, valor: valor})
+	}
+	slices.SortFunc(miembros, func(a, b miembro) int { return strings.Compare(a.clave, b.clave) })
+	var canonica bytes.Buffer
+	canonica.WriteByte('{')
+	for i, m := range miembros {
+		if i > 0 {
+			canonica.WriteByte(',')
+		}
+		// json.Marshal no falla al codificar un string.
+		clave, _ := json.Marshal(m.clave)
+		canonica.Write(clave)
+		canonica.WriteByte(':')
+		json.Compact(&canonica, m.valor)
+	}
+	canonica.WriteByte('}')
+	return canonica.Bytes()
+}
+
+// hashConsistente implementa el "jump consistent hash" de Lamping y Veach:
+// distribuye clave de forma uniforme entre n particiones y, al agregar una
+// partición, solo reasigna las claves que pasan a la nueva.
+func hashConsistente(clave uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		clave = clave*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((clave>>33)+1)))
+	}
+	return int(b)
+}
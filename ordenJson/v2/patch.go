@@ -0,0 +1,425 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operacionPatch es una operación de un JSON Patch (RFC 6902).
+type operacionPatch struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// AplicarPatch aplica un JSON Patch con las opciones recibidas. Ver
+// Ordenador.AplicarPatch.
+func AplicarPatch(doc, patch string, opts ...Option) (string, error) {
+	return Nuevo(opts...).AplicarPatch(doc, patch)
+}
+
+// AplicarPatch aplica a doc las operaciones de patch, un JSON Patch (RFC
+// 6902), y devuelve el resultado ordenado con OrdenarJSON, por lo que también
+// se valida. Se admiten las operaciones add, remove, replace, move, copy y
+// test; las rutas son JSON Pointer (RFC 6901), como "/cm:title" o
+// "/lista/-". Las operaciones se aplican en orden; si una falla, incluido un
+// test que no se cumple, se devuelve un *ErrorPatch y ningún resultado. Las
+// claves de primer nivel fuera del perfil conservan su orden, y las que se
+// agregan van después de las existentes. Si doc no es un objeto JSON válido se devuelve
+// un *ErrorJSONInvalido.
+func (o *Ordenador) AplicarPatch(doc, patch string) (string, error) {
+	datos, claves, err := decodificarUbicado(doc)
+	if err != nil {
+		return "", err
+	}
+	var operaciones []operacionPatch
+	if err := json.Unmarshal([]byte(patch), &operaciones); err != nil {
+		return "", fmt.Errorf("el patch debe ser un arreglo JSON de operaciones: %w", err)
+	}
+
+	var raiz interface{} = datos
+	for i, op := range operaciones {
+		ruta := ""
+		if op.Path != nil {
+			ruta = *op.Path
+		}
+		if raiz, err = aplicarOperacion(raiz, op); err != nil {
+			return "", &ErrorPatch{Indice: i, Op: op.Op, Ruta: ruta, Err: err}
+		}
+		objeto, ok := raiz.(map[string]interface{})
+		if !ok {
+			return "", &ErrorPatch{Indice: i, Op: op.Op, Ruta: ruta, Err: errors.New("el documento debe seguir siendo un objeto")}
+		}
+		claves = sincronizarClaves(claves, objeto)
+	}
+
+	texto, err := codificarObjeto(raiz.(map[string]interface{}), claves)
+	if err != nil {
+		return "", err
+	}
+	return o.OrdenarJSON(texto)
+}
+
+// AplicarMergePatch aplica un JSON Merge Patch con las opciones recibidas.
+// Ver Ordenador.AplicarMergePatch.
+func AplicarMergePatch(doc, patch string, opts ...Option) (string, error) {
+	return Nuevo(opts...).AplicarMergePatch(doc, patch)
+}
+
+// AplicarMergePatch aplica a doc el objeto patch con la semántica de JSON
+// Merge Patch (RFC 7386): cada clave de patch reemplaza a la de doc, una
+// clave con valor null la elimina y los objetos se combinan recursivamente;
+// los arreglos se reemplazan completos. El resultado se devuelve ordenado con
+// OrdenarJSON, por lo que también se valida. Las claves de primer nivel
+// fuera del perfil conservan su orden, y las que se agregan van después de
+// las existentes, en el orden de patch. Si doc o patch no son objetos JSON
+// válidos se devuelve un *ErrorJSONInvalido.
+func (o *Ordenador) AplicarMergePatch(doc, patch string) (string, error) {
+	datos, claves, err := decodificarUbicado(doc)
+	if err != nil {
+		return "", err
+	}
+	cambios, clavesPatch, err := decodificarUbicado(patch)
+	if err != nil {
+		return "", fmt.Errorf("patch: %w", err)
+	}
+	for _, clave := range clavesPatch {
+		valor := cambios[clave]
+		if valor == nil {
+			delete(datos, clave)
+			continue
+		}
+		if _, ok := datos[clave]; !ok {
+			claves = append(claves, clave)
+		}
+		datos[clave] = fusionarMergePatch(datos[clave], valor)
+	}
+
+	texto, err := codificarObjeto(datos, sincronizarClaves(claves, datos))
+	if err != nil {
+		return "", err
+	}
+	return o.OrdenarJSON(texto)
+}
+
+// fusionarMergePatch aplica patch sobre destino como indica el RFC 7386.
+func fusionarMergePatch(destino, patch interface{}) interface{} {
+	cambios, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	objeto, ok := destino.(map[string]interface{})
+	if !ok {
+		objeto = make(map[string]interface{}, len(cambios))
+	}
+	for clave, valor := range cambios {
+		if valor == nil {
+			delete(objeto, clave)
+			continue
+		}
+		objeto[clave] = fusionarMergePatch(objeto[clave], valor)
+	}
+	return objeto
+}
+
+// sincronizarClaves quita de claves las que ya no están en objeto y agrega al
+// final las nuevas. Cada operación agrega a lo sumo una clave de primer
+// nivel, salvo las que reemplazan la raíz, cuyas claves nuevas se agregan en
+// orden alfabético.
+func sincronizarClaves(claves []string, objeto map[string]interface{}) []string {
+	vigentes := claves[:0]
+	presentes := make(map[string]bool, len(claves))
+	for _, clave := range claves {
+		if _, ok := objeto[clave]; ok {
+			vigentes = append(vigentes, clave)
+			presentes[clave] = true
+		}
+	}
+	var nuevas []string
+	for clave := range objeto {
+		if !presentes[clave] {
+			nuevas = append(nuevas, clave)
+		}
+	}
+	sort.Strings(nuevas)
+	return append(vigentes, nuevas...)
+}
+
+// aplicarOperacion aplica una operación a raiz, que puede modificar, y
+// devuelve la raíz resultante.
+func aplicarOperacion(raiz interface{}, op operacionPatch) (interface{}, error) {
+	if op.Path == nil {
+		return nil, errors.New(`falta "path"`)
+	}
+	ruta, err := separarPuntero(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	valor := func() (interface{}, error) {
+		if op.Value == nil {
+			return nil, errors.New(`falta "value"`)
+		}
+		var v interface{}
+		if err := json.Unmarshal(op.Value, &v); err != nil {
+			return nil, fmt.Errorf(`"value" inválido: %w`, err)
+		}
+		return v, nil
+	}
+	origen := func() ([]string, error) {
+		if op.From == nil {
+			return nil, errors.New(`falta "from"`)
+		}
+		return separarPuntero(*op.From)
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := valor()
+		if err != nil {
+			return nil, err
+		}
+		return agregarEnPuntero(raiz, ruta, v)
+	case "remove":
+		raiz, _, err := quitarEnPuntero(raiz, ruta)
+		return raiz, err
+	case "replace":
+		v, err := valor()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := buscarEnPuntero(raiz, ruta); err != nil {
+			return nil, err
+		}
+		return reemplazarEnPuntero(raiz, ruta, v)
+	case "move":
+		desde, err := origen()
+		if err != nil {
+			return nil, err
+		}
+		if len(ruta) > len(desde) && esPrefijo(desde, ruta) {
+			return nil, errors.New("no se puede mover un valor dentro de sí mismo")
+		}
+		raiz, movido, err := quitarEnPuntero(raiz, desde)
+		if err != nil {
+			return nil, err
+		}
+		return agregarEnPuntero(raiz, ruta, movido)
+	case "copy":
+		desde, err := origen()
+		if err != nil {
+			return nil, err
+		}
+		copiado, err := buscarEnPuntero(raiz, desde)
+		if err != nil {
+			return nil, err
+		}
+		return agregarEnPuntero(raiz, ruta, copiaProfunda(copiado))
+	case "test":
+		v, err := valor()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := buscarEnPuntero(raiz, ruta)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, v) {
+			return nil, fmt.Errorf("el valor actual %s no es el esperado", valorDiff(actual))
+		}
+		return raiz, nil
+	default:
+		return nil, fmt.Errorf("operación desconocida %q", op.Op)
+	}
+}
+
+// separarPuntero divide un JSON Pointer en sus segmentos sin escapes. La
+// cadena vacía apunta a la raíz.
+func separarPuntero(puntero string) ([]string, error) {
+	if puntero == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(puntero, "/") {
+		return nil, fmt.Errorf("la ruta %q debe comenzar con /", puntero)
+	}
+	segmentos := strings.Split(puntero[1:], "/")
+	for i, s := range segmentos {
+		s = strings.ReplaceAll(s, "~1", "/")
+		segmentos[i] = strings.ReplaceAll(s, "~0", "~")
+	}
+	return segmentos, nil
+}
+
+// esPrefijo indica si los segmentos de prefijo son el comienzo de ruta.
+func esPrefijo(prefijo, ruta []string) bool {
+	for i := range prefijo {
+		if prefijo[i] != ruta[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// indiceArreglo interpreta un segmento como posición de un arreglo de largo
+// n. Con alFinal, el segmento "-" y la posición n, una después de la última,
+// también son válidos.
+func indiceArreglo(segmento string, n int, alFinal bool) (int, error) {
+	if alFinal && segmento == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(segmento)
+	if err != nil || segmento[0] < '0' || segmento[0] > '9' || (len(segmento) > 1 && segmento[0] == '0') {
+		return 0, fmt.Errorf("%q no es una posición de arreglo", segmento)
+	}
+	if i > n || (i == n && !alFinal) {
+		return 0, fmt.Errorf("la posición %d está fuera del arreglo de %d elementos", i, n)
+	}
+	return i, nil
+}
+
+// buscarEnPuntero devuelve el valor al que apunta ruta dentro de v.
+func buscarEnPuntero(v interface{}, ruta []string) (interface{}, error) {
+	for _, segmento := range ruta {
+		switch contenedor := v.(type) {
+		case map[string]interface{}:
+			hijo, ok := contenedor[segmento]
+			if !ok {
+				return nil, fmt.Errorf("no existe la clave %q", segmento)
+			}
+			v = hijo
+		case []interface{}:
+			i, err := indiceArreglo(segmento, len(contenedor), false)
+			if err != nil {
+				return nil, err
+			}
+			v = contenedor[i]
+		default:
+			return nil, fmt.Errorf("no se puede entrar en un valor %s con %q", valorDiff(v), segmento)
+		}
+	}
+	return v, nil
+}
+
+// enPuntero recorre v hasta el contenedor del último segmento de ruta, que
+// no debe estar vacía, le aplica cambiar y devuelve v con el contenedor
+// resultante en su lugar.
+func enPuntero(v interface{}, ruta []string, cambiar func(contenedor interface{}, segmento string) (interface{}, error)) (interface{}, error) {
+	if len(ruta) == 1 {
+		return cambiar(v, ruta[0])
+	}
+	hijo, err := buscarEnPuntero(v, ruta[:1])
+	if err != nil {
+		return nil, err
+	}
+	nuevo, err := enPuntero(hijo, ruta[1:], cambiar)
+	if err != nil {
+		return nil, err
+	}
+	switch contenedor := v.(type) {
+	case map[string]interface{}:
+		contenedor[ruta[0]] = nuevo
+	case []interface{}:
+		i, _ := indiceArreglo(ruta[0], len(contenedor), false)
+		contenedor[i] = nuevo
+	}
+	return v, nil
+}
+
+// agregarEnPuntero implementa add: asigna la clave de un objeto o inserta en
+// un arreglo. Con la ruta vacía, nuevo reemplaza a v.
+func agregarEnPuntero(v interface{}, ruta []string, nuevo interface{}) (interface{}, error) {
+	if len(ruta) == 0 {
+		return nuevo, nil
+	}
+	return enPuntero(v, ruta, func(contenedor interface{}, segmento string) (interface{}, error) {
+		switch c := contenedor.(type) {
+		case map[string]interface{}:
+			c[segmento] = nuevo
+			return c, nil
+		case []interface{}:
+			i, err := indiceArreglo(segmento, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = nuevo
+			return c, nil
+		default:
+			return nil, fmt.Errorf("no se puede agregar %q a un valor %s", segmento, valorDiff(contenedor))
+		}
+	})
+}
+
+// reemplazarEnPuntero implementa replace sobre un valor que existe.
+func reemplazarEnPuntero(v interface{}, ruta []string, nuevo interface{}) (interface{}, error) {
+	if len(ruta) == 0 {
+		return nuevo, nil
+	}
+	return enPuntero(v, ruta, func(contenedor interface{}, segmento string) (interface{}, error) {
+		switch c := contenedor.(type) {
+		case map[string]interface{}:
+			c[segmento] = nuevo
+		case []interface{}:
+			i, _ := indiceArreglo(segmento, len(c), false)
+			c[i] = nuevo
+		}
+		return contenedor, nil
+	})
+}
+
+// quitarEnPuntero implementa remove y devuelve además el valor quitado.
+func quitarEnPuntero(v interface{}, ruta []string) (interface{}, interface{}, error) {
+	if len(ruta) == 0 {
+		return nil, nil, errors.New("no se puede quitar la raíz del documento")
+	}
+	var quitado interface{}
+	v, err := enPuntero(v, ruta, func(contenedor interface{}, segmento string) (interface{}, error) {
+		switch c := contenedor.(type) {
+		case map[string]interface{}:
+			hijo, ok := c[segmento]
+			if !ok {
+				return nil, fmt.Errorf("no existe la clave %q", segmento)
+			}
+			quitado = hijo
+			delete(c, segmento)
+			return c, nil
+		case []interface{}:
+			i, err := indiceArreglo(segmento, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			quitado = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("no se puede quitar %q de un valor %s", segmento, valorDiff(contenedor))
+		}
+	})
+	return v, quitado, err
+}
+
+// copiaProfunda copia los objetos y arreglos de v, para modificarlos sin
+// afectar al original.
+func copiaProfunda(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		copia := make(map[string]interface{}, len(c))
+		for clave, hijo := range c {
+			copia[clave] = copiaProfunda(hijo)
+		}
+		return copia
+	case []interface{}:
+		copia := make([]interface{}, len(c))
+		for i, hijo := range c {
+			copia[i] = copiaProfunda(hijo)
+		}
+		return copia
+	default:
+		return v
+	}
+}
@@ -0,0 +1,125 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Perfil describe un orden de campos junto con las estructuras precalculadas
+// necesarias para aplicarlo. Se construye una sola vez con NuevoPerfil y puede
+// compartirse entre goroutines, ya que no se modifica después de creado.
+type Perfil struct {
+	nombre      string
+	campos      []string
+	indice      map[string]int // Posición de cada campo en campos.
+	codificadas [][]byte       // Forma JSON de cada campo, incluidos los dos puntos.
+
+	// Reglas de validación propias del perfil, por ejemplo las derivadas de un esquema.
+	requeridos []string
+	estricto   bool
+	reglas     map[string]*reglaCampo
+
+	// tamanoSalida es la media móvil del tamaño de las salidas producidas con
+	// el perfil; se usa para reservar los buffers de la siguiente llamada.
+	tamanoSalida atomic.Int64
+}
+
+// PerfilPorDefecto es el perfil construido a partir de OrdenCampos. Es el que
+// utilizan las funciones de ordenamiento cuando no se indica WithPerfil.
+var PerfilPorDefecto *Perfil
+
+// NuevoPerfil crea un perfil con el orden de campos recibido. El índice en el
+// slice representa la prioridad (menor índice = mayor prioridad); si un campo
+// aparece repetido se conserva su primera posición.
+// La forma codificada de cada campo se calcula aquí, de modo que ordenar un
+// documento no vuelve a codificar las claves conocidas.
+func NuevoPerfil(nombre string, campos []string) *Perfil {
+	p := &Perfil{
+		nombre:      nombre,
+		campos:      make([]string, 0, len(campos)),
+		indice:      make(map[string]int, len(campos)),
+		codificadas: make([][]byte, 0, len(campos)),
+	}
+	for _, campo := range campos {
+		if _, repetido := p.indice[campo]; repetido {
+			continue
+		}
+		// json.Marshal no falla al codificar un string.
+		codificada, _ := json.Marshal(campo)
+		p.indice[campo] = len(p.campos)
+		p.campos = append(p.campos, campo)
+		p.codificadas = append(p.codificadas, append(codificada, ':'))
+	}
+	return p
+}
+
+// conPrioritarios devuelve un perfil con el mismo nombre y las mismas reglas
+// que p, cuyo orden antepone prioritarios a los campos de p.
+func (p *Perfil) conPrioritarios(prioritarios []string) *Perfil {
+	campos := make([]string, 0, len(prioritarios)+len(p.campos))
+	derivado := NuevoPerfil(p.nombre, append(append(campos, prioritarios...), p.campos...))
+	derivado.requeridos, derivado.estricto, derivado.reglas = p.requeridos, p.estricto, p.reglas
+	return derivado
+}
+
+// Nombre devuelve el nombre con el que se creó el perfil.
+func (p *Perfil) Nombre() string {
+	return p.nombre
+}
+
+// Campos devuelve una copia del orden de campos del perfil.
+func (p *Perfil) Campos() []string {
+	return append([]string(nil), p.campos...)
+}
+
+// posicion devuelve la posición de un campo en el perfil.
+// Si el campo no está en la lista, retorna la longitud de la lista, ubicándolo al final.
+func (p *Perfil) posicion(campo string) int {
+	if orden, ok := p.indice[campo]; ok {
+		return orden
+	}
+	return len(p.campos)
+}
+
+// claveCodificada devuelve la forma JSON de la clave lista para escribir.
+// Las claves del perfil salen de la tabla precalculada; el resto se obtiene
+// de la tabla de claves internadas.
+func (p *Perfil) claveCodificada(clave string) ([]byte, error) {
+	if orden, ok := p.indice[clave]; ok {
+		return p.codificadas[orden], nil
+	}
+	entrada, err := internarClave(clave)
+	if err != nil {
+		return nil, err
+	}
+	return entrada.codificada, nil
+}
+
+// tamanoEstimado devuelve el tamaño de salida esperado para el perfil, con un
+// margen para que un documento algo mayor que el promedio no obligue a crecer el buffer.
+func (p *Perfil) tamanoEstimado() int {
+	estimado := int(p.tamanoSalida.Load())
+	return estimado + estimado/4
+}
+
+// registrarTamano incorpora el tamaño de una salida a la media móvil del perfil.
+// Las actualizaciones concurrentes pueden pisarse entre sí; como se trata de una
+// estimación, perder alguna muestra no tiene consecuencias.
+func (p *Perfil) registrarTamano(n int) {
+	anterior := p.tamanoSalida.Load()
+	if anterior == 0 {
+		p.tamanoSalida.Store(int64(n))
+		return
+	}
+	p.tamanoSalida.Store((anterior*7 + int64(n)) / 8)
+}
+
+// WithPerfil indica el perfil cuyo orden de campos se aplica. Por defecto se
+// utiliza PerfilPorDefecto.
+func WithPerfil(p *Perfil) Option {
+	return func(cfg *configuracion) {
+		if p != nil {
+			cfg.perfil = p
+		}
+	}
+}
@@ -0,0 +1,550 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// planStruct describe cómo leer los campos de un tipo struct sin recorrerlo
+// con reflexión en cada llamada; solo los campos que no son string se leen
+// con reflexión. Se construye una vez por tipo con planDe.
+type planStruct struct {
+	// campos tiene los campos con etiqueta json, ordenados por nombre:
+	// es el orden alfabético del que parte ordenar con un mapa, por lo que la
+	// salida no cambia respecto de convertir el struct en mapa.
+	campos []campoPlan
+
+	// prioritarios tiene las claves de los campos con etiqueta orden, de
+	// menor a mayor valor y, con el mismo valor, en el orden de declaración.
+	prioritarios []string
+	// err es el error de una etiqueta orden mal formada; ver OrdenarStruct.
+	err error
+	// perfiles asocia cada *Perfil con el perfil derivado que antepone los
+	// prioritarios a sus campos, para construirlo una sola vez.
+	perfiles sync.Map
+}
+
+// campoPlan ubica un campo dentro del struct.
+type campoPlan struct {
+	nombre    string       // Clave JSON, tomada de la etiqueta json.
+	offset    uintptr      // Desplazamiento del campo desde el inicio del struct que lo contiene.
+	tipo      reflect.Type // Tipo del campo; nil si es string.
+	omitempty bool         // Si la etiqueta json tiene la opción omitempty.
+	// via tiene, si el campo es de un struct embebido por puntero, el
+	// desplazamiento de cada puntero que hay que seguir desde el inicio del
+	// struct para llegar al que contiene el campo.
+	via []uintptr
+}
+
+// direccion devuelve la dirección del campo en el struct que comienza en
+// base, o nil si está en un struct embebido por un puntero nil.
+func (c *campoPlan) direccion(base unsafe.Pointer) unsafe.Pointer {
+	for _, offset := range c.via {
+		if base = *(*unsafe.Pointer)(unsafe.Add(base, offset)); base == nil {
+			return nil
+		}
+	}
+	return unsafe.Add(base, c.offset)
+}
+
+// candidatoPlan es un campo encontrado al recorrer un struct y sus structs
+// embebidos, antes de descartar los nombres repetidos.
+type candidatoPlan struct {
+	campoPlan
+	profundidad  int // Cantidad de structs embebidos que lo contienen.
+	prioridad    int // Valor de la etiqueta orden.
+	conPrioridad bool
+}
+
+// planes asocia cada tipo struct con su *planStruct.
+var planes sync.Map
+
+// tipoDocumentMetadata es el tipo cuyo plan usa OrdenarDocumentoMetadata.
+var tipoDocumentMetadata = reflect.TypeOf(DocumentMetadata{})
+
+// tipoTime es el tipo de los campos time.Time, que se escriben como fecha.
+var tipoTime = reflect.TypeOf(time.Time{})
+
+// tipoMarshaler y tipoStringer son las interfaces con que un tipo decide su
+// propia representación; ver valorDeCampo.
+var (
+	tipoMarshaler = reflect.TypeFor[json.Marshaler]()
+	tipoStringer  = reflect.TypeFor[fmt.Stringer]()
+)
+
+// planDe devuelve el plan de t, que debe ser un tipo struct, construyéndolo
+// en el primer uso. Los campos sin etiqueta json, con etiqueta json "-" o no
+// exportados se ignoran, como en encoding/json, igual que los de un tipo que
+// no admite valorDeCampo. Los campos de los structs embebidos sin nombre en
+// la etiqueta json se suben al struct que los embebe, también como en
+// encoding/json: si dos campos tienen el mismo nombre gana el menos
+// anidado, y si están en el mismo nivel se omiten los dos.
+func planDe(t reflect.Type) *planStruct {
+	if plan, ok := planes.Load(t); ok {
+		return plan.(*planStruct)
+	}
+	plan := &planStruct{}
+	var candidatos []candidatoPlan
+	plan.err = recorrerCampos(t, 0, nil, 0, map[reflect.Type]bool{t: true}, &candidatos)
+
+	// El menos anidado de cada nombre, si es único en su nivel.
+	elegidos := make(map[string]int, len(candidatos))
+	for i, candidato := range candidatos {
+		anterior, ok := elegidos[candidato.nombre]
+		switch {
+		case !ok || candidato.profundidad < candidatos[anterior].profundidad:
+			elegidos[candidato.nombre] = i
+		case candidato.profundidad == candidatos[anterior].profundidad:
+			elegidos[candidato.nombre] = -1
+		}
+	}
+	var prioridades []int
+	for i, candidato := range candidatos {
+		if elegidos[candidato.nombre] != i {
+			continue
+		}
+		plan.campos = append(plan.campos, candidato.campoPlan)
+		if candidato.conPrioridad {
+			plan.prioritarios = append(plan.prioritarios, candidato.nombre)
+			prioridades = append(prioridades, candidato.prioridad)
+		}
+	}
+	sort.SliceStable(plan.campos, func(i, j int) bool { return plan.campos[i].nombre < plan.campos[j].nombre })
+	sort.Stable(prioritarios{plan.prioritarios, prioridades})
+	existente, _ := planes.LoadOrStore(t, plan)
+	return existente.(*planStruct)
+}
+
+// recorrerCampos agrega a candidatos los campos de t, que comienza offset
+// bytes después del struct al que se llega con via, y los de sus structs
+// embebidos, en el orden de declaración. visitados tiene los tipos del
+// camino actual, para no recorrer de nuevo un struct que se embebe a sí
+// mismo. Devuelve el error de la primera etiqueta orden mal formada.
+func recorrerCampos(t reflect.Type, offset uintptr, via []uintptr, profundidad int, visitados map[reflect.Type]bool, candidatos *[]candidatoPlan) error {
+	var primerError error
+	for i := 0; i < t.NumField(); i++ {
+		campo := t.Field(i)
+		nombre, opciones, _ := strings.Cut(campo.Tag.Get("json"), ",")
+		if nombre == "-" {
+			continue
+		}
+		if campo.Anonymous && nombre == "" {
+			embebido, porPuntero := campo.Type, campo.Type.Kind() == reflect.Pointer
+			if porPuntero {
+				embebido = embebido.Elem()
+			}
+			// Como encoding/json, se ignoran los punteros a structs no
+			// exportados, que no se pueden asignar desde afuera del paquete.
+			if embebido.Kind() != reflect.Struct || (porPuntero && !campo.IsExported()) || visitados[embebido] {
+				continue
+			}
+			interno, viaInterna := offset+campo.Offset, via
+			if porPuntero {
+				interno, viaInterna = 0, append(slices.Clip(via), offset+campo.Offset)
+			}
+			visitados[embebido] = true
+			if err := recorrerCampos(embebido, interno, viaInterna, profundidad+1, visitados, candidatos); err != nil && primerError == nil {
+				primerError = err
+			}
+			delete(visitados, embebido)
+			continue
+		}
+		if nombre == "" || !campo.IsExported() || !tipoLegible(campo.Type, false) {
+			continue
+		}
+		candidato := candidatoPlan{
+			campoPlan: campoPlan{
+				nombre:    nombre,
+				offset:    offset + campo.Offset,
+				omitempty: slices.Contains(strings.Split(opciones, ","), "omitempty"),
+				via:       via,
+			},
+			profundidad: profundidad,
+		}
+		if campo.Type.Kind() != reflect.String || representacionPropia(campo.Type) {
+			candidato.tipo = campo.Type
+		}
+		if etiqueta, ok := campo.Tag.Lookup("orden"); ok {
+			prioridad, err := strconv.Atoi(strings.TrimSpace(etiqueta))
+			if err != nil {
+				if primerError == nil {
+					primerError = fmt.Errorf("campo %s de %s: etiqueta orden %q no es un entero", campo.Name, t, etiqueta)
+				}
+			} else {
+				candidato.prioridad, candidato.conPrioridad = prioridad, true
+			}
+		}
+		*candidatos = append(*candidatos, candidato)
+	}
+	return primerError
+}
+
+// lectura guarda el estado con que se leen los campos de un struct en una
+// llamada.
+type lectura struct {
+	cfg *configuracion
+	// camino tiene los punteros que se están leyendo, desde el struct de
+	// entrada hasta el valor actual, para detectar los ciclos.
+	camino map[visita]struct{}
+}
+
+// visita identifica el valor al que apunta un puntero. Se guarda también el
+// tipo porque un struct y su primer campo comparten la dirección.
+type visita struct {
+	direccion unsafe.Pointer
+	tipo      reflect.Type
+}
+
+// errCiclo es el error de un valor que se contiene a sí mismo a través de un
+// puntero.
+var errCiclo = errors.New("el valor se contiene a sí mismo")
+
+// leer agrega a datos los campos del struct que comienza en base, y sus
+// claves a claves en el orden del plan. Los campos vacíos se tratan según
+// WithCamposVacios: un campo está vacío si es una cadena para la que
+// cfg.esVacio devuelve true o el valor cero de su tipo; ver valorDeCampo.
+// Devuelve también cuántos campos no estaban vacíos. Si un campo no se puede
+// leer devuelve un *ErrorValorNoSerializable con la ruta del campo, con los
+// nombres separados por puntos.
+func (p *planStruct) leer(base unsafe.Pointer, datos map[string]interface{}, claves []string, l *lectura) ([]string, int, error) {
+	presentes := 0
+	for _, campo := range p.campos {
+		var valor interface{}
+		var presente bool
+		switch direccion := campo.direccion(base); {
+		case direccion == nil:
+			// El campo está en un struct embebido por un puntero nil, por lo
+			// que no está informado.
+		case campo.tipo == nil:
+			texto := *(*string)(direccion)
+			valor, presente = texto, !l.cfg.esVacio(texto)
+		default:
+			var err error
+			valor, presente, err = l.valorDeCampo(reflect.NewAt(campo.tipo, direccion).Elem())
+			if err != nil {
+				var errValor *ErrorValorNoSerializable
+				if errors.As(err, &errValor) {
+					errValor.Campo = campo.nombre + "." + errValor.Campo
+					return nil, 0, errValor
+				}
+				return nil, 0, &ErrorValorNoSerializable{Campo: campo.nombre, Err: err}
+			}
+		}
+		if presente {
+			presentes++
+		} else {
+			switch l.cfg.camposVacios {
+			case CamposVaciosConservar:
+			case CamposVaciosNull:
+				valor = nil
+			case CamposVaciosOmitempty:
+				if campo.omitempty {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		datos[campo.nombre] = valor
+		claves = append(claves, campo.nombre)
+	}
+	return claves, presentes, nil
+}
+
+// tipoLegible indica si valorDeCampo admite los valores de tipo t. Los mapas
+// y las interfaces solo se admiten con conMapas, porque los campos de esos
+// tipos se ignoran en los structs. Los tipos recursivos, como un mapa de sí
+// mismo, se admiten si lo son sus demás partes.
+func tipoLegible(t reflect.Type, conMapas bool) bool {
+	vistos := make(map[reflect.Type]bool)
+	for !vistos[t] {
+		vistos[t] = true
+		if representacionPropia(t) {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.String, reflect.Bool, reflect.Struct,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		case reflect.Interface:
+			return conMapas
+		case reflect.Map:
+			if !conMapas || !claveLegible(t.Key()) {
+				return false
+			}
+			t = t.Elem()
+		case reflect.Slice, reflect.Array, reflect.Pointer:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// claveLegible indica si las claves de tipo t se pueden escribir como claves
+// de un objeto JSON: como en encoding/json, las de clase cadena o entero.
+func claveLegible(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// representacionPropia indica si los valores de t, o los punteros a ellos,
+// implementan json.Marshaler o fmt.Stringer. time.Time no cuenta, porque se
+// escribe con el formato de WithFormatoSalidaFecha.
+func representacionPropia(t reflect.Type) bool {
+	if t == tipoTime || t.Kind() == reflect.Pointer {
+		return false
+	}
+	puntero := reflect.PointerTo(t)
+	return puntero.Implements(tipoMarshaler) || puntero.Implements(tipoStringer)
+}
+
+// valorDeCampo convierte v, direccionable y de un tipo que admite
+// tipoLegible, en el valor que se escribe. Los tipos con representación
+// propia la eligen ellos mismos: los que implementan json.Marshaler, como el
+// JSON de MarshalJSON, y los que implementan fmt.Stringer, como el texto de
+// String; el valor se omite si es el valor cero de su tipo. El resto se
+// escribe según su clase: los números, booleanos y cadenas como el tipo
+// básico de su clase, los time.Time como texto con el formato
+// de WithFormatoSalidaFecha, los slices y arreglos como []interface{} (los []byte,
+// como en encoding/json, en base64; un slice nil, como null), los mapas como
+// map[string]interface{} (las claves enteras, en decimal), los structs como
+// un mapa con sus campos leídos con su plan y los punteros e interfaces como
+// el valor al que apuntan. Indica además si el valor está presente: no lo
+// está el valor cero de su tipo (false, 0, una fecha cero, un slice o mapa
+// vacío, un struct sin campos presentes, un puntero o interfaz nil), ni una
+// cadena para la que
+// cfg.esVacio devuelve true. Un puntero no nil siempre está presente, aunque
+// apunte a un valor vacío, para distinguir un dato informado vacío de uno no
+// informado. Los elementos de un slice se conservan aunque sean el valor
+// cero, igual que las entradas de un mapa. Si el valor se contiene a sí
+// mismo a través de un puntero, un mapa o un slice, o si
+// MarshalJSON o String fallan o entran en pánico, devuelve un error.
+func (l *lectura) valorDeCampo(v reflect.Value) (interface{}, bool, error) {
+	if representacionPropia(v.Type()) {
+		return l.valorPropio(v)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), !l.cfg.esVacio(v.String()), nil
+	case reflect.Bool:
+		return v.Bool(), v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), v.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), v.Uint() != 0, nil
+	case reflect.Float32:
+		// float32 conserva el formato corto que le da encoding/json.
+		return float32(v.Float()), v.Float() != 0, nil
+	case reflect.Float64:
+		return v.Float(), v.Float() != 0, nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		if err := l.entrar(v); err != nil {
+			return nil, false, err
+		}
+		defer l.salir(v)
+		valor, _, err := l.valorDeCampo(v.Elem())
+		return valor, err == nil, err
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		if !tipoLegible(v.Elem().Type(), true) {
+			return nil, false, fmt.Errorf("tipo %s no admitido", v.Elem().Type())
+		}
+		// El valor de una interfaz no es direccionable; se lee una copia.
+		copia := reflect.New(v.Elem().Type()).Elem()
+		copia.Set(v.Elem())
+		return l.valorDeCampo(copia)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		if err := l.entrar(v); err != nil {
+			return nil, false, err
+		}
+		defer l.salir(v)
+		datos, err := l.mapaDe(v)
+		return datos, len(datos) > 0, err
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, false, nil
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), v.Bytes()...), v.Len() > 0, nil
+		}
+		// Un slice solo se puede contener a sí mismo a través de interfaces.
+		if v.Kind() == reflect.Slice && v.Len() > 0 && v.Type().Elem().Kind() == reflect.Interface {
+			if err := l.entrar(v); err != nil {
+				return nil, false, err
+			}
+			defer l.salir(v)
+		}
+		elementos := make([]interface{}, v.Len())
+		for i := range elementos {
+			var err error
+			if elementos[i], _, err = l.valorDeCampo(v.Index(i)); err != nil {
+				return nil, false, err
+			}
+		}
+		if v.Kind() == reflect.Array {
+			return elementos, !v.IsZero(), nil
+		}
+		return elementos, len(elementos) > 0, nil
+	case reflect.Struct:
+		if v.Type() == tipoTime {
+			fecha := v.Interface().(time.Time)
+			return fecha.Format(l.cfg.formatoFecha()), !fecha.IsZero(), nil
+		}
+		datos := make(map[string]interface{})
+		_, presentes, err := planDe(v.Type()).leer(v.Addr().UnsafePointer(), datos, nil, l)
+		return datos, presentes > 0, err
+	}
+	return nil, false, nil
+}
+
+// entrar agrega a l.camino el valor al que apunta v, un puntero, mapa o
+// slice no nil, o devuelve errCiclo si ya se está leyendo.
+func (l *lectura) entrar(v reflect.Value) error {
+	clave := visita{direccion: v.UnsafePointer(), tipo: v.Type()}
+	if _, repetida := l.camino[clave]; repetida {
+		return errCiclo
+	}
+	if l.camino == nil {
+		l.camino = make(map[visita]struct{})
+	}
+	l.camino[clave] = struct{}{}
+	return nil
+}
+
+// salir quita de l.camino el valor que agregó entrar.
+func (l *lectura) salir(v reflect.Value) {
+	delete(l.camino, visita{direccion: v.UnsafePointer(), tipo: v.Type()})
+}
+
+// mapaDe convierte v, un mapa de un tipo que admite tipoLegible, en
+// un map[string]interface{} con sus entradas leídas con valorDeCampo.
+func (l *lectura) mapaDe(v reflect.Value) (map[string]interface{}, error) {
+	datos := make(map[string]interface{}, v.Len())
+	// Cada valor se copia en uno direccionable, que es lo que espera
+	// valorDeCampo.
+	valor := reflect.New(v.Type().Elem()).Elem()
+	iter := v.MapRange()
+	for iter.Next() {
+		clave := claveDeMapa(iter.Key())
+		valor.SetIterValue(iter)
+		leido, _, err := l.valorDeCampo(valor)
+		if err != nil {
+			var errValor *ErrorValorNoSerializable
+			if errors.As(err, &errValor) {
+				errValor.Campo = clave + "." + errValor.Campo
+				return nil, errValor
+			}
+			return nil, &ErrorValorNoSerializable{Campo: clave, Err: err}
+		}
+		datos[clave] = leido
+	}
+	return datos, nil
+}
+
+// claveDeMapa escribe la clave k, de un tipo que admite claveLegible, como
+// la escribe encoding/json.
+func claveDeMapa(k reflect.Value) string {
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	return k.String()
+}
+
+// valorPropio convierte v, direccionable y con representación propia, en el
+// valor que se escribe; ver valorDeCampo. El JSON de MarshalJSON se
+// decodifica igual que un documento de entrada, para que las opciones lo
+// traten como a cualquier otro valor.
+func (l *lectura) valorPropio(v reflect.Value) (interface{}, bool, error) {
+	presente := !v.IsZero()
+	switch propio := v.Addr().Interface().(type) {
+	case json.Marshaler:
+		// codificarJSON valida el resultado y devuelve los pánicos como error.
+		codificado, err := codificarJSON(propio)
+		if err != nil {
+			return nil, false, err
+		}
+		var valor interface{}
+		if err := json.Unmarshal(codificado, &valor); err != nil {
+			return nil, false, err
+		}
+		if texto, ok := valor.(string); ok && l.cfg.esVacio(texto) {
+			presente = false
+		}
+		return valor, presente, nil
+	case fmt.Stringer:
+		texto, err := textoDe(propio)
+		if err != nil {
+			return nil, false, err
+		}
+		return texto, presente && !l.cfg.esVacio(texto), nil
+	}
+	return nil, false, nil
+}
+
+// textoDe devuelve el resultado de s.String(). Si entra en pánico, se
+// devuelve como error.
+func textoDe(s fmt.Stringer) (texto string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pánico en el método String de %T: %v", s, r)
+		}
+	}()
+	return s.String(), nil
+}
+
+// perfil devuelve el perfil con que se ordena el struct: base si ningún
+// campo tiene etiqueta orden o, si no, uno con los mismos campos y reglas
+// precedidos por los prioritarios.
+func (p *planStruct) perfil(base *Perfil) *Perfil {
+	if len(p.prioritarios) == 0 {
+		return base
+	}
+	if derivado, ok := p.perfiles.Load(base); ok {
+		return derivado.(*Perfil)
+	}
+	derivado, _ := p.perfiles.LoadOrStore(base, base.conPrioritarios(p.prioritarios))
+	return derivado.(*Perfil)
+}
+
+// prioritarios ordena las claves de los campos con etiqueta orden por el
+// valor de la etiqueta.
+type prioritarios struct {
+	claves      []string
+	prioridades []int
+}
+
+func (p prioritarios) Len() int           { return len(p.claves) }
+func (p prioritarios) Less(i, j int) bool { return p.prioridades[i] < p.prioridades[j] }
+func (p prioritarios) Swap(i, j int) {
+	p.claves[i], p.claves[j] = p.claves[j], p.claves[i]
+	p.prioridades[i], p.prioridades[j] = p.prioridades[j], p.prioridades[i]
+}
@@ -0,0 +1,171 @@
+package ordenJson
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// maxClavesPlanas es la cantidad máxima de claves de un documento que se
+// ordena por la ruta plana. Los documentos con más claves usan la ruta
+// general, porque detectar claves repetidas en la ruta plana es cuadrático.
+const maxClavesPlanas = 64
+
+// tramoPlano ubica un miembro de un documento plano dentro del texto original.
+type tramoPlano struct {
+	clave    string // Contenido de la clave, sin comillas.
+	valor    string // Valor con sus comillas, tal como se escribe en la salida.
+	posicion int    // Posición de la clave en el perfil; ver Perfil.posicion.
+}
+
+// admiteRutaPlana indica si la configuración permite ordenar por la ruta
+// plana: ninguna opción necesita el documento decodificado.
+func (cfg *configuracion) admiteRutaPlana() bool {
+	p := cfg.perfil
+	return !cfg.normalizarFechas && !cfg.tiposDocumento && cfg.vacio == nil && !cfg.reporte && cfg.observar == nil && cfg.explicacion == nil &&
+		len(cfg.renombres) == 0 && len(cfg.transformaciones) == 0 && len(cfg.mascaras) == 0 &&
+		cfg.solo == nil && cfg.excluir == nil && cfg.defaults == nil &&
+		cfg.presupuesto <= 0 && len(cfg.requeridos) == 0 && !cfg.estricto && len(cfg.valoresPermitidos) == 0 &&
+		len(p.requeridos) == 0 && !p.estricto && len(p.reglas) == 0
+}
+
+// ordenarPlano agrega a dst el documento texto ordenado, si es un objeto
+// plano: todos sus valores son cadenas y ni las claves ni los valores
+// necesitan escapes en la salida. En ese caso no se decodifica el documento:
+// cada miembro se copia del texto original, y el resultado es idéntico al de
+// la ruta general. Si el documento no es plano devuelve false y dst sin
+// cambios, para que se ordene por la ruta general.
+func ordenarPlano(dst []byte, texto string, cfg *configuracion) ([]byte, bool) {
+	var almacen [maxClavesPlanas]tramoPlano
+	tramos, ok := leerPlano(texto, almacen[:0], cfg.perfil)
+	if !ok {
+		return dst, false
+	}
+	cfg.atributoTramo(AtributoClaves, len(tramos))
+
+	// Ordenación estable por inserción: las claves fuera del perfil mantienen
+	// su orden relativo, igual que en la ruta general.
+	for i := 1; i < len(tramos); i++ {
+		for j := i; j > 0 && tramos[j].posicion < tramos[j-1].posicion; j-- {
+			tramos[j], tramos[j-1] = tramos[j-1], tramos[j]
+		}
+	}
+
+	tamanoEsperado := cfg.tamanoEsperado
+	if tamanoEsperado <= 0 {
+		tamanoEsperado = cfg.perfil.tamanoEstimado()
+	}
+	ind := indentadorPorDefecto
+	inicio := len(dst)
+	resultado := append(slices.Grow(dst, tamanoEsperado), '{')
+	for i, tramo := range tramos {
+		if i > 0 {
+			resultado = append(resultado, ',')
+		}
+		resultado = append(resultado, ind.linea(1)...)
+		resultado = append(resultado, '"')
+		resultado = append(resultado, tramo.clave...)
+		resultado = append(resultado, '"', ':', ' ')
+		resultado = append(resultado, tramo.valor...)
+	}
+	if len(tramos) > 0 {
+		resultado = append(resultado, ind.linea(0)...)
+	}
+	resultado = append(resultado, '}')
+	cfg.perfil.registrarTamano(len(resultado) - inicio)
+	return resultado, true
+}
+
+// leerPlano agrega a tramos los miembros del objeto plano texto. Devuelve
+// false si texto no es un objeto plano, si tiene claves repetidas o si tiene
+// más de maxClavesPlanas claves.
+func leerPlano(texto string, tramos []tramoPlano, perfil *Perfil) ([]tramoPlano, bool) {
+	i := saltarEspacios(texto, 0)
+	if i >= len(texto) || texto[i] != '{' {
+		return nil, false
+	}
+	i = saltarEspacios(texto, i+1)
+	if i < len(texto) && texto[i] == '}' {
+		i++
+	} else {
+		for {
+			clave, fin, ok := cadenaPlana(texto, i)
+			if !ok {
+				return nil, false
+			}
+			i = saltarEspacios(texto, fin)
+			if i >= len(texto) || texto[i] != ':' {
+				return nil, false
+			}
+			i = saltarEspacios(texto, i+1)
+			inicioValor := i
+			if _, fin, ok = cadenaPlana(texto, i); !ok {
+				return nil, false
+			}
+			if len(tramos) == maxClavesPlanas {
+				return nil, false
+			}
+			for _, tramo := range tramos {
+				if tramo.clave == clave {
+					return nil, false
+				}
+			}
+			tramos = append(tramos, tramoPlano{clave: clave, valor: texto[inicioValor:fin], posicion: perfil.posicion(clave)})
+
+			i = saltarEspacios(texto, fin)
+			if i < len(texto) && texto[i] == ',' {
+				i = saltarEspacios(texto, i+1)
+				continue
+			}
+			if i < len(texto) && texto[i] == '}' {
+				i++
+				break
+			}
+			return nil, false
+		}
+	}
+	// No se admite contenido después del objeto.
+	if saltarEspacios(texto, i) != len(texto) {
+		return nil, false
+	}
+	return tramos, true
+}
+
+// saltarEspacios devuelve la posición del primer carácter de texto, desde i,
+// que no es un espacio en blanco de JSON.
+func saltarEspacios(texto string, i int) int {
+	for i < len(texto) && (texto[i] == ' ' || texto[i] == '\t' || texto[i] == '\n' || texto[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// cadenaPlana lee la cadena JSON que comienza en texto[i] y devuelve su
+// contenido sin comillas y la posición siguiente a la comilla de cierre.
+// Devuelve false si en i no hay una cadena o si su contenido no se escribe
+// tal cual en la salida: contiene escapes o caracteres que json.Marshal
+// escapa (ver agregarCadena).
+func cadenaPlana(texto string, i int) (string, int, bool) {
+	if i >= len(texto) || texto[i] != '"' {
+		return "", 0, false
+	}
+	inicio := i + 1
+	for i = inicio; i < len(texto); {
+		c := texto[i]
+		if c == '"' {
+			return texto[inicio:i], i + 1, true
+		}
+		if c < utf8.RuneSelf {
+			if !cadenaSegura[c] {
+				return "", 0, false
+			}
+			i++
+			continue
+		}
+		r, tamano := utf8.DecodeRuneInString(texto[i:])
+		if (r == utf8.RuneError && tamano == 1) || r == '\u2028' || r == '\u2029' {
+			return "", 0, false
+		}
+		i += tamano
+	}
+	return "", 0, false
+}
@@ -0,0 +1,107 @@
+package ordenJson
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// perfilesRegistrados asocia cada nombre registrado con RegistrarPerfil con
+// su *Perfil.
+var perfilesRegistrados sync.Map
+
+// RegistrarPerfil registra p con su nombre, para buscarlo con BuscarPerfil y
+// GenerarPlantilla. Si ya había un perfil con ese nombre, lo reemplaza.
+// PerfilPorDefecto está registrado desde el inicio.
+func RegistrarPerfil(p *Perfil) {
+	perfilesRegistrados.Store(p.Nombre(), p)
+}
+
+// BuscarPerfil devuelve el perfil registrado con el nombre recibido.
+func BuscarPerfil(nombre string) (*Perfil, bool) {
+	p, ok := perfilesRegistrados.Load(nombre)
+	if !ok {
+		return nil, false
+	}
+	return p.(*Perfil), true
+}
+
+// GenerarPlantilla devuelve el esqueleto del perfil registrado con el nombre
+// indicado; ver Ordenador.GenerarPlantilla. Si no hay un perfil con ese
+// nombre devuelve un *ErrorPerfilDesconocido.
+func GenerarPlantilla(perfil string) (string, error) {
+	p, ok := BuscarPerfil(perfil)
+	if !ok {
+		return "", &ErrorPerfilDesconocido{Nombre: perfil}
+	}
+	return Nuevo(WithPerfil(p)).GenerarPlantilla()
+}
+
+// GenerarPlantilla devuelve un documento JSON indentado con todas las claves
+// conocidas del perfil en orden canónico, seguidas de las obligatorias de
+// WithRequired que no están en el perfil, para completarlo a mano sin
+// equivocarse en el conjunto de claves. Cada valor es el de WithDefaults, si
+// lo hay, o uno vacío del tipo que exige el esquema del perfil: "" si no
+// indica uno. WithOnly y WithExclude limitan las claves. La plantilla no se
+// valida, ya que sus valores vacíos no suelen cumplir las reglas.
+func (o *Ordenador) GenerarPlantilla() (string, error) {
+	cfg := &o.cfg
+	perfil := cfg.perfil
+	datos := make(map[string]interface{}, len(perfil.campos))
+	claves := make([]string, 0, len(perfil.campos))
+	agregar := func(campo string) {
+		if _, ok := datos[campo]; ok {
+			return
+		}
+		valor, ok := cfg.defaults[campo]
+		if !ok {
+			valor = valorPlantilla(perfil.reglas[campo])
+		}
+		datos[campo] = valor
+		claves = append(claves, campo)
+	}
+	for _, campo := range perfil.campos {
+		agregar(campo)
+	}
+	for _, campo := range perfil.requeridos {
+		agregar(campo)
+	}
+	for _, campo := range cfg.requeridos {
+		agregar(campo)
+	}
+	if cfg.solo != nil || cfg.excluir != nil {
+		claves = cfg.filtrarClaves(claves)
+	}
+
+	compacto, err := codificarObjeto(datos, claves)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(compacto), "", "  "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// valorPlantilla devuelve el valor vacío del primer tipo que admite la
+// regla, o "" si no exige ninguno.
+func valorPlantilla(regla *reglaCampo) interface{} {
+	if regla == nil || len(regla.tipos) == 0 {
+		return ""
+	}
+	switch regla.tipos[0] {
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	case "null":
+		return nil
+	default:
+		return ""
+	}
+}
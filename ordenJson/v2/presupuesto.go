@@ -0,0 +1,101 @@
+package ordenJson
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// tamanoBloqueLectura es la cantidad máxima de bytes que el decodificador recibe
+// por lectura cuando hay un presupuesto de tiempo, para revisar el plazo con frecuencia.
+const tamanoBloqueLectura = 4096
+
+// clavesEntreRevisiones es la cantidad de claves que se serializan entre dos
+// revisiones del plazo.
+const clavesEntreRevisiones = 64
+
+// errPlazoVencido lo devuelve lectorConPlazo cuando se agota el presupuesto o
+// termina el contexto de la llamada.
+var errPlazoVencido = errors.New("plazo vencido")
+
+// WithPresupuesto limita el tiempo que puede tomar ordenar un solo documento.
+// Si se excede, la operación se aborta y devuelve un *ErrorTiempoExcedido.
+// El plazo se revisa durante la decodificación (cada pocos kilobytes de
+// entrada), entre las etapas y durante la serialización, de modo que un
+// documento patológico no puede retener el procesador indefinidamente.
+// Se mide tiempo de reloj; un valor menor o igual a cero desactiva el límite.
+func WithPresupuesto(d time.Duration) Option {
+	return func(cfg *configuracion) {
+		cfg.presupuesto = d
+	}
+}
+
+// plazo controla el presupuesto de tiempo de una llamada de ordenamiento y
+// el contexto recibido en las variantes Ctx. El valor cero representa una
+// llamada sin límite.
+type plazo struct {
+	inicio      time.Time
+	presupuesto time.Duration
+	limite      time.Time
+	ctx         context.Context // nil si la llamada no tiene un contexto que pueda terminar.
+}
+
+// nuevoPlazo inicia la cuenta del presupuesto configurado.
+func nuevoPlazo(cfg *configuracion) plazo {
+	var p plazo
+	if cfg.ctx != nil && cfg.ctx.Done() != nil {
+		p.ctx = cfg.ctx
+	}
+	if cfg.presupuesto > 0 {
+		p.inicio = time.Now()
+		p.presupuesto = cfg.presupuesto
+		p.limite = p.inicio.Add(cfg.presupuesto)
+	}
+	return p
+}
+
+// activo indica si la llamada tiene un presupuesto de tiempo o un contexto.
+func (p plazo) activo() bool {
+	return p.presupuesto > 0 || p.ctx != nil
+}
+
+// revisar devuelve un *ErrorCancelado si terminó el contexto o un
+// *ErrorTiempoExcedido si el presupuesto se agotó.
+func (p plazo) revisar(etapa string) error {
+	if p.ctx != nil {
+		if err := p.ctx.Err(); err != nil {
+			return &ErrorCancelado{Etapa: etapa, Err: err}
+		}
+	}
+	if p.presupuesto <= 0 {
+		return nil
+	}
+	if ahora := time.Now(); ahora.After(p.limite) {
+		return &ErrorTiempoExcedido{Presupuesto: p.presupuesto, Transcurrido: ahora.Sub(p.inicio), Etapa: etapa}
+	}
+	return nil
+}
+
+// vencido indica si terminó el contexto o se agotó el presupuesto.
+func (p plazo) vencido() bool {
+	return (p.ctx != nil && p.ctx.Err() != nil) || (p.presupuesto > 0 && time.Now().After(p.limite))
+}
+
+// lectorConPlazo entrega la entrada en bloques pequeños y falla con
+// errPlazoVencido en cuanto vence el plazo, lo que interrumpe al
+// decodificador JSON en medio de un documento.
+type lectorConPlazo struct {
+	r      io.Reader
+	limite plazo
+}
+
+func (l *lectorConPlazo) Read(b []byte) (int, error) {
+	if l.limite.vencido() {
+		return 0, errPlazoVencido
+	}
+	if len(b) > tamanoBloqueLectura {
+		b = b[:tamanoBloqueLectura]
+	}
+	return l.r.Read(b)
+}
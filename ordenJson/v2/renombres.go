@@ -0,0 +1,63 @@
+package ordenJson
+
+// WithRenombres renombra las claves de primer nivel antes de ordenar, según
+// un mapa de nombre anterior a nombre actual, para que los documentos que
+// producen sistemas antiguos queden con la convención de nombres vigente en
+// la misma pasada:
+//
+//	ordenJson.WithRenombres(map[string]string{
+//		"tanner:rut_cliente": "tanner:rut-cliente",
+//		"titulo":             "cm:title",
+//	})
+//
+// La clave renombrada ocupa el lugar de la anterior y se valida y ordena con
+// su nombre actual. Si el documento ya trae la clave con el nombre actual y
+// esa clave no se renombra, prevalece su valor y la anterior se descarta. Los
+// renombres no se encadenan: cada clave se busca en el mapa una sola vez, con
+// el nombre que trae el documento, por lo que un mapa como {"a": "b", "b": "a"}
+// intercambia los valores. El mapa se copia; un mapa nil o vacío desactiva la opción.
+func WithRenombres(renombres map[string]string) Option {
+	return func(cfg *configuracion) {
+		cfg.renombres = nil
+		if len(renombres) == 0 {
+			return
+		}
+		cfg.renombres = make(map[string]string, len(renombres))
+		for anterior, actual := range renombres {
+			if actual != "" && actual != anterior {
+				cfg.renombres[anterior] = actual
+			}
+		}
+	}
+}
+
+// renombrarClaves aplica los renombres a datos y devuelve las claves
+// resultantes en el mismo orden. Modifica datos.
+func renombrarClaves(datos map[string]interface{}, claves []string, renombres map[string]string) []string {
+	// Quitar primero todas las claves que se renombran, guardando sus
+	// valores, para que un intercambio como {"a": "b", "b": "a"} no confunda
+	// una clave renombrada con una que ya tenía el nombre actual.
+	valores := make(map[string]interface{})
+	for _, clave := range claves {
+		if _, ok := renombres[clave]; ok {
+			valores[clave] = datos[clave]
+			delete(datos, clave)
+		}
+	}
+	restantes := claves[:0]
+	for _, clave := range claves {
+		actual, ok := renombres[clave]
+		if !ok {
+			restantes = append(restantes, clave)
+			continue
+		}
+		if _, ocupada := datos[actual]; ocupada {
+			// El documento ya trae la clave con el nombre actual, o dos
+			// nombres anteriores de la misma clave: prevalece el primero.
+			continue
+		}
+		datos[actual] = valores[clave]
+		restantes = append(restantes, actual)
+	}
+	return restantes
+}
@@ -0,0 +1,150 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Nombres de las reglas que se informan en Problema.Regla. Las reglas derivadas
+// de un esquema usan el nombre de la palabra clave correspondiente ("type",
+// "pattern", "format", "minLength" o "maxLength").
+const (
+	ReglaRequerido        = "requerido"
+	ReglaClaveDesconocida = "clave-desconocida"
+	ReglaFecha            = "fecha"
+	ReglaValorPermitido   = "valor-permitido"
+)
+
+// ReglasDisponibles lista todas las reglas de validación que el paquete sabe
+// aplicar, incluidas las derivadas de un esquema.
+var ReglasDisponibles = []string{
+	ReglaRequerido, ReglaClaveDesconocida, ReglaFecha, ReglaValorPermitido,
+	"type", "pattern", "format", "minLength", "maxLength",
+}
+
+// Severidad indica si un Problema haría fallar a OrdenarJSON o es solo informativo.
+type Severidad string
+
+const (
+	// SeveridadError marca los problemas que OrdenarJSON devolvería como error.
+	SeveridadError Severidad = "error"
+	// SeveridadAdvertencia marca los hallazgos que no impiden ordenar el documento,
+	// como una clave fuera del perfil cuando no se usa WithStrict.
+	SeveridadAdvertencia Severidad = "advertencia"
+)
+
+// Problema describe un hallazgo en un documento ordenado con OrdenarJSONConReporte.
+// Se serializa a JSON con las claves campo, regla, severidad y mensaje.
+type Problema struct {
+	Campo     string    `json:"campo"`     // Campo o clave al que se refiere el problema.
+	Regla     string    `json:"regla"`     // Regla que lo detectó; ver las constantes Regla*.
+	Severidad Severidad `json:"severidad"` // SeveridadError si Err no es nil, SeveridadAdvertencia si no.
+	Mensaje   string    `json:"mensaje"`   // Descripción legible del problema.
+	Err       error     `json:"-"`         // Error tipado equivalente; nil si el hallazgo no sería un error en OrdenarJSON.
+}
+
+// Reporte agrupa los problemas de un documento en una forma pensada para ser
+// consumida por otras herramientas. Se construye con NuevoReporte y se
+// serializa con encoding/json o con su método JSON.
+type Reporte struct {
+	Valido       bool       `json:"valido"`       // true si ningún problema es de severidad error.
+	Errores      int        `json:"errores"`      // Cantidad de problemas con SeveridadError.
+	Advertencias int        `json:"advertencias"` // Cantidad de problemas con SeveridadAdvertencia.
+	Problemas    []Problema `json:"problemas"`    // Problemas en el orden en que se detectaron; nunca es null.
+}
+
+// NuevoReporte resume la lista de problemas devuelta por OrdenarJSONConReporte.
+func NuevoReporte(problemas []Problema) Reporte {
+	r := Reporte{Problemas: problemas}
+	if r.Problemas == nil {
+		r.Problemas = []Problema{}
+	}
+	for _, p := range problemas {
+		if p.Severidad == SeveridadError {
+			r.Errores++
+		} else {
+			r.Advertencias++
+		}
+	}
+	r.Valido = r.Errores == 0
+	return r
+}
+
+// JSON devuelve el reporte serializado e indentado con dos espacios.
+func (r Reporte) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// OrdenarJSONConReporte ordena el documento igual que OrdenarJSON, pero en
+// lugar de fallar ante el primer problema de validación devuelve la salida
+// ordenada junto con la lista de problemas encontrados: campos obligatorios
+// sin valor, claves fuera del perfil (siempre se informan, aunque no se use
+// WithStrict), fechas que no se pudieron normalizar (se mantienen sin cambios)
+// y valores que no cumplen las reglas configuradas.
+// Solo se devuelve error cuando no es posible producir una salida: JSON
+// inválido, tipo de entrada no soportado, presupuesto de tiempo agotado o
+// valores que no se pueden serializar.
+func OrdenarJSONConReporte(input interface{}, opts ...Option) (string, []Problema, error) {
+	return Nuevo(opts...).OrdenarJSONConReporte(input)
+}
+
+// revisar aplica las mismas reglas que validar pero acumula todos los problemas,
+// en el orden: campos obligatorios, claves desconocidas y reglas por campo.
+func revisar(datos map[string]interface{}, claves []string, cfg *configuracion) []Problema {
+	var problemas []Problema
+	perfil := cfg.perfil
+
+	requeridos := append(append([]string(nil), perfil.requeridos...), cfg.requeridos...)
+	for _, campo := range camposFaltantes(datos, requeridos) {
+		problemas = append(problemas, Problema{
+			Campo:     campo,
+			Regla:     ReglaRequerido,
+			Severidad: SeveridadError,
+			Mensaje:   "campo obligatorio sin valor",
+			Err:       &ErrorCamposFaltantes{Campos: []string{campo}},
+		})
+	}
+
+	estricto := cfg.estricto || perfil.estricto
+	for _, clave := range clavesDesconocidas(claves, perfil) {
+		p := Problema{
+			Campo:     clave,
+			Regla:     ReglaClaveDesconocida,
+			Severidad: SeveridadAdvertencia,
+			Mensaje:   fmt.Sprintf("la clave no pertenece al perfil %q", perfil.nombre),
+		}
+		if estricto {
+			p.Severidad = SeveridadError
+			p.Err = &ErrorClavesNoPermitidas{Claves: []string{clave}}
+		}
+		problemas = append(problemas, p)
+	}
+
+	for _, clave := range claves {
+		if regla, ok := perfil.reglas[clave]; ok {
+			if err := regla.validarValor(clave, datos[clave]); err != nil {
+				problemas = append(problemas, problemaDesdeError(ReglaValorPermitido, err))
+			}
+		}
+		if permitidos, ok := cfg.valoresPermitidos[clave]; ok && !estaVacio(datos[clave]) && !valorAdmitido(datos[clave], permitidos) {
+			err := &ErrorValorNoPermitido{Campo: clave, Valor: datos[clave], Permitidos: permitidos}
+			problemas = append(problemas, problemaDesdeError(ReglaValorPermitido, err))
+		}
+	}
+	return problemas
+}
+
+// problemaDesdeError construye el Problema que corresponde a un error tipado.
+// regla se usa cuando el error no indica su propia regla.
+func problemaDesdeError(regla string, err error) Problema {
+	p := Problema{Regla: regla, Severidad: SeveridadError, Mensaje: err.Error(), Err: err}
+	switch e := err.(type) {
+	case *ErrorRegla:
+		p.Campo, p.Regla, p.Mensaje = e.Campo, e.Regla, e.Detalle
+	case *ErrorValorNoPermitido:
+		p.Campo = e.Campo
+	case *ErrorFechaInvalida:
+		p.Campo = e.Campo
+	}
+	return p
+}
@@ -0,0 +1,53 @@
+package ordenJson
+
+import "sync"
+
+// capacidadMaximaReutilizable es la capacidad, en elementos, a partir de la
+// cual un buffer no vuelve a su pool: un documento excepcionalmente grande no
+// debe dejar retenida esa memoria para todas las llamadas siguientes.
+const capacidadMaximaReutilizable = 1 << 20
+
+// poolSalidas guarda los buffers donde ordenar escribe el documento antes de
+// convertirlo en string, y poolClaves los slices con las claves de cada
+// documento. Al ordenar miles de documentos por segundo, reutilizarlos evita
+// reservar y liberar esa memoria en cada llamada.
+var (
+	poolSalidas = sync.Pool{New: func() interface{} { return new([]byte) }}
+	poolClaves  = sync.Pool{New: func() interface{} { return new([]string) }}
+)
+
+// tomarSalida devuelve un buffer vacío del pool.
+func tomarSalida() *[]byte {
+	salida := poolSalidas.Get().(*[]byte)
+	*salida = (*salida)[:0]
+	return salida
+}
+
+// devolverSalida guarda en el pool el buffer tomado con tomarSalida, que
+// ahora tiene el contenido buf, salvo que haya crecido demasiado.
+func devolverSalida(salida *[]byte, buf []byte) {
+	if cap(buf) > capacidadMaximaReutilizable {
+		return
+	}
+	*salida = buf[:0]
+	poolSalidas.Put(salida)
+}
+
+// tomarClaves devuelve un slice de claves vacío del pool.
+func tomarClaves() *[]string {
+	claves := poolClaves.Get().(*[]string)
+	*claves = (*claves)[:0]
+	return claves
+}
+
+// devolverClaves guarda en el pool el slice tomado con tomarClaves, que ahora
+// tiene el contenido usadas. Las claves se borran para no retener los strings
+// del documento.
+func devolverClaves(claves *[]string, usadas []string) {
+	if cap(usadas) > capacidadMaximaReutilizable {
+		return
+	}
+	clear(usadas)
+	*claves = usadas[:0]
+	poolClaves.Put(claves)
+}
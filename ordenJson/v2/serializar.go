@@ -0,0 +1,23 @@
+package ordenJson
+
+// Serializar convierte v a JSON con las claves en orden canónico. Ver
+// Ordenador.Serializar.
+func Serializar(v interface{}, opts ...Option) ([]byte, error) {
+	return Nuevo(opts...).Serializar(v)
+}
+
+// Serializar convierte v a JSON con el Codec configurado (ver WithCodec) y, si el resultado es un
+// objeto, lo ordena con la configuración del Ordenador, con la misma
+// indentación que OrdenarJSON. Los demás valores (arreglos, números, null)
+// se devuelven tal como los produce el Codec. Sirve para responder con
+// structs o mapas en orden canónico sin pasar por OrdenarJSON a mano.
+func (o *Ordenador) Serializar(v interface{}) ([]byte, error) {
+	datos, err := o.cfg.marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(datos) == 0 || datos[0] != '{' {
+		return datos, nil
+	}
+	return o.AgregarJSON(nil, string(datos))
+}
@@ -0,0 +1,96 @@
+package ordenJson
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SugerirOrden analiza un conjunto de documentos JSON y propone una lista de
+// campos para un perfil que los cubra a todos. Sirve para extender OrdenCampos
+// cuando aparecen aspectos nuevos en los documentos reales.
+//
+// Los campos del perfil (PerfilPorDefecto o el indicado con WithPerfil) se
+// mantienen fijos y en su orden, aunque no aparezcan en el corpus. Cada clave
+// nueva se ubica a continuación del campo conocido que con más frecuencia la
+// precede en los documentos, o al comienzo si suele aparecer antes que todos
+// ellos. Las claves nuevas que comparten posición se ordenan según cuántas
+// veces aparecen antes que las demás en un mismo documento, luego por
+// frecuencia y por último alfabéticamente, de modo que el resultado es
+// determinista. Las demás opciones no tienen efecto.
+func SugerirOrden(corpus []string, opts ...Option) ([]string, error) {
+	perfil := nuevaConfiguracion(opts).perfil
+
+	frecuencia := make(map[string]int)        // Documentos en que aparece cada clave nueva.
+	anclas := make(map[string]map[string]int) // Campo conocido que precede a cada clave nueva, con su cantidad.
+	antes := make(map[string]map[string]int)  // antes[a][b]: documentos donde la clave nueva a precede a b.
+	var nuevas []string
+	for i, documento := range corpus {
+		_, claves, err := decodificarObjeto(documento)
+		if err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i, err)
+		}
+		ancla := ""
+		var vistas []string
+		for _, clave := range claves {
+			if _, conocido := perfil.indice[clave]; conocido {
+				ancla = clave
+				continue
+			}
+			if _, ok := frecuencia[clave]; !ok {
+				nuevas = append(nuevas, clave)
+				anclas[clave] = make(map[string]int)
+				antes[clave] = make(map[string]int)
+			}
+			frecuencia[clave]++
+			anclas[clave][ancla]++
+			for _, previa := range vistas {
+				antes[previa][clave]++
+			}
+			vistas = append(vistas, clave)
+		}
+	}
+
+	// Agrupar las claves nuevas según el campo conocido tras el cual se ubican.
+	grupos := make(map[string][]string)
+	for _, clave := range nuevas {
+		ancla := masVotado(anclas[clave])
+		grupos[ancla] = append(grupos[ancla], clave)
+	}
+	for _, grupo := range grupos {
+		puntaje := make(map[string]int, len(grupo))
+		for _, a := range grupo {
+			for _, b := range grupo {
+				puntaje[a] += antes[a][b] - antes[b][a]
+			}
+		}
+		sort.Slice(grupo, func(i, j int) bool {
+			a, b := grupo[i], grupo[j]
+			if puntaje[a] != puntaje[b] {
+				return puntaje[a] > puntaje[b]
+			}
+			if frecuencia[a] != frecuencia[b] {
+				return frecuencia[a] > frecuencia[b]
+			}
+			return a < b
+		})
+	}
+
+	orden := make([]string, 0, len(perfil.campos)+len(nuevas))
+	orden = append(orden, grupos[""]...)
+	for _, campo := range perfil.campos {
+		orden = append(orden, campo)
+		orden = append(orden, grupos[campo]...)
+	}
+	return orden, nil
+}
+
+// masVotado devuelve la clave con más votos; ante un empate, la menor alfabéticamente.
+func masVotado(votos map[string]int) string {
+	elegida, maximo := "", -1
+	for clave, n := range votos {
+		if n > maximo || (n == maximo && clave < elegida) {
+			elegida, maximo = clave, n
+		}
+	}
+	return elegida
+}
@@ -0,0 +1,155 @@
+package ordenJson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// claveTipoDocumento es la clave cuyo valor selecciona el tipo de documento
+// registrado con RegisterTipoDocumento.
+const claveTipoDocumento = "tanner:tipo-documento"
+
+// tipoDocumento es una familia de documentos registrada con
+// RegisterTipoDocumento.
+type tipoDocumento struct {
+	tipo   reflect.Type // Struct de metadatos en que se decodifica.
+	perfil *Perfil      // Orden y reglas de validación.
+}
+
+// tiposDocumento asocia cada valor de tanner:tipo-documento registrado con
+// su *tipoDocumento.
+var tiposDocumento sync.Map
+
+// RegisterTipoDocumento registra una familia de documentos, como contratos,
+// facturas o pagarés, identificada por el valor tipo de tanner:tipo-documento:
+//
+//	ordenPagare := ordenJson.NuevoPerfil("pagare", []string{"tanner:tipo-documento", "tanner:rut-deudor", "monto"})
+//	ordenJson.RegisterTipoDocumento("pagare", PagareMetadata{}, ordenPagare)
+//
+// prototipo es un valor del struct de metadatos de la familia, o un puntero
+// a él, en el que DecodificarDocumento decodifica los documentos; sus campos
+// se leen igual que en OrdenarStruct. orden es el perfil con que se ordenan
+// y validan los documentos de la familia con WithTiposDocumento; nil usa
+// PerfilPorDefecto. Si ya había una familia con ese tipo, la reemplaza.
+// "contrato" y "factura" están registrados desde el inicio con
+// DocumentMetadata y PerfilPorDefecto.
+//
+// Como los registros son globales, conviene hacerlos al iniciar el programa.
+// Entra en pánico si prototipo no es un struct o si una etiqueta orden de
+// sus campos no es un entero.
+func RegisterTipoDocumento(tipo string, prototipo interface{}, orden *Perfil) {
+	t := reflect.TypeOf(prototipo)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("ordenJson: RegisterTipoDocumento(%q): el prototipo debe ser un struct, se recibió %T", tipo, prototipo))
+	}
+	if err := planDe(t).err; err != nil {
+		panic(fmt.Sprintf("ordenJson: RegisterTipoDocumento(%q): %v", tipo, err))
+	}
+	if orden == nil {
+		orden = PerfilPorDefecto
+	}
+	tiposDocumento.Store(tipo, &tipoDocumento{tipo: t, perfil: orden})
+}
+
+// buscarTipoDocumento devuelve la familia registrada para el valor de
+// tanner:tipo-documento de datos, si es una cadena registrada.
+func buscarTipoDocumento(datos map[string]interface{}) (*tipoDocumento, bool) {
+	tipo, ok := datos[claveTipoDocumento].(string)
+	if !ok {
+		return nil, false
+	}
+	registrado, ok := tiposDocumento.Load(tipo)
+	if !ok {
+		return nil, false
+	}
+	return registrado.(*tipoDocumento), true
+}
+
+// familiaDocumento recibe, en DecodificarDocumento, la familia con que se
+// ordenó el documento, elegida antes de que las opciones como WithOnly o
+// WithExclude quiten tanner:tipo-documento de la salida.
+type familiaDocumento struct {
+	tipo     string         // Valor de tanner:tipo-documento; vacío si no es una cadena.
+	registro *tipoDocumento // nil si tipo no está registrado.
+}
+
+// WithTiposDocumento ordena y valida cada documento con el perfil de la
+// familia registrada con RegisterTipoDocumento para su valor de
+// tanner:tipo-documento, en lugar del perfil de WithPerfil. Los documentos
+// sin tipo o con uno no registrado usan el perfil de WithPerfil. En
+// OrdenarStruct, los campos con etiqueta orden se anteponen al perfil de la
+// familia. Las opciones de validación, como WithRequired, se aplican a todas
+// las familias.
+func WithTiposDocumento() Option {
+	return func(cfg *configuracion) {
+		cfg.tiposDocumento = true
+	}
+}
+
+// DecodificarDocumento ordena y valida input con las opciones recibidas y
+// lo decodifica en el struct de su familia. Ver Ordenador.DecodificarDocumento.
+func DecodificarDocumento(input interface{}, opts ...Option) (interface{}, error) {
+	return Nuevo(opts...).DecodificarDocumento(input)
+}
+
+// DecodificarDocumento ordena y valida input igual que OrdenarJSON con
+// WithTiposDocumento y lo decodifica en un struct nuevo de la familia
+// registrada para su valor de tanner:tipo-documento. Devuelve un puntero a
+// ese struct, por ejemplo un *PagareMetadata, que se distingue con un type
+// switch:
+//
+//	doc, err := ordenJson.DecodificarDocumento(entrada)
+//	switch d := doc.(type) {
+//	case *PagareMetadata:
+//		...
+//	case *ordenJson.DocumentMetadata:
+//		...
+//	}
+//
+// Si el documento no tiene un tipo registrado devuelve un
+// *ErrorTipoDocumentoDesconocido, y si el valor de un campo no corresponde
+// al tipo del campo en el struct, un *ErrorRegla de la regla "type". La
+// familia se elige con el documento completo, aunque WithOnly, WithExclude o
+// WithMask cambien tanner:tipo-documento en la salida que se decodifica.
+// El resultado no se guarda en la caché de WithCache.
+func (o *Ordenador) DecodificarDocumento(input interface{}) (interface{}, error) {
+	inicio := time.Now()
+	cfg := o.cfg
+	cfg.tiposDocumento = true
+	cfg.familia = &familiaDocumento{}
+	salida, _, err := ordenar(input, &cfg)
+	var documento interface{}
+	if err == nil {
+		documento, err = decodificarFamilia(salida, cfg.familia)
+		localizarError(err, cfg.idioma)
+	}
+	cfg.registrarEvento(OperacionOrdenar, inicio, input, salida, nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return documento, nil
+}
+
+// decodificarFamilia decodifica salida, un documento ya ordenado, en un
+// struct nuevo de la familia con que se ordenó.
+func decodificarFamilia(salida string, familia *familiaDocumento) (interface{}, error) {
+	if familia.registro == nil {
+		return nil, &ErrorTipoDocumentoDesconocido{Tipo: familia.tipo}
+	}
+	documento := reflect.New(familia.registro.tipo)
+	if err := json.Unmarshal([]byte(salida), documento.Interface()); err != nil {
+		var errTipo *json.UnmarshalTypeError
+		if errors.As(err, &errTipo) {
+			return nil, nuevoErrorRegla(errTipo.Field, "type", errTipo.Value, []string{errTipo.Type.String()})
+		}
+		return nil, err
+	}
+	return documento.Interface(), nil
+}
@@ -0,0 +1,158 @@
+package ordenJson
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Transformacion recibe el valor decodificado de un campo y devuelve el que
+// se escribe en su lugar. Ver WithTransform.
+type Transformacion func(valor interface{}) interface{}
+
+// WithTransform aplica f al valor del campo de primer nivel indicado en cada
+// documento que se ordena, en la misma pasada: la normalización (recortar,
+// pasar a mayúsculas, reformatear fechas) y el ordenamiento no necesitan
+// decodificar el documento dos veces. Ver Recortar, Mayusculas y
+// ReformatearFecha.
+//
+// f recibe el valor como lo decodifica encoding/json (string, float64, bool,
+// nil, map[string]interface{} o []interface{}) y solo se llama si el
+// documento trae el campo. Las transformaciones se aplican después de
+// WithRenombres, con el nombre actual del campo, y antes de omitir los
+// vacíos, normalizar las fechas y validar. Varias transformaciones del mismo
+// campo se aplican en el orden en que se pasan las opciones.
+func WithTransform(campo string, f func(valor interface{}) interface{}) Option {
+	return func(cfg *configuracion) {
+		if f == nil {
+			return
+		}
+		if cfg.transformaciones == nil {
+			cfg.transformaciones = make(map[string][]Transformacion)
+		}
+		cfg.transformaciones[campo] = append(cfg.transformaciones[campo], f)
+	}
+}
+
+// Recortar quita los espacios al comienzo y al final de los valores de texto.
+// Los demás valores quedan sin cambios.
+func Recortar(valor interface{}) interface{} {
+	if texto, ok := valor.(string); ok {
+		return strings.TrimSpace(texto)
+	}
+	return valor
+}
+
+// Mayusculas pasa a mayúsculas los valores de texto. Los demás valores quedan
+// sin cambios.
+func Mayusculas(valor interface{}) interface{} {
+	if texto, ok := valor.(string); ok {
+		return strings.ToUpper(texto)
+	}
+	return valor
+}
+
+// ReformatearFecha devuelve una transformación que interpreta los valores de
+// texto con el primero de los layouts de entrada que coincida y los escribe
+// con el layout salida. Sin layouts de entrada se usan
+// FormatosFechaPorDefecto. Los valores que no son fechas quedan sin cambios;
+// para rechazarlos, ver WithNormalizarFechas.
+func ReformatearFecha(salida string, entradas ...string) Transformacion {
+	if len(entradas) == 0 {
+		entradas = FormatosFechaPorDefecto
+	}
+	return func(valor interface{}) interface{} {
+		texto, ok := valor.(string)
+		if !ok {
+			return valor
+		}
+		if t, ok := interpretarFecha(texto, entradas); ok {
+			return t.Format(salida)
+		}
+		return valor
+	}
+}
+
+// transformar aplica a datos las transformaciones de WithTransform de los
+// campos presentes. Modifica datos.
+func transformar(datos map[string]interface{}, transformaciones map[string][]Transformacion) {
+	for campo, fs := range transformaciones {
+		valor, ok := datos[campo]
+		if !ok {
+			continue
+		}
+		for _, f := range fs {
+			valor = f(valor)
+		}
+		datos[campo] = valor
+	}
+}
+
+// WithOrdenarLista ordena los elementos del campo de primer nivel indicado
+// cuando su valor es un arreglo, como tanner:categorias, para que la salida
+// sea determinista aunque la fuente los envíe en cualquier orden. comparar
+// devuelve un número negativo, cero o positivo como cmp.Compare; con nil se
+// usa CompararAlfabetico. La ordenación es estable y no modifica el arreglo
+// de un mapa recibido como entrada. Se aplica como una Transformacion más,
+// en su lugar entre las de WithTransform del mismo campo.
+func WithOrdenarLista(campo string, comparar func(a, b interface{}) int) Option {
+	if comparar == nil {
+		comparar = CompararAlfabetico
+	}
+	return WithTransform(campo, func(valor interface{}) interface{} {
+		lista, ok := valor.([]interface{})
+		if !ok {
+			return valor
+		}
+		lista = slices.Clone(lista)
+		slices.SortStableFunc(lista, comparar)
+		return lista
+	})
+}
+
+// CompararAlfabetico compara dos elementos de un arreglo: los textos en
+// orden alfabético por bytes, los números por su valor, false antes que true
+// y los objetos y arreglos por su codificación JSON. Los elementos de
+// distinto tipo se ordenan por tipo: null, booleanos, números, textos,
+// arreglos y objetos.
+func CompararAlfabetico(a, b interface{}) int {
+	if c := cmp.Compare(rangoTipo(a), rangoTipo(b)); c != 0 {
+		return c
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case float64:
+		return cmp.Compare(x, b.(float64))
+	case bool:
+		return cmp.Compare(boolAEntero(x), boolAEntero(b.(bool)))
+	}
+	return strings.Compare(valorDiff(a), valorDiff(b))
+}
+
+// rangoTipo devuelve la posición del tipo de v en el orden de
+// CompararAlfabetico.
+func rangoTipo(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	case []interface{}:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// boolAEntero devuelve 1 para true y 0 para false.
+func boolAEntero(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
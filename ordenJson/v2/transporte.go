@@ -0,0 +1,72 @@
+package ordenJson
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// Transporte envuelve base para que los cuerpos application/json (o con un
+// tipo +json) de las solicitudes salientes se envíen con las claves en el
+// orden canónico de PerfilPorDefecto. Ver Ordenador.Transporte.
+func Transporte(base http.RoundTripper) http.RoundTripper {
+	return Nuevo().Transporte(base)
+}
+
+// Transporte envuelve base, o http.DefaultTransport si es nil, para que los
+// cuerpos JSON de las solicitudes salientes se ordenen con la configuración
+// del Ordenador antes de enviarse:
+//
+//	cliente := &http.Client{Transport: ordenador.Transporte(nil)}
+//
+// Las solicitudes sin cuerpo, las que no son JSON, las comprimidas (con
+// Content-Encoding) y los cuerpos que no son objetos pasan sin cambios. Si un
+// objeto no se puede ordenar, por ejemplo porque no cumple las validaciones,
+// la solicitud no se envía y el cliente recibe el error tipado de
+// OrdenarJSON, para no mandar propiedades fuera de orden.
+func (o *Ordenador) Transporte(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transporteOrdenado{base: base, ordenador: o}
+}
+
+// transporteOrdenado implementa http.RoundTripper sobre otro transporte.
+type transporteOrdenado struct {
+	base      http.RoundTripper
+	ordenador *Ordenador
+}
+
+// RoundTrip ordena el cuerpo de la solicitud, si corresponde, y la envía con
+// el transporte base. La solicitud original no se modifica.
+func (t *transporteOrdenado) RoundTrip(solicitud *http.Request) (*http.Response, error) {
+	if solicitud.Body == nil || solicitud.Body == http.NoBody ||
+		!esTipoJSON(solicitud.Header.Get("Content-Type")) ||
+		solicitud.Header.Get("Content-Encoding") != "" {
+		return t.base.RoundTrip(solicitud)
+	}
+	cuerpo, err := io.ReadAll(solicitud.Body)
+	solicitud.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// Solo se ordenan objetos: OrdenarJSON convertiría un null en {}.
+	if bytes.HasPrefix(bytes.TrimSpace(cuerpo), []byte("{")) {
+		ordenado, err := t.ordenador.OrdenarJSON(string(cuerpo))
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasSuffix(cuerpo, []byte("\n")) {
+			ordenado += "\n"
+		}
+		cuerpo = []byte(ordenado)
+	}
+
+	copia := solicitud.Clone(solicitud.Context())
+	copia.Body = io.NopCloser(bytes.NewReader(cuerpo))
+	copia.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(cuerpo)), nil
+	}
+	copia.ContentLength = int64(len(cuerpo))
+	return t.base.RoundTrip(copia)
+}
@@ -0,0 +1,118 @@
+package ordenJson
+
+import "context"
+
+// Nombres de los tramos que se crean con WithTrazador.
+const (
+	TramoOrdenar = "ordenjson.ordenar" // Un documento: OrdenarJSON, AgregarJSON y sus variantes.
+	TramoValidar = "ordenjson.validar" // Validación de un documento, dentro de TramoOrdenar.
+	TramoLote    = "ordenjson.lote"    // OrdenarLote; contiene un TramoOrdenar por documento.
+	TramoFlujo   = "ordenjson.flujo"   // OrdenarFlujo; contiene un TramoOrdenar por documento.
+)
+
+// Atributos que se agregan a los tramos.
+const (
+	AtributoPerfil       = "ordenjson.perfil"        // Nombre del perfil; en todos los tramos.
+	AtributoBytesEntrada = "ordenjson.bytes_entrada" // Tamaño del documento recibido, si es una cadena.
+	AtributoBytesSalida  = "ordenjson.bytes_salida"  // Tamaño del documento ordenado.
+	AtributoClaves       = "ordenjson.claves"        // Cantidad de claves de primer nivel.
+	AtributoCache        = "ordenjson.cache"         // true si la salida se tomó de WithCache.
+	AtributoReglas       = "ordenjson.reglas"        // En TramoValidar, reglas aplicadas separadas por comas; ver Ordenador.Reglas.
+	AtributoProblemas    = "ordenjson.problemas"     // En TramoValidar, problemas encontrados con OrdenarJSONConReporte.
+	AtributoDocumentos   = "ordenjson.documentos"    // En TramoLote y TramoFlujo, documentos procesados.
+	AtributoFallidos     = "ordenjson.fallidos"      // En TramoLote, documentos con error.
+	AtributoTrabajadores = "ordenjson.trabajadores"  // En TramoLote, goroutines usadas.
+)
+
+// Trazador crea los tramos (spans) con los que se instrumentan las
+// operaciones, para ver su costo dentro de una traza distribuida. El paquete
+// ordenJson/ordenotel lo implementa con OpenTelemetry.
+type Trazador interface {
+	// Iniciar crea un tramo hijo del que lleva ctx, si lo hay, y devuelve un
+	// contexto que lo lleva.
+	Iniciar(ctx context.Context, nombre string) (context.Context, Tramo)
+}
+
+// Tramo es una operación en curso de un Trazador.
+type Tramo interface {
+	// Atributo agrega un atributo al tramo. valor es un int, un string o un
+	// bool.
+	Atributo(clave string, valor interface{})
+	// Terminar cierra el tramo; si err no es nil, lo marca como fallido.
+	Terminar(err error)
+}
+
+// WithTrazador crea con t un tramo por cada documento que se ordena, por su
+// validación y por cada lote o flujo, con los atributos de arriba. El
+// contexto de las variantes Ctx, como OrdenarJSONCtx, determina el tramo
+// padre; las demás inician una traza nueva.
+func WithTrazador(t Trazador) Option {
+	return func(cfg *configuracion) {
+		cfg.trazador = t
+	}
+}
+
+// tramoEn crea, si hay un trazador, un tramo hijo del que lleva ctx y
+// devuelve un contexto que lo lleva. Sin trazador devuelve ctx y nil.
+func (cfg *configuracion) tramoEn(ctx context.Context, nombre string) (context.Context, Tramo) {
+	if cfg.trazador == nil {
+		return ctx, nil
+	}
+	ctx, tramo := cfg.trazador.Iniciar(ctx, nombre)
+	tramo.Atributo(AtributoPerfil, cfg.perfil.Nombre())
+	return ctx, tramo
+}
+
+// iniciarTramo crea, si hay un trazador, un tramo para la operación y
+// devuelve una copia de cfg cuyo contexto lo lleva, para que los tramos de
+// las etapas internas queden dentro de él. Sin trazador devuelve cfg y nil.
+func (cfg *configuracion) iniciarTramo(nombre string) (*configuracion, Tramo) {
+	if cfg.trazador == nil {
+		return cfg, nil
+	}
+	ctx := cfg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	copia := *cfg
+	copia.ctx, copia.tramo = cfg.tramoEn(ctx, nombre)
+	return &copia, copia.tramo
+}
+
+// atributoTramo agrega un atributo al tramo de la operación en curso, si lo
+// hay.
+func (cfg *configuracion) atributoTramo(clave string, valor interface{}) {
+	if cfg.tramo != nil {
+		cfg.tramo.Atributo(clave, valor)
+	}
+}
+
+// subtramo crea un tramo hijo del de la operación en curso, o devuelve nil si
+// la operación no se está trazando.
+func (cfg *configuracion) subtramo(nombre string) Tramo {
+	if cfg.tramo == nil {
+		return nil
+	}
+	_, tramo := cfg.tramoEn(cfg.ctx, nombre)
+	return tramo
+}
+
+// atributosEntrada agrega al tramo en curso el tamaño de input, si es una
+// cadena.
+func (cfg *configuracion) atributosEntrada(input interface{}) {
+	if texto, ok := input.(string); ok {
+		cfg.atributoTramo(AtributoBytesEntrada, len(texto))
+	}
+}
+
+// terminarTramo cierra tramo, si no es nil, con el tamaño de la salida si la
+// operación terminó sin error.
+func terminarTramo(tramo Tramo, bytesSalida int, err error) {
+	if tramo == nil {
+		return
+	}
+	if err == nil {
+		tramo.Atributo(AtributoBytesSalida, bytesSalida)
+	}
+	tramo.Terminar(err)
+}
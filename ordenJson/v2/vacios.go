@@ -0,0 +1,96 @@
+package ordenJson
+
+// WithVacio reemplaza el criterio que decide si un valor de texto cuenta como
+// vacío. Por defecto solo la cadena vacía es un valor vacío, y solo
+// OrdenarDocumentoMetadata omite esos campos.
+//
+// Con esta opción, los campos cuyo valor es una cadena para la que esVacio
+// devuelve true se omiten de la salida tanto en OrdenarDocumentoMetadata como
+// en OrdenarJSON, y cuentan como ausentes al validar WithRequired. Sirve para
+// que los marcadores que usan algunas fuentes heredadas ("-", "N/A") no lleguen
+// al repositorio; ver MarcadoresVacios. Un esVacio nil restablece el criterio
+// por defecto.
+func WithVacio(esVacio func(string) bool) Option {
+	return func(cfg *configuracion) {
+		cfg.vacio = esVacio
+	}
+}
+
+// CamposVacios decide qué hacer con los campos vacíos al ordenar un struct
+// con OrdenarDocumentoMetadata u OrdenarStruct. Un campo está vacío si es una
+// cadena para la que el criterio de WithVacio devuelve true o el valor cero
+// de su tipo; ver OrdenarStruct.
+type CamposVacios string
+
+const (
+	// CamposVaciosOmitir omite los campos vacíos. Es el valor por defecto.
+	CamposVaciosOmitir CamposVacios = "omitir"
+	// CamposVaciosConservar escribe los campos vacíos con su valor, por
+	// ejemplo "" o 0, como encoding/json sin omitempty.
+	CamposVaciosConservar CamposVacios = "conservar"
+	// CamposVaciosNull escribe los campos vacíos como null, para los sistemas
+	// que esperan todas las claves del struct.
+	CamposVaciosNull CamposVacios = "null"
+	// CamposVaciosOmitempty omite solo los campos vacíos cuya etiqueta json
+	// tiene la opción omitempty; el resto se escribe con su valor.
+	CamposVaciosOmitempty CamposVacios = "omitempty"
+)
+
+// WithCamposVacios indica qué hacer con los campos vacíos de los structs que
+// se ordenan con OrdenarDocumentoMetadata u OrdenarStruct; por defecto se
+// omiten (CamposVaciosOmitir). Se aplica también a los structs anidados. No
+// afecta a los documentos recibidos como cadena o mapa: para omitir sus
+// valores vacíos se usa WithVacio. Un valor desconocido equivale a
+// CamposVaciosOmitir.
+func WithCamposVacios(politica CamposVacios) Option {
+	return func(cfg *configuracion) {
+		cfg.camposVacios = politica
+	}
+}
+
+// omiteCamposVacios indica si WithCamposVacios omite todos los campos vacíos,
+// como por defecto.
+func (cfg *configuracion) omiteCamposVacios() bool {
+	switch cfg.camposVacios {
+	case CamposVaciosConservar, CamposVaciosNull, CamposVaciosOmitempty:
+		return false
+	}
+	return true
+}
+
+// MarcadoresVacios devuelve un criterio para WithVacio que considera vacíos la
+// cadena vacía y cada uno de los marcadores recibidos. La comparación es exacta.
+func MarcadoresVacios(marcadores ...string) func(string) bool {
+	conjunto := make(map[string]struct{}, len(marcadores)+1)
+	conjunto[""] = struct{}{}
+	for _, m := range marcadores {
+		conjunto[m] = struct{}{}
+	}
+	return func(texto string) bool {
+		_, ok := conjunto[texto]
+		return ok
+	}
+}
+
+// esVacio aplica el criterio configurado con WithVacio o, si no hay uno, el
+// criterio por defecto: solo la cadena vacía.
+func (cfg *configuracion) esVacio(texto string) bool {
+	if cfg.vacio != nil {
+		return cfg.vacio(texto)
+	}
+	return texto == ""
+}
+
+// omitirVacios elimina de datos los campos de texto que esVacio considera vacíos
+// y devuelve las claves restantes en el mismo orden. Modifica datos.
+func omitirVacios(datos map[string]interface{}, claves []string, esVacio func(string) bool) []string {
+	restantes := claves[:0]
+	for _, clave := range claves {
+		if texto, ok := datos[clave].(string); ok && esVacio(texto) {
+			delete(datos, clave)
+			continue
+		}
+		restantes = append(restantes, clave)
+	}
+	return restantes
+}
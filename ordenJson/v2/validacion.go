@@ -0,0 +1,199 @@
+package ordenJson
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithRequired marca campos como obligatorios. Si alguno de ellos no está
+// presente, es null o es una cadena vacía, el ordenamiento falla con un
+// *ErrorCamposFaltantes. Puede usarse más de una vez; los campos se acumulan.
+func WithRequired(campos ...string) Option {
+	return func(cfg *configuracion) {
+		cfg.requeridos = append(cfg.requeridos, campos...)
+	}
+}
+
+// WithStrict activa el modo estricto: cualquier clave que no forme parte del
+// perfil configurado produce un *ErrorClavesNoPermitidas que lista las claves
+// desconocidas. Sirve para detectar errores de tipeo como "tanner:rut_cliente"
+// antes de enviar el documento a su destino.
+func WithStrict() Option {
+	return func(cfg *configuracion) {
+		cfg.estricto = true
+	}
+}
+
+// WithClavesUnicas rechaza los documentos de texto que repiten una clave de
+// primer nivel, con un *ErrorJSONInvalido cuyo Err es un *ErrorClaveDuplicada
+// (errors.Is(err, ErrClaveDuplicada)). Sin la opción, igual que con
+// json.Unmarshal, prevalece el último valor, lo que puede ocultar que dos
+// sistemas escribieron el mismo campo con valores distintos.
+func WithClavesUnicas() Option {
+	return func(cfg *configuracion) {
+		cfg.clavesUnicas = true
+	}
+}
+
+// ValoresEstadoVisado son los valores admitidos por defecto para "tanner:estado-visado".
+var ValoresEstadoVisado = []string{"aprobado", "rechazado", "pendiente"}
+
+// ValoresEstadoVigencia son los valores admitidos por defecto para "tanner:estado-vigencia".
+var ValoresEstadoVigencia = []string{"vigente", "vencido"}
+
+// WithValoresPermitidos restringe los valores que puede tomar un campo. Si el
+// documento trae otro valor, el ordenamiento falla con un *ErrorValorNoPermitido.
+// Los campos ausentes, nulos o vacíos no se validan; para exigirlos se usa WithRequired.
+// Llamarla de nuevo para el mismo campo reemplaza el conjunto anterior.
+func WithValoresPermitidos(campo string, valores ...string) Option {
+	permitidos := make([]interface{}, len(valores))
+	for i, valor := range valores {
+		permitidos[i] = valor
+	}
+	return func(cfg *configuracion) {
+		if cfg.valoresPermitidos == nil {
+			cfg.valoresPermitidos = make(map[string][]interface{})
+		}
+		cfg.valoresPermitidos[campo] = permitidos
+	}
+}
+
+// WithValidarEstados valida "tanner:estado-visado" y "tanner:estado-vigencia"
+// contra ValoresEstadoVisado y ValoresEstadoVigencia.
+func WithValidarEstados() Option {
+	visado := WithValoresPermitidos("tanner:estado-visado", ValoresEstadoVisado...)
+	vigencia := WithValoresPermitidos("tanner:estado-vigencia", ValoresEstadoVigencia...)
+	return func(cfg *configuracion) {
+		visado(cfg)
+		vigencia(cfg)
+	}
+}
+
+// Reglas devuelve, sin repetir y ordenados, los nombres de las reglas de
+// validación que aplica el Ordenador, tanto las configuradas con opciones como
+// las de su perfil. Ver ReglasDisponibles.
+func (o *Ordenador) Reglas() []string {
+	return o.cfg.reglas()
+}
+
+// reglas implementa Reglas.
+func (cfg *configuracion) reglas() []string {
+	perfil := cfg.perfil
+	activas := make(map[string]bool)
+	activas[ReglaRequerido] = len(cfg.requeridos) > 0 || len(perfil.requeridos) > 0
+	activas[ReglaClaveDesconocida] = cfg.estricto || perfil.estricto
+	activas[ReglaFecha] = cfg.normalizarFechas
+	activas[ReglaValorPermitido] = len(cfg.valoresPermitidos) > 0
+	for _, regla := range perfil.reglas {
+		activas["type"] = activas["type"] || len(regla.tipos) > 0
+		activas[ReglaValorPermitido] = activas[ReglaValorPermitido] || len(regla.valores) > 0
+		activas["pattern"] = activas["pattern"] || regla.patron != nil
+		activas["format"] = activas["format"] || regla.formato != ""
+		activas["minLength"] = activas["minLength"] || regla.minLargo >= 0
+		activas["maxLength"] = activas["maxLength"] || regla.maxLargo >= 0
+	}
+	var reglas []string
+	for regla, activa := range activas {
+		if activa {
+			reglas = append(reglas, regla)
+		}
+	}
+	sort.Strings(reglas)
+	return reglas
+}
+
+// validarDocumento aplica las validaciones de cfg: con cfg.reporte devuelve
+// todos los problemas encontrados y, si no, el primer error. Si la operación
+// se está trazando, lo hace dentro de un TramoValidar.
+func validarDocumento(datos map[string]interface{}, claves []string, cfg *configuracion) ([]Problema, error) {
+	tramo := cfg.subtramo(TramoValidar)
+	var problemas []Problema
+	var err error
+	if cfg.reporte {
+		problemas = revisar(datos, claves, cfg)
+	} else {
+		err = validar(datos, claves, cfg)
+	}
+	if tramo != nil {
+		tramo.Atributo(AtributoReglas, strings.Join(cfg.reglas(), ","))
+		if cfg.reporte {
+			tramo.Atributo(AtributoProblemas, len(problemas))
+		}
+		tramo.Terminar(err)
+	}
+	return problemas, err
+}
+
+// validar aplica sobre datos las reglas configuradas y las del perfil, y
+// devuelve el primer error encontrado. claves contiene las claves del documento
+// en su orden original y se usa para reportar los problemas en ese mismo orden.
+func validar(datos map[string]interface{}, claves []string, cfg *configuracion) error {
+	perfil := cfg.perfil
+	requeridos := cfg.requeridos
+	if len(perfil.requeridos) > 0 {
+		requeridos = append(append([]string(nil), perfil.requeridos...), cfg.requeridos...)
+	}
+	if faltantes := camposFaltantes(datos, requeridos); len(faltantes) > 0 {
+		return &ErrorCamposFaltantes{Campos: faltantes}
+	}
+	if cfg.estricto || perfil.estricto {
+		if desconocidas := clavesDesconocidas(claves, perfil); len(desconocidas) > 0 {
+			return &ErrorClavesNoPermitidas{Claves: desconocidas}
+		}
+	}
+	for _, clave := range claves {
+		regla, ok := perfil.reglas[clave]
+		if !ok {
+			continue
+		}
+		if err := regla.validarValor(clave, datos[clave]); err != nil {
+			return err
+		}
+	}
+	for _, clave := range claves {
+		permitidos, ok := cfg.valoresPermitidos[clave]
+		if !ok || estaVacio(datos[clave]) {
+			continue
+		}
+		if !valorAdmitido(datos[clave], permitidos) {
+			return &ErrorValorNoPermitido{Campo: clave, Valor: datos[clave], Permitidos: permitidos}
+		}
+	}
+	return nil
+}
+
+// clavesDesconocidas devuelve las claves que no pertenecen al perfil.
+func clavesDesconocidas(claves []string, perfil *Perfil) []string {
+	var desconocidas []string
+	for _, clave := range claves {
+		if _, ok := perfil.indice[clave]; !ok {
+			desconocidas = append(desconocidas, clave)
+		}
+	}
+	return desconocidas
+}
+
+// camposFaltantes devuelve, en el orden recibido, los campos obligatorios que no tienen valor.
+func camposFaltantes(datos map[string]interface{}, requeridos []string) []string {
+	var faltantes []string
+	vistos := make(map[string]bool, len(requeridos))
+	for _, campo := range requeridos {
+		if vistos[campo] {
+			continue
+		}
+		vistos[campo] = true
+		if estaVacio(datos[campo]) {
+			faltantes = append(faltantes, campo)
+		}
+	}
+	return faltantes
+}
+
+// estaVacio indica si un valor cuenta como ausente: nil o una cadena vacía.
+func estaVacio(valor interface{}) bool {
+	if valor == nil {
+		return true
+	}
+	texto, ok := valor.(string)
+	return ok && texto == ""
+}
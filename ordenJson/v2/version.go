@@ -0,0 +1,98 @@
+package ordenJson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// rutaModulo es la ruta del módulo que contiene este paquete.
+const rutaModulo = "github.com/samuel/prueba-orden"
+
+// VersionPerfil es la versión del perfil por defecto. Se incrementa cada vez
+// que cambia OrdenCampos o las reglas que lo acompañan, porque eso cambia la
+// salida que producen las funciones de ordenamiento.
+const VersionPerfil = "1"
+
+// InfoVersion describe qué comportamiento de ordenamiento tiene un binario.
+type InfoVersion struct {
+	Modulo        string `json:"modulo"`                   // Ruta del módulo.
+	Version       string `json:"version"`                  // Versión del módulo; "(devel)" si se compiló desde el código fuente.
+	VersionPerfil string `json:"version_perfil"`           // Ver VersionPerfil.
+	HuellaPerfil  string `json:"huella_perfil"`            // Hash de los campos del perfil por defecto.
+	VersionGo     string `json:"version_go"`               // Versión de Go con la que se compiló.
+	Revision      string `json:"revision,omitempty"`       // Commit de control de versiones, si está disponible.
+	FechaRevision string `json:"fecha_revision,omitempty"` // Fecha de ese commit.
+	Modificado    bool   `json:"modificado,omitempty"`     // Indica si el árbol tenía cambios sin commit al compilar.
+}
+
+// String devuelve la información en una sola línea, pensada para --version.
+func (v InfoVersion) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s (perfil v%s %s, %s)", v.Modulo, v.Version, v.VersionPerfil, v.HuellaPerfil, v.VersionGo)
+	if v.Revision != "" {
+		fmt.Fprintf(&sb, " revisión %s", v.Revision)
+		if v.FechaRevision != "" {
+			fmt.Fprintf(&sb, " del %s", v.FechaRevision)
+		}
+		if v.Modificado {
+			sb.WriteString(" con cambios locales")
+		}
+	}
+	return sb.String()
+}
+
+// Version devuelve la versión del módulo, la del perfil por defecto y la
+// información de compilación del binario, obtenida de debug.ReadBuildInfo.
+// Soporte la usa para saber exactamente qué comportamiento de ordenamiento
+// tiene un despliegue.
+func Version() InfoVersion {
+	return infoVersion()
+}
+
+// infoVersion calcula la información una sola vez; no cambia durante la ejecución.
+var infoVersion = sync.OnceValue(func() InfoVersion {
+	info := InfoVersion{
+		Modulo:        rutaModulo,
+		Version:       "(devel)",
+		VersionPerfil: VersionPerfil,
+		HuellaPerfil:  huellaPerfil(PerfilPorDefecto),
+		VersionGo:     runtime.Version(),
+	}
+
+	compilacion, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if compilacion.Main.Path == rutaModulo {
+		info.Version = compilacion.Main.Version
+	} else {
+		for _, dep := range compilacion.Deps {
+			if dep.Path == rutaModulo {
+				info.Version = dep.Version
+				break
+			}
+		}
+	}
+	for _, ajuste := range compilacion.Settings {
+		switch ajuste.Key {
+		case "vcs.revision":
+			info.Revision = ajuste.Value
+		case "vcs.time":
+			info.FechaRevision = ajuste.Value
+		case "vcs.modified":
+			info.Modificado = ajuste.Value == "true"
+		}
+	}
+	return info
+})
+
+// huellaPerfil devuelve los primeros 12 caracteres del SHA-256 de los campos del perfil.
+func huellaPerfil(p *Perfil) string {
+	suma := sha256.Sum256([]byte(strings.Join(p.campos, "\n")))
+	return hex.EncodeToString(suma[:])[:12]
+}
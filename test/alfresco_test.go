@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/alfresco"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestAlfresco_OrdenarNodoConReintento(t *testing.T) {
+	const nodo = "a1b2c3d4-0000-4000-8000-000000000001"
+	propiedades := `{"zzz": "x", "cm:title": "Contrato", "tanner:rut-cliente": "1-9"}`
+
+	var lecturas atomic.Int32
+	var escrito []byte
+	var autorizado bool
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usuario, clave, ok := r.BasicAuth()
+		autorizado = ok && usuario == "admin" && clave == "secreto"
+		if r.URL.Path != alfresco.RutaAPI+"/nodes/"+nodo {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error": {"errorKey": "framework.exception.EntityNotFound", "statusCode": 404, "briefSummary": "nodo no encontrado"}}`)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			// La primera lectura falla con un error temporal.
+			if lecturas.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			io.WriteString(w, `{"entry": {"id": "`+nodo+`", "properties": `+propiedades+`}}`)
+		case http.MethodPut:
+			escrito, _ = io.ReadAll(r.Body)
+			io.WriteString(w, `{"entry": {"id": "`+nodo+`"}}`)
+		}
+	}))
+	defer servidor.Close()
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, propiedades)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Las propiedades se leen con un reintento, se ordenan y se escriben de vuelta"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando el nodo en un Alfresco simulado")
+	cliente, err := alfresco.Nuevo(alfresco.Config{
+		URL:           servidor.URL,
+		Usuario:       "admin",
+		Clave:         "secreto",
+		EsperaInicial: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ordenadas, err := cliente.Ordenar(context.Background(), nodo)
+
+	status := "Completado"
+	esperado, _ := ordenJson.OrdenarJSON(propiedades)
+	if err != nil || ordenadas != esperado {
+		status = "Fallido"
+		t.Errorf("Ordenar() = %q, %v; esperado %q", ordenadas, err, esperado)
+	}
+	if string(escrito) != `{"properties":`+esperado+`}` || lecturas.Load() != 2 || !autorizado {
+		status = "Fallido"
+		t.Errorf("PUT = %s; %d lecturas; autorizado %v", escrito, lecturas.Load(), autorizado)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Leyendo un nodo inexistente")
+	_, err = cliente.Propiedades(context.Background(), "otro")
+	var errAlfresco *alfresco.ErrorAlfresco
+	if !errors.As(err, &errAlfresco) || errAlfresco.Estado != http.StatusNotFound || errAlfresco.Mensaje != "nodo no encontrado" {
+		status = "Fallido"
+		t.Errorf("Propiedades() de un nodo inexistente: %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: ordenadas}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,37 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/bench"
+)
+
+func TestBench_CorpusSinRegresiones(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "bench.Todos()")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Corpus deterministas; los documentos planos se ordenan sin reservar memoria"})
+
+	registradorGlobal.AgregarProceso(testName, "Generando los corpus dos veces")
+	primeros, segundos := bench.Todos(), bench.Todos()
+	status := "Completado"
+	for i, c := range primeros {
+		if len(c.Documentos) == 0 || c.Bytes() != segundos[i].Bytes() {
+			status = "Fallido"
+			t.Errorf("%s: corpus vacío o no determinista", c.Nombre)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Verificando las reservas del corpus pequeño")
+	if !t.Run("reservas", func(t *testing.T) { bench.VerificarReservas(t, bench.Pequeno(), 0) }) {
+		status = "Fallido"
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func BenchmarkCorpus(b *testing.B) {
+	bench.EjecutarTodos(b)
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestCache_EntradasRepetidasYDescarte(t *testing.T) {
+	documentos := []string{
+		`{"zzz": 1, "cm:title": "a"}`,
+		`{"zzz": 2, "cm:title": "b"}`,
+		`{"zzz": 3, "cm:title": "c"}`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documentos)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Las entradas repetidas no se decodifican; al llenarse se descarta la menos usada"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con una caché de 2 documentos y contando las decodificaciones")
+	contador := &codecContador{Codec: ordenJson.CodecEstandar}
+	ord := ordenJson.Nuevo(ordenJson.WithCache(2), ordenJson.WithCodec(contador), ordenJson.WithPresupuesto(time.Hour))
+	sinCache := ordenJson.Nuevo()
+
+	status := "Completado"
+	ordenar := func(doc string, decodifica bool) string {
+		t.Helper()
+		antes := contador.llamadas.Load()
+		salida, err := ord.OrdenarJSON(doc)
+		esperado, _ := sinCache.OrdenarJSON(doc)
+		if err != nil || salida != esperado {
+			status = "Fallido"
+			t.Errorf("OrdenarJSON(%s) = %q, %v; esperado %q", doc, salida, err, esperado)
+		}
+		if (contador.llamadas.Load() != antes) != decodifica {
+			status = "Fallido"
+			t.Errorf("OrdenarJSON(%s): decodificó = %v, esperado %v", doc, !decodifica, decodifica)
+		}
+		return salida
+	}
+	ordenar(documentos[0], true)
+	ordenar(documentos[0], false)
+	ordenar(documentos[1], true)
+	ordenar(documentos[0], false)
+	// La caché está llena: el tercer documento descarta el segundo, el menos usado.
+	ordenar(documentos[2], true)
+	ordenar(documentos[0], false)
+	salida := ordenar(documentos[1], true)
+
+	// AgregarJSON comparte la caché.
+	antes := contador.llamadas.Load()
+	buf, err := ord.AgregarJSON([]byte("x"), documentos[1])
+	if err != nil || string(buf) != "x"+salida || contador.llamadas.Load() != antes {
+		status = "Fallido"
+		t.Errorf("AgregarJSON = %q, %v; %d decodificaciones", buf, err, contador.llamadas.Load()-antes)
+	}
+	// Los errores no se guardan.
+	for i := 0; i < 2; i++ {
+		if _, err := ord.OrdenarJSON(`{"a": `); err == nil {
+			status = "Fallido"
+			t.Errorf("OrdenarJSON de un JSON inválido no falló en el intento %d", i)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestClavesInternadas_Reutilizadas(t *testing.T) {
+	input := `{"extra:\"comillas\"": "x", "cm:title": "t", "tanner:rut-cliente": "1-9"}`
+	expected := `{
+  "tanner:rut-cliente": "1-9",
+  "cm:title": "t",
+  "extra:\"comillas\"": "x"
+}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	var actual ResultadosObtenidos
+	status := "Completado"
+	for i := 0; i < 3; i++ {
+		registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con claves ya internadas")
+		got, err := ordenJson.OrdenarJSON(input)
+		if err != nil {
+			actual = ResultadosObtenidos{Error: err.Error()}
+			registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+			t.Fatalf("OrdenarJSON() error = %v", err)
+		}
+
+		actual = ResultadosObtenidos{
+			ClavesOrdenadas: extraerClavesJSON(got),
+			JsonSalida:      got,
+		}
+		if strings.TrimSpace(got) != expected {
+			status = "Fallido"
+			t.Errorf("Iteración %d: se esperaba\n%s\nse obtuvo\n%s", i, expected, got)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
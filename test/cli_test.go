@@ -0,0 +1,592 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// comandoCLI es el binario de ordena-json que compilan, una sola vez, las
+// pruebas que lo ejecutan como proceso. TestMain borra dir al terminar.
+var comandoCLI struct {
+	once sync.Once
+	dir  string
+	ruta string
+	err  error
+}
+
+// rutaOrdenaJSON compila ordena-json si todavía no se compiló y devuelve la
+// ruta del binario.
+func rutaOrdenaJSON(t *testing.T) string {
+	t.Helper()
+	comandoCLI.once.Do(func() {
+		if comandoCLI.dir, comandoCLI.err = os.MkdirTemp("", "ordena-json-"); comandoCLI.err != nil {
+			return
+		}
+		comandoCLI.ruta = filepath.Join(comandoCLI.dir, "ordena-json")
+		salida, err := exec.Command("go", "build", "-o", comandoCLI.ruta, "github.com/samuel/prueba-orden/cmd/ordena-json").CombinedOutput()
+		if err != nil {
+			comandoCLI.err = fmt.Errorf("%v: %s", err, salida)
+		}
+	})
+	if comandoCLI.err != nil {
+		t.Fatalf("Compilando ordena-json: %v", comandoCLI.err)
+	}
+	return comandoCLI.ruta
+}
+
+// ejecutarOrdenaJSON ejecuta ordena-json en dir con la entrada estándar
+// indicada y devuelve la salida estándar, la de errores y el código de salida.
+func ejecutarOrdenaJSON(t *testing.T, dir, entrada string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(rutaOrdenaJSON(t), args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(entrada)
+	var salida, errores bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &salida, &errores
+	var errSalida *exec.ExitError
+	if err := cmd.Run(); err != nil && !errors.As(err, &errSalida) {
+		t.Fatalf("Ejecutando ordena-json %v: %v", args, err)
+	}
+	return salida.String(), errores.String(), cmd.ProcessState.ExitCode()
+}
+
+// escribirArchivos crea en dir cada archivo de contenidos, con sus
+// directorios, y devuelve dir.
+func escribirArchivos(t *testing.T, dir string, contenidos map[string]string) string {
+	t.Helper()
+	for nombre, contenido := range contenidos {
+		ruta := filepath.Join(dir, nombre)
+		if err := os.MkdirAll(filepath.Dir(ruta), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(ruta, []byte(contenido), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// canonicoCLI devuelve la salida de ordena-json para documento con las
+// opciones por defecto: el resultado de OrdenarJSON con un salto de línea.
+func canonicoCLI(t *testing.T, documento string) string {
+	t.Helper()
+	ordenado, err := ordenJson.OrdenarJSON(documento)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ordenado + "\n"
+}
+
+func TestCLI_Write(t *testing.T) {
+	desordenado := `{"zzz": 1, "cm:title": "uno"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, desordenado)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "-write reescribe solo los archivos desordenados, con sus permisos y un respaldo con -backup-suffix"})
+
+	registradorGlobal.AgregarProceso(testName, "Reescribiendo con -write -backup-suffix .bak")
+	ordenado := canonicoCLI(t, `{"b": 1, "a": 2}`)
+	dir := escribirArchivos(t, t.TempDir(), map[string]string{"desordenado.json": desordenado, "ordenado.json": ordenado})
+	if err := os.Chmod(filepath.Join(dir, "desordenado.json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	antes := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filepath.Join(dir, "ordenado.json"), antes, antes); err != nil {
+		t.Fatal(err)
+	}
+	_, errores, codigo := ejecutarOrdenaJSON(t, dir, "", "-write", "-backup-suffix", ".bak", "desordenado.json", "ordenado.json")
+
+	status := "Completado"
+	reescrito, _ := os.ReadFile(filepath.Join(dir, "desordenado.json"))
+	if codigo != 1 || string(reescrito) != canonicoCLI(t, desordenado) {
+		status = "Fallido"
+		t.Errorf("-write = código %d (%s), contenido:\n%s", codigo, errores, reescrito)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "desordenado.json")); err != nil || info.Mode().Perm() != 0o600 {
+		status = "Fallido"
+		t.Errorf("No se conservaron los permisos: %v, %v", info.Mode(), err)
+	}
+	if respaldo, err := os.ReadFile(filepath.Join(dir, "desordenado.json.bak")); err != nil || string(respaldo) != desordenado {
+		status = "Fallido"
+		t.Errorf("Respaldo incorrecto: %q, %v", respaldo, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que un archivo ya ordenado no se toca")
+	if _, err := os.Stat(filepath.Join(dir, "ordenado.json.bak")); !errors.Is(err, os.ErrNotExist) {
+		status = "Fallido"
+		t.Errorf("Se respaldó un archivo ya ordenado: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "ordenado.json")); err != nil || !info.ModTime().Equal(antes) {
+		status = "Fallido"
+		t.Errorf("Se reescribió un archivo ya ordenado: %v, %v", info.ModTime(), err)
+	}
+	if _, errores, codigo := ejecutarOrdenaJSON(t, dir, "", "-write", "desordenado.json", "ordenado.json"); codigo != 0 {
+		status = "Fallido"
+		t.Errorf("-write sobre archivos ordenados = código %d (%s)", codigo, errores)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando -backup-suffix sin -write")
+	if _, _, codigo := ejecutarOrdenaJSON(t, dir, "", "-backup-suffix", ".bak", "desordenado.json", "ordenado.json"); codigo != 2 {
+		status = "Fallido"
+		t.Errorf("-backup-suffix sin -write = código %d", codigo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: string(reescrito)}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCLI_CodigosDeSalida(t *testing.T) {
+	desordenado := `{"zzz": 1, "cm:title": "uno"}`
+	archivos := map[string]string{
+		"ordenado.json":    canonicoCLI(t, `{"cm:title": "uno"}`),
+		"desordenado.json": desordenado,
+		"invalido.json":    `{"cm:title": `,
+		"sin-titulo.json":  `{"zzz": 1}`,
+		"reglas.yaml":      "requeridos: [cm:title]\n",
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		codigo  int
+		resumen string // Línea de -summary esperada; vacía si no se pide.
+	}{
+		{
+			name:    "ordenado",
+			args:    []string{"-summary", "ordenado.json"},
+			resumen: "procesados=1 sin-cambios=1 modificados=0 fallidos=0 json-invalido=0 validacion=0 otros=0",
+		},
+		{
+			name:    "desordenado sin -check",
+			args:    []string{"-summary", "desordenado.json"},
+			resumen: "procesados=1 sin-cambios=0 modificados=1 fallidos=0 json-invalido=0 validacion=0 otros=0",
+		},
+		{
+			name:    "desordenado con -check",
+			args:    []string{"-check", "-summary", "desordenado.json"},
+			codigo:  1,
+			resumen: "procesados=1 sin-cambios=0 modificados=1 fallidos=0 json-invalido=0 validacion=0 otros=0",
+		},
+		{
+			name:    "JSON inválido",
+			args:    []string{"-summary", "invalido.json"},
+			codigo:  2,
+			resumen: "procesados=1 sin-cambios=0 modificados=0 fallidos=1 json-invalido=1 validacion=0 otros=0",
+		},
+		{
+			name:   "archivo inexistente",
+			args:   []string{"no-existe.json"},
+			codigo: 2,
+		},
+		{
+			name:    "validación",
+			args:    []string{"-order-file", "reglas.yaml", "-summary", "sin-titulo.json"},
+			codigo:  3,
+			resumen: "procesados=1 sin-cambios=0 modificados=0 fallidos=1 json-invalido=0 validacion=1 otros=0",
+		},
+		{
+			name:    "lote con el mayor de los códigos",
+			args:    []string{"-out", "salida", "-order-file", "reglas.yaml", "-summary", "ordenado.json", "desordenado.json", "invalido.json", "sin-titulo.json"},
+			codigo:  3,
+			resumen: "procesados=4 sin-cambios=1 modificados=1 fallidos=2 json-invalido=1 validacion=1 otros=0",
+		},
+		{
+			name:   "argumentos incorrectos",
+			args:   []string{"-write", "-out", "salida", "ordenado.json"},
+			codigo: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.args)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: fmt.Sprintf("código %d; resumen %q", tt.codigo, tt.resumen)})
+
+			registradorGlobal.AgregarProceso(testName, "Ejecutando ordena-json")
+			dir := escribirArchivos(t, t.TempDir(), archivos)
+			_, errores, codigo := ejecutarOrdenaJSON(t, dir, "", tt.args...)
+
+			status := "Completado"
+			if codigo != tt.codigo {
+				status = "Fallido"
+				t.Errorf("Código de salida = %d, esperado %d (%s)", codigo, tt.codigo, errores)
+			}
+			resumen := ""
+			for _, linea := range strings.Split(errores, "\n") {
+				if strings.HasPrefix(linea, "procesados=") {
+					resumen = linea
+				}
+			}
+			if resumen != tt.resumen {
+				status = "Fallido"
+				t.Errorf("Resumen = %q, esperado %q", resumen, tt.resumen)
+			}
+
+			registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: errores}, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestCLI_DiffYCheck(t *testing.T) {
+	desordenado := `{"zzz": 1, "cm:title": "uno"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, desordenado)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "diff informa las claves quitadas, agregadas, cambiadas y movidas; -check falla solo con documentos fuera de orden"})
+
+	dir := escribirArchivos(t, t.TempDir(), map[string]string{
+		"antes.json":       `{"a": 1, "b": 2, "c": 3}`,
+		"despues.json":     `{"c": 3, "b": 5, "d": 4}`,
+		"desordenado.json": desordenado,
+		"ordenado.json":    canonicoCLI(t, desordenado),
+	})
+	status := "Completado"
+
+	registradorGlobal.AgregarProceso(testName, "Comparando dos documentos con diff")
+	salida, errores, codigo := ejecutarOrdenaJSON(t, dir, "", "diff", "antes.json", "despues.json")
+	esperada := "- a\n~ b\n+ d\n> b: posición 1 -> 2\n"
+	if codigo != 1 || salida != esperada {
+		status = "Fallido"
+		t.Errorf("diff = código %d (%s), salida:\n%s\nesperada:\n%s", codigo, errores, salida, esperada)
+	}
+	if salida, _, codigo := ejecutarOrdenaJSON(t, dir, "", "diff", "antes.json", "antes.json"); codigo != 0 || salida != "" {
+		status = "Fallido"
+		t.Errorf("diff de documentos iguales = código %d, salida %q", codigo, salida)
+	}
+	if _, _, codigo := ejecutarOrdenaJSON(t, dir, "", "diff", "antes.json"); codigo != 2 {
+		status = "Fallido"
+		t.Errorf("diff con un solo archivo = código %d", codigo)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Revisando archivos con -check")
+	if salida, errores, codigo := ejecutarOrdenaJSON(t, dir, "", "-check", "desordenado.json"); codigo != 1 || salida != "desordenado.json: zzz: posición 1 -> 2\n" {
+		status = "Fallido"
+		t.Errorf("-check desordenado = código %d (%s), salida %q", codigo, errores, salida)
+	}
+	if salida, errores, codigo := ejecutarOrdenaJSON(t, dir, "", "-check", "ordenado.json"); codigo != 0 || salida != "" {
+		status = "Fallido"
+		t.Errorf("-check ordenado = código %d (%s), salida %q", codigo, errores, salida)
+	}
+	contenido, _ := os.ReadFile(filepath.Join(dir, "desordenado.json"))
+	if string(contenido) != desordenado {
+		status = "Fallido"
+		t.Errorf("-check modificó el archivo: %s", contenido)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Revisando la entrada estándar con -check")
+	if _, _, codigo := ejecutarOrdenaJSON(t, dir, desordenado, "-check"); codigo != 1 {
+		status = "Fallido"
+		t.Errorf("-check desde stdin desordenado = código %d", codigo)
+	}
+	if _, _, codigo := ejecutarOrdenaJSON(t, dir, canonicoCLI(t, desordenado), "-check", "-"); codigo != 0 {
+		status = "Fallido"
+		t.Errorf("-check desde stdin ordenado = código %d", codigo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCLI_EntradaEstandarYArchivo(t *testing.T) {
+	documento := `{"zzz": 1, "cm:title": "uno"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documento)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Con la entrada estándar, \"-\" o un archivo se escribe el documento ordenado en la salida estándar"})
+
+	dir := escribirArchivos(t, t.TempDir(), map[string]string{"documento.json": documento})
+	esperado := canonicoCLI(t, documento)
+	status := "Completado"
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando desde la entrada estándar, \"-\" y un archivo")
+	var salida string
+	for _, args := range [][]string{nil, {"-"}, {"documento.json"}} {
+		var errores string
+		var codigo int
+		salida, errores, codigo = ejecutarOrdenaJSON(t, dir, documento, args...)
+		if codigo != 0 || salida != esperado {
+			status = "Fallido"
+			t.Errorf("ordena-json %v = código %d (%s), salida:\n%s", args, codigo, errores, salida)
+		}
+	}
+	if entradas, _ := os.ReadDir(dir); len(entradas) != 1 {
+		status = "Fallido"
+		t.Errorf("Con un solo archivo no se esperaban archivos nuevos: %v", entradas)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con -compact y -indent")
+	if compacto, _, codigo := ejecutarOrdenaJSON(t, dir, documento, "-compact"); codigo != 0 || compacto != `{"cm:title":"uno","zzz":1}`+"\n" {
+		status = "Fallido"
+		t.Errorf("-compact = código %d, salida %q", codigo, compacto)
+	}
+	if tabulado, _, codigo := ejecutarOrdenaJSON(t, dir, documento, "-indent", "\t"); codigo != 0 || tabulado != "{\n\t\"cm:title\": \"uno\",\n\t\"zzz\": 1\n}\n" {
+		status = "Fallido"
+		t.Errorf("-indent = código %d, salida %q", codigo, tabulado)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Informando un documento inválido en la entrada estándar")
+	if _, errores, codigo := ejecutarOrdenaJSON(t, dir, `{"a": `); codigo != 2 || !strings.Contains(errores, "<stdin>") {
+		status = "Fallido"
+		t.Errorf("Documento inválido = código %d, errores %q", codigo, errores)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: extraerClavesJSON(salida)}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// archivosBajo devuelve, ordenadas y con "/", las rutas relativas de los
+// archivos bajo dir.
+func archivosBajo(t *testing.T, dir string) []string {
+	t.Helper()
+	var rutas []string
+	err := filepath.WalkDir(dir, func(ruta string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relativa, err := filepath.Rel(dir, ruta)
+		rutas = append(rutas, filepath.ToSlash(relativa))
+		return err
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Fatal(err)
+	}
+	return rutas
+}
+
+func TestCLI_PatronesYRecursivo(t *testing.T) {
+	documento := `{"zzz": 1, "cm:title": "uno"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "docs/a.json, docs/sub/b.json, docs/sub/profundo/c.json y docs/notas.txt")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Los directorios, -recursive y los patrones glob con ** eligen los archivos .json y conservan las rutas relativas"})
+
+	tests := []struct {
+		name      string
+		args      []string
+		dirSalida string
+		esperados []string
+	}{
+		{name: "directorio", args: []string{"docs"}, dirSalida: "docs", esperados: []string{"a.json", "a.ordenado.json", "notas.txt", "sub/b.json", "sub/profundo/c.json"}},
+		{name: "directorio recursivo", args: []string{"-recursive", "-out", "salida", "docs"}, dirSalida: "salida", esperados: []string{"a.json", "sub/b.json", "sub/profundo/c.json"}},
+		{name: "patrón con **", args: []string{"-out", "salida", "docs/**/*.json"}, dirSalida: "salida", esperados: []string{"a.json", "sub/b.json", "sub/profundo/c.json"}},
+		{name: "patrón de un nivel", args: []string{"-out", "salida", "docs/*/b.json"}, dirSalida: "salida", esperados: []string{"sub/b.json"}},
+		{name: "patrones repetidos", args: []string{"-out", "salida", "docs/a.json", "docs/*.json"}, dirSalida: "salida", esperados: []string{"a.json"}},
+	}
+
+	status := "Completado"
+	for _, tt := range tests {
+		registradorGlobal.AgregarProceso(testName, "Ordenando en lote: "+tt.name)
+		dir := escribirArchivos(t, t.TempDir(), map[string]string{
+			"docs/a.json":              documento,
+			"docs/sub/b.json":          documento,
+			"docs/sub/profundo/c.json": documento,
+			"docs/notas.txt":           "no es JSON",
+		})
+		// Dos veces, para comprobar que no se vuelven a ordenar los resultados.
+		for i := 0; i < 2; i++ {
+			if _, errores, codigo := ejecutarOrdenaJSON(t, dir, "", tt.args...); codigo != 0 {
+				status = "Fallido"
+				t.Errorf("%s: código %d (%s)", tt.name, codigo, errores)
+			}
+		}
+		if obtenidos := archivosBajo(t, filepath.Join(dir, tt.dirSalida)); !slices.Equal(obtenidos, tt.esperados) {
+			status = "Fallido"
+			t.Errorf("%s: archivos = %v, esperados %v", tt.name, obtenidos, tt.esperados)
+		}
+		if tt.dirSalida == "salida" {
+			if contenido, _ := os.ReadFile(filepath.Join(dir, "salida", tt.esperados[0])); string(contenido) != canonicoCLI(t, documento) {
+				status = "Fallido"
+				t.Errorf("%s: resultado incorrecto:\n%s", tt.name, contenido)
+			}
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Informando un patrón sin coincidencias")
+	if _, errores, codigo := ejecutarOrdenaJSON(t, t.TempDir(), "", "-out", "salida", "docs/**/*.json"); codigo != 2 || !strings.Contains(errores, "no coincide") {
+		status = "Fallido"
+		t.Errorf("Patrón sin coincidencias = código %d, errores %q", codigo, errores)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCLI_Watch(t *testing.T) {
+	documento := `{"zzz": 1, "cm:title": "uno"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documento)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "-watch ordena los archivos que llegan, también en subdirectorios nuevos, y termina con código 0 al interrumpirlo"})
+
+	registradorGlobal.AgregarProceso(testName, "Vigilando entrada/ con -recursive -out salida")
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "entrada"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(rutaOrdenaJSON(t), "-watch", "-recursive", "-settle", "50ms", "-out", "salida", "entrada")
+	cmd.Dir = dir
+	var errores bytes.Buffer
+	cmd.Stderr = &errores
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	// llega escribe el archivo hasta que aparece su resultado, porque los
+	// eventos anteriores a que el comando empiece a vigilar se pierden.
+	llega := func(nombre string) bool {
+		origen := filepath.Join(dir, "entrada", nombre)
+		limite := time.Now().Add(10 * time.Second)
+		for time.Now().Before(limite) {
+			if err := os.MkdirAll(filepath.Dir(origen), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(origen, []byte(documento), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 10; i++ {
+				time.Sleep(50 * time.Millisecond)
+				if contenido, err := os.ReadFile(filepath.Join(dir, "salida", nombre)); err == nil && string(contenido) == canonicoCLI(t, documento) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	status := "Completado"
+	if !llega("uno.json") {
+		status = "Fallido"
+		t.Errorf("No se escribió salida/uno.json (%s)", errores.String())
+	}
+	registradorGlobal.AgregarProceso(testName, "Creando un subdirectorio mientras se vigila")
+	if !llega(filepath.Join("nuevo", "dos.json")) {
+		status = "Fallido"
+		t.Errorf("No se escribió salida/nuevo/dos.json (%s)", errores.String())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "entrada", "notas.txt"), []byte("no es JSON"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Interrumpiendo el comando")
+	time.Sleep(200 * time.Millisecond)
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		status = "Fallido"
+		t.Errorf("-watch terminó con %v (%s)", err, errores.String())
+	}
+	if obtenidos := archivosBajo(t, filepath.Join(dir, "salida")); !slices.Equal(obtenidos, []string{"nuevo/dos.json", "uno.json"}) {
+		status = "Fallido"
+		t.Errorf("Archivos en salida/ = %v", obtenidos)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: errores.String()}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCLI_ArchivoDeOrden(t *testing.T) {
+	documento := `{"a": 1, "zzz": 2, "cm:title": "uno"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documento)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Se usa el .ordenajson.yaml de un ancestro, salvo que -order-file indique otro archivo"})
+
+	dir := escribirArchivos(t, t.TempDir(), map[string]string{
+		".ordenajson.yaml":    "campos: [zzz]\n",
+		"reglas/otra.yaml":    "campos: [a]\n",
+		"reglas/mala.yaml":    "campo: [a]\n",
+		"proyecto/sub/x.json": documento,
+	})
+	sub := filepath.Join(dir, "proyecto", "sub")
+	status := "Completado"
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando desde un subdirectorio con el archivo en un ancestro")
+	salida, errores, codigo := ejecutarOrdenaJSON(t, sub, "", "x.json")
+	if claves := extraerClavesJSON(salida); codigo != 0 || !slices.Equal(claves, []string{"zzz", "a", "cm:title"}) {
+		status = "Fallido"
+		t.Errorf("Con .ordenajson.yaml = código %d (%s), claves %v", codigo, errores, claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con -order-file")
+	salida, errores, codigo = ejecutarOrdenaJSON(t, sub, "", "-order-file", filepath.Join(dir, "reglas", "otra.yaml"), "x.json")
+	if claves := extraerClavesJSON(salida); codigo != 0 || !slices.Equal(claves, []string{"a", "zzz", "cm:title"}) {
+		status = "Fallido"
+		t.Errorf("Con -order-file = código %d (%s), claves %v", codigo, errores, claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando un -order-file con claves desconocidas")
+	if _, errores, codigo := ejecutarOrdenaJSON(t, sub, "", "-order-file", filepath.Join(dir, "reglas", "mala.yaml"), "x.json"); codigo != 2 || !strings.Contains(errores, "campo") {
+		status = "Fallido"
+		t.Errorf("-order-file inválido = código %d, errores %q", codigo, errores)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: extraerClavesJSON(salida)}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCLI_Workers(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "40 archivos, 5 inválidos")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Con -workers el lote produce los mismos archivos, código y resumen que con un solo trabajador"})
+
+	archivos := map[string]string{}
+	for i := 0; i < 40; i++ {
+		contenido := fmt.Sprintf(`{"zzz": %d, "cm:title": "doc %d"}`, i, i)
+		if i%8 == 0 {
+			contenido = `{"cm:title": `
+		}
+		archivos[fmt.Sprintf("docs/%02d.json", i)] = contenido
+	}
+	resumenEsperado := "procesados=40 sin-cambios=0 modificados=35 fallidos=5 json-invalido=5 validacion=0 otros=0"
+
+	status := "Completado"
+	var resultados []string
+	for _, trabajadores := range []string{"1", "4", "0"} {
+		registradorGlobal.AgregarProceso(testName, "Ordenando el lote con -workers "+trabajadores)
+		dir := escribirArchivos(t, t.TempDir(), archivos)
+		_, errores, codigo := ejecutarOrdenaJSON(t, dir, "", "-workers", trabajadores, "-summary", "-out", "salida", "docs")
+		if codigo != 2 || !strings.Contains(errores, resumenEsperado) || strings.Count(errores, "ordena-json: docs") != 5 {
+			status = "Fallido"
+			t.Errorf("-workers %s = código %d, errores:\n%s", trabajadores, codigo, errores)
+		}
+		obtenidos := archivosBajo(t, filepath.Join(dir, "salida"))
+		if len(obtenidos) != 35 {
+			status = "Fallido"
+			t.Errorf("-workers %s escribió %d archivos", trabajadores, len(obtenidos))
+		}
+		if contenido, _ := os.ReadFile(filepath.Join(dir, "salida", "01.json")); string(contenido) != canonicoCLI(t, archivos["docs/01.json"]) {
+			status = "Fallido"
+			t.Errorf("-workers %s: salida/01.json incorrecto:\n%s", trabajadores, contenido)
+		}
+		if resultados != nil && !slices.Equal(obtenidos, resultados) {
+			status = "Fallido"
+			t.Errorf("-workers %s escribió %v, con un trabajador %v", trabajadores, obtenidos, resultados)
+		}
+		resultados = obtenidos
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/cmis"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestCMIS_OrdenarYEscribirPropiedades(t *testing.T) {
+	completo := `{
+		"cm:title": {"id": "cm:title", "type": "string", "cardinality": "single", "value": "Contrato"},
+		"cmis:secondaryObjectTypeIds": {"id": "cmis:secondaryObjectTypeIds", "type": "id", "cardinality": "multi", "value": ["P:cm:titled"]},
+		"tanner:rut-cliente": {"id": "tanner:rut-cliente", "type": "string", "cardinality": "single", "value": "1-9"},
+		"cmis:objectTypeId": {"id": "cmis:objectTypeId", "type": "id", "cardinality": "single", "value": "D:tanner:documento"}
+	}`
+	succinct := `{"cm:title": "Contrato", "cmis:secondaryObjectTypeIds": ["P:cm:titled"], "tanner:rut-cliente": "1-9", "cmis:objectTypeId": "D:tanner:documento"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, completo)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Formato completo y succinct producen el mismo documento, con los tipos primero"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando ambos formatos con el perfil CMIS")
+	ord := ordenJson.Nuevo(ordenJson.WithPerfil(cmis.Perfil(ordenJson.PerfilPorDefecto)))
+	status := "Completado"
+	var salidas []string
+	var original cmis.Propiedades
+	for _, datos := range []string{completo, succinct} {
+		props, err := cmis.LeerPropiedades([]byte(datos))
+		if err != nil {
+			t.Fatalf("LeerPropiedades() = %v", err)
+		}
+		if original == nil {
+			original = props
+		}
+		ordenado, err := cmis.Ordenar(ord, props)
+		if err != nil {
+			t.Fatalf("Ordenar() = %v", err)
+		}
+		salidas = append(salidas, ordenado)
+	}
+	esperado := "{\n" +
+		`  "cmis:objectTypeId": "D:tanner:documento",` + "\n" +
+		`  "cmis:secondaryObjectTypeIds": [` + "\n" +
+		`    "P:cm:titled"` + "\n" +
+		`  ],` + "\n" +
+		`  "tanner:rut-cliente": "1-9",` + "\n" +
+		`  "cm:title": "Contrato"` + "\n" +
+		"}"
+	if salidas[0] != esperado || salidas[1] != esperado {
+		status = "Fallido"
+		t.Errorf("Ordenar() = %q y %q; esperado %q", salidas[0], salidas[1], esperado)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Volviendo al formato completo")
+	escritas, err := cmis.EscribirPropiedades(salidas[0], original)
+	esperadas := `{"cmis:objectTypeId":{"id":"cmis:objectTypeId","type":"id","cardinality":"single","value":"D:tanner:documento"},` +
+		`"cmis:secondaryObjectTypeIds":{"id":"cmis:secondaryObjectTypeIds","type":"id","cardinality":"multi","value":["P:cm:titled"]},` +
+		`"tanner:rut-cliente":{"id":"tanner:rut-cliente","type":"string","cardinality":"single","value":"1-9"},` +
+		`"cm:title":{"id":"cm:title","type":"string","cardinality":"single","value":"Contrato"}}`
+	if err != nil || string(escritas) != esperadas {
+		status = "Fallido"
+		t.Errorf("EscribirPropiedades() = %s, %v", escritas, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando una propiedad de un aspecto no declarado")
+	props, _ := cmis.LeerPropiedades([]byte(`{"cmis:objectTypeId": "cmis:document", "cmis:secondaryObjectTypeIds": ["P:cm:titled"], "exif:pixelXDimension": 10}`))
+	var errTipo *cmis.ErrorTipoSecundario
+	if _, err := cmis.Ordenar(ord, props); !errors.As(err, &errTipo) || len(errTipo.Propiedades) != 1 {
+		status = "Fallido"
+		t.Errorf("Ordenar() con un aspecto faltante = %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salidas[0]}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/bench"
+	"github.com/samuel/prueba-orden/ordenJson/ordencodec"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// codecContador cuenta las llamadas que recibe antes de delegarlas.
+type codecContador struct {
+	ordenJson.Codec
+	llamadas atomic.Int64
+}
+
+func (c *codecContador) Marshal(v interface{}) ([]byte, error) {
+	c.llamadas.Add(1)
+	return c.Codec.Marshal(v)
+}
+
+func (c *codecContador) Unmarshal(datos []byte, v interface{}) error {
+	c.llamadas.Add(1)
+	return c.Codec.Unmarshal(datos, v)
+}
+
+func TestCodec_MismaSalidaQueEstandar(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "bench.Todos() y documentos inválidos")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Con jsoniter la salida y los errores coinciden con encoding/json"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando cada documento con ambos codecs")
+	contador := &codecContador{Codec: ordencodec.Jsoniter}
+	// WithPresupuesto evita la ruta plana, que no decodifica el documento.
+	estandar := ordenJson.Nuevo(ordenJson.WithPresupuesto(time.Hour))
+	alternativo := ordenJson.Nuevo(ordenJson.WithPresupuesto(time.Hour), ordenJson.WithCodec(contador))
+
+	documentos := []string{
+		`null`,
+		` {} `,
+		`{"aé\n": 1, "b": {"y": [1, "<&>", null], "x": true}, "aé\n": 2}`,
+		`{"cm:title": "doc", "zzz": [1, 2, {"k": "v\"}"}]}`,
+		`{"a": 1`,
+		`{"a": 1,}`,
+		`{"a": tru}`,
+		`{"a": [1, 2}`,
+		`{"a": 1} {}`,
+		`[1, 2]`,
+		``,
+	}
+	for _, c := range bench.Todos() {
+		documentos = append(documentos, c.Documentos...)
+	}
+
+	status := "Completado"
+	var ultimo string
+	for i, doc := range documentos {
+		esperado, errEsperado := estandar.OrdenarJSON(doc)
+		obtenido, err := alternativo.OrdenarJSON(doc)
+		var errJSON *ordenJson.ErrorJSONInvalido
+		if obtenido != esperado || (err == nil) != (errEsperado == nil) || (err != nil && !errors.As(err, &errJSON)) {
+			status = "Fallido"
+			t.Errorf("Documento %d: %q, %v; esperado %q, %v", i, obtenido, err, esperado, errEsperado)
+		}
+		ultimo = obtenido
+	}
+	if contador.llamadas.Load() == 0 {
+		status = "Fallido"
+		t.Errorf("WithCodec no usó el codec configurado")
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: ultimo}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCodec_EstablecerCodec(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, `{"b": [1], "a": 2}`)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "El Codec establecido lo usan los Ordenadores creados después"})
+
+	registradorGlobal.AgregarProceso(testName, "Estableciendo un codec por defecto y restaurándolo al terminar")
+	anterior := ordenJson.CodecActual()
+	defer ordenJson.EstablecerCodec(anterior)
+	antes := ordenJson.Nuevo(ordenJson.WithPresupuesto(time.Hour))
+	contador := &codecContador{Codec: ordenJson.CodecEstandar}
+	ordenJson.EstablecerCodec(contador)
+
+	status := "Completado"
+	if _, err := antes.OrdenarJSON(`{"b": [1], "a": 2}`); err != nil || contador.llamadas.Load() != 0 {
+		status = "Fallido"
+		t.Errorf("Ordenador previo: %v, %d llamadas al codec nuevo", err, contador.llamadas.Load())
+	}
+	salida, err := ordenJson.OrdenarJSON(`{"b": [1], "a": 2}`, ordenJson.WithPresupuesto(time.Hour))
+	if err != nil || contador.llamadas.Load() == 0 {
+		status = "Fallido"
+		t.Errorf("Ordenador nuevo: %v, %d llamadas al codec nuevo", err, contador.llamadas.Load())
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func BenchmarkCodec(b *testing.B) {
+	b.Run("estandar", func(b *testing.B) { bench.Ejecutar(b, bench.Grande()) })
+	b.Run("jsoniter", func(b *testing.B) { bench.Ejecutar(b, bench.Grande(), ordenJson.WithCodec(ordencodec.Jsoniter)) })
+}
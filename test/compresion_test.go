@@ -0,0 +1,99 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/samuel/prueba-orden/ordenJson/compresion"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// documentoMetadata genera un documento con valores que se repiten entre documentos.
+func documentoMetadata(gen *rand.Rand, i int) string {
+	estados := []string{"aprobado", "rechazado", "pendiente"}
+	tipos := []string{"contrato", "factura", "pagare"}
+	return fmt.Sprintf(`{
+		"cm:title": "Documento %d",
+		"tanner:tipo-documento": %q,
+		"tanner:rut-cliente": "%d-%d",
+		"tanner:estado-visado": %q,
+		"tanner:estado-vigencia": "vigente",
+		"tanner:origen": "Departamento Legal",
+		"tanner:fecha-carga": "2024-0%d-1%dT10:00:00.000Z",
+		"cm:versionType": "MAJOR",
+		"cm:versionLabel": "1.0"
+	}`, i, tipos[gen.IntN(len(tipos))], 10000000+gen.IntN(9000000), gen.IntN(10), estados[gen.IntN(len(estados))], 1+gen.IntN(9), gen.IntN(10))
+}
+
+func TestDiccionario_ComprimeYRecupera(t *testing.T) {
+	gen := rand.New(rand.NewPCG(1, 2))
+	muestras := make([]string, 200)
+	for i := range muestras {
+		muestras[i] = documentoMetadata(gen, i)
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, muestras[0])
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Documentos recuperados sin cambios y más chicos que con zstd sin diccionario"})
+
+	registradorGlobal.AgregarProceso(testName, "Construyendo el diccionario a partir de las muestras")
+	dic, err := compresion.NuevoDiccionario(nil, muestras)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("NuevoDiccionario() error = %v", err)
+	}
+	defer dic.Cerrar()
+
+	registradorGlobal.AgregarProceso(testName, "Comprimiendo documentos nuevos con y sin diccionario")
+	sinDiccionario, _ := zstd.NewWriter(nil, zstd.WithEncoderCRC(false))
+	defer sinDiccionario.Close()
+	recargado, err := compresion.Cargar(dic.ID(), dic.Bytes())
+	if err != nil {
+		t.Fatalf("Cargar() error = %v", err)
+	}
+	defer recargado.Cerrar()
+
+	status := "Completado"
+	var original, conDic, sinDic int
+	for i := 0; i < 50; i++ {
+		ordenado, err := ordenJson.OrdenarJSON(documentoMetadata(gen, 1000+i))
+		if err != nil {
+			t.Fatalf("OrdenarJSON() error = %v", err)
+		}
+		comprimido := dic.Comprimir([]byte(ordenado))
+		original += len(ordenado)
+		conDic += len(comprimido)
+		sinDic += len(sinDiccionario.EncodeAll([]byte(ordenado), nil))
+
+		recuperado, err := recargado.Descomprimir(comprimido)
+		if err != nil || string(recuperado) != ordenado {
+			status = "Fallido"
+			t.Fatalf("Documento %d no se recuperó (err=%v):\n%s", i, err, recuperado)
+		}
+	}
+	actual := ResultadosObtenidos{JsonSalida: fmt.Sprintf("original=%d con-diccionario=%d sin-diccionario=%d", original, conDic, sinDic)}
+	if conDic*2 > sinDic {
+		status = "Fallido"
+		t.Errorf("El diccionario debía reducir al menos a la mitad: %s", actual.JsonSalida)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Descomprimiendo con otro diccionario")
+	otro, _ := compresion.Cargar(0, []byte(`{"otro": "diccionario"}`))
+	defer otro.Cerrar()
+	if _, err := otro.Descomprimir(dic.Comprimir([]byte(`{}`))); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error al descomprimir con otro diccionario")
+	}
+	if _, err := compresion.Cargar(1, nil); !errors.Is(err, compresion.ErrDiccionarioVacio) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrDiccionarioVacio, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
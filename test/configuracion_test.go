@@ -0,0 +1,207 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/configuracion"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// escribirConfiguracion guarda contenido en un archivo .ordenajson.yaml de un
+// directorio temporal y devuelve su ruta.
+func escribirConfiguracion(t *testing.T, contenido string) string {
+	t.Helper()
+	ruta := filepath.Join(t.TempDir(), configuracion.ArchivoPorDefecto)
+	if err := os.WriteFile(ruta, []byte(contenido), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return ruta
+}
+
+func TestConfiguracion_CamposVacios(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		contiene string // Vacío si se espera un error al cargar.
+		omite    bool   // cm:title no debe aparecer en la salida.
+	}{
+		{name: "null sin comillas", yaml: "campos-vacios: null\n", contiene: `"cm:title": null`},
+		{name: "null entre comillas", yaml: "campos-vacios: \"null\"\n", contiene: `"cm:title": null`},
+		{name: "conservar", yaml: "campos-vacios: conservar\n", contiene: `"cm:title": ""`},
+		{name: "ausente", yaml: "estricto: false\n", omite: true},
+		{name: "valor desconocido", yaml: "campos-vacios: nul\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.yaml)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "campos-vacios se aplica o se rechaza al cargar"})
+
+			registradorGlobal.AgregarProceso(testName, "Cargando la configuración y ordenando un DocumentMetadata")
+			cfg, err := configuracion.Cargar(escribirConfiguracion(t, tt.yaml))
+			status := "Completado"
+			if tt.contiene == "" && !tt.omite {
+				if err == nil || !strings.Contains(err.Error(), "campos-vacios") {
+					status = "Fallido"
+					t.Errorf("Se esperaba un error de campos-vacios, se obtuvo %v", err)
+				}
+				registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+				registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+				return
+			}
+			if err != nil {
+				registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+				t.Fatalf("Cargar() error = %v", err)
+			}
+			salida, err := ordenJson.OrdenarDocumentoMetadata(ordenJson.DocumentMetadata{TipoDocumento: "contrato"}, cfg.Opciones()...)
+			if err != nil || (tt.omite && strings.Contains(salida, "cm:title")) || !strings.Contains(salida, tt.contiene) {
+				status = "Fallido"
+				t.Errorf("Salida inesperada (%v):\n%s", err, salida)
+			}
+
+			registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestConfiguracion_Opciones(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		input    string
+		expected string // Salida compacta esperada; vacía si se espera un error.
+		err      error
+		mensaje  string // Con err, texto que debe contener el mensaje.
+	}{
+		{name: "archivo vacío", yaml: "", input: `{"b": 1, "a": 2}`, expected: `{"b":1,"a":2}`},
+		{name: "campos", yaml: "campos: [b, a]\n", input: `{"a": 1, "c": 3, "b": 2}`, expected: `{"b":2,"a":1,"c":3}`},
+		{name: "requeridos", yaml: "requeridos: [x]\n", input: `{"a": 1}`, err: ordenJson.ErrCamposFaltantes},
+		{name: "estricto", yaml: "campos: [a]\nestricto: true\n", input: `{"a": 1, "b": 2}`, err: ordenJson.ErrClavesNoPermitidas},
+		{name: "tipos-documento", yaml: "tipos-documento: true\n", input: `{"monto": 1, "cm:title": "P", "tanner:rut-deudor": "1-9", "tanner:tipo-documento": "pagare-test"}`, expected: `{"tanner:tipo-documento":"pagare-test","tanner:rut-deudor":"1-9","monto":1,"cm:title":"P"}`},
+		{name: "claves-unicas", yaml: "claves-unicas: true\n", input: `{"a": 1, "a": 2}`, err: ordenJson.ErrClaveDuplicada},
+		{name: "fechas", yaml: "formatos-fecha: [\"02/01/2006\"]\ncampos-fecha: [fecha]\nformato-salida-fecha: \"2006-01-02\"\n", input: `{"fecha": "31/12/2024"}`, expected: `{"fecha":"2024-12-31"}`},
+		{name: "normalizar-fechas con un formato desconocido", yaml: "normalizar-fechas: true\ncampos-fecha: [fecha]\n", input: `{"fecha": "mañana"}`, err: ordenJson.ErrFechaInvalida},
+		{name: "validar-estados", yaml: "validar-estados: true\n", input: `{"tanner:estado-visado": "otro"}`, err: ordenJson.ErrValorNoPermitido},
+		{name: "valores-permitidos", yaml: "valores-permitidos:\n  estado: [a, b]\n", input: `{"estado": "c"}`, err: ordenJson.ErrValorNoPermitido},
+		{name: "vacios", yaml: "vacios: [\"N/A\"]\n", input: `{"a": "N/A", "b": 1}`, expected: `{"b":1}`},
+		{name: "renombres", yaml: "renombres:\n  titulo: cm:title\n", input: `{"zzz": 1, "titulo": "x"}`, expected: `{"cm:title":"x","zzz":1}`},
+		{name: "solo", yaml: "solo: [a]\n", input: `{"a": 1, "b": 2}`, expected: `{"a":1}`},
+		{name: "excluir", yaml: "excluir: [b]\n", input: `{"a": 1, "b": 2}`, expected: `{"a":1}`},
+		{name: "valores-por-defecto", yaml: "valores-por-defecto:\n  origen: crm\n", input: `{"a": 1}`, expected: `{"a":1,"origen":"crm"}`},
+		{name: "ordenar-listas", yaml: "ordenar-listas: [l]\n", input: `{"l": ["b", "a"]}`, expected: `{"l":["a","b"]}`},
+		{name: "idioma", yaml: "idioma: en\nrequeridos: [x]\n", input: `{"a": 1}`, err: ordenJson.ErrCamposFaltantes, mensaje: "missing required fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.yaml)
+			esperado := ResultadosEsperados{ClavesOrdenadas: extraerClavesJSON(tt.expected)}
+			if tt.err != nil {
+				esperado = ResultadosEsperados{TipoError: tt.err.Error()}
+			}
+			registradorGlobal.ConfigResultadoEsperado(testName, esperado)
+
+			registradorGlobal.AgregarProceso(testName, "Cargando la configuración y ordenando con sus opciones")
+			cfg, err := configuracion.Cargar(escribirConfiguracion(t, tt.yaml))
+			if err != nil {
+				registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+				t.Fatalf("Cargar() error = %v", err)
+			}
+			salida, err := ordenJson.OrdenarJSON(tt.input, cfg.Opciones()...)
+
+			status := "Completado"
+			actual := ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: extraerClavesJSON(salida)}
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) || !strings.Contains(fmt.Sprint(err), tt.mensaje) {
+					status = "Fallido"
+					t.Errorf("Se esperaba %v con %q, se obtuvo %v", tt.err, tt.mensaje, err)
+				}
+				if err != nil {
+					actual.Error = err.Error()
+				}
+			} else {
+				var compacta bytes.Buffer
+				if err != nil || json.Compact(&compacta, []byte(salida)) != nil || compacta.String() != tt.expected {
+					status = "Fallido"
+					t.Errorf("Salida incorrecta (%v).\nEsperado: %s\nObtenido: %s", err, tt.expected, salida)
+				}
+			}
+
+			registradorGlobal.GuardarResultado(testName, actual, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestConfiguracion_CargarYBuscar(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, configuracion.ArchivoPorDefecto)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Cargar rechaza claves desconocidas y usa el nombre del archivo; Buscar encuentra el archivo en los ancestros"})
+
+	status := "Completado"
+
+	registradorGlobal.AgregarProceso(testName, "Cargando archivos vacíos, con nombre, con claves desconocidas e inexistentes")
+	if cfg, err := configuracion.Cargar(escribirConfiguracion(t, "")); err != nil || cfg.Nombre != configuracion.ArchivoPorDefecto {
+		status = "Fallido"
+		t.Errorf("Cargar(vacío) = %+v, %v", cfg, err)
+	}
+	if cfg, err := configuracion.Cargar(escribirConfiguracion(t, "nombre: contratos\n")); err != nil || cfg.Nombre != "contratos" {
+		status = "Fallido"
+		t.Errorf("Cargar(nombre) = %+v, %v", cfg, err)
+	}
+	if _, err := configuracion.Cargar(escribirConfiguracion(t, "requerido: [x]\n")); err == nil || !strings.Contains(err.Error(), "requerido") {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error por la clave desconocida, se obtuvo %v", err)
+	}
+	if _, err := configuracion.Cargar(filepath.Join(t.TempDir(), "no-existe.yaml")); !errors.Is(err, fs.ErrNotExist) {
+		status = "Fallido"
+		t.Errorf("Se esperaba fs.ErrNotExist, se obtuvo %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Buscando el archivo desde un subdirectorio")
+	raiz, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	escribirArchivos(t, raiz, map[string]string{configuracion.ArchivoPorDefecto: "campos: [b, a]\n"})
+	profundo := filepath.Join(raiz, "a", "b")
+	if err := os.MkdirAll(profundo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	directorio, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(directorio) })
+	if err := os.Chdir(profundo); err != nil {
+		t.Fatal(err)
+	}
+	if ruta, err := configuracion.Buscar(); err != nil || ruta != filepath.Join(raiz, configuracion.ArchivoPorDefecto) {
+		status = "Fallido"
+		t.Errorf("Buscar() = %q, %v", ruta, err)
+	}
+	opts, err := configuracion.CargarOpciones("")
+	if salida, _ := ordenJson.OrdenarJSON(`{"a": 1, "b": 2}`, opts...); err != nil || !slices.Equal(extraerClavesJSON(salida), []string{"b", "a"}) {
+		status = "Fallido"
+		t.Errorf("CargarOpciones(\"\") no aplicó el archivo encontrado: %s, %v", salida, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
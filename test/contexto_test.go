@@ -0,0 +1,110 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/servidor"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// contextoTras es un contexto que se informa cancelado a partir de la
+// revisión número n, para abortar en medio de un documento sin depender del reloj.
+type contextoTras struct {
+	context.Context
+	n         int64
+	revisados atomic.Int64
+}
+
+func (c *contextoTras) Done() <-chan struct{} { return make(chan struct{}) }
+
+func (c *contextoTras) Err() error {
+	if c.revisados.Add(1) > c.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestOrdenarJSONCtx_Cancelacion(t *testing.T) {
+	input := jsonConMilesDeClaves(20000)
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "documento de 20000 claves")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorCancelado"})
+
+	status := "Completado"
+	registradorGlobal.AgregarProceso(testName, "Sin cancelar: misma salida que OrdenarJSON")
+	esperado, _ := ordenJson.OrdenarJSON(`{"b": 1, "cm:title": "x"}`)
+	got, err := ordenJson.OrdenarJSONCtx(context.Background(), `{"b": 1, "cm:title": "x"}`)
+	if err != nil || got != esperado {
+		status = "Fallido"
+		t.Errorf("OrdenarJSONCtx(Background) = %q, %v; esperado %q", got, err, esperado)
+	}
+
+	var actual ResultadosObtenidos
+	for _, revisiones := range []int64{0, 5, 100} {
+		registradorGlobal.AgregarProceso(testName, "Cancelando tras algunas revisiones")
+		ctx := &contextoTras{Context: context.Background(), n: revisiones}
+		_, err := ordenJson.OrdenarJSONCtx(ctx, input)
+		var errCancelado *ordenJson.ErrorCancelado
+		if !errors.As(err, &errCancelado) || !errors.Is(err, context.Canceled) {
+			status = "Fallido"
+			t.Errorf("Tras %d revisiones: error = %v; esperado *ErrorCancelado", revisiones, err)
+			continue
+		}
+		actual.Error = err.Error()
+		if revisiones == 0 && errCancelado.Etapa != "decodificación" {
+			status = "Fallido"
+			t.Errorf("Un contexto ya cancelado debe abortar al comenzar, no en %q", errCancelado.Etapa)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Flujo con el contexto cancelado")
+	cancelado, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	var salida bytes.Buffer
+	n, err := ordenJson.OrdenarFlujoCtx(cancelado, strings.NewReader("{\"a\": 1}\n{\"b\": 2}\n"), &salida)
+	if n != 0 || !errors.Is(err, context.Canceled) {
+		status = "Fallido"
+		t.Errorf("OrdenarFlujoCtx() = %d, %v; esperado 0, context.Canceled", n, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestServidor_SolicitudCancelada(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, `{"a": 1}`)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "503 si el contexto de la solicitud terminó"})
+
+	s, err := servidor.Nuevo(servidor.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+
+	registradorGlobal.AgregarProceso(testName, "POST /ordenar con la solicitud cancelada")
+	grabador := httptest.NewRecorder()
+	s.ServeHTTP(grabador, httptest.NewRequest(http.MethodPost, "/ordenar", strings.NewReader(`{"a": 1}`)).WithContext(ctx))
+
+	actual := ResultadosObtenidos{JsonSalida: grabador.Body.String()}
+	status := "Completado"
+	if grabador.Code != http.StatusServiceUnavailable {
+		status = "Fallido"
+		t.Errorf("Código = %d, esperado 503: %s", grabador.Code, grabador.Body.String())
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
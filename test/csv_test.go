@@ -0,0 +1,76 @@
+package test
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestExportarCSV(t *testing.T) {
+	docs := []ordenJson.DocumentMetadata{
+		{TipoDocumento: "contrato", CmTitle: "Contrato, marco", RUTCliente: "1-9"},
+		{TipoDocumento: "anexo", Observaciones: "línea 1\nlínea 2"},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, docs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: ordenJson.OrdenCampos})
+
+	registradorGlobal.AgregarProceso(testName, "Exportando dos documentos con ExportarCSV")
+	var salida strings.Builder
+	err := ordenJson.ExportarCSV(docs, &salida)
+	actual := ResultadosObtenidos{JsonSalida: salida.String()}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("ExportarCSV() error = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Leyendo el CSV generado")
+	filas, err := csv.NewReader(strings.NewReader(salida.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV inválido: %v", err)
+	}
+	status := "Completado"
+	if len(filas) != 3 {
+		t.Fatalf("Se esperaban 3 filas, se obtuvieron %d", len(filas))
+	}
+	actual.ClavesOrdenadas = filas[0]
+	if !reflect.DeepEqual(filas[0], ordenJson.OrdenCampos) {
+		status = "Fallido"
+		t.Errorf("Encabezado incorrecto. Esperado: %v, Obtenido: %v", ordenJson.OrdenCampos, filas[0])
+	}
+	columna := func(campo string) int {
+		for i, c := range filas[0] {
+			if c == campo {
+				return i
+			}
+		}
+		return -1
+	}
+	if filas[1][0] != "contrato" || filas[1][columna("cm:title")] != "Contrato, marco" || filas[1][columna("tanner:rut-cliente")] != "1-9" {
+		status = "Fallido"
+		t.Errorf("Primera fila incorrecta: %q", filas[1])
+	}
+	if filas[2][0] != "anexo" || filas[2][columna("tanner:observaciones")] != "línea 1\nlínea 2" || filas[2][columna("cm:title")] != "" {
+		status = "Fallido"
+		t.Errorf("Segunda fila incorrecta: %q", filas[2])
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Exportando con un campo requerido ausente")
+	err = ordenJson.ExportarCSV(docs, &strings.Builder{}, ordenJson.WithRequired("cm:title"))
+	var faltantes *ordenJson.ErrorCamposFaltantes
+	if !errors.As(err, &faltantes) || !strings.HasPrefix(err.Error(), "documento 1:") {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ordenJson.ErrorCamposFaltantes en el documento 1, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestCuarentena_Directorio(t *testing.T) {
+	input := `{"tanner:estado-visado": "listo", "tanner:fecha-carga": "ayer"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: cuarentena.TipoValidacion, CustomCheck: "Documento sin cambios y reporte con todos los problemas"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un documento inválido y enviándolo a cuarentena")
+	ordenador := ordenJson.Nuevo(ordenJson.WithValidarEstados(), ordenJson.WithNormalizarFechas())
+	_, err := ordenador.OrdenarJSON(input)
+	if err == nil {
+		t.Fatal("Se esperaba un error de validación")
+	}
+	registro := cuarentena.NuevoRegistro("../lote/doc.json", []byte(input), err, ordenador)
+
+	dir := t.TempDir()
+	errGuardar := cuarentena.Directorio(dir).Guardar([]byte(input), registro)
+
+	var actual ResultadosObtenidos
+	if errGuardar != nil {
+		actual.Error = errGuardar.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Guardar() error = %v", errGuardar)
+	}
+
+	status := "Completado"
+	// Los ".." del origen se descartan para no escribir fuera del directorio.
+	ruta := filepath.Join(dir, "lote", "doc.json")
+	guardado, err := os.ReadFile(ruta)
+	if err != nil || string(guardado) != input {
+		status = "Fallido"
+		t.Errorf("El documento debía guardarse sin cambios en %s: %q, %v", ruta, guardado, err)
+	}
+	contenido, err := os.ReadFile(ruta + cuarentena.SufijoReporte)
+	if err != nil {
+		status = "Fallido"
+		t.Fatalf("No se escribió el reporte: %v", err)
+	}
+	actual.JsonSalida = string(contenido)
+
+	var leido cuarentena.Registro
+	if err := json.Unmarshal(contenido, &leido); err != nil {
+		status = "Fallido"
+		t.Fatalf("El reporte no es JSON válido: %v", err)
+	}
+	if leido.Tipo != cuarentena.TipoValidacion || leido.Origen != "../lote/doc.json" || leido.Error == "" {
+		status = "Fallido"
+		t.Errorf("Registro incorrecto: %+v", leido)
+	}
+	if len(leido.Problemas) != 2 {
+		status = "Fallido"
+		t.Errorf("Se esperaban los dos problemas de validación, se obtuvieron %+v", leido.Problemas)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestCuarentena_Clasificar(t *testing.T) {
+	_, errJSON := ordenJson.OrdenarJSON(`{"cm:title": `)
+	_, errRequerido := ordenJson.OrdenarJSON(`{"cm:title": "t"}`, ordenJson.WithRequired("tanner:rut-cliente"))
+	casos := []struct {
+		err  error
+		tipo string
+	}{
+		{errJSON, cuarentena.TipoJSONInvalido},
+		{errRequerido, cuarentena.TipoValidacion},
+		{errors.New("tiempo agotado"), cuarentena.TipoOtro},
+	}
+	for _, c := range casos {
+		if got := cuarentena.Clasificar(c.err); got != c.tipo {
+			t.Errorf("Clasificar(%v) = %q, se esperaba %q", c.err, got, c.tipo)
+		}
+	}
+}
+
+func TestCuarentena_Reprocesar(t *testing.T) {
+	dir := t.TempDir()
+	destino := cuarentena.Directorio(dir)
+	estricto := ordenJson.Nuevo(ordenJson.WithValidarEstados())
+	documentos := map[string]string{
+		"a/estado.json": `{"tanner:estado-visado": "listo", "cm:title": "t"}`,
+		"roto.json":     `{"cm:title": `,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documentos)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Se recupera el documento corregido y el JSON inválido sigue en cuarentena"})
+
+	registradorGlobal.AgregarProceso(testName, "Enviando los documentos a cuarentena")
+	for origen, documento := range documentos {
+		_, err := estricto.OrdenarJSON(documento)
+		if err == nil {
+			t.Fatalf("%s: se esperaba un error", origen)
+		}
+		if err := destino.Guardar([]byte(documento), cuarentena.NuevoRegistro(origen, []byte(documento), err, estricto)); err != nil {
+			t.Fatalf("Guardar(%s) error = %v", origen, err)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Reprocesando sin la validación de estados")
+	recibidos := make(map[string]string)
+	reproceso, err := cuarentena.Reprocesar(dir, ordenJson.Nuevo(), func(origen string, ordenado []byte) error {
+		recibidos[origen] = string(ordenado)
+		return nil
+	})
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Reprocesar() error = %v", err)
+	}
+
+	status := "Completado"
+	if len(reproceso.Recuperados) != 1 || reproceso.Recuperados[0] != "a/estado.json" {
+		status = "Fallido"
+		t.Errorf("Recuperados incorrectos: %v", reproceso.Recuperados)
+	}
+	actual.JsonSalida = recibidos["a/estado.json"]
+	if keys := extraerClavesJSON(recibidos["a/estado.json"]); !reflect.DeepEqual(keys, []string{"tanner:estado-visado", "cm:title"}) {
+		status = "Fallido"
+		t.Errorf("El documento recuperado debía llegar ordenado, se obtuvo claves %v", keys)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a", "estado.json")); !errors.Is(err, os.ErrNotExist) {
+		status = "Fallido"
+		t.Errorf("El documento recuperado debía quitarse de la cuarentena: %v", err)
+	}
+	if len(reproceso.Pendientes) != 1 || reproceso.Pendientes[0].Origen != "roto.json" ||
+		reproceso.Pendientes[0].Tipo != cuarentena.TipoJSONInvalido || reproceso.Pendientes[0].Intentos != 2 {
+		status = "Fallido"
+		t.Errorf("Pendientes incorrectos: %+v", reproceso.Pendientes)
+	}
+	contenido, err := os.ReadFile(filepath.Join(dir, "roto.json"+cuarentena.SufijoReporte))
+	var leido cuarentena.Registro
+	if err != nil || json.Unmarshal(contenido, &leido) != nil || leido.Intentos != 2 {
+		status = "Fallido"
+		t.Errorf("El reporte del pendiente debía actualizarse: %s, %v", contenido, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
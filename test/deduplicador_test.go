@@ -0,0 +1,147 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestDeduplicador(t *testing.T) {
+	a := `{"cm:title": "Contrato", "tanner:tipo-documento": "contrato"}`
+	replay := `{
+		"tanner:tipo-documento": "contrato",
+		"cm:title": "Contrato"
+	}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, a)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Repetidos detectados y falsos positivos dentro de la tasa"})
+
+	registradorGlobal.AgregarProceso(testName, "Revisando un documento y su reenvío con otro orden")
+	dedup, err := ordenJson.NuevoDeduplicador(10000, 0.01)
+	if err != nil {
+		t.Fatalf("NuevoDeduplicador() error = %v", err)
+	}
+	status := "Completado"
+	if visto, err := dedup.Visto(a); err != nil || visto {
+		status = "Fallido"
+		t.Errorf("El primer documento no debía estar visto (err=%v)", err)
+	}
+	if visto, err := dedup.Visto(replay); err != nil || !visto {
+		status = "Fallido"
+		t.Errorf("El reenvío debía detectarse como repetido (err=%v)", err)
+	}
+	if visto, err := dedup.Visto(`{"cm:title": "t", "x-b": "1", "x-a": "2"}`); err != nil || visto {
+		status = "Fallido"
+		t.Errorf("El documento con claves fuera del perfil no debía estar visto (err=%v)", err)
+	}
+	if visto, err := dedup.Visto(`{"x-a": "2", "cm:title": "t", "x-b": "1"}`); err != nil || !visto {
+		status = "Fallido"
+		t.Errorf("El reenvío con las claves fuera del perfil en otro orden debía detectarse como repetido (err=%v)", err)
+	}
+	if _, err := dedup.Visto(`{"cm:title": `); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error con JSON inválido")
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Midiendo falsos positivos con documentos distintos")
+	falsos := 0
+	for i := 0; i < 9999; i++ {
+		if visto, _ := dedup.Visto(map[string]interface{}{"cm:title": fmt.Sprintf("doc-%d", i)}); visto {
+			falsos++
+		}
+	}
+	actual := ResultadosObtenidos{JsonSalida: fmt.Sprintf("falsos-positivos=%d cantidad=%d", falsos, dedup.Cantidad())}
+	if falsos > 200 {
+		status = "Fallido"
+		t.Errorf("Demasiados falsos positivos para una tasa de 1%%: %d", falsos)
+	}
+	if dedup.Cantidad() != 10001-falsos {
+		status = "Fallido"
+		t.Errorf("Cantidad incorrecta: %d", dedup.Cantidad())
+	}
+
+	dedup.Reiniciar()
+	if visto, _ := dedup.Visto(a); visto || dedup.Cantidad() != 1 {
+		status = "Fallido"
+		t.Errorf("Reiniciar debía olvidar los documentos registrados")
+	}
+	if _, err := ordenJson.NuevoDeduplicador(10, 1.5); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error con una tasa fuera de rango")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestDeduplicar(t *testing.T) {
+	docs := []string{
+		`{"cm:title": "Contrato", "tanner:tipo-documento": "contrato"}`,
+		`{"tanner:tipo-documento": "factura"}`,
+		"{\n  \"tanner:tipo-documento\": \"contrato\",\n  \"cm:title\": \"Contrato\"\n}",
+		`{"tanner:tipo-documento": "factura"}`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, docs[0])
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "2 únicos, duplicados 2->0 y 3->1"})
+
+	registradorGlobal.AgregarProceso(testName, "Deduplicando un lote")
+	res, err := ordenJson.Deduplicar(docs)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("Deduplicar() error = %v", err)
+	}
+	status := "Completado"
+	if len(res.Unicos) != 2 || res.Documentos != 4 || res.Distintos != 2 || len(res.Duplicados) != 2 ||
+		res.Duplicados[0].Indice != 2 || res.Duplicados[0].Original != 0 ||
+		res.Duplicados[1].Indice != 3 || res.Duplicados[1].Original != 1 || len(res.Duplicados[0].Hash) != 64 {
+		status = "Fallido"
+		t.Errorf("Resultado inesperado: %+v", res)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Deduplicando un flujo NDJSON")
+	var salida strings.Builder
+	entrada := strings.Join([]string{docs[0], docs[1], docs[0], docs[1], `{"tanner:tipo-documento": "boleta"}`}, "\n")
+	flujo, err := ordenJson.DeduplicarFlujo(strings.NewReader(entrada), &salida)
+	if err != nil {
+		status = "Fallido"
+		t.Errorf("DeduplicarFlujo() error = %v", err)
+	}
+	if lineas := strings.Count(salida.String(), "\n"); lineas != 3 || flujo.Unicos != nil || len(flujo.Duplicados) != 2 || flujo.Documentos != 5 {
+		status = "Fallido"
+		t.Errorf("Flujo inesperado (%d líneas): %+v\n%s", lineas, flujo, salida.String())
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Deduplicando documentos con las claves fuera del perfil en otro orden")
+	extra := []string{`{"cm:title": "t", "x-b": "1", "x-a": "2"}`, `{"x-a": "2", "cm:title": "t", "x-b": "1"}`}
+	iguales, _ := ordenJson.Iguales(extra[0], extra[1])
+	res, err = ordenJson.Deduplicar(extra)
+	if err != nil || !iguales || res.Distintos != 1 || len(res.Duplicados) != 1 {
+		status = "Fallido"
+		t.Errorf("Deduplicar debía coincidir con Iguales (%v): %+v (%v)", iguales, res, err)
+	}
+	salida.Reset()
+	flujo, err = ordenJson.DeduplicarFlujo(strings.NewReader(strings.Join(extra, "\n")), &salida)
+	if err != nil || flujo.Distintos != 1 || strings.Count(salida.String(), "\n") != 1 {
+		status = "Fallido"
+		t.Errorf("DeduplicarFlujo debía coincidir con Iguales: %+v (%v)\n%s", flujo, err, salida.String())
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando la posición del documento inválido")
+	_, err = ordenJson.DeduplicarFlujo(strings.NewReader(docs[0]+"\n"+docs[0]+"\n{"), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "documento 2") {
+		status = "Fallido"
+		t.Errorf("Se esperaba el error del documento 2, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida.String()}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestWithDefaults(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:origen": null, "tanner:estado-visado": "-", "tanner:estado-vigencia": "vencido"}`
+	expected := []string{"tanner:estado-visado", "tanner:estado-vigencia", "tanner:origen", "cm:title", "z-fuente"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con WithDefaults")
+	o := ordenJson.Nuevo(
+		ordenJson.WithDefaults(map[string]interface{}{"tanner:estado-vigencia": "vigente", "tanner:origen": "ingesta", "z-fuente": "batch"}),
+		ordenJson.WithDefaults(map[string]interface{}{"tanner:estado-visado": "pendiente"}),
+		ordenJson.WithVacio(ordenJson.MarcadoresVacios("-")),
+		ordenJson.WithRequired("tanner:estado-visado"),
+	)
+	salida, err := o.OrdenarJSON(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	claves := extraerClavesJSON(salida)
+	actual := ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+	status := "Completado"
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	for _, fragmento := range []string{`"pendiente"`, `"vencido"`, `"ingesta"`, `"batch"`} {
+		if !strings.Contains(salida, fragmento) {
+			status = "Fallido"
+			t.Errorf("La salida no contiene %s:\n%s", fragmento, salida)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Completando un DocumentMetadata")
+	salida, err = o.OrdenarDocumentoMetadata(ordenJson.DocumentMetadata{CmTitle: "Contrato"})
+	if err != nil || !strings.Contains(salida, `"tanner:estado-vigencia": "vigente"`) {
+		status = "Fallido"
+		t.Errorf("Se esperaba el valor por defecto en el DocumentMetadata: %s, %v", salida, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestDiff(t *testing.T) {
+	a := `{"extra": {"b": 1, "a": [1, 2, 3]}, "cm:title": "viejo", "tanner:rut-cliente": "1-9", "a/b": true}`
+	b := `{
+		"tanner:tipo-documento": "contrato",
+		"a/b": true,
+		"tanner:rut-cliente": "1-9",
+		"cm:title": "nuevo",
+		"extra": {"a": [1, 2.0], "b": 1.0, "c": null}
+	}`
+	expected := "+ /tanner:tipo-documento: \"contrato\"\n" +
+		"~ /cm:title: \"viejo\" -> \"nuevo\"\n" +
+		"- /extra/a/2: 3\n" +
+		"+ /extra/c: null\n"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, []string{a, b})
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Comparando los documentos con Diff")
+	d, err := ordenJson.Diff(a, b)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	actual := ResultadosObtenidos{JsonSalida: d.String()}
+	status := "Completado"
+	if d.String() != expected {
+		status = "Fallido"
+		t.Errorf("Diferencias incorrectas.\nEsperado:\n%s\nObtenido:\n%s", expected, d.String())
+	}
+	if d.Iguales() || d.Cambios[0].Tipo != ordenJson.CambioAgregado {
+		status = "Fallido"
+		t.Errorf("Cambios inesperados: %+v", d.Cambios)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que el orden y los espacios no cuentan")
+	d, err = ordenJson.Diff(`{"x": 1, "y": [true]}`, "{\"y\":[ true ],\n \"x\":1}")
+	if err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Se esperaban documentos iguales, se obtuvo %v, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que los números se comparan con precisión exacta, como en Iguales")
+	d, err = ordenJson.Diff(`{"n": 9007199254740993, "m": [1]}`, `{"n": 9007199254740992, "m": [1.0]}`)
+	if err != nil || d.String() != "~ /n: 9007199254740993 -> 9007199254740992\n" {
+		status = "Fallido"
+		t.Errorf("Se esperaba un cambio en /n, se obtuvo %q, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando la ruta escapada y el error ante un documento inválido")
+	d, _ = ordenJson.Diff(`{"a/b": 1}`, `{}`)
+	if len(d.Cambios) != 1 || d.Cambios[0].Ruta != "/a~1b" {
+		status = "Fallido"
+		t.Errorf("Ruta incorrecta: %+v", d.Cambios)
+	}
+	_, err = ordenJson.Diff(`{}`, "{\n\"a\": ")
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) || errJSON.Linea != 2 {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorJSONInvalido en la línea 2, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestIguales(t *testing.T) {
+	casos := []struct {
+		a, b     string
+		esperado bool
+	}{
+		{`{"a": 1, "b": [true, null, "x"]}`, "{\"b\":[true,null,\"x\"],\n\"a\":1.0}", true},
+		{`{"título": "á/b"}`, `{"t\u00edtulo": "\u00e1\/b"}`, true},
+		{`{"n": 12345678901234567}`, `{"n": 12345678901234568}`, false},
+		{`{"n": 1e3}`, `{"n": 1000}`, true},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`{"a": {"b": 1}}`, `{"a": {"b": 1, "c": 2}}`, false},
+		{`{"a": "1"}`, `{"a": 1}`, false},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, casos[0].a)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "igualdad sin importar orden ni escapes"})
+
+	registradorGlobal.AgregarProceso(testName, "Comparando pares de documentos con Iguales")
+	status := "Completado"
+	for _, caso := range casos {
+		got, err := ordenJson.Iguales(caso.a, caso.b)
+		if err != nil || got != caso.esperado {
+			status = "Fallido"
+			t.Errorf("Iguales(%s, %s) = %v, %v; se esperaba %v", caso.a, caso.b, got, err, caso.esperado)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error ante un documento inválido")
+	_, err := ordenJson.Iguales(`{}`, `{} {}`)
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorJSONInvalido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordencodec"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestErrorJSONInvalido_Ubicacion(t *testing.T) {
+	input := "{\n  \"cm:title\": \"t\",\n  \"tanner:origen\" \"x\"\n}"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorJSONInvalido en la línea 3"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con JSON inválido en la tercera línea")
+	_, err := ordenJson.OrdenarJSON(input)
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) {
+		status = "Fallido"
+		t.Fatalf("Se esperaba *ErrorJSONInvalido, se obtuvo %v", err)
+	}
+	if errJSON.Linea != 3 || errJSON.Columna < 2 || errJSON.Offset <= 0 {
+		status = "Fallido"
+		t.Errorf("Ubicación incorrecta: %+v", errJSON)
+	}
+	var errSintaxis *json.SyntaxError
+	if !errors.As(err, &errSintaxis) {
+		status = "Fallido"
+		t.Errorf("Se esperaba que el error envolviera un *json.SyntaxError, se obtuvo %T", errJSON.Err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestErroresTipados(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     interface{}
+		opts      []ordenJson.Option
+		target    interface{}
+		centinela error
+	}{
+		{name: "tipo no soportado", input: 123, target: new(*ordenJson.ErrorTipoNoSoportado), centinela: ordenJson.ErrTipoNoSoportado},
+		{name: "JSON truncado", input: `{"cm:title": "t"`, target: new(*ordenJson.ErrorJSONInvalido), centinela: ordenJson.ErrJSONInvalido},
+		{name: "no es un objeto", input: `[1, 2]`, target: new(*ordenJson.ErrorJSONInvalido), centinela: ordenJson.ErrJSONInvalido},
+		{name: "contenido posterior", input: `{} {}`, target: new(*ordenJson.ErrorJSONInvalido), centinela: ordenJson.ErrJSONInvalido},
+		{
+			name:      "clave duplicada",
+			input:     `{"cm:title": "a", "cm:title": "b"}`,
+			opts:      []ordenJson.Option{ordenJson.WithClavesUnicas()},
+			target:    new(*ordenJson.ErrorClaveDuplicada),
+			centinela: ordenJson.ErrClaveDuplicada,
+		},
+		{
+			name:      "campos faltantes",
+			input:     `{}`,
+			opts:      []ordenJson.Option{ordenJson.WithRequired("tanner:rut-cliente")},
+			target:    new(*ordenJson.ErrorCamposFaltantes),
+			centinela: ordenJson.ErrCamposFaltantes,
+		},
+		{
+			name:      "claves no permitidas",
+			input:     `{"x": 1}`,
+			opts:      []ordenJson.Option{ordenJson.WithStrict()},
+			target:    new(*ordenJson.ErrorClavesNoPermitidas),
+			centinela: ordenJson.ErrClavesNoPermitidas,
+		},
+		{
+			name:      "fecha inválida",
+			input:     `{"tanner:fecha-carga": 20231001}`,
+			opts:      []ordenJson.Option{ordenJson.WithNormalizarFechas()},
+			target:    new(*ordenJson.ErrorFechaInvalida),
+			centinela: ordenJson.ErrFechaInvalida,
+		},
+		{
+			name:      "valor no serializable",
+			input:     map[string]interface{}{"canal": make(chan int)},
+			target:    new(*ordenJson.ErrorValorNoSerializable),
+			centinela: ordenJson.ErrValorNoSerializable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.input)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: reflect.TypeOf(tt.target).Elem().String()})
+
+			registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON y verificando el tipo de error")
+			_, err := ordenJson.OrdenarJSON(tt.input, tt.opts...)
+
+			var actual ResultadosObtenidos
+			if err != nil {
+				actual.Error = err.Error()
+			}
+
+			status := "Completado"
+			if !errors.As(err, tt.target) {
+				status = "Fallido"
+				t.Errorf("Se esperaba %s, se obtuvo %T (%v)", reflect.TypeOf(tt.target).Elem(), err, err)
+			}
+			if envuelto := fmt.Errorf("documento 0: %w", err); !errors.Is(envuelto, tt.centinela) {
+				status = "Fallido"
+				t.Errorf("errors.Is(%v, %v) = false", envuelto, tt.centinela)
+			}
+
+			registradorGlobal.GuardarResultado(testName, actual, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestErrorRegla_Esquema(t *testing.T) {
+	perfil, err := ordenJson.PerfilDesdeEsquema([]byte(esquemaContrato))
+	if err != nil {
+		t.Fatalf("PerfilDesdeEsquema() error = %v", err)
+	}
+
+	_, err = ordenJson.OrdenarJSON(`{"tanner:rut-cliente": "rut"}`, ordenJson.WithPerfil(perfil))
+
+	var errRegla *ordenJson.ErrorRegla
+	if !errors.As(err, &errRegla) || errRegla.Campo != "tanner:rut-cliente" || errRegla.Regla != "pattern" {
+		t.Errorf("Se esperaba *ErrorRegla de pattern para tanner:rut-cliente, se obtuvo %v", err)
+	}
+
+	var errEsquema *ordenJson.ErrorEsquemaInvalido
+	_, err = ordenJson.PerfilDesdeEsquema([]byte(`{"properties": {"a": {"pattern": "("}}}`))
+	if !errors.As(err, &errEsquema) || errEsquema.Propiedad != "a" {
+		t.Errorf("Se esperaba *ErrorEsquemaInvalido para la propiedad a, se obtuvo %v", err)
+	}
+}
+
+func TestWithClavesUnicas(t *testing.T) {
+	input := "{\n  \"cm:title\": \"a\",\n  \"cm:title\": \"b\"\n}"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorClaveDuplicada en la línea 3"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando sin WithClavesUnicas: prevalece el último valor")
+	salida, err := ordenJson.OrdenarJSON(input)
+	status := "Completado"
+	if err != nil || !strings.Contains(salida, `"b"`) {
+		status = "Fallido"
+		t.Errorf("Sin la opción se esperaba el último valor: %q, %v", salida, err)
+	}
+
+	var actual ResultadosObtenidos
+	for _, codec := range []ordenJson.Codec{ordenJson.CodecEstandar, ordencodec.Jsoniter} {
+		registradorGlobal.AgregarProceso(testName, fmt.Sprintf("Ordenando con WithClavesUnicas y el codec %T", codec))
+		_, err := ordenJson.OrdenarJSON(input, ordenJson.WithClavesUnicas(), ordenJson.WithCodec(codec))
+		if err != nil {
+			actual.Error = err.Error()
+		}
+		var errJSON *ordenJson.ErrorJSONInvalido
+		var errDuplicada *ordenJson.ErrorClaveDuplicada
+		if !errors.As(err, &errJSON) || !errors.As(err, &errDuplicada) || !errors.Is(err, ordenJson.ErrJSONInvalido) {
+			status = "Fallido"
+			t.Fatalf("Se esperaba *ErrorClaveDuplicada dentro de *ErrorJSONInvalido, se obtuvo %v", err)
+		}
+		if errDuplicada.Clave != "cm:title" || errJSON.Linea != 3 {
+			status = "Fallido"
+			t.Errorf("Detalles incorrectos: %+v, %+v", errDuplicada, errJSON)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
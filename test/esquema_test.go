@@ -0,0 +1,142 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+const esquemaContrato = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "contrato",
+	"type": "object",
+	"properties": {
+		"tanner:rut-cliente": {"type": "string", "pattern": "^[0-9]{7,8}-[0-9kK]$"},
+		"tanner:tipo-documento": {"type": "string", "enum": ["contrato", "anexo"]},
+		"tanner:fecha-carga": {"type": "string", "format": "date-time"},
+		"cm:title": {"type": "string", "maxLength": 10},
+		"tanner:version": {"type": "integer"}
+	},
+	"required": ["tanner:rut-cliente"],
+	"additionalProperties": false
+}`
+
+func TestPerfilDesdeEsquema_Orden(t *testing.T) {
+	input := `{
+		"tanner:version": 2,
+		"cm:title": "Contrato",
+		"tanner:tipo-documento": "contrato",
+		"tanner:rut-cliente": "12345678-9"
+	}`
+
+	expectedOrder := []string{"tanner:rut-cliente", "tanner:tipo-documento", "cm:title", "tanner:version"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Construyendo perfil desde esquema")
+	perfil, err := ordenJson.PerfilDesdeEsquema([]byte(esquemaContrato))
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("PerfilDesdeEsquema() error = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con perfil de esquema")
+	got, err := ordenJson.OrdenarJSON(input, ordenJson.WithPerfil(perfil))
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	keys := extraerClavesJSON(got)
+	actual = ResultadosObtenidos{
+		ClavesOrdenadas: keys,
+		JsonSalida:      got,
+	}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if perfil.Nombre() != "contrato" {
+		status = "Fallido"
+		t.Errorf("Nombre de perfil esperado contrato, obtenido %s", perfil.Nombre())
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestPerfilDesdeEsquema_Validacion(t *testing.T) {
+	perfil, err := ordenJson.PerfilDesdeEsquema([]byte(esquemaContrato))
+	if err != nil {
+		t.Fatalf("PerfilDesdeEsquema() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantError string
+	}{
+		{name: "requerido ausente", input: `{"cm:title": "x"}`, wantError: "tanner:rut-cliente"},
+		{name: "clave adicional", input: `{"tanner:rut-cliente": "1234567-8", "otra": 1}`, wantError: "otra"},
+		{name: "patrón", input: `{"tanner:rut-cliente": "12.345.678-9"}`, wantError: "patrón"},
+		{name: "enum", input: `{"tanner:rut-cliente": "1234567-8", "tanner:tipo-documento": "factura"}`, wantError: "factura"},
+		{name: "formato de fecha", input: `{"tanner:rut-cliente": "1234567-8", "tanner:fecha-carga": "01/10/2023"}`, wantError: "date-time"},
+		{name: "largo máximo", input: `{"tanner:rut-cliente": "1234567-8", "cm:title": "Un título demasiado largo"}`, wantError: "cm:title"},
+		{name: "tipo entero", input: `{"tanner:rut-cliente": "1234567-8", "tanner:version": 1.5}`, wantError: "integer"},
+		{name: "documento válido", input: `{"tanner:rut-cliente": "1234567-K", "tanner:fecha-carga": "2023-10-01T00:00:00.000Z", "tanner:version": 3}`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.input)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: tt.wantError})
+
+			registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con perfil de esquema")
+			got, err := ordenJson.OrdenarJSON(tt.input, ordenJson.WithPerfil(perfil))
+
+			actual := ResultadosObtenidos{JsonSalida: got}
+			if err != nil {
+				actual.Error = err.Error()
+			}
+
+			status := "Completado"
+			switch {
+			case tt.wantError == "" && err != nil:
+				status = "Fallido"
+				t.Errorf("Error inesperado: %v", err)
+			case tt.wantError != "" && err == nil:
+				status = "Fallido"
+				t.Errorf("Se esperaba un error que mencione %q, pero no se produjo ninguno", tt.wantError)
+			case tt.wantError != "" && !strings.Contains(err.Error(), tt.wantError):
+				status = "Fallido"
+				t.Errorf("Se esperaba un error que mencione %q, se obtuvo %v", tt.wantError, err)
+			}
+
+			registradorGlobal.GuardarResultado(testName, actual, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestPerfilDesdeEsquema_Invalido(t *testing.T) {
+	for _, esquema := range []string{`no es json`, `{"title": "sin propiedades"}`, `{"properties": {"a": {"pattern": "("}}}`} {
+		if _, err := ordenJson.PerfilDesdeEsquema([]byte(esquema)); err == nil {
+			t.Errorf("Se esperaba error para el esquema %s", esquema)
+		}
+	}
+}
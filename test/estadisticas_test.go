@@ -0,0 +1,127 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestEstadisticas_Resumen(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "1000 documentos generados")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Tasas de llenado, valores distintos y rango de fechas por campo"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando el corpus desde varias goroutines")
+	est := ordenJson.NuevasEstadisticas(ordenJson.WithNormalizarFechas())
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < 1000; i += 4 {
+				doc := map[string]interface{}{
+					"tanner:rut-cliente":   fmt.Sprintf("%d-%d", 10000000+i, i%10),
+					"tanner:estado-visado": []string{"aprobado", "rechazado"}[i%2],
+					"tanner:fecha-carga":   fmt.Sprintf("2024-01-%02d", 1+i%28),
+				}
+				if i%4 == 0 {
+					doc["cm:title"] = ""
+				}
+				if _, err := est.OrdenarJSON(doc); err != nil {
+					t.Errorf("OrdenarJSON() error = %v", err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if _, err := est.OrdenarJSON(`{"cm:title": `); err == nil {
+		t.Errorf("Se esperaba un error con JSON inválido")
+	}
+
+	resumen := est.Resumen()
+	salida, _ := resumen.JSON()
+	actual := ResultadosObtenidos{JsonSalida: string(salida)}
+	status := "Completado"
+
+	campos := make(map[string]ordenJson.ResumenCampo)
+	var orden []string
+	for _, c := range resumen.Campos {
+		campos[c.Campo] = c
+		orden = append(orden, c.Campo)
+	}
+	esperado := []string{"tanner:rut-cliente", "tanner:estado-visado", "tanner:fecha-carga", "cm:title"}
+	if resumen.Documentos != 1000 || fmt.Sprint(orden) != fmt.Sprint(esperado) {
+		status = "Fallido"
+		t.Errorf("Resumen incorrecto: %s", salida)
+	}
+	if rut := campos["tanner:rut-cliente"]; rut.TasaLlenado != 1 || rut.ValoresDistintos < 960 || rut.ValoresDistintos > 1040 {
+		status = "Fallido"
+		t.Errorf("Estadística de RUT incorrecta: %+v", rut)
+	}
+	if visado := campos["tanner:estado-visado"]; visado.ValoresDistintos != 2 {
+		status = "Fallido"
+		t.Errorf("Estado de visado debía tener 2 valores distintos: %+v", visado)
+	}
+	if titulo := campos["cm:title"]; titulo.Presentes != 250 || titulo.Llenos != 0 || titulo.TasaLlenado != 0 {
+		status = "Fallido"
+		t.Errorf("Estadística de título incorrecta: %+v", titulo)
+	}
+	if fecha := campos["tanner:fecha-carga"]; fecha.FechaMinima != "2024-01-01T00:00:00.000Z" || fecha.FechaMaxima != "2024-01-28T00:00:00.000Z" {
+		status = "Fallido"
+		t.Errorf("Rango de fechas incorrecto: %+v", fecha)
+	}
+	var documento map[string]interface{}
+	if err := json.Unmarshal(salida, &documento); err != nil || documento["documentos"] != float64(1000) {
+		status = "Fallido"
+		t.Errorf("El resumen no es un documento JSON válido: %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestEstadisticas_Filtros(t *testing.T) {
+	input := `{"cm:title": "t", "tanner:fecha-carga": "2023-01-01", "x-extra": "1"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Estadísticas de todos los campos, también los que WithExclude y WithOnly omiten de la salida"})
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+	for _, opt := range []ordenJson.Option{ordenJson.WithExclude("tanner:fecha-carga"), ordenJson.WithOnly("cm:title")} {
+		registradorGlobal.AgregarProceso(testName, "Ordenando con un filtro de claves")
+		est := ordenJson.NuevasEstadisticas(opt)
+		salida, err := est.OrdenarJSON(input)
+		if err != nil {
+			status = "Fallido"
+			t.Fatalf("OrdenarJSON() error = %v", err)
+		}
+		actual.JsonSalida = salida
+		if !json.Valid([]byte(salida)) || extraerClavesJSON(salida)[0] != "cm:title" {
+			status = "Fallido"
+			t.Errorf("Salida incorrecta:\n%s", salida)
+		}
+		campos := make(map[string]ordenJson.ResumenCampo)
+		for _, c := range est.Resumen().Campos {
+			campos[c.Campo] = c
+		}
+		if fecha := campos["tanner:fecha-carga"]; fecha.Presentes != 1 || fecha.FechaMinima != "2023-01-01T00:00:00.000Z" {
+			status = "Fallido"
+			t.Errorf("Estadística de fecha incorrecta: %+v", fecha)
+		}
+		if titulo := campos["cm:title"]; titulo.Llenos != 1 {
+			status = "Fallido"
+			t.Errorf("Estadística de título incorrecta: %+v", titulo)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,496 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// contratoConOrden declara la prioridad de algunos campos con la etiqueta
+// orden; el resto sigue el perfil.
+type contratoConOrden struct {
+	Titulo      string `json:"cm:title"`
+	Numero      string `json:"numero" orden:"2"`
+	Tipo        string `json:"tanner:tipo-documento"`
+	Monto       string `json:"monto,omitempty" orden:"1"`
+	Moneda      string `json:"moneda" orden:"2"`
+	Notas       string `json:"notas"`
+	Ignorado    string `json:"-"`
+	interno     string
+	Descripcion string
+}
+
+func TestOrdenarStruct_EtiquetaOrden(t *testing.T) {
+	input := contratoConOrden{
+		Titulo:      "Contrato",
+		Numero:      "42",
+		Tipo:        "contrato",
+		Monto:       "1000",
+		Moneda:      "CLP",
+		Notas:       "n",
+		Ignorado:    "x",
+		interno:     "y",
+		Descripcion: "z",
+	}
+	expected := []string{"monto", "numero", "moneda", "tanner:tipo-documento", "cm:title", "notas"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un struct con etiquetas orden")
+	salida, err := ordenJson.OrdenarStruct(&input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarStruct() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que un struct sin etiquetas orden sigue el perfil")
+	metadata := ordenJson.DocumentMetadata{CmTitle: "t", TipoDocumento: "contrato", Observaciones: "o"}
+	porValor, err := ordenJson.OrdenarStruct(metadata)
+	esperada, _ := ordenJson.OrdenarDocumentoMetadata(metadata)
+	if err != nil || porValor != esperada {
+		status = "Fallido"
+		t.Errorf("OrdenarStruct difiere de OrdenarDocumentoMetadata:\n%s\n%s (%v)", porValor, esperada, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando WithStrict con los campos de etiqueta orden")
+	if _, err := ordenJson.OrdenarStruct(input, ordenJson.WithStrict()); !errors.Is(err, ordenJson.ErrClavesNoPermitidas) {
+		status = "Fallido"
+		t.Errorf("Se esperaba rechazar solo notas, se obtuvo %v", err)
+	} else if errClaves := new(ordenJson.ErrorClavesNoPermitidas); errors.As(err, &errClaves) && !reflect.DeepEqual(errClaves.Claves, []string{"notas"}) {
+		status = "Fallido"
+		t.Errorf("Claves rechazadas incorrectas: %v", errClaves.Claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando los errores de tipo y de etiqueta")
+	if _, err := ordenJson.OrdenarStruct("texto"); !errors.Is(err, ordenJson.ErrTipoNoSoportado) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrTipoNoSoportado, se obtuvo %v", err)
+	}
+	type etiquetaInvalida struct {
+		A string `json:"a" orden:"primero"`
+	}
+	if _, err := ordenJson.OrdenarStruct(etiquetaInvalida{A: "a"}); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error por la etiqueta orden inválida")
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// condicionesPago es un struct anidado de facturaTipada.
+type condicionesPago struct {
+	Plazo   int    `json:"plazo"`
+	Moneda  string `json:"moneda"`
+	Cuotas  []int  `json:"cuotas"`
+	Vencida bool   `json:"vencida"`
+}
+
+// facturaTipada tiene campos de distintos tipos, además de string.
+type facturaTipada struct {
+	Tipo        string          `json:"tanner:tipo-documento"`
+	Monto       int64           `json:"monto"`
+	Tasa        float64         `json:"tasa"`
+	Descuento   float32         `json:"descuento"`
+	Pagada      bool            `json:"pagada"`
+	Folio       uint            `json:"folio"`
+	Etiquetas   []string        `json:"etiquetas"`
+	Emision     time.Time       `json:"emision"`
+	Condiciones condicionesPago `json:"condiciones"`
+	Sin         condicionesPago `json:"sin-condiciones"`
+	Adjunto     []byte          `json:"adjunto"`
+	Extra       map[string]int  `json:"extra"`
+}
+
+func TestOrdenarStruct_TiposNoTexto(t *testing.T) {
+	input := facturaTipada{
+		Tipo:        "factura",
+		Monto:       9007199254740993,
+		Tasa:        0.5,
+		Descuento:   0.1,
+		Etiquetas:   []string{"a", ""},
+		Emision:     time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Condiciones: condicionesPago{Plazo: 30, Cuotas: []int{0, 1}},
+		Adjunto:     []byte("hola"),
+		Extra:       map[string]int{"x": 1},
+	}
+	expected := []string{"tanner:tipo-documento", "adjunto", "condiciones", "cuotas", "plazo", "descuento", "emision", "etiquetas", "monto", "tasa"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un struct con números, booleanos, slices, fechas y structs anidados")
+	salida, err := ordenJson.OrdenarStruct(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarStruct() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el formato de cada valor")
+	for _, fragmento := range []string{
+		`"monto": 9007199254740993`,
+		`"tasa": 0.5`,
+		`"descuento": 0.1`,
+		`"emision": "2024-03-01T12:00:00.000Z"`,
+		`"adjunto": "aG9sYQ=="`,
+		`"plazo": 30`,
+		"\"etiquetas\": [\n    \"a\",\n    \"\"\n  ]",
+		"\"cuotas\": [\n      0,\n      1\n    ]",
+	} {
+		if !strings.Contains(salida, fragmento) {
+			status = "Fallido"
+			t.Errorf("No se encontró %q en la salida:\n%s", fragmento, salida)
+		}
+	}
+	for _, omitido := range []string{"pagada", "folio", "vencida", "moneda", "extra", "sin-condiciones"} {
+		if strings.Contains(salida, `"`+omitido+`"`) {
+			status = "Fallido"
+			t.Errorf("Se esperaba omitir %s:\n%s", omitido, salida)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// contactoVacios tiene campos vacíos con y sin omitempty.
+type contactoVacios struct {
+	Nombre   string   `json:"nombre"`
+	Correo   string   `json:"correo,omitempty"`
+	Edad     int      `json:"edad"`
+	Activo   bool     `json:"activo,omitempty"`
+	Telefono []string `json:"telefonos"`
+}
+
+func TestOrdenarStruct_CamposVacios(t *testing.T) {
+	input := contactoVacios{Nombre: "Ana"}
+	casos := []struct {
+		politica ordenJson.CamposVacios
+		esperado string
+	}{
+		{"", `{"nombre":"Ana"}`},
+		{ordenJson.CamposVaciosOmitir, `{"nombre":"Ana"}`},
+		{ordenJson.CamposVaciosConservar, `{"activo":false,"correo":"","edad":0,"nombre":"Ana","telefonos":null}`},
+		{ordenJson.CamposVaciosNull, `{"activo":null,"correo":null,"edad":null,"nombre":"Ana","telefonos":null}`},
+		{ordenJson.CamposVaciosOmitempty, `{"edad":0,"nombre":"Ana","telefonos":null}`},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Cada política de WithCamposVacios omite, conserva o anula los campos vacíos"})
+
+	status := "Completado"
+	for _, caso := range casos {
+		registradorGlobal.AgregarProceso(testName, "Ordenando con la política "+string(caso.politica))
+		salida, err := ordenJson.OrdenarStruct(input, ordenJson.WithCamposVacios(caso.politica))
+		if err != nil {
+			status = "Fallido"
+			t.Errorf("OrdenarStruct(%q) error = %v", caso.politica, err)
+			continue
+		}
+		var compacta bytes.Buffer
+		json.Compact(&compacta, []byte(salida))
+		if compacta.String() != caso.esperado {
+			status = "Fallido"
+			t.Errorf("Política %q:\nEsperado: %s\nObtenido: %s", caso.politica, caso.esperado, compacta.String())
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Combinando CamposVaciosConservar con WithVacio en un DocumentMetadata")
+	salida, err := ordenJson.OrdenarDocumentoMetadata(ordenJson.DocumentMetadata{CmTitle: "N/A", TipoDocumento: "contrato"},
+		ordenJson.WithCamposVacios(ordenJson.CamposVaciosNull), ordenJson.WithVacio(ordenJson.MarcadoresVacios("N/A")))
+	if err != nil || !strings.Contains(salida, `"cm:title": null`) || !strings.Contains(salida, `"tanner:observaciones": null`) {
+		status = "Fallido"
+		t.Errorf("Se esperaban los campos vacíos como null: %s (%v)", salida, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// solicitudPunteros distingue los datos no informados (nil) de los
+// informados vacíos.
+type solicitudPunteros struct {
+	Titulo      *string    `json:"cm:title"`
+	Descripcion *string    `json:"cm:description"`
+	Vence       *time.Time `json:"tanner:fecha-termino-vigencia"`
+	Carga       *time.Time `json:"tanner:fecha-carga"`
+	Cuotas      *int       `json:"cuotas"`
+}
+
+// nodoCiclico puede contenerse a sí mismo a través de Siguiente.
+type nodoCiclico struct {
+	Nombre    string       `json:"nombre"`
+	Siguiente *nodoCiclico `json:"siguiente"`
+}
+
+func TestOrdenarStruct_Punteros(t *testing.T) {
+	vacio, cero := "", 0
+	vence := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	input := solicitudPunteros{Titulo: &vacio, Vence: &vence, Cuotas: &cero}
+	expected := []string{"tanner:fecha-termino-vigencia", "cm:title", "cuotas"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un struct con punteros nil y punteros a valores vacíos")
+	salida, err := ordenJson.OrdenarStruct(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarStruct() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	for _, fragmento := range []string{`"cm:title": ""`, `"cuotas": 0`, `"tanner:fecha-termino-vigencia": "2025-12-31T00:00:00.000Z"`} {
+		if !strings.Contains(salida, fragmento) {
+			status = "Fallido"
+			t.Errorf("No se encontró %q en la salida:\n%s", fragmento, salida)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Escribiendo los punteros nil como null")
+	nulos, err := ordenJson.OrdenarStruct(input, ordenJson.WithCamposVacios(ordenJson.CamposVaciosNull))
+	if err != nil || !strings.Contains(nulos, `"cm:description": null`) || !strings.Contains(nulos, `"cm:title": ""`) {
+		status = "Fallido"
+		t.Errorf("Se esperaba null solo en los punteros nil: %s (%v)", nulos, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error de un valor que se contiene a sí mismo")
+	nodo := &nodoCiclico{Nombre: "a"}
+	nodo.Siguiente = &nodoCiclico{Nombre: "b", Siguiente: nodo}
+	_, err = ordenJson.OrdenarStruct(nodo)
+	var errValor *ordenJson.ErrorValorNoSerializable
+	if !errors.As(err, &errValor) || errValor.Campo != "siguiente.siguiente" {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorValorNoSerializable en siguiente.siguiente, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// CamposCm y CamposTanner son structs comunes que se embeben en los tipos de
+// metadatos de cada familia de documentos.
+type CamposCm struct {
+	Titulo      string `json:"cm:title"`
+	Descripcion string `json:"cm:description"`
+}
+
+type CamposTanner struct {
+	Tipo   string `json:"tanner:tipo-documento"`
+	RUT    string `json:"tanner:rut-cliente" orden:"1"`
+	Origen string `json:"tanner:origen"`
+}
+
+// facturaEmbebida se arma con structs embebidos; Descripcion redefine la de
+// CamposCm y Auditoria va por puntero.
+type facturaEmbebida struct {
+	CamposCm
+	*CamposTanner
+	Auditoria   *CamposAuditoria `json:"auditoria"`
+	Folio       int              `json:"folio" orden:"2"`
+	Descripcion string           `json:"cm:description"`
+	Anidado     CamposCm         `json:"anidado"`
+}
+
+type CamposAuditoria struct {
+	Usuario string `json:"usuario"`
+}
+
+func TestOrdenarStruct_Embebidos(t *testing.T) {
+	input := facturaEmbebida{
+		CamposCm:     CamposCm{Titulo: "Factura", Descripcion: "oculta"},
+		CamposTanner: &CamposTanner{Tipo: "factura", RUT: "1-9"},
+		Folio:        7,
+		Descripcion:  "propia",
+		Anidado:      CamposCm{Titulo: "interno"},
+	}
+	expected := []string{"tanner:rut-cliente", "folio", "tanner:tipo-documento", "cm:title", "cm:description", "anidado", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un struct con structs embebidos por valor y por puntero")
+	salida, err := ordenJson.OrdenarStruct(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarStruct() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	if !strings.Contains(salida, `"cm:description": "propia"`) {
+		status = "Fallido"
+		t.Errorf("El campo propio debía ganar al embebido:\n%s", salida)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con el struct embebido por puntero en nil")
+	input.CamposTanner = nil
+	sinTanner, err := ordenJson.OrdenarStruct(&input, ordenJson.WithCamposVacios(ordenJson.CamposVaciosNull))
+	if err != nil || !strings.Contains(sinTanner, `"tanner:rut-cliente": null`) || !strings.Contains(sinTanner, `"auditoria": null`) {
+		status = "Fallido"
+		t.Errorf("Se esperaban los campos del puntero nil como null: %s (%v)", sinTanner, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// contratoFechas declara sus fechas como time.Time en lugar de cadenas.
+type contratoFechas struct {
+	Tipo  string     `json:"tanner:tipo-documento"`
+	Carga time.Time  `json:"tanner:fecha-carga"`
+	Vence *time.Time `json:"tanner:fecha-termino-vigencia"`
+}
+
+func TestOrdenarStruct_FormatoSalidaFecha(t *testing.T) {
+	zona := time.FixedZone("CLT", -3*60*60)
+	vence := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	input := contratoFechas{Tipo: "contrato", Carga: time.Date(2024, 5, 6, 7, 8, 9, 123456789, zona), Vence: &vence}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Las fechas time.Time se escriben con FormatoFechaCanonico o el layout de WithFormatoSalidaFecha"})
+
+	status := "Completado"
+	registradorGlobal.AgregarProceso(testName, "Ordenando con el formato por defecto")
+	salida, err := ordenJson.OrdenarStruct(input)
+	if err != nil || !strings.Contains(salida, `"tanner:fecha-carga": "2024-05-06T07:08:09.123-03:00"`) || !strings.Contains(salida, `"tanner:fecha-termino-vigencia": "2026-01-31T00:00:00.000Z"`) {
+		status = "Fallido"
+		t.Errorf("Fechas incorrectas con el formato por defecto: %s (%v)", salida, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con un layout propio, también al normalizar")
+	o := ordenJson.Nuevo(ordenJson.WithFormatoSalidaFecha("02-01-2006 15:04"), ordenJson.WithNormalizarFechas("2006-01-02"))
+	propio, err := o.OrdenarStruct(input)
+	if err != nil || !strings.Contains(propio, `"tanner:fecha-carga": "06-05-2024 07:08"`) || !strings.Contains(propio, `"tanner:fecha-termino-vigencia": "31-01-2026 00:00"`) {
+		status = "Fallido"
+		t.Errorf("Fechas incorrectas con el layout propio: %s (%v)", propio, err)
+	}
+	normalizado, err := o.OrdenarJSON(`{"tanner:fecha-carga": "2024-05-06", "tanner:fecha-termino-vigencia": "31-01-2026 00:00"}`)
+	if err != nil || !strings.Contains(normalizado, `"tanner:fecha-carga": "06-05-2024 00:00"`) || !strings.Contains(normalizado, `"tanner:fecha-termino-vigencia": "31-01-2026 00:00"`) {
+		status = "Fallido"
+		t.Errorf("Normalización incorrecta con el layout propio: %s (%v)", normalizado, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: propio}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// rutCliente controla su representación JSON: sin puntos y con guion.
+type rutCliente string
+
+func (r rutCliente) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(strings.ReplaceAll(string(r), ".", "")))
+}
+
+// versionDocumento se escribe con su método String.
+type versionDocumento struct {
+	Mayor, Menor int
+}
+
+func (v *versionDocumento) String() string {
+	return fmt.Sprintf("%d.%d", v.Mayor, v.Menor)
+}
+
+// montoRoto entra en pánico al codificarse.
+type montoRoto int
+
+func (montoRoto) MarshalJSON() ([]byte, error) {
+	panic("monto sin moneda")
+}
+
+type documentoPropio struct {
+	RUT      rutCliente       `json:"tanner:rut-cliente"`
+	Version  versionDocumento `json:"cm:versionLabel"`
+	Avales   []rutCliente     `json:"avales"`
+	Anterior *rutCliente      `json:"rut-anterior"`
+	Crudo    json.RawMessage  `json:"crudo"`
+	SinRUT   rutCliente       `json:"sin-rut"`
+}
+
+func TestOrdenarStruct_RepresentacionPropia(t *testing.T) {
+	anterior := rutCliente("")
+	input := documentoPropio{
+		RUT:      "12.345.678-k",
+		Version:  versionDocumento{Mayor: 1, Menor: 2},
+		Avales:   []rutCliente{"1.111.111-1"},
+		Anterior: &anterior,
+		Crudo:    json.RawMessage(`{"b": 1, "a": [true]}`),
+	}
+	expected := []string{"tanner:rut-cliente", "cm:versionLabel", "avales", "crudo", "a", "b", "rut-anterior"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un struct con MarshalJSON y String propios")
+	salida, err := ordenJson.OrdenarStruct(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarStruct() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	for _, fragmento := range []string{`"tanner:rut-cliente": "12345678-K"`, `"cm:versionLabel": "1.2"`, `"1111111-1"`, `"rut-anterior": ""`} {
+		if !strings.Contains(salida, fragmento) {
+			status = "Fallido"
+			t.Errorf("No se encontró %q en la salida:\n%s", fragmento, salida)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que un MarshalJSON con pánico produce un error")
+	_, err = ordenJson.OrdenarStruct(struct {
+		Monto montoRoto `json:"monto"`
+	}{Monto: 10})
+	var errValor *ordenJson.ErrorValorNoSerializable
+	if !errors.As(err, &errValor) || errValor.Campo != "monto" {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorValorNoSerializable en monto, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
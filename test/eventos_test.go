@@ -0,0 +1,115 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestRegistroDeEventos_JSON(t *testing.T) {
+	inputs := []string{
+		`{"cm:title": "t", "tanner:tipo-documento": "contrato"}`,
+		`{"tanner:tipo-documento": "contrato", "cm:title": "t"}`,
+		`{"cm:title": `,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, inputs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Un evento JSON por línea con el esquema documentado"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando documentos con WithRegistroDeEventos(NuevoRegistroJSON)")
+	var buf bytes.Buffer
+	registro := ordenJson.NuevoRegistroJSON(&buf)
+	ordenador := ordenJson.Nuevo(ordenJson.WithRegistroDeEventos(registro), ordenJson.WithRequired("tanner:rut-cliente"))
+	for _, input := range inputs {
+		ordenJson.OrdenarJSON(input, ordenJson.WithRegistroDeEventos(registro))
+	}
+	ordenador.OrdenarJSONConReporte(inputs[0])
+
+	actual := ResultadosObtenidos{JsonSalida: buf.String()}
+	if err := registro.Err(); err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Err() = %v", err)
+	}
+
+	status := "Completado"
+	var eventos []ordenJson.Evento
+	lineas := bufio.NewScanner(&buf)
+	for lineas.Scan() {
+		var campos map[string]interface{}
+		if err := json.Unmarshal(lineas.Bytes(), &campos); err != nil {
+			status = "Fallido"
+			t.Fatalf("Línea que no es JSON: %s", lineas.Text())
+		}
+		for _, clave := range []string{"duracion_ns", "esquema", "fecha", "operacion", "resultado"} {
+			if _, ok := campos[clave]; !ok {
+				status = "Fallido"
+				t.Errorf("Falta el campo %q en %s", clave, lineas.Text())
+			}
+		}
+		var e ordenJson.Evento
+		json.Unmarshal(lineas.Bytes(), &e)
+		eventos = append(eventos, e)
+	}
+	if len(eventos) != 4 {
+		status = "Fallido"
+		t.Fatalf("Se esperaban 4 eventos, se obtuvieron %d:\n%s", len(eventos), actual.JsonSalida)
+	}
+
+	obtenidos := make([]string, len(eventos))
+	for i, e := range eventos {
+		obtenidos[i] = e.Operacion + " " + e.Resultado
+		if e.Esquema != ordenJson.EsquemaEventos || e.Fecha.IsZero() || e.Hash == "" {
+			status = "Fallido"
+			t.Errorf("Evento %d incompleto: %+v", i, e)
+		}
+	}
+	esperados := []string{
+		ordenJson.OperacionOrdenar + " " + ordenJson.ResultadoOK,
+		ordenJson.OperacionOrdenar + " " + ordenJson.ResultadoOK,
+		ordenJson.OperacionOrdenar + " " + ordenJson.ResultadoError,
+		ordenJson.OperacionReporte + " " + ordenJson.ResultadoProblemas,
+	}
+	if !reflect.DeepEqual(obtenidos, esperados) {
+		status = "Fallido"
+		t.Errorf("Eventos esperados %v, obtenidos %v", esperados, obtenidos)
+	}
+	// El hash se calcula sobre la salida canónica: no depende del orden de entrada.
+	if eventos[0].Hash != eventos[1].Hash || eventos[0].Hash == eventos[2].Hash {
+		status = "Fallido"
+		t.Errorf("Hashes inesperados: %q %q %q", eventos[0].Hash, eventos[1].Hash, eventos[2].Hash)
+	}
+	if eventos[2].Error == "" || eventos[3].Problemas != 1 || eventos[0].Bytes == 0 {
+		status = "Fallido"
+		t.Errorf("Detalles incorrectos: %+v", eventos)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestRegistroDeEventos_ParticionarYDeduplicar(t *testing.T) {
+	var operaciones []string
+	registro := ordenJson.RegistroFunc(func(e ordenJson.Evento) {
+		operaciones = append(operaciones, e.Operacion)
+	})
+	doc := `{"cm:title": "t"}`
+	if _, err := ordenJson.Particionar(doc, 4, ordenJson.WithRegistroDeEventos(registro)); err != nil {
+		t.Fatalf("Particionar() error = %v", err)
+	}
+	dedup, err := ordenJson.NuevoDeduplicador(100, 0.01, ordenJson.WithRegistroDeEventos(registro))
+	if err != nil {
+		t.Fatalf("NuevoDeduplicador() error = %v", err)
+	}
+	dedup.Visto(doc)
+	if esperado := []string{ordenJson.OperacionParticionar, ordenJson.OperacionDeduplicar}; !reflect.DeepEqual(operaciones, esperado) {
+		t.Errorf("Operaciones esperadas %v, obtenidas %v", esperado, operaciones)
+	}
+}
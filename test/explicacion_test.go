@@ -0,0 +1,82 @@
+package test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestOrdenarJSONExplicado(t *testing.T) {
+	input := `{"zzz": 1, "titulo": "Contrato", "interno": true, "tanner:tipo-documento": "contrato"}`
+	expected := []string{"tanner:tipo-documento", "cm:title", "zzz", "x-origen"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con OrdenarJSONExplicado")
+	o := ordenJson.Nuevo(
+		ordenJson.WithRenombres(map[string]string{"titulo": "cm:title"}),
+		ordenJson.WithDefaults(map[string]interface{}{"x-origen": "crm"}),
+		ordenJson.WithExclude("interno"),
+	)
+	salida, decisiones, err := o.OrdenarJSONExplicado(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSONExplicado() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	if esperada, _ := o.OrdenarJSON(input); salida != esperada {
+		status = "Fallido"
+		t.Errorf("La salida difiere de OrdenarJSON:\n%s\n%s", salida, esperada)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Revisando la decisión de cada clave")
+	campos := ordenJson.PerfilPorDefecto.Campos()
+	esperadas := []ordenJson.DecisionClave{
+		{Clave: "tanner:tipo-documento", PosicionOriginal: 3, Criterio: ordenJson.CriterioPerfil, PosicionPerfil: slices.Index(campos, "tanner:tipo-documento"), PosicionFinal: 0},
+		{Clave: "cm:title", ClaveOriginal: "titulo", PosicionOriginal: 1, Criterio: ordenJson.CriterioPerfil, PosicionPerfil: slices.Index(campos, "cm:title"), PosicionFinal: 1},
+		{Clave: "zzz", PosicionOriginal: 0, Criterio: ordenJson.CriterioEntrada, PosicionPerfil: -1, PosicionFinal: 2},
+		{Clave: "x-origen", PosicionOriginal: -1, Criterio: ordenJson.CriterioEntrada, PosicionPerfil: -1, PosicionFinal: 3},
+		{Clave: "interno", PosicionOriginal: 2, Criterio: ordenJson.CriterioOmitida, PosicionPerfil: -1, PosicionFinal: -1},
+	}
+	if !reflect.DeepEqual(decisiones, esperadas) {
+		status = "Fallido"
+		t.Errorf("Decisiones incorrectas.\nEsperado: %+v\nObtenido: %+v", esperadas, decisiones)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Explicando un documento plano con caché")
+	plano := `{"b": 1, "cm:title": "t"}`
+	cacheado := ordenJson.Nuevo(ordenJson.WithCache(10))
+	cacheado.OrdenarJSON(plano)
+	_, decisiones, err = cacheado.OrdenarJSONExplicado(plano)
+	if err != nil || len(decisiones) != 2 || decisiones[0].Clave != "cm:title" || decisiones[1].PosicionOriginal != 0 {
+		status = "Fallido"
+		t.Errorf("Decisiones incorrectas con caché: %+v, %v", decisiones, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Explicando un intercambio de claves")
+	_, decisiones, err = ordenJson.OrdenarJSONExplicado(`{"a": 1, "b": 2}`, ordenJson.WithRenombres(map[string]string{"a": "b", "b": "a"}))
+	if err != nil || len(decisiones) != 2 || decisiones[0].ClaveOriginal != "a" || decisiones[1].ClaveOriginal != "b" {
+		status = "Fallido"
+		t.Errorf("Decisiones incorrectas en el intercambio: %+v, %v", decisiones, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que un error no devuelve decisiones")
+	if _, decisiones, err := ordenJson.OrdenarJSONExplicado(`{"a": `); err == nil || decisiones != nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error sin decisiones: %+v, %v", decisiones, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
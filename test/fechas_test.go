@@ -0,0 +1,158 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestNormalizarFechas(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []ordenJson.Option
+		expected []string
+	}{
+		{
+			name: "formatos por defecto",
+			input: `{
+				"tanner:fecha-termino-vigencia": "01/10/2024",
+				"tanner:fecha-carga": "2023-10-01 08:30:00"
+			}`,
+			opts: []ordenJson.Option{ordenJson.WithNormalizarFechas()},
+			expected: []string{
+				`"tanner:fecha-carga": "2023-10-01T08:30:00.000Z"`,
+				`"tanner:fecha-termino-vigencia": "2024-10-01T00:00:00.000Z"`,
+			},
+		},
+		{
+			name:  "zona horaria conservada",
+			input: `{"tanner:fecha-carga": "2023-10-01T08:30:00.5-03:00"}`,
+			opts:  []ordenJson.Option{ordenJson.WithNormalizarFechas()},
+			expected: []string{
+				`"tanner:fecha-carga": "2023-10-01T08:30:00.500-03:00"`,
+			},
+		},
+		{
+			name:  "formatos personalizados",
+			input: `{"tanner:fecha-carga": "20231001"}`,
+			opts:  []ordenJson.Option{ordenJson.WithNormalizarFechas("20060102")},
+			expected: []string{
+				`"tanner:fecha-carga": "2023-10-01T00:00:00.000Z"`,
+			},
+		},
+		{
+			name:  "campos de fecha personalizados",
+			input: `{"tanner:fecha-carga": "sin cambios", "cm:title": "2023-10-01"}`,
+			opts: []ordenJson.Option{
+				ordenJson.WithNormalizarFechas(),
+				ordenJson.WithCamposFecha("cm:title"),
+			},
+			expected: []string{
+				`"tanner:fecha-carga": "sin cambios"`,
+				`"cm:title": "2023-10-01T00:00:00.000Z"`,
+			},
+		},
+		{
+			name:  "sin la opción no se modifica",
+			input: `{"tanner:fecha-carga": "01/10/2024"}`,
+			expected: []string{
+				`"tanner:fecha-carga": "01/10/2024"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.input)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: tt.expected})
+
+			registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con normalización de fechas")
+			got, err := ordenJson.OrdenarJSON(tt.input, tt.opts...)
+
+			var actual ResultadosObtenidos
+			if err != nil {
+				actual = ResultadosObtenidos{Error: err.Error()}
+				registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+				t.Fatalf("OrdenarJSON() error = %v", err)
+			}
+
+			actual = ResultadosObtenidos{
+				ClavesOrdenadas: extraerClavesJSON(got),
+				JsonSalida:      got,
+			}
+
+			status := "Completado"
+			for _, esperado := range tt.expected {
+				if !strings.Contains(got, esperado) {
+					status = "Fallido"
+					t.Errorf("Se esperaba %s en la salida:\n%s", esperado, got)
+				}
+			}
+
+			registradorGlobal.GuardarResultado(testName, actual, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestNormalizarFechas_FormatoInvalido(t *testing.T) {
+	input := `{"tanner:fecha-carga": "primero de octubre"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "Fecha inválida"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con fecha inválida")
+	_, err := ordenJson.OrdenarJSON(input, ordenJson.WithNormalizarFechas())
+
+	var actual ResultadosObtenidos
+	if err == nil {
+		actual = ResultadosObtenidos{Error: "Se esperaba error para fecha inválida, pero no se produjo ninguno"}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Errorf("Se esperaba error para fecha inválida, pero no se produjo ninguno")
+	} else {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Completado")
+	}
+
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestNormalizarFechas_MapaNoModificado(t *testing.T) {
+	inputMap := map[string]interface{}{
+		"tanner:fecha-carga": "2023-10-01",
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, inputMap)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "2023-10-01"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con mapa y normalización de fechas")
+	got, err := ordenJson.OrdenarJSON(inputMap, ordenJson.WithNormalizarFechas())
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	actual = ResultadosObtenidos{JsonSalida: got}
+
+	status := "Completado"
+	if inputMap["tanner:fecha-carga"] != "2023-10-01" {
+		status = "Fallido"
+		t.Errorf("El mapa de entrada fue modificado: %v", inputMap)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestWithOnlyYWithExclude(t *testing.T) {
+	input := `{"x-extra": "1", "tanner:observaciones": "interna", "cm:title": "Contrato", "tanner:rut-cliente": "1-9", "tanner:tipo-documento": "contrato"}`
+	casos := []struct {
+		nombre   string
+		opts     []ordenJson.Option
+		esperado []string
+	}{
+		{"solo", []ordenJson.Option{ordenJson.WithOnly("cm:title", "tanner:tipo-documento"), ordenJson.WithOnly("no-existe")}, []string{"tanner:tipo-documento", "cm:title"}},
+		{"excluir", []ordenJson.Option{ordenJson.WithExclude("tanner:observaciones", "x-extra")}, []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title"}},
+		{"combinados", []ordenJson.Option{ordenJson.WithOnly("cm:title", "tanner:observaciones"), ordenJson.WithExclude("tanner:observaciones")}, []string{"cm:title"}},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: casos[0].esperado})
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+	for _, caso := range casos {
+		registradorGlobal.AgregarProceso(testName, "Ordenando con filtro: "+caso.nombre)
+		salida, err := ordenJson.OrdenarJSON(input, caso.opts...)
+		if err != nil {
+			registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+			t.Fatalf("%s: OrdenarJSON() error = %v", caso.nombre, err)
+		}
+		claves := extraerClavesJSON(salida)
+		if actual.JsonSalida == "" {
+			actual = ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+		}
+		if !reflect.DeepEqual(claves, caso.esperado) {
+			status = "Fallido"
+			t.Errorf("%s: orden incorrecto.\nEsperado: %v\nObtenido: %v", caso.nombre, caso.esperado, claves)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que los campos omitidos se siguen validando")
+	_, err := ordenJson.OrdenarJSON(`{"cm:title": "Contrato"}`, ordenJson.WithOnly("cm:title"), ordenJson.WithRequired("tanner:rut-cliente"))
+	var errFaltantes *ordenJson.ErrorCamposFaltantes
+	if !errors.As(err, &errFaltantes) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorCamposFaltantes, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
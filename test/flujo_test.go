@@ -0,0 +1,108 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/servidor"
+)
+
+func TestServidor_Flujo(t *testing.T) {
+	documentos := []string{
+		`{"cm:title": "Contrato", "tanner:tipo-documento": "contrato"}`,
+		`{"cm:title": `,
+		`{"tanner:rut-cliente": "1-9", "tanner:tipo-documento": "anexo"}`,
+	}
+	expectedOrder := []string{"tanner:tipo-documento", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documentos)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Abriendo POST /flujo y enviando un documento a la vez")
+	s, err := servidor.Nuevo(servidor.Config{})
+	if err != nil {
+		t.Fatalf("Nuevo() error = %v", err)
+	}
+	servidorHTTP := httptest.NewServer(s)
+	defer servidorHTTP.Close()
+
+	lectorEntrada, escritorEntrada := io.Pipe()
+	defer escritorEntrada.Close()
+	respuestas := make(chan *http.Response, 1)
+	errores := make(chan error, 1)
+	go func() {
+		respuesta, err := http.Post(servidorHTTP.URL+"/flujo", "application/x-ndjson", lectorEntrada)
+		if err != nil {
+			errores <- err
+			return
+		}
+		respuestas <- respuesta
+	}()
+
+	// El primer documento se envía antes de tener la respuesta: los encabezados
+	// llegan de inmediato y cada resultado antes de enviar el siguiente documento.
+	io.WriteString(escritorEntrada, documentos[0]+"\n")
+	var respuesta *http.Response
+	select {
+	case respuesta = <-respuestas:
+	case err := <-errores:
+		t.Fatalf("POST error = %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("La respuesta no comenzó antes de terminar la entrada")
+	}
+	defer respuesta.Body.Close()
+	lector := bufio.NewReader(respuesta.Body)
+
+	status := "Completado"
+	if tipo := respuesta.Header.Get("Content-Type"); tipo != servidor.TipoFlujoNDJSON {
+		status = "Fallido"
+		t.Errorf("Content-Type inesperado: %q", tipo)
+	}
+	var resultados []servidor.ResultadoFlujo
+	for i, documento := range documentos {
+		if i > 0 {
+			io.WriteString(escritorEntrada, documento+"\n\n")
+		}
+		linea, err := lector.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("Leyendo el resultado %d: %v", i, err)
+		}
+		var resultado servidor.ResultadoFlujo
+		if err := json.Unmarshal(linea, &resultado); err != nil {
+			t.Fatalf("Resultado %d no es JSON: %v", i, err)
+		}
+		resultados = append(resultados, resultado)
+	}
+	escritorEntrada.Close()
+	if resto, _ := io.ReadAll(lector); len(resto) != 0 {
+		status = "Fallido"
+		t.Errorf("Resultados de más: %s", resto)
+	}
+
+	keys := extraerClavesJSON(string(resultados[0].Documento))
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: string(resultados[0].Documento)}
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if r := resultados[1]; r.Indice != 1 || r.Error == nil || r.Error.Tipo != cuarentena.TipoJSONInvalido {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error de JSON inválido en el índice 1, se obtuvo %+v", r)
+	}
+	if r := resultados[2]; r.Indice != 2 || r.Error != nil || string(r.Documento) != `{"tanner:tipo-documento":"anexo","tanner:rut-cliente":"1-9"}` {
+		status = "Fallido"
+		t.Errorf("Resultado 2 inesperado: %+v (%s)", r, r.Documento)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
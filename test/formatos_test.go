@@ -0,0 +1,393 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/formatos"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFormatos_YAMLConservaOrden(t *testing.T) {
+	input := `{"cm:title": "true", "tanner:rut-cliente": "12345678-9", "tanner:tipo-documento": "contrato", "extra": {"n": 10, "lista": [1.5, null, "sí"]}}`
+	expected := "tanner:tipo-documento: contrato\n" +
+		"tanner:rut-cliente: 12345678-9\n" +
+		"cm:title: \"true\"\n" +
+		"extra:\n" +
+		"  lista:\n" +
+		"    - 1.5\n" +
+		"    - null\n" +
+		"    - sí\n" +
+		"  n: 10\n"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando y convirtiendo a YAML")
+	ordenado, err := ordenJson.OrdenarJSON(input)
+	if err != nil {
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+	got, err := formatos.Convertir([]byte(ordenado), formatos.YAML)
+	actual := ResultadosObtenidos{JsonSalida: string(got)}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Convertir() error = %v", err)
+	}
+
+	status := "Completado"
+	if string(got) != expected {
+		status = "Fallido"
+		t.Errorf("YAML incorrecto.\nEsperado:\n%s\nObtenido:\n%s", expected, got)
+	}
+	// Los valores deben conservar su tipo al leer el YAML.
+	var leido map[string]interface{}
+	if err := yaml.Unmarshal(got, &leido); err != nil || leido["cm:title"] != "true" {
+		status = "Fallido"
+		t.Errorf("El YAML no conserva los tipos: %v (%v)", leido, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Compactando y validando formatos desconocidos")
+	if compacto, err := formatos.Convertir([]byte(ordenado), formatos.Compacto); err != nil || string(compacto[:26]) != `{"tanner:tipo-documento":"` {
+		status = "Fallido"
+		t.Errorf("Compacto incorrecto: %s (%v)", compacto, err)
+	}
+	if _, err := formatos.Parsear("xml"); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error con un formato desconocido")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_OrdenarYAML(t *testing.T) {
+	input := "cm:title: Contrato\n" +
+		"tanner:fecha-documento: 2024-01-02\n" +
+		"tanner:tipo-documento: contrato\n" +
+		"extra: {n: 10, activo: true}\n"
+	expected := "tanner:tipo-documento: contrato\n" +
+		"cm:title: Contrato\n" +
+		"tanner:fecha-documento: \"2024-01-02\"\n" +
+		"extra:\n" +
+		"  activo: true\n" +
+		"  n: 10\n"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un documento YAML con OrdenarYAML")
+	got, err := formatos.OrdenarYAML(input)
+	actual := ResultadosObtenidos{JsonSalida: got}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarYAML() error = %v", err)
+	}
+
+	status := "Completado"
+	if got != expected {
+		status = "Fallido"
+		t.Errorf("YAML incorrecto.\nEsperado:\n%s\nObtenido:\n%s", expected, got)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Convirtiendo YAML a JSON y rechazando valores sin equivalente")
+	if enJSON, err := formatos.DesdeYAML([]byte(input)); err != nil || string(enJSON) != `{"cm:title":"Contrato","tanner:fecha-documento":"2024-01-02","tanner:tipo-documento":"contrato","extra":{"n":10,"activo":true}}` {
+		status = "Fallido"
+		t.Errorf("DesdeYAML incorrecto: %s (%v)", enJSON, err)
+	}
+	for _, invalido := range []string{"n: .inf\n", "a: 1\n---\nb: 2\n", "a: [\n"} {
+		if _, err := formatos.DesdeYAML([]byte(invalido)); err == nil {
+			status = "Fallido"
+			t.Errorf("Se esperaba un error con %q", invalido)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_AlfrescoXML(t *testing.T) {
+	input := `{"cm:title": "Contrato & anexo", "tanner:tipo-documento": "contrato", "tanner:categorias": ["legal", "clientes"], "tanner:observaciones": null}`
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<!DOCTYPE properties SYSTEM "http://java.sun.com/dtd/properties.dtd">` + "\n" +
+		"<properties>\n" +
+		`  <entry key="tanner:tipo-documento">contrato</entry>` + "\n" +
+		`  <entry key="tanner:categorias">legal,clientes</entry>` + "\n" +
+		`  <entry key="cm:title">Contrato &amp; anexo</entry>` + "\n" +
+		"</properties>\n"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando y convirtiendo a propiedades XML de Alfresco")
+	ordenado, err := ordenJson.OrdenarJSON(input)
+	if err != nil {
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+	got, err := formatos.Convertir([]byte(ordenado), formatos.AlfrescoXML)
+	actual := ResultadosObtenidos{JsonSalida: string(got)}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Convertir() error = %v", err)
+	}
+
+	status := "Completado"
+	if string(got) != expected {
+		status = "Fallido"
+		t.Errorf("XML incorrecto.\nEsperado:\n%s\nObtenido:\n%s", expected, got)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando objetos anidados")
+	if _, err := formatos.AAlfrescoXML([]byte(`{"cm:title": {"es": "Contrato"}}`)); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error con un objeto anidado")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_OrdenarMsgPack(t *testing.T) {
+	input := `{"cm:title": "Contrato", "extra": {"n": 300, "f": 1.5, "l": [true, null]}, "tanner:tipo-documento": "contrato"}`
+	expectedOrder := []string{"tanner:tipo-documento", "cm:title", "extra"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con OrdenarMsgPack")
+	got, err := formatos.OrdenarMsgPack(input)
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarMsgPack() error = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Leyendo las claves del mapa en el orden en que se codificaron")
+	dec := msgpack.NewDecoder(bytes.NewReader(got))
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		t.Fatalf("DecodeMapLen() error = %v", err)
+	}
+	valores := map[string]interface{}{}
+	for i := 0; i < n; i++ {
+		clave, err := dec.DecodeString()
+		if err != nil {
+			t.Fatalf("DecodeString() error = %v", err)
+		}
+		if valores[clave], err = dec.DecodeInterface(); err != nil {
+			t.Fatalf("DecodeInterface() error = %v", err)
+		}
+		actual.ClavesOrdenadas = append(actual.ClavesOrdenadas, clave)
+	}
+
+	status := "Completado"
+	if !reflect.DeepEqual(actual.ClavesOrdenadas, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, actual.ClavesOrdenadas)
+	}
+	// fmt imprime los mapas con las claves ordenadas y sin el tamaño de los enteros.
+	if extra := fmt.Sprint(valores["extra"]); extra != "map[f:1.5 l:[true <nil>] n:300]" {
+		status = "Fallido"
+		t.Errorf("Valores anidados incorrectos: %s", extra)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_OrdenarCBOR(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:tipo-documento": "contrato", "n": 1.5}`
+	// Mapa de 3 pares con las claves en el orden del perfil; 1.5 cabe en un float16.
+	expected := "a3" +
+		"75" + hex.EncodeToString([]byte("tanner:tipo-documento")) + "68" + hex.EncodeToString([]byte("contrato")) +
+		"68" + hex.EncodeToString([]byte("cm:title")) + "68" + hex.EncodeToString([]byte("Contrato")) +
+		"61" + hex.EncodeToString([]byte("n")) + "f93e00"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con OrdenarCBOR")
+	got, err := formatos.OrdenarCBOR(input)
+	actual := ResultadosObtenidos{JsonSalida: hex.EncodeToString(got)}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarCBOR() error = %v", err)
+	}
+
+	status := "Completado"
+	if actual.JsonSalida != expected {
+		status = "Fallido"
+		t.Errorf("CBOR incorrecto.\nEsperado: %s\nObtenido: %s", expected, actual.JsonSalida)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Codificando con la codificación determinista de RFC 8949")
+	ordenado, _ := ordenJson.OrdenarJSON(input)
+	deterministico, err := formatos.ACBORDeterministico([]byte(ordenado))
+	// Las claves más cortas van primero: "n", "cm:title", "tanner:tipo-documento".
+	esperadoDeterministico := "a3" +
+		"61" + hex.EncodeToString([]byte("n")) + "f93e00" +
+		"68" + hex.EncodeToString([]byte("cm:title")) + "68" + hex.EncodeToString([]byte("Contrato")) +
+		"75" + hex.EncodeToString([]byte("tanner:tipo-documento")) + "68" + hex.EncodeToString([]byte("contrato"))
+	if err != nil || hex.EncodeToString(deterministico) != esperadoDeterministico {
+		status = "Fallido"
+		t.Errorf("CBOR determinista incorrecto: %x (%v)", deterministico, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_TOML(t *testing.T) {
+	input := `{"cm:title": "Contrato \"marco\"", "tanner:tipo-documento": "contrato", "tanner:observaciones": null, "extra": {"n": 10, "f": 2.0e3, "l": ["a", true]}}`
+	expected := "\"tanner:tipo-documento\" = \"contrato\"\n" +
+		"\"cm:title\" = \"Contrato \\\"marco\\\"\"\n" +
+		"extra = { f = 2000, l = [\"a\", true], n = 10 }\n"
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando y convirtiendo a TOML")
+	ordenado, err := ordenJson.OrdenarJSON(input)
+	if err != nil {
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+	got, err := formatos.Convertir([]byte(ordenado), formatos.TOML)
+	actual := ResultadosObtenidos{JsonSalida: string(got)}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Convertir() error = %v", err)
+	}
+
+	status := "Completado"
+	if string(got) != expected {
+		status = "Fallido"
+		t.Errorf("TOML incorrecto.\nEsperado:\n%s\nObtenido:\n%s", expected, got)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando null dentro de un arreglo")
+	if _, err := formatos.ATOML([]byte(`{"l": [1, null]}`)); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error con null en un arreglo")
+	}
+	if got, err := formatos.ATOML([]byte(`{"f": 3.0}`)); err != nil || string(got) != "f = 3.0\n" {
+		status = "Fallido"
+		t.Errorf("Número incorrecto: %q (%v)", got, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_FluentdForward(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:tipo-documento": "contrato"}`
+	instante := time.Unix(1700000000, 123456789)
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: []string{"tanner:tipo-documento", "cm:title"}})
+
+	registradorGlobal.AgregarProceso(testName, "Convirtiendo a un evento del protocolo forward")
+	ordenado, err := ordenJson.OrdenarJSON(input)
+	if err != nil {
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+	got, err := formatos.AFluentd([]byte(ordenado), "docs.ordenados", instante)
+	if err != nil {
+		t.Fatalf("AFluentd() error = %v", err)
+	}
+
+	// [etiqueta, EventTime (ext 0 de 8 bytes: segundos y nanosegundos), registro]
+	status := "Completado"
+	prefijo := "93" + "ae" + hex.EncodeToString([]byte("docs.ordenados")) + "d700" + "6553f100" + "075bcd15"
+	if hex.EncodeToString(got[:len(prefijo)/2]) != prefijo {
+		status = "Fallido"
+		t.Errorf("Encabezado del evento = %x; esperado %s", got[:len(prefijo)/2], prefijo)
+	}
+	var actual ResultadosObtenidos
+	dec := msgpack.NewDecoder(bytes.NewReader(got[len(prefijo)/2:]))
+	n, err := dec.DecodeMapLen()
+	for i := 0; err == nil && i < n; i++ {
+		var clave string
+		if clave, err = dec.DecodeString(); err == nil {
+			actual.ClavesOrdenadas = append(actual.ClavesOrdenadas, clave)
+			_, err = dec.DecodeInterface()
+		}
+	}
+	if err != nil || !reflect.DeepEqual(actual.ClavesOrdenadas, []string{"tanner:tipo-documento", "cm:title"}) {
+		status = "Fallido"
+		t.Errorf("Registro = %v, %v", actual.ClavesOrdenadas, err)
+	}
+	if _, err := formatos.AFluentd([]byte(`[1]`), "docs", instante); err == nil {
+		status = "Fallido"
+		t.Error("AFluentd() de un arreglo no devolvió error")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestFormatos_LogstashEnFlujo(t *testing.T) {
+	input := "{\"zzz\": 1, \"cm:title\": \"uno\"}\n{\"@timestamp\": \"2024-01-02T03:04:05.000Z\", \"cm:title\": \"dos\"}\n"
+	expected := `{"@timestamp":"2023-11-14T22:13:20.123Z","@version":"1","cm:title":"uno","zzz":1}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Convirtiendo un documento a un evento de Logstash")
+	got, err := formatos.ALogstash([]byte(`{"cm:title":"uno","zzz":1}`), time.Unix(1700000000, 123456789))
+	actual := ResultadosObtenidos{JsonSalida: string(got)}
+	status := "Completado"
+	if err != nil || string(got) != expected {
+		status = "Fallido"
+		t.Errorf("ALogstash() = %s, %v; esperado %s", got, err, expected)
+	}
+	if vacio, err := formatos.ALogstash([]byte(`{}`), time.Unix(0, 0)); err != nil || string(vacio) != `{"@timestamp":"1970-01-01T00:00:00.000Z","@version":"1"}` {
+		status = "Fallido"
+		t.Errorf("ALogstash() de un objeto vacío = %s, %v", vacio, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un flujo NDJSON hacia un EscritorEventos")
+	var salida bytes.Buffer
+	eventos := formatos.NuevoEscritorEventos(&salida, formatos.Logstash)
+	n, err := ordenJson.Nuevo().OrdenarFlujo(bytes.NewReader([]byte(input)), eventos)
+	if err == nil {
+		err = eventos.Close()
+	}
+	lineas := bytes.Split(bytes.TrimSuffix(salida.Bytes(), []byte("\n")), []byte("\n"))
+	if err != nil || n != 2 || len(lineas) != 2 ||
+		!bytes.HasSuffix(lineas[0], []byte(`"@version":"1","cm:title":"uno","zzz":1}`)) ||
+		string(lineas[1]) != `{"@version":"1","cm:title":"dos","@timestamp":"2024-01-02T03:04:05.000Z"}` {
+		status = "Fallido"
+		t.Errorf("OrdenarFlujo() = %d, %v:\n%s", n, err, salida.Bytes())
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,168 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestMerge(t *testing.T) {
+	base := ordenJson.DocumentMetadata{
+		TipoDocumento: "contrato",
+		RUTCliente:    "11111111-1",
+		CmTitle:       "Contrato base",
+		Observaciones: "revisar",
+	}
+	overlay := ordenJson.DocumentMetadata{
+		TipoDocumento: "factura",
+		RUTCliente:    "22222222-2",
+		CmTitle:       "Título corregido",
+		Origen:        "legal",
+	}
+	policy := ordenJson.MergePolicy{
+		Campos: map[string]ordenJson.Precedencia{
+			"tanner:rut-cliente":    ordenJson.PrecedenciaConservar,
+			"tanner:tipo-documento": ordenJson.PrecedenciaBase,
+			"tanner:observaciones":  ordenJson.PrecedenciaReemplazar,
+		},
+	}
+	expected := ordenJson.DocumentMetadata{
+		TipoDocumento: "contrato",
+		RUTCliente:    "11111111-1",
+		CmTitle:       "Título corregido",
+		Origen:        "legal",
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, base)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Combinando los metadatos con Merge")
+	got := ordenJson.Merge(base, overlay, policy)
+	status := "Completado"
+	if got != expected {
+		status = "Fallido"
+		t.Errorf("Merge incorrecto.\nEsperado: %+v\nObtenido: %+v", expected, got)
+	}
+	if base.CmTitle != "Contrato base" || overlay.Observaciones != "" {
+		status = "Fallido"
+		t.Errorf("Merge modificó sus argumentos")
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Combinando documentos JSON con MergeJSON")
+	salida, err := ordenJson.MergeJSON(
+		`{"cm:title": "a", "x-extra": 1, "tanner:rut-cliente": "11111111-1", "anidado": [1, 2]}`,
+		`{"anidado": [3], "tanner:rut-cliente": null, "tanner:tipo-documento": "contrato", "y-extra": true}`,
+		ordenJson.MergePolicy{Campos: map[string]ordenJson.Precedencia{"cm:title": ordenJson.PrecedenciaReemplazar}},
+	)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("MergeJSON() error = %v", err)
+	}
+	claves := extraerClavesJSON(salida)
+	esperadas := []string{"tanner:tipo-documento", "tanner:rut-cliente", "x-extra", "anidado", "y-extra"}
+	if len(claves) != len(esperadas) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", esperadas, claves)
+	} else {
+		for i := range claves {
+			if claves[i] != esperadas[i] {
+				status = "Fallido"
+				t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", esperadas, claves)
+				break
+			}
+		}
+	}
+	if d, err := ordenJson.Diff(salida, `{"tanner:tipo-documento": "contrato", "tanner:rut-cliente": "11111111-1", "x-extra": 1, "anidado": [3], "y-extra": true}`); err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Valores combinados incorrectos: %v, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error ante un documento inválido")
+	_, err = ordenJson.MergeJSON(`{}`, `{"a": `, ordenJson.MergePolicy{})
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorJSONInvalido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestComponer(t *testing.T) {
+	escaner := `{"x-paginas": 3, "cm:title": "Contrato", "cliente": {"rut": "11111111-1"}}`
+	crm := `{"tanner:tipo-documento": "contrato", "cliente": {"nombre": "Ana"}, "cm:title": "Contrato", "x-paginas": 3.0, "x-crm": null}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, []string{escaner, crm})
+	esperadas := []string{"tanner:tipo-documento", "cm:title", "x-paginas", "cliente", "nombre", "rut"}
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: esperadas})
+
+	registradorGlobal.AgregarProceso(testName, "Componiendo fragmentos compatibles")
+	salida, err := ordenJson.Componer(escaner, crm)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("Componer() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if len(claves) != len(esperadas) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", esperadas, claves)
+	} else {
+		for i := range claves {
+			if claves[i] != esperadas[i] {
+				status = "Fallido"
+				t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", esperadas, claves)
+				break
+			}
+		}
+	}
+	if d, err := ordenJson.Diff(salida, `{"tanner:tipo-documento": "contrato", "cm:title": "Contrato", "x-paginas": 3, "cliente": {"nombre": "Ana", "rut": "11111111-1"}}`); err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Valores compuestos incorrectos: %v, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Detectando conflictos entre fragmentos")
+	_, err = ordenJson.Componer(
+		`{"x-extra": 1, "cliente": {"rut": "11111111-1"}}`,
+		`{"cm:title": "A"}`,
+		`{"cm:title": "B", "x-extra": 1, "cliente": {"rut": "22222222-2"}}`,
+	)
+	var errConflictos *ordenJson.ErrorConflictos
+	if !errors.As(err, &errConflictos) {
+		status = "Fallido"
+		t.Fatalf("Se esperaba ErrorConflictos, se obtuvo %v", err)
+	}
+	esperados := []ordenJson.Conflicto{
+		{Ruta: "/cm:title", Fragmentos: [2]int{1, 2}, Valores: [2]interface{}{"A", "B"}},
+		{Ruta: "/cliente/rut", Fragmentos: [2]int{0, 2}, Valores: [2]interface{}{"11111111-1", "22222222-2"}},
+	}
+	if len(errConflictos.Conflictos) != len(esperados) {
+		status = "Fallido"
+		t.Errorf("Conflictos incorrectos.\nEsperado: %v\nObtenido: %v", esperados, errConflictos.Conflictos)
+	} else {
+		for i, c := range errConflictos.Conflictos {
+			if c != esperados[i] {
+				status = "Fallido"
+				t.Errorf("Conflicto %d incorrecto.\nEsperado: %v\nObtenido: %v", i, esperados[i], c)
+			}
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error ante un fragmento inválido")
+	_, err = ordenJson.Componer(`{}`, `{"a": `)
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorJSONInvalido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordencodec"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// semillasFuzz son entradas iniciales de los objetivos de fuzzing, además del
+// corpus de testdata/fuzz: documentos válidos de distintas formas y entradas
+// malformadas conocidas.
+var semillasFuzz = []string{
+	`{}`,
+	`null`,
+	`{"cm:title": "t", "tanner:tipo-documento": "contrato", "x": [1, {"b": 2, "a": null}]}`,
+	`{"tanner:fecha-carga": "2024-01-02", "tanner:rut-cliente": "1-9"}`,
+	`{"a": 1, "a": 2}`,
+	`{"\u00e1": "\ud83d\ude00", "n": 1e308, "m": -0, "k": 12345678901234567890}`,
+	`[{"a": 1}, {"b": 2}]`,
+	`{"a": [[[[[[[[[[[[[[[[[[[[1]]]]]]]]]]]]]]]]]]]]}`,
+	`{"a": "\ud800"}`,
+	`{"cm:title": `,
+	`{"a": 1} trailing`,
+}
+
+// opcionesFuzz son las configuraciones con que se ordena cada entrada, para
+// recorrer tanto la ruta plana como la general y el codec alternativo.
+var opcionesFuzz = [][]ordenJson.Option{
+	nil,
+	{ordenJson.WithNormalizarFechas(), ordenJson.WithStrict(), ordenJson.WithRequired("cm:title")},
+	{ordenJson.WithCodec(ordencodec.Jsoniter), ordenJson.WithClavesUnicas(), ordenJson.WithMaxDepth(8)},
+	{ordenJson.WithVacio(nil), ordenJson.WithMask("cm:title", ordenJson.MaskAllButLast4), ordenJson.WithOrdenarLista("x", nil)},
+}
+
+// FuzzOrdenarJSON verifica que OrdenarJSON nunca entra en pánico y que, si
+// acepta un documento, su salida es JSON válido y ya está en orden canónico.
+func FuzzOrdenarJSON(f *testing.F) {
+	for _, semilla := range semillasFuzz {
+		f.Add(semilla)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, opts := range opcionesFuzz {
+			salida, err := ordenJson.OrdenarJSON(input, opts...)
+			if err != nil {
+				continue
+			}
+			if !json.Valid([]byte(salida)) {
+				t.Fatalf("Salida inválida para %q: %s", input, salida)
+			}
+			if len(opts) == 0 {
+				// Sin opciones que transformen valores, ordenar la salida no la cambia.
+				otra, err := ordenJson.OrdenarJSON(salida)
+				if err != nil || otra != salida {
+					t.Fatalf("Ordenar la salida no es idempotente para %q:\n%s\n%s (%v)", input, salida, otra, err)
+				}
+			}
+		}
+	})
+}
+
+// FuzzOrdenarFlujo verifica que OrdenarFlujo nunca entra en pánico y que, si
+// termina sin error, escribe NDJSON o un arreglo JSON válidos.
+func FuzzOrdenarFlujo(f *testing.F) {
+	for _, semilla := range semillasFuzz {
+		f.Add(semilla)
+	}
+	f.Add("{\"b\": 1}\n{\"a\": 2}\n")
+	f.Add("[{\"b\": 1},\n{\"a\": 2}]")
+	f.Fuzz(func(t *testing.T, input string) {
+		var salida bytes.Buffer
+		n, err := ordenJson.OrdenarFlujo(strings.NewReader(input), &salida, ordenJson.WithMaxBytes(1<<16))
+		if err != nil {
+			return
+		}
+		texto := strings.TrimSpace(salida.String())
+		if strings.HasPrefix(texto, "[") {
+			var documentos []json.RawMessage
+			if err := json.Unmarshal([]byte(texto), &documentos); err != nil || len(documentos) != n {
+				t.Fatalf("Arreglo inválido para %q: %s (%v)", input, texto, err)
+			}
+			return
+		}
+		lineas := 0
+		for _, linea := range strings.Split(texto, "\n") {
+			if linea == "" {
+				continue
+			}
+			lineas++
+			if !json.Valid([]byte(linea)) {
+				t.Fatalf("Línea inválida para %q: %s", input, linea)
+			}
+		}
+		if lineas != n {
+			t.Fatalf("Se informaron %d documentos y se escribieron %d para %q", n, lineas, input)
+		}
+	})
+}
+
+// valorConPanico es un valor cuyo MarshalJSON entra en pánico, como el de un
+// tipo de terceros con un defecto.
+type valorConPanico struct{}
+
+func (valorConPanico) MarshalJSON() ([]byte, error) {
+	panic("MarshalJSON defectuoso")
+}
+
+// codecConPanico es un Codec cuyo Unmarshal entra en pánico.
+type codecConPanico struct{}
+
+func (codecConPanico) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (codecConPanico) Unmarshal(datos []byte, v interface{}) error {
+	panic("Unmarshal defectuoso")
+}
+
+func TestSinPanico_CodigoAjeno(t *testing.T) {
+	input := map[string]interface{}{"cm:title": "t", "x": valorConPanico{}}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, `{"cm:title": "t", "x": valorConPanico{}}`)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorValorNoSerializable y *ordenJson.ErrorJSONInvalido en lugar de pánicos"})
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un mapa con un valor cuyo MarshalJSON entra en pánico")
+	_, err := ordenJson.OrdenarJSON(input)
+	var errValor *ordenJson.ErrorValorNoSerializable
+	if !errors.As(err, &errValor) || errValor.Campo != "x" {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorValorNoSerializable en x, se obtuvo %v", err)
+	}
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Serializando el mismo mapa")
+	if _, err := ordenJson.Serializar(input); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error al serializar")
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con un Codec cuyo Unmarshal entra en pánico")
+	_, err = ordenJson.OrdenarJSON(`{"x": [1, 2]}`, ordenJson.WithCodec(codecConPanico{}))
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) || errJSON.Offset != 6 {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorJSONInvalido en el valor, se obtuvo %+v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestMensajesLocalizados(t *testing.T) {
+	input := `{"cm:title": "t"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "missing required fields: tanner:rut-cliente"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando sin idioma: el mensaje queda en español")
+	requerido := ordenJson.WithRequired("tanner:rut-cliente")
+	_, err := ordenJson.OrdenarJSON(input, requerido)
+	status := "Completado"
+	if err == nil || err.Error() != "faltan campos obligatorios: tanner:rut-cliente" {
+		status = "Fallido"
+		t.Errorf("Mensaje en español incorrecto: %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con WithIdioma(IdiomaIngles)")
+	ordenador := ordenJson.Nuevo(requerido, ordenJson.WithIdioma(ordenJson.IdiomaIngles))
+	_, err = ordenador.OrdenarJSON(input)
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+	if err == nil || err.Error() != "missing required fields: tanner:rut-cliente" || !errors.Is(err, ordenJson.ErrCamposFaltantes) {
+		status = "Fallido"
+		t.Errorf("Mensaje en inglés incorrecto: %v", err)
+	}
+	if _, err := ordenador.AgregarJSON(nil, `{"cm:title": `); err == nil || !strings.HasPrefix(err.Error(), "invalid JSON at line 1") {
+		status = "Fallido"
+		t.Errorf("Mensaje en inglés incorrecto en AgregarJSON: %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Traduciendo la etapa y el detalle de las reglas")
+	cancelado, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	_, err = ordenJson.OrdenarJSONCtx(cancelado, input, ordenJson.WithIdioma(ordenJson.IdiomaIngles))
+	if err == nil || !strings.HasPrefix(err.Error(), "ordering canceled during decoding") {
+		status = "Fallido"
+		t.Errorf("Mensaje de cancelación incorrecto: %v", err)
+	}
+	perfil, err := ordenJson.PerfilDesdeEsquema([]byte(`{"properties": {"cm:title": {"type": "string", "maxLength": 1}}}`))
+	if err != nil {
+		t.Fatalf("PerfilDesdeEsquema() error = %v", err)
+	}
+	_, err = ordenJson.OrdenarJSON(`{"cm:title": "largo"}`, ordenJson.WithPerfil(perfil), ordenJson.WithIdioma(ordenJson.IdiomaIngles))
+	var errRegla *ordenJson.ErrorRegla
+	if !errors.As(err, &errRegla) || err.Error() != "field cm:title: length 5 is greater than the maximum 1" || errRegla.Detalle != "largo 5 mayor que el máximo 1" {
+		status = "Fallido"
+		t.Errorf("Mensaje de regla incorrecto: %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Cambiando el idioma del paquete con EstablecerIdioma")
+	_, err = ordenJson.OrdenarJSON(input, requerido)
+	ordenJson.EstablecerIdioma(ordenJson.IdiomaIngles)
+	defer ordenJson.EstablecerIdioma(ordenJson.IdiomaEspanol)
+	if err.Error() != "missing required fields: tanner:rut-cliente" || ordenJson.IdiomaActual() != ordenJson.IdiomaIngles {
+		status = "Fallido"
+		t.Errorf("EstablecerIdioma no cambió el mensaje: %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// jsonConMilesDeClaves genera un documento con n claves, algunas con valores anidados.
+func jsonConMilesDeClaves(n int) string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		key := fmt.Sprintf("campo%d", i)
+		if i < len(ordenJson.OrdenCampos) {
+			key = ordenJson.OrdenCampos[i]
+		}
+		if i%10 == 0 {
+			sb.WriteString(fmt.Sprintf(`"%s": {"lista": [%d, "valor \"%d\"", {}, []], "vacio": {}}`, key, i, i))
+		} else {
+			sb.WriteString(fmt.Sprintf(`"%s": "valor%d"`, key, i))
+		}
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func TestIndentacion_EquivalenteAJSONIndent(t *testing.T) {
+	input := jsonConMilesDeClaves(200)
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Salida idéntica a json.Indent"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con valores anidados")
+	got, err := ordenJson.OrdenarJSON(input)
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	actual = ResultadosObtenidos{JsonSalida: got}
+
+	registradorGlobal.AgregarProceso(testName, "Comparando con json.Indent")
+	var compacto, esperado bytes.Buffer
+	if err := json.Compact(&compacto, []byte(got)); err != nil {
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("La salida no es JSON válido: %v", err)
+	}
+	if err := json.Indent(&esperado, compacto.Bytes(), "", "  "); err != nil {
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("json.Indent() error = %v", err)
+	}
+
+	status := "Completado"
+	if got != esperado.String() {
+		status = "Fallido"
+		t.Errorf("La indentación difiere de json.Indent")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestIndentacion_ValoresEquivalentesAMarshalIndent(t *testing.T) {
+	// Claves fuera del perfil: con un mapa de entrada se escriben en orden
+	// alfabético, igual que json.MarshalIndent.
+	input := map[string]interface{}{
+		"cadena":  "<a href=\"x\">&</a> \b\f\n\r\t\x01 \u2028\u2029 ñ \xff",
+		"numeros": []interface{}{0.0, -0.0, 1.5, 1e-7, 1e21, 123456789.0, 1e20, -2.5e-10},
+		"anidado": map[string]interface{}{"z": true, "a": nil, "m": []interface{}{}, "b": map[string]interface{}{}},
+		"lista":   []interface{}{map[string]interface{}{"y": "1", "x": []interface{}{1.0, "dos"}}, nil},
+		"nulos":   []interface{}(nil),
+		"entero":  42,
+		"estructura": struct {
+			Nombre string `json:"nombre"`
+			Tags   []int  `json:"tags"`
+		}{Nombre: "<x>", Tags: []int{1, 2}},
+		"numero": json.Number("12.50"),
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, fmt.Sprint(input))
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Salida idéntica a json.MarshalIndent"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con valores que requieren escapes")
+	got, err := ordenJson.OrdenarJSON(input)
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	actual = ResultadosObtenidos{JsonSalida: got}
+
+	registradorGlobal.AgregarProceso(testName, "Comparando con json.MarshalIndent")
+	esperado, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("json.MarshalIndent() error = %v", err)
+	}
+
+	status := "Completado"
+	if got != string(esperado) {
+		status = "Fallido"
+		t.Errorf("La salida difiere de json.MarshalIndent:\n%s\nesperado:\n%s", got, esperado)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func BenchmarkOrdenarJSON_MilesDeClaves(b *testing.B) {
+	input := jsonConMilesDeClaves(5000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		_, _ = ordenJson.OrdenarJSON(input)
+	}
+}
+
+// BenchmarkIndentacion_JSONIndent mide el costo de la indentación con json.Indent
+// sobre la misma salida, como referencia para comparar con el indentador del paquete.
+func BenchmarkIndentacion_JSONIndent(b *testing.B) {
+	got, err := ordenJson.OrdenarJSON(jsonConMilesDeClaves(5000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var compacto bytes.Buffer
+	if err := json.Compact(&compacto, []byte(got)); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(compacto.Len()))
+	for i := 0; i < b.N; i++ {
+		var resultado bytes.Buffer
+		_ = json.Indent(&resultado, compacto.Bytes(), "", "  ")
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/kafka"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// lectorKafka entrega mensajes fijos y, al agotarlos, cancela el contexto
+// del puente.
+type lectorKafka struct {
+	mensajes    []kafkago.Message
+	confirmados []int64
+	alTerminar  context.CancelFunc
+}
+
+func (l *lectorKafka) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	if len(l.mensajes) == 0 {
+		l.alTerminar()
+		<-ctx.Done()
+		return kafkago.Message{}, ctx.Err()
+	}
+	msg := l.mensajes[0]
+	l.mensajes = l.mensajes[1:]
+	return msg, nil
+}
+
+func (l *lectorKafka) CommitMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	for _, msg := range msgs {
+		l.confirmados = append(l.confirmados, msg.Offset)
+	}
+	return nil
+}
+
+// escritorKafka guarda los mensajes publicados.
+type escritorKafka struct {
+	mu       sync.Mutex
+	mensajes []kafkago.Message
+}
+
+func (e *escritorKafka) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mensajes = append(e.mensajes, msgs...)
+	return nil
+}
+
+func TestKafka_PuenteConMensajesMuertos(t *testing.T) {
+	documentos := []string{
+		`{"zzz": 1, "cm:title": "uno"}`,
+		`{"cm:title": "sin cerrar"`,
+		`{"zzz": 2}`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documentos)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Los válidos se publican ordenados, los inválidos van a mensajes muertos y todos se confirman"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando el puente con un requerido y un JSON inválido")
+	ctx, cancelar := context.WithCancel(context.Background())
+	defer cancelar()
+	entrada := &lectorKafka{alTerminar: cancelar}
+	for i, doc := range documentos {
+		entrada.mensajes = append(entrada.mensajes, kafkago.Message{Topic: "docs", Offset: int64(i), Key: []byte{byte('a' + i)}, Value: []byte(doc)})
+	}
+	salida, muertos := &escritorKafka{}, &escritorKafka{}
+	puente, err := kafka.Nuevo(kafka.Config{
+		Entrada:         entrada,
+		Salida:          salida,
+		MensajesMuertos: muertos,
+		Ordenador:       ordenJson.Nuevo(ordenJson.WithRequired("cm:title")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = puente.Ejecutar(ctx)
+
+	status := "Completado"
+	if err != nil || len(entrada.confirmados) != 3 || puente.Resumen() != (kafka.Resumen{Publicados: 1, Rechazados: 2}) {
+		status = "Fallido"
+		t.Fatalf("Ejecutar() = %v; confirmados %v; %+v", err, entrada.confirmados, puente.Resumen())
+	}
+	if got := string(salida.mensajes[0].Value); got != `{"cm:title":"uno","zzz":1}` || string(salida.mensajes[0].Key) != "a" {
+		status = "Fallido"
+		t.Errorf("Salida = %s", got)
+	}
+	encabezados := map[string]string{}
+	for _, h := range muertos.mensajes[0].Headers {
+		encabezados[h.Key] = string(h.Value)
+	}
+	if string(muertos.mensajes[0].Value) != documentos[1] || encabezados[kafka.EncabezadoTipo] != cuarentena.TipoJSONInvalido ||
+		encabezados[kafka.EncabezadoOrigen] != "docs/0@1" || encabezados[kafka.EncabezadoError] == "" {
+		status = "Fallido"
+		t.Errorf("Mensaje muerto = %s, %v", muertos.mensajes[0].Value, encabezados)
+	}
+	if tipo := string(muertos.mensajes[1].Headers[len(muertos.mensajes[1].Headers)-2].Value); tipo != cuarentena.TipoValidacion {
+		status = "Fallido"
+		t.Errorf("Tipo del segundo mensaje muerto = %s", tipo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: string(salida.mensajes[0].Value)}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
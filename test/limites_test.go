@@ -0,0 +1,95 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// lectorInfinito entrega un documento que nunca termina y cuenta los bytes leídos.
+type lectorInfinito struct {
+	leidos int
+}
+
+func (l *lectorInfinito) Read(p []byte) (int, error) {
+	inicio := `{"a": "`
+	n := 0
+	for n < len(p) {
+		if l.leidos+n < len(inicio) {
+			p[n] = inicio[l.leidos+n]
+		} else {
+			p[n] = 'x'
+		}
+		n++
+	}
+	l.leidos += n
+	return n, nil
+}
+
+func TestLimites_BytesYProfundidad(t *testing.T) {
+	profundo := `{"a": ` + strings.Repeat(`[`, 5) + strings.Repeat(`]`, 5) + `}`
+	casos := []struct {
+		nombre string
+		input  interface{}
+		opts   []ordenJson.Option
+		limite string // Vacío si el documento se ordena.
+	}{
+		{"dentro del tamaño", `{"b": "1", "a": "2"}`, []ordenJson.Option{ordenJson.WithMaxBytes(20)}, ""},
+		{"excede el tamaño", `{"b": "1", "a": "22"}`, []ordenJson.Option{ordenJson.WithMaxBytes(20)}, ordenJson.LimiteBytes},
+		{"dentro de la profundidad", profundo, []ordenJson.Option{ordenJson.WithMaxDepth(6)}, ""},
+		{"excede la profundidad", profundo, []ordenJson.Option{ordenJson.WithMaxDepth(5)}, ordenJson.LimiteProfundidad},
+		{"llaves dentro de cadenas", `{"a": "[[[[[[", "b": {}}`, []ordenJson.Option{ordenJson.WithMaxDepth(2)}, ""},
+		{"mapa dentro de la profundidad", map[string]interface{}{"a": []interface{}{map[string]interface{}{}}}, []ordenJson.Option{ordenJson.WithMaxDepth(3)}, ""},
+		{"mapa excede la profundidad", map[string]interface{}{"a": []interface{}{map[string]interface{}{}}}, []ordenJson.Option{ordenJson.WithMaxDepth(2)}, ordenJson.LimiteProfundidad},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, len(casos))
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorLimiteExcedido"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando documentos en el borde de cada límite")
+	status := "Completado"
+	var ultimo error
+	for _, c := range casos {
+		_, err := ordenJson.OrdenarJSON(c.input, c.opts...)
+		var errLimite *ordenJson.ErrorLimiteExcedido
+		switch {
+		case c.limite == "" && err != nil:
+			status = "Fallido"
+			t.Errorf("%s: error inesperado %v", c.nombre, err)
+		case c.limite != "" && (!errors.As(err, &errLimite) || errLimite.Limite != c.limite):
+			status = "Fallido"
+			t.Errorf("%s: error = %v, se esperaba el límite de %s", c.nombre, err, c.limite)
+		}
+		if err != nil {
+			ultimo = err
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un flujo con un documento que nunca termina")
+	entrada := &lectorInfinito{}
+	n, err := ordenJson.OrdenarFlujo(io.MultiReader(strings.NewReader(`{"b": 1}`+"\n"), entrada), io.Discard, ordenJson.WithMaxBytes(1<<16))
+	var errLimite *ordenJson.ErrorLimiteExcedido
+	if n != 1 || !errors.As(err, &errLimite) || entrada.leidos > 1<<17 {
+		status = "Fallido"
+		t.Errorf("OrdenarFlujo() = %d, %v; leídos %d bytes", n, err, entrada.leidos)
+	}
+	var salida bytes.Buffer
+	if _, err := ordenJson.OrdenarFlujo(strings.NewReader(`[{"b": 1}, {"a": 2}]`), &salida, ordenJson.WithMaxBytes(8)); err != nil {
+		status = "Fallido"
+		t.Errorf("OrdenarFlujo() de documentos dentro del límite: %v", err)
+	}
+
+	var actual ResultadosObtenidos
+	if ultimo != nil {
+		actual.Error = ultimo.Error()
+	}
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestOrdenarLote_ResultadosPorDocumento(t *testing.T) {
+	var inputs []string
+	for i := 0; i < 500; i++ {
+		if i%50 == 7 {
+			inputs = append(inputs, fmt.Sprintf(`{"cm:title": "doc %d"`, i))
+			continue
+		}
+		inputs = append(inputs, fmt.Sprintf(`{"zzz": %d, "cm:title": "doc %d", "tanner:rut-cliente": "1-9"}`, i, i))
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, fmt.Sprintf("%d documentos, 10 inválidos", len(inputs)))
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Un resultado por documento, igual al de OrdenarJSON"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarLote con 4 trabajadores")
+	resultados, err := ordenJson.OrdenarLote(context.Background(), inputs, ordenJson.WithTrabajadores(4))
+
+	status := "Completado"
+	if err != nil || len(resultados) != len(inputs) {
+		status = "Fallido"
+		t.Fatalf("OrdenarLote() = %d resultados, %v", len(resultados), err)
+	}
+	fallidos := 0
+	for i, r := range resultados {
+		esperado, errEsperado := ordenJson.OrdenarJSON(inputs[i])
+		if r.Salida != esperado || (r.Err == nil) != (errEsperado == nil) {
+			status = "Fallido"
+			t.Errorf("Documento %d: %q, %v; esperado %q, %v", i, r.Salida, r.Err, esperado, errEsperado)
+		}
+		if r.Err != nil {
+			fallidos++
+		}
+	}
+	if fallidos != 10 {
+		status = "Fallido"
+		t.Errorf("Documentos fallidos = %d, esperado 10", fallidos)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: resultados[0].Salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestOrdenarLote_ContextoCancelado(t *testing.T) {
+	inputs := []string{`{"a": 1}`, `{"b": 2}`, `{"c": 3}`}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, inputs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "context.Canceled"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarLote con el contexto ya cancelado")
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	resultados, err := ordenJson.OrdenarLote(ctx, inputs)
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+	status := "Completado"
+	if !errors.Is(err, context.Canceled) || len(resultados) != len(inputs) {
+		status = "Fallido"
+		t.Fatalf("OrdenarLote() = %d resultados, %v", len(resultados), err)
+	}
+	for i, r := range resultados {
+		if !errors.Is(r.Err, context.Canceled) || r.Salida != "" {
+			status = "Fallido"
+			t.Errorf("Documento %d: %q, %v; esperado context.Canceled", i, r.Salida, r.Err)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// mapaRecursivo es un mapa que puede contenerse a sí mismo.
+type mapaRecursivo map[string]mapaRecursivo
+
+func TestOrdenarJSON_MapasTipados(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		opts     []ordenJson.Option
+		expected string
+	}{
+		{
+			name:     "map[string]string",
+			input:    map[string]string{"zzz": "1", "cm:title": "Contrato", "tanner:tipo-documento": "contrato"},
+			expected: `{"tanner:tipo-documento":"contrato","cm:title":"Contrato","zzz":"1"}`,
+		},
+		{
+			name: "map[string]json.RawMessage",
+			input: map[string]json.RawMessage{
+				"zzz":      json.RawMessage(`{"b": 1, "a": [true, null]}`),
+				"cm:title": json.RawMessage(`"Contrato"`),
+			},
+			expected: `{"cm:title":"Contrato","zzz":{"a":[true,null],"b":1}}`,
+		},
+		{
+			name:     "map[string]int con ceros",
+			input:    map[string]int{"b": 0, "a": 2},
+			expected: `{"a":2,"b":0}`,
+		},
+		{
+			name:     "mapas anidados y claves enteras",
+			input:    map[string]map[int]float64{"cm:title": {10: 1.5, 2: 0}},
+			expected: `{"cm:title":{"10":1.5,"2":0}}`,
+		},
+		{
+			name:     "map[string]string con marcadores vacíos",
+			input:    map[string]string{"b": "x", "a": "N/A"},
+			opts:     []ordenJson.Option{ordenJson.WithVacio(ordenJson.MarcadoresVacios("N/A"))},
+			expected: `{"b":"x"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.input)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: extraerClavesJSON(tt.expected)})
+
+			registradorGlobal.AgregarProceso(testName, "Ordenando un mapa tipado con OrdenarJSON")
+			salida, err := ordenJson.OrdenarJSON(tt.input, tt.opts...)
+			if err != nil {
+				registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+				t.Fatalf("OrdenarJSON() error = %v", err)
+			}
+			status := "Completado"
+			var compacta bytes.Buffer
+			if err := json.Compact(&compacta, []byte(salida)); err != nil || compacta.String() != tt.expected {
+				status = "Fallido"
+				t.Errorf("Salida incorrecta.\nEsperado: %s\nObtenido: %s", tt.expected, salida)
+			}
+
+			registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: extraerClavesJSON(salida)}, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestOrdenarJSON_MapasTipadosInvalidos(t *testing.T) {
+	ciclo := mapaRecursivo{}
+	ciclo["a"] = ciclo
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, `map[bool]string, map[string]chan int, json.RawMessage inválido y un mapa que se contiene a sí mismo`)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorTipoNoSoportado y *ordenJson.ErrorValorNoSerializable"})
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando mapas con claves o valores no admitidos")
+	for _, input := range []interface{}{map[bool]string{true: "x"}, map[string]chan int{"a": nil}} {
+		_, err := ordenJson.OrdenarJSON(input)
+		var errTipo *ordenJson.ErrorTipoNoSoportado
+		if !errors.As(err, &errTipo) {
+			status = "Fallido"
+			t.Errorf("Se esperaba *ErrorTipoNoSoportado para %T, se obtuvo %v", input, err)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un json.RawMessage inválido anidado")
+	_, err := ordenJson.OrdenarJSON(map[string]map[string]json.RawMessage{"a": {"b": json.RawMessage(`{`)}})
+	var errValor *ordenJson.ErrorValorNoSerializable
+	if !errors.As(err, &errValor) || errValor.Campo != "a.b" {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorValorNoSerializable en a.b, se obtuvo %v", err)
+	}
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un mapa que se contiene a sí mismo")
+	if _, err := ordenJson.OrdenarJSON(ciclo); !errors.As(err, &errValor) {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorValorNoSerializable por el ciclo, se obtuvo %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un mapa tipado más profundo que WithMaxDepth")
+	profundo := map[string]map[string][]int{"a": {"b": {1}}}
+	var errLimite *ordenJson.ErrorLimiteExcedido
+	if _, err := ordenJson.OrdenarJSON(profundo, ordenJson.WithMaxDepth(2)); !errors.As(err, &errLimite) {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorLimiteExcedido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
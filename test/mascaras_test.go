@@ -0,0 +1,58 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestWithMask(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:rut-cliente": "12345678-K", "telefonos": ["+56911112222", 56933334444], "cuenta": {"numero": "000123456", "activa": true}, "tanner:estado-visado": "aprobado"}`
+	expected := []string{"tanner:rut-cliente", "tanner:estado-visado", "cm:title", "telefonos", "cuenta", "activa", "numero"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con WithMask")
+	salida, err := ordenJson.OrdenarJSON(input,
+		ordenJson.WithMask("tanner:rut-cliente", ordenJson.MaskAllButLast4),
+		ordenJson.WithMask("telefonos", ordenJson.MaskAllButLast4),
+		ordenJson.WithMask("cuenta", ordenJson.MaskAll),
+		ordenJson.WithValoresPermitidos("tanner:estado-visado", "aprobado"),
+		ordenJson.WithMask("tanner:estado-visado", ordenJson.MaskAll),
+	)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	claves := extraerClavesJSON(salida)
+	actual := ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+	status := "Completado"
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	for _, fragmento := range []string{`"******78-K"`, `"********2222"`, `"*******4444"`, `"*********"`, `"activa": true`, `"********"`, `"Contrato"`} {
+		if !strings.Contains(salida, fragmento) {
+			status = "Fallido"
+			t.Errorf("La salida no contiene %s:\n%s", fragmento, salida)
+		}
+	}
+	if strings.Contains(salida, "12345678") || strings.Contains(salida, "000123456") {
+		status = "Fallido"
+		t.Errorf("La salida expone valores sensibles:\n%s", salida)
+	}
+	if got := ordenJson.MaskAllButLast4("ñandú"); got != "*andú" {
+		status = "Fallido"
+		t.Errorf("MaskAllButLast4(ñandú) = %q", got)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
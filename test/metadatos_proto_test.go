@@ -0,0 +1,58 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/samuel/prueba-orden/ordenJson/rpc"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestRPC_DocumentMetadataProto(t *testing.T) {
+	metadata := ordenJson.DocumentMetadata{CmTitle: "Contrato", TipoDocumento: "contrato", RUTCliente: "1-9", Observaciones: "sin observaciones"}
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title", "tanner:observaciones"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, metadata)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Convirtiendo el struct al mensaje y de vuelta")
+	mensaje := rpc.DesdeMetadata(metadata)
+	status := "Completado"
+	if vuelta := mensaje.Metadata(); vuelta != metadata {
+		status = "Fallido"
+		t.Errorf("La conversión de ida y vuelta cambió el documento: %+v", vuelta)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Serializando el mensaje con protojson")
+	crudo, err := protojson.Marshal(mensaje)
+	if err != nil {
+		t.Fatalf("protojson.Marshal() error = %v", err)
+	}
+	keys := extraerClavesJSON(string(crudo))
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: string(crudo)}
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto en protojson. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Convirtiendo entre el mensaje y el JSON ordenado")
+	ordenado, err := rpc.OrdenarMetadata(mensaje)
+	esperado, _ := ordenJson.OrdenarDocumentoMetadata(metadata)
+	if err != nil || ordenado != esperado {
+		status = "Fallido"
+		t.Errorf("OrdenarMetadata() = %s, %v; se esperaba %s", ordenado, err, esperado)
+	}
+	desdeJSON, err := rpc.MetadataDesdeJSON(`{"cm:title": "Contrato", "otro": 1, "tanner:tipo-documento": "contrato"}`)
+	if err != nil || desdeJSON.GetCmTitle() != "Contrato" || desdeJSON.GetTipoDocumento() != "contrato" || desdeJSON.GetRutCliente() != "" {
+		status = "Fallido"
+		t.Errorf("MetadataDesdeJSON() = %v, %v", desdeJSON, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
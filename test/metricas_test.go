@@ -0,0 +1,74 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenprom"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestMetricasPrometheus(t *testing.T) {
+	inputs := []string{
+		`{"cm:title": "t", "tanner:rut-cliente": "1-9"}`,
+		`{"cm:title": `,
+		`{"cm:title": "t"}`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, inputs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Contadores por resultado y tipo de error, e histogramas de tamaño y duración"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando documentos con WithMetricas")
+	registro := prometheus.NewRegistry()
+	metricas, err := ordenprom.NuevasMetricas(registro)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("NuevasMetricas() error = %v", err)
+	}
+	ordenador := ordenJson.Nuevo(ordenprom.WithMetricas(metricas), ordenJson.WithRequired("tanner:rut-cliente"))
+	for _, input := range inputs {
+		ordenador.OrdenarJSON(input)
+	}
+
+	status := "Completado"
+	esperado := `
+# HELP ordenjson_documentos_total Documentos procesados, por operación y resultado.
+# TYPE ordenjson_documentos_total counter
+ordenjson_documentos_total{operacion="ordenar",resultado="error"} 2
+ordenjson_documentos_total{operacion="ordenar",resultado="ok"} 1
+# HELP ordenjson_errores_total Errores al procesar documentos, por operación y tipo de error.
+# TYPE ordenjson_errores_total counter
+ordenjson_errores_total{operacion="ordenar",tipo="campos-faltantes"} 1
+ordenjson_errores_total{operacion="ordenar",tipo="json-invalido"} 1
+`
+	if err := testutil.GatherAndCompare(registro, strings.NewReader(esperado), "ordenjson_documentos_total", "ordenjson_errores_total"); err != nil {
+		status = "Fallido"
+		t.Errorf("Contadores incorrectos: %v", err)
+	}
+	if n := testutil.CollectAndCount(registro, "ordenjson_bytes_entrada", "ordenjson_duracion_segundos"); n != 2 {
+		status = "Fallido"
+		t.Errorf("Se esperaba un histograma de cada tipo, se obtuvieron %d", n)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error al registrar dos veces")
+	if _, err := ordenprom.NuevasMetricas(registro); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba error al registrar las métricas dos veces")
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Clasificando errores con TipoDeError")
+	_, err = ordenJson.OrdenarJSON(`{"a": 1}`, ordenJson.WithMaxBytes(2))
+	if tipo := ordenJson.TipoDeError(err); tipo != ordenJson.TipoErrorLimiteExcedido {
+		status = "Fallido"
+		t.Errorf("TipoDeError(%v) = %q", err, tipo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
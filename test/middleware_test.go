@@ -0,0 +1,156 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestMiddleware_OrdenaRespuestasJSON(t *testing.T) {
+	input := map[string]interface{}{"cm:title": "Contrato", "tanner:rut-cliente": "1-9", "tanner:tipo-documento": "contrato"}
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Sirviendo una respuesta JSON a través de Middleware")
+	handler := ordenJson.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(input)
+	}))
+	grabador := httptest.NewRecorder()
+	handler.ServeHTTP(grabador, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cuerpo := grabador.Body.String()
+	keys := extraerClavesJSON(cuerpo)
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: cuerpo}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if grabador.Code != http.StatusCreated {
+		status = "Fallido"
+		t.Errorf("Se esperaba el código 201, se obtuvo %d", grabador.Code)
+	}
+	if largo := grabador.Header().Get("Content-Length"); largo != strconv.Itoa(len(cuerpo)) {
+		status = "Fallido"
+		t.Errorf("Content-Length %s no coincide con el cuerpo de %d bytes", largo, len(cuerpo))
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestMiddleware_NoModificaOtrasRespuestas(t *testing.T) {
+	casos := []struct {
+		nombre      string
+		contentType string
+		cuerpo      string
+	}{
+		{"texto", "text/plain", `{"cm:title": "t", "tanner:tipo-documento": "x"}`},
+		{"arreglo JSON", "application/json", `[{"cm:title": "t", "tanner:tipo-documento": "x"}]`},
+		{"null", "application/json", `null`},
+		{"sin Content-Type", "", `{"cm:title": "t", "tanner:tipo-documento": "x"}`},
+	}
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			servidor := httptest.NewServer(ordenJson.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if c.contentType != "" {
+					w.Header().Set("Content-Type", c.contentType)
+				}
+				io.WriteString(w, c.cuerpo)
+			})))
+			defer servidor.Close()
+
+			respuesta, err := http.Get(servidor.URL)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer respuesta.Body.Close()
+			cuerpo, _ := io.ReadAll(respuesta.Body)
+			if string(cuerpo) != c.cuerpo {
+				t.Errorf("El cuerpo no debía cambiar: %q", cuerpo)
+			}
+		})
+	}
+}
+
+func TestMiddleware_FlushYHead(t *testing.T) {
+	input := `{"cm:title": "Contrato",   "tanner:tipo-documento": "contrato"}`
+	expectedOrder := []string{"tanner:tipo-documento", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	var errFlush error
+	servidor := httptest.NewServer(ordenJson.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/sin-cuerpo":
+			w.Header().Set("Content-Length", "57")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/texto":
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, input)
+			errFlush = http.NewResponseController(w).Flush()
+		default:
+			io.WriteString(w, input[:20])
+			errFlush = http.NewResponseController(w).Flush()
+			io.WriteString(w, input[20:])
+		}
+	})))
+	defer servidor.Close()
+	pedir := func(metodo, ruta string) (*http.Response, string) {
+		t.Helper()
+		peticion, _ := http.NewRequest(metodo, servidor.URL+ruta, nil)
+		respuesta, err := http.DefaultClient.Do(peticion)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", metodo, ruta, err)
+		}
+		defer respuesta.Body.Close()
+		cuerpo, _ := io.ReadAll(respuesta.Body)
+		return respuesta, string(cuerpo)
+	}
+
+	status := "Completado"
+
+	registradorGlobal.AgregarProceso(testName, "Vaciando el búfer con http.ResponseController a mitad del cuerpo")
+	respuesta, cuerpo := pedir(http.MethodGet, "/")
+	keys := extraerClavesJSON(cuerpo)
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: cuerpo}
+	if errFlush != nil || !reflect.DeepEqual(keys, expectedOrder) || respuesta.ContentLength != int64(len(cuerpo)) {
+		status = "Fallido"
+		t.Errorf("Flush no debía enviar el cuerpo sin ordenar: %q (Content-Length %d, Flush %v)", cuerpo, respuesta.ContentLength, errFlush)
+	}
+	if _, cuerpo := pedir(http.MethodGet, "/texto"); errFlush != nil || cuerpo != input {
+		status = "Fallido"
+		t.Errorf("Flush de una respuesta sin ordenar = %v, cuerpo %q", errFlush, cuerpo)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el Content-Length de las peticiones HEAD")
+	if head, _ := pedir(http.MethodHead, "/"); head.ContentLength != int64(len(cuerpo)) {
+		status = "Fallido"
+		t.Errorf("HEAD con cuerpo: Content-Length %d, se esperaba %d", head.ContentLength, len(cuerpo))
+	}
+	if head, _ := pedir(http.MethodHead, "/sin-cuerpo"); head.ContentLength != 57 {
+		status = "Fallido"
+		t.Errorf("HEAD sin cuerpo: Content-Length %d, se esperaba 57", head.ContentLength)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
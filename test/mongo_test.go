@@ -0,0 +1,52 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/samuel/prueba-orden/ordenJson/mongo"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestMongo_ReordenarComoInsertOrdenado(t *testing.T) {
+	metadata := ordenJson.DocumentMetadata{
+		TipoDocumento: "contrato",
+		RUTCliente:    "1-9",
+		NombreDoc:     "contrato.pdf",
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, metadata)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Un documento guardado en desorden se reordena igual que InsertOrdenado, sin cambiar sus valores"})
+
+	registradorGlobal.AgregarProceso(testName, "Construyendo el documento canónico")
+	canonico, err := mongo.Documento(metadata)
+	if err != nil {
+		t.Fatalf("Documento() = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Reordenando el mismo documento guardado en desorden")
+	id := bson.NewObjectID()
+	desordenado := bson.D{{Key: "extra", Value: bson.D{{Key: "z", Value: 1}, {Key: "a", Value: bson.A{bson.D{{Key: "y", Value: 2}, {Key: "b", Value: 3}}}}}}}
+	for i := len(canonico) - 1; i >= 0; i-- {
+		desordenado = append(desordenado, canonico[i])
+	}
+	desordenado = append(desordenado, bson.E{Key: "_id", Value: id})
+	reordenado := mongo.Reordenar(desordenado, ordenJson.PerfilPorDefecto)
+
+	esperado := append(bson.D{{Key: "_id", Value: id}}, canonico...)
+	esperado = append(esperado, bson.E{Key: "extra", Value: bson.D{{Key: "a", Value: bson.A{bson.D{{Key: "b", Value: 3}, {Key: "y", Value: 2}}}}, {Key: "z", Value: 1}}})
+	status := "Completado"
+	if !reflect.DeepEqual(reordenado, esperado) {
+		status = "Fallido"
+		t.Errorf("Reordenar() = %v; esperado %v", reordenado, esperado)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: fmt.Sprint(reordenado)}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
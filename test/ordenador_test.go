@@ -0,0 +1,273 @@
+package test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/samuel/prueba-orden/ordenJson"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+const entradaOrdenador = `{
+	"cm:title": "Contrato",
+	"tanner:estado-visado": "aprobado",
+	"tanner:rut-cliente": "12345678-9",
+	"tanner:tipo-documento": "contrato"
+}`
+
+func TestOrdenador_MismaSalidaQueFunciones(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, entradaOrdenador)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Ordenador reutilizado produce la misma salida que OrdenarJSON"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON")
+	esperado, err := ordenJson.OrdenarJSON(entradaOrdenador, ordenJson.WithValidarEstados())
+	if err != nil {
+		t.Fatalf("OrdenarJSON falló: %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando Ordenador desde varias goroutines")
+	ord := ordenJson.Nuevo(ordenJson.WithValidarEstados())
+	salidas := make([]string, 8)
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range salidas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			salidas[i], errs[i] = ord.OrdenarJSON(entradaOrdenador)
+		}(i)
+	}
+	wg.Wait()
+
+	actual := ResultadosObtenidos{JsonSalida: salidas[0]}
+	status := "Completado"
+	for i := range salidas {
+		if errs[i] != nil || salidas[i] != esperado {
+			status = "Fallido"
+			t.Errorf("Salida %d distinta (err=%v):\n%s\nesperado:\n%s", i, errs[i], salidas[i], esperado)
+		}
+	}
+	if ord.Perfil() != ordenJson.PerfilPorDefecto {
+		status = "Fallido"
+		t.Errorf("Perfil() debe ser el perfil por defecto")
+	}
+
+	_, problemas, err := ord.OrdenarJSONConReporte(`{"tanner:estado-visado": "otro"}`)
+	if err != nil || len(problemas) != 1 {
+		status = "Fallido"
+		t.Errorf("OrdenarJSONConReporte: err=%v problemas=%v", err, problemas)
+	}
+	if _, err := ord.OrdenarJSON(`{"tanner:estado-visado": "otro"}`); err == nil {
+		status = "Fallido"
+		t.Errorf("El reporte no debe alterar la configuración del Ordenador")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestOrdenador_AgregarJSONReutilizaBuffer(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, entradaOrdenador)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "AgregarJSON agrega la misma salida que OrdenarJSON sin reservar un buffer nuevo"})
+
+	ord := ordenJson.Nuevo(ordenJson.WithValidarEstados())
+	esperado, err := ord.OrdenarJSON(entradaOrdenador)
+	if err != nil {
+		t.Fatalf("OrdenarJSON falló: %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Agregando a un buffer con prefijo")
+	buf := append(make([]byte, 0, 4096), "prefijo:"...)
+	buf, err = ord.AgregarJSON(buf, entradaOrdenador)
+	actual := ResultadosObtenidos{JsonSalida: string(buf)}
+	status := "Completado"
+	if err != nil || string(buf) != "prefijo:"+esperado {
+		status = "Fallido"
+		t.Errorf("AgregarJSON = %q, %v; esperado %q", buf, err, "prefijo:"+esperado)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Reutilizando el buffer")
+	inicio := &buf[0]
+	buf, err = ord.AgregarJSON(buf[:0], entradaOrdenador)
+	if err != nil || string(buf) != esperado || &buf[0] != inicio {
+		status = "Fallido"
+		t.Errorf("AgregarJSON con buf[:0] debe reutilizar el buffer (err=%v)", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Documento inválido")
+	previo, err := ord.AgregarJSON(buf, `{"tanner:estado-visado": "otro"}`)
+	if err == nil || len(previo) != len(buf) {
+		status = "Fallido"
+		t.Errorf("Con error AgregarJSON debe devolver dst sin cambios (err=%v, len=%d)", err, len(previo))
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func BenchmarkOrdenador_AgregarJSON(b *testing.B) {
+	ord := ordenJson.Nuevo()
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = ord.AgregarJSON(buf[:0], entradaOrdenador)
+	}
+}
+
+func TestOrdenarDocumentoMetadata_MismaSalidaQueMapa(t *testing.T) {
+	metadata := ordenJson.DocumentMetadata{
+		TipoDocumento:  "contrato",
+		RUTCliente:     "12345678-9",
+		FechaCarga:     "2024-01-02T03:04:05Z",
+		CmTitle:        "Contrato <marco>",
+		CmVersionLabel: "1.0",
+		Observaciones:  "-",
+		Origen:         "central",
+	}
+	// Un perfil parcial deja campos fuera del orden: deben quedar al final en
+	// orden alfabético, igual que al ordenar el mapa equivalente.
+	perfil := ordenJson.NuevoPerfil("parcial", []string{"cm:title", "tanner:rut-cliente"})
+	opts := []ordenJson.Option{ordenJson.WithPerfil(perfil), ordenJson.WithVacio(ordenJson.MarcadoresVacios("-"))}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, metadata)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Misma salida que OrdenarJSON con el mapa de los campos no vacíos"})
+
+	registradorGlobal.AgregarProceso(testName, "Construyendo el mapa equivalente")
+	var completo map[string]interface{}
+	datos, _ := json.Marshal(metadata)
+	if err := json.Unmarshal(datos, &completo); err != nil {
+		t.Fatal(err)
+	}
+	mapa := make(map[string]interface{})
+	for clave, valor := range completo {
+		if valor != "" && valor != "-" {
+			mapa[clave] = valor
+		}
+	}
+	esperado, err := ordenJson.OrdenarJSON(mapa, opts...)
+	if err != nil {
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarDocumentoMetadata dos veces")
+	status := "Completado"
+	var actual ResultadosObtenidos
+	for i := 0; i < 2; i++ {
+		got, err := ordenJson.OrdenarDocumentoMetadata(metadata, opts...)
+		actual = ResultadosObtenidos{ClavesOrdenadas: extraerClavesJSON(got), JsonSalida: got}
+		if err != nil || got != esperado {
+			status = "Fallido"
+			t.Errorf("Llamada %d: OrdenarDocumentoMetadata() = %s, %v\nesperado:\n%s", i, got, err, esperado)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func BenchmarkOrdenarDocumentoMetadata(b *testing.B) {
+	ord := ordenJson.Nuevo()
+	metadata := ordenJson.DocumentMetadata{
+		TipoDocumento: "contrato",
+		RUTCliente:    "12345678-9",
+		EstadoVisado:  "aprobado",
+		FechaCarga:    "2024-01-02T03:04:05Z",
+		CmTitle:       "Contrato marco",
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ord.OrdenarDocumentoMetadata(metadata)
+	}
+}
+
+func TestCompatV1_DelegaEnV2(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, entradaOrdenador)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "El paquete v1 produce la misma salida que v2"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON en v1 y v2")
+	salidaV1, errV1 := v1.OrdenarJSON(entradaOrdenador, v1.WithRequired("cm:title"))
+	salidaV2, errV2 := ordenJson.OrdenarJSON(entradaOrdenador, ordenJson.WithRequired("cm:title"))
+
+	actual := ResultadosObtenidos{JsonSalida: salidaV1}
+	status := "Completado"
+	if errV1 != nil || errV2 != nil || salidaV1 != salidaV2 {
+		status = "Fallido"
+		t.Errorf("v1 y v2 difieren (%v, %v):\n%s\n%s", errV1, errV2, salidaV1, salidaV2)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarDocumentoMetadata en v1 y v2")
+	doc := v1.DocumentMetadata{TipoDocumento: "contrato", CmTitle: "Contrato"}
+	docV1, errV1 := v1.OrdenarDocumentoMetadata(doc)
+	docV2, errV2 := ordenJson.OrdenarDocumentoMetadata(doc)
+	if errV1 != nil || errV2 != nil || docV1 != docV2 {
+		status = "Fallido"
+		t.Errorf("OrdenarDocumentoMetadata difiere entre v1 y v2:\n%s\n%s", docV1, docV2)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestOrdenador_RutaPlanaIgualQueGeneral(t *testing.T) {
+	entradas := []string{
+		entradaOrdenador,
+		`{}`,
+		` { "zzz" : "1" , "tanner:tipo-documento":"a", "aaa": "ñandú 日本" } `,
+		`{"cm:title": "con \"comillas\"", "tanner:rut-cliente": "1"}`,
+		`{"cm:title": "<b>&</b>", "tanner:rut-cliente": "1"}`,
+		`{"cm:title": "línea` + "\u2028" + `", "tanner:rut-cliente": "1"}`,
+		`{"cm:title": "a", "cm:title": "b", "tanner:rut-cliente": "1"}`,
+		`{"cm:title": "a", "tanner:rut-cliente": 1}`,
+		`{"cm:title": "a", "anidado": {"z": "1", "a": "2"}}`,
+		`{"cm:title": "a"} {}`,
+		`{"cm:title": "a",}`,
+		`null`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, entradas)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "La ruta plana produce la misma salida y los mismos errores que la ruta general"})
+
+	rapido := ordenJson.Nuevo()
+	// Un presupuesto de tiempo obliga a decodificar el documento sin cambiar la salida.
+	general := ordenJson.Nuevo(ordenJson.WithPresupuesto(time.Hour))
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+	for _, entrada := range entradas {
+		registradorGlobal.AgregarProceso(testName, "Ordenando "+entrada)
+		got, err := rapido.OrdenarJSON(entrada)
+		esperado, errEsperado := general.OrdenarJSON(entrada)
+		actual = ResultadosObtenidos{JsonSalida: got}
+		if got != esperado || (err == nil) != (errEsperado == nil) {
+			status = "Fallido"
+			t.Errorf("OrdenarJSON(%s) = %q, %v; esperado %q, %v", entrada, got, err, esperado, errEsperado)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Contando reservas de memoria de la ruta plana")
+	buf := make([]byte, 0, 4096)
+	var entrada interface{} = entradaOrdenador
+	reservas := testing.AllocsPerRun(100, func() {
+		buf, _ = rapido.AgregarJSON(buf[:0], entrada)
+	})
+	if reservas != 0 {
+		status = "Fallido"
+		t.Errorf("AgregarJSON con un documento plano reservó memoria %v veces", reservas)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -804,6 +804,7 @@ func TestJSONCamposVacios(t *testing.T) {
 func BenchmarkOrdenarJSON(b *testing.B) {
 	input := `{"zzz": "valor", "tanner:tipo-documento": "test", "cm:title": "title"}`
 
+	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		_, _ = ordenJson.OrdenarJSON(input)
 	}
@@ -816,6 +817,9 @@ func BenchmarkOrdenarJSON(b *testing.B) {
 
 func TestMain(m *testing.M) {
 	code := m.Run()
+	if comandoCLI.dir != "" {
+		os.RemoveAll(comandoCLI.dir)
+	}
 	if err := registradorGlobal.CrearArchivoLog(); err != nil {
 		fmt.Printf("Error escribiendo logs: %v\n", err)
 	}
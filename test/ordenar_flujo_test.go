@@ -0,0 +1,144 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestOrdenarFlujo_NDJSONYArreglo(t *testing.T) {
+	documentos := []string{
+		`{"cm:title": "a", "tanner:tipo-documento": "x"}`,
+		`{"b": 1, "a": {"z": 1, "y": [true, null]}}`,
+		`{}`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documentos)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Cada documento ordenado igual que con OrdenarJSON, en la misma forma que la entrada"})
+
+	var ndjson, compactos, ordenados []string
+	for _, doc := range documentos {
+		ordenado, err := ordenJson.OrdenarJSON(doc)
+		if err != nil {
+			t.Fatalf("OrdenarJSON() error = %v", err)
+		}
+		var compacto bytes.Buffer
+		json.Compact(&compacto, []byte(ordenado))
+		ndjson = append(ndjson, doc)
+		compactos = append(compactos, compacto.String())
+		ordenados = append(ordenados, ordenado)
+	}
+
+	status := "Completado"
+	registradorGlobal.AgregarProceso(testName, "Ordenando NDJSON")
+	var salida bytes.Buffer
+	n, err := ordenJson.OrdenarFlujo(strings.NewReader(strings.Join(ndjson, "\n")+"\n"), &salida)
+	esperado := strings.Join(compactos, "\n") + "\n"
+	if err != nil || n != len(documentos) || salida.String() != esperado {
+		status = "Fallido"
+		t.Errorf("NDJSON: n=%d err=%v\n%s\nesperado:\n%s", n, err, salida.String(), esperado)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un arreglo")
+	salida.Reset()
+	n, err = ordenJson.OrdenarFlujo(strings.NewReader(" ["+strings.Join(documentos, ",")+"]"), &salida)
+	var indentado bytes.Buffer
+	json.Indent(&indentado, []byte("["+strings.Join(ordenados, ",")+"]"), "", "  ")
+	if err != nil || n != len(documentos) || salida.String() != indentado.String()+"\n" {
+		status = "Fallido"
+		t.Errorf("Arreglo: n=%d err=%v\n%s\nesperado:\n%s", n, err, salida.String(), indentado.String())
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Documento inválido en la posición 1")
+	salida.Reset()
+	n, err = ordenJson.OrdenarFlujo(strings.NewReader(`{"a": 1}`+"\n"+`{"b": }`), &salida)
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if n != 1 || !errors.As(err, &errJSON) || !strings.Contains(err.Error(), "documento 1") || salida.String() != "{\"a\":1}\n" {
+		status = "Fallido"
+		t.Errorf("Con error: n=%d err=%v salida=%q", n, err, salida.String())
+	}
+
+	actual := ResultadosObtenidos{JsonSalida: salida.String()}
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// generadorNDJSON produce documentos NDJSON a pedido y cuenta los bytes leídos.
+type generadorNDJSON struct {
+	restantes int
+	pendiente []byte
+	leidos    atomic.Int64
+}
+
+func (g *generadorNDJSON) Read(p []byte) (int, error) {
+	if len(g.pendiente) == 0 {
+		if g.restantes == 0 {
+			return 0, io.EOF
+		}
+		g.restantes--
+		g.pendiente = []byte(fmt.Sprintf(`{"zzz": %d, "cm:title": "titulo %d", "tanner:tipo-documento": "contrato"}`+"\n", g.restantes, g.restantes))
+	}
+	n := copy(p, g.pendiente)
+	g.pendiente = g.pendiente[n:]
+	g.leidos.Add(int64(n))
+	return n, nil
+}
+
+func TestOrdenarFlujo_ContrapresionConMemoriaAcotada(t *testing.T) {
+	const documentos = 100_000 // Unos 8 MB de entrada.
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, fmt.Sprintf("%d documentos generados a pedido", documentos))
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Con la salida bloqueada solo se lee una parte acotada de la entrada"})
+
+	entrada := &generadorNDJSON{restantes: documentos}
+	lector, escritor := io.Pipe()
+	type resultado struct {
+		n   int
+		err error
+	}
+	fin := make(chan resultado, 1)
+	go func() {
+		n, err := ordenJson.OrdenarFlujo(entrada, escritor)
+		escritor.CloseWithError(err)
+		fin <- resultado{n, err}
+	}()
+
+	registradorGlobal.AgregarProceso(testName, "Esperando con la salida sin leer")
+	time.Sleep(100 * time.Millisecond)
+	status := "Completado"
+	if leidos := entrada.leidos.Load(); leidos > 64<<10 {
+		status = "Fallido"
+		t.Errorf("Con la salida bloqueada se leyeron %d bytes de la entrada", leidos)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Consumiendo la salida")
+	lineas := 0
+	var buf [32 << 10]byte
+	for {
+		n, err := lector.Read(buf[:])
+		lineas += bytes.Count(buf[:n], []byte("\n"))
+		if err != nil {
+			break
+		}
+	}
+	r := <-fin
+	if r.err != nil || r.n != documentos || lineas != documentos {
+		status = "Fallido"
+		t.Errorf("n=%d lineas=%d err=%v", r.n, lineas, r.err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
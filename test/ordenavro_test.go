@@ -0,0 +1,82 @@
+package test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenavro"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestOrdenAvro_Codificar(t *testing.T) {
+	docs := []ordenJson.DocumentMetadata{
+		{TipoDocumento: "contrato", CmTitle: "Contrato"},
+		{TipoDocumento: "anexo", RUTCliente: "1-9"},
+	}
+	var expectedOrder []string
+	for _, clave := range ordenJson.OrdenCampos {
+		expectedOrder = append(expectedOrder, ordenavro.NombreCampo(clave))
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, docs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Revisando el orden de los campos del esquema")
+	var actual ResultadosObtenidos
+	for _, campo := range ordenavro.Esquema.(*avro.RecordSchema).Fields() {
+		actual.ClavesOrdenadas = append(actual.ClavesOrdenadas, campo.Name())
+	}
+	status := "Completado"
+	if !reflect.DeepEqual(actual.ClavesOrdenadas, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden de campos incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, actual.ClavesOrdenadas)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Codificando registros con EncodeAvro y decodificándolos")
+	registros, err := ordenavro.EncodeAvro(docs)
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("EncodeAvro() error = %v", err)
+	}
+	var leido map[string]interface{}
+	if err := avro.Unmarshal(ordenavro.Esquema, registros[1], &leido); err != nil {
+		t.Fatalf("avro.Unmarshal() error = %v", err)
+	}
+	if leido["tanner_tipo_documento"] != "anexo" || leido["tanner_rut_cliente"] != "1-9" || leido["cm_title"] != nil {
+		status = "Fallido"
+		t.Errorf("Registro decodificado incorrecto: %v", leido)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Escribiendo y leyendo un archivo contenedor")
+	var contenedor bytes.Buffer
+	if err := ordenavro.EscribirContenedor(&contenedor, docs); err != nil {
+		t.Fatalf("EscribirContenedor() error = %v", err)
+	}
+	lector, err := ocf.NewDecoder(&contenedor)
+	if err != nil {
+		t.Fatalf("ocf.NewDecoder() error = %v", err)
+	}
+	var titulos []interface{}
+	for lector.HasNext() {
+		var registro map[string]interface{}
+		if err := lector.Decode(&registro); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		titulos = append(titulos, registro["cm_title"])
+	}
+	if !reflect.DeepEqual(titulos, []interface{}{"Contrato", nil}) {
+		status = "Fallido"
+		t.Errorf("Contenido del contenedor incorrecto: %v", titulos)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenbson"
+)
+
+func TestOrdenBSON_ConservaOrden(t *testing.T) {
+	input := `{"cm:title": "Contrato", "extra": {"z": 1, "a": [1.5, "x"]}, "tanner:tipo-documento": "contrato"}`
+	expected := bson.D{
+		{Key: "_tanner:tipo-documento", Value: "contrato"},
+		{Key: "_cm:title", Value: "Contrato"},
+		{Key: "extra", Value: bson.D{{Key: "a", Value: bson.A{1.5, "x"}}, {Key: "z", Value: int64(1)}}},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: []string{"_tanner:tipo-documento", "_cm:title", "extra"}})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con OrdenarBSON")
+	got, err := ordenbson.OrdenarBSON(input)
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarBSON() error = %v", err)
+	}
+	for _, e := range got {
+		actual.ClavesOrdenadas = append(actual.ClavesOrdenadas, e.Key)
+	}
+
+	status := "Completado"
+	if !reflect.DeepEqual(got, expected) {
+		status = "Fallido"
+		t.Errorf("bson.D incorrecto.\nEsperado: %v\nObtenido: %v", expected, got)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Serializando a BSON y leyendo el orden de los campos")
+	crudo, err := bson.Marshal(got)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	elementos, err := bson.Raw(crudo).Elements()
+	if err != nil || len(elementos) != 3 || elementos[0].Key() != "_tanner:tipo-documento" || elementos[2].Key() != "extra" {
+		status = "Fallido"
+		t.Errorf("El BSON serializado no conserva el orden: %v (%v)", bson.Raw(crudo), err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
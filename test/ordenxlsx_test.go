@@ -0,0 +1,65 @@
+package test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenxlsx"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestOrdenXLSX_ExportarXLSX(t *testing.T) {
+	docs := []ordenJson.DocumentMetadata{
+		{TipoDocumento: "contrato", CmTitle: "Contrato"},
+		{TipoDocumento: "anexo", RUTCliente: "1-9"},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, docs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: ordenJson.OrdenCampos})
+
+	registradorGlobal.AgregarProceso(testName, "Exportando dos documentos con ExportarXLSX")
+	ruta := filepath.Join(t.TempDir(), "metadatos.xlsx")
+	var actual ResultadosObtenidos
+	if err := ordenxlsx.ExportarXLSX(docs, ruta); err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("ExportarXLSX() error = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Leyendo la planilla generada")
+	libro, err := excelize.OpenFile(ruta)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer libro.Close()
+	filas, err := libro.GetRows(ordenxlsx.NombreHoja)
+	if err != nil {
+		t.Fatalf("GetRows() error = %v", err)
+	}
+	status := "Completado"
+	if len(filas) != 3 {
+		t.Fatalf("Se esperaban 3 filas, se obtuvieron %d", len(filas))
+	}
+	actual.ClavesOrdenadas = filas[0]
+	if !reflect.DeepEqual(filas[0], ordenJson.OrdenCampos) {
+		status = "Fallido"
+		t.Errorf("Encabezado incorrecto. Esperado: %v, Obtenido: %v", ordenJson.OrdenCampos, filas[0])
+	}
+	if filas[1][0] != "contrato" || filas[1][12] != "Contrato" || filas[2][0] != "anexo" || filas[2][2] != "1-9" {
+		status = "Fallido"
+		t.Errorf("Filas incorrectas: %q", filas[1:])
+	}
+	if paneles, err := libro.GetPanes(ordenxlsx.NombreHoja); err != nil || !paneles.Freeze || paneles.YSplit != 1 {
+		status = "Fallido"
+		t.Errorf("El encabezado no quedó fijo: %+v (%v)", paneles, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
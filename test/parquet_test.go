@@ -0,0 +1,73 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	parquetgo "github.com/parquet-go/parquet-go"
+
+	"github.com/samuel/prueba-orden/ordenJson/parquet"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestParquet_EscribirArchivo(t *testing.T) {
+	docs := []ordenJson.DocumentMetadata{
+		{TipoDocumento: "contrato", CmTitle: "Contrato"},
+		{TipoDocumento: "anexo", RUTCliente: "1-9"},
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, docs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: ordenJson.OrdenCampos})
+
+	registradorGlobal.AgregarProceso(testName, "Escribiendo dos documentos con EscribirArchivo")
+	ruta := filepath.Join(t.TempDir(), "metadatos.parquet")
+	var actual ResultadosObtenidos
+	if err := parquet.EscribirArchivo(ruta, docs); err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("EscribirArchivo() error = %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Leyendo las columnas y las filas del archivo")
+	archivo, err := os.Open(ruta)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer archivo.Close()
+	info, _ := archivo.Stat()
+	leido, err := parquetgo.OpenFile(archivo, info.Size())
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	for _, columna := range leido.Schema().Fields() {
+		actual.ClavesOrdenadas = append(actual.ClavesOrdenadas, columna.Name())
+	}
+	status := "Completado"
+	if !reflect.DeepEqual(actual.ClavesOrdenadas, ordenJson.OrdenCampos) {
+		status = "Fallido"
+		t.Errorf("Orden de columnas incorrecto. Esperado: %v, Obtenido: %v", ordenJson.OrdenCampos, actual.ClavesOrdenadas)
+	}
+	if leido.NumRows() != 2 {
+		status = "Fallido"
+		t.Errorf("Se esperaban 2 filas, se obtuvieron %d", leido.NumRows())
+	}
+
+	filas := make([]parquetgo.Row, 2)
+	lector := parquetgo.NewReader(leido)
+	if n, _ := lector.ReadRows(filas); n != 2 {
+		t.Fatalf("ReadRows() leyó %d filas", n)
+	}
+	tipo, titulo := filas[0][0], filas[0][12]
+	if tipo.String() != "contrato" || titulo.String() != "Contrato" || !filas[1][12].IsNull() {
+		status = "Fallido"
+		t.Errorf("Filas incorrectas: %v", filas)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
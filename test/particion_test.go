@@ -0,0 +1,101 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestParticionar_Estable(t *testing.T) {
+	a := `{"cm:title": "Contrato", "tanner:rut-cliente": "12345678-9", "tanner:tipo-documento": "contrato"}`
+	b := `{
+		"tanner:tipo-documento": "contrato",
+		"tanner:rut-cliente": "12345678-9",
+		"cm:title": "Contrato"
+	}`
+	otroTitulo := `{"cm:title": "Otro", "tanner:rut-cliente": "12345678-9"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, a)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Misma partición para el mismo contenido canónico o la misma clave"})
+
+	registradorGlobal.AgregarProceso(testName, "Particionando el mismo documento con distinto orden y formato")
+	pa, errA := ordenJson.Particionar(a, 16)
+	pb, errB := ordenJson.Particionar(b, 16)
+	actual := ResultadosObtenidos{JsonSalida: fmt.Sprintf("a=%d b=%d", pa, pb)}
+	status := "Completado"
+	if errA != nil || errB != nil || pa != pb || pa < 0 || pa >= 16 {
+		status = "Fallido"
+		t.Errorf("Particiones distintas o inválidas: %d (%v), %d (%v)", pa, errA, pb, errB)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Particionando el mismo documento con las claves fuera del perfil en otro orden")
+	for _, par := range [][2]string{
+		{`{"cm:title": "t", "x-b": "1", "x-a": "2"}`, `{"x-a": "2", "cm:title": "t", "x-b": "1"}`},
+		{`{"x-b": {"d": 1, "c": [2]}, "x-a": 3}`, `{"x-a": 3, "x-b": {"c": [2], "d": 1}}`},
+	} {
+		p1, err1 := ordenJson.Particionar(par[0], 1000)
+		p2, err2 := ordenJson.Particionar(par[1], 1000)
+		if err1 != nil || err2 != nil || p1 != p2 {
+			status = "Fallido"
+			t.Errorf("Particiones distintas para %s y %s: %d (%v), %d (%v)", par[0], par[1], p1, err1, p2, err2)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Particionando por RUT")
+	porRUT := ordenJson.Nuevo(ordenJson.WithClaveParticion("tanner:rut-cliente"))
+	p1, err1 := porRUT.Particionar(a, 16)
+	p2, err2 := porRUT.Particionar(map[string]interface{}{"tanner:rut-cliente": "12345678-9"}, 16)
+	p3, err3 := porRUT.Particionar(otroTitulo, 16)
+	if err1 != nil || err2 != nil || err3 != nil || p1 != p2 || p1 != p3 {
+		status = "Fallido"
+		t.Errorf("Por RUT se esperaba la misma partición: %d %d %d (%v %v %v)", p1, p2, p3, err1, err2, err3)
+	}
+	var faltantes *ordenJson.ErrorCamposFaltantes
+	if _, err := porRUT.Particionar(`{"cm:title": "x"}`, 16); !errors.As(err, &faltantes) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorCamposFaltantes, se obtuvo %v", err)
+	}
+	if _, err := ordenJson.Particionar(a, 0); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba un error con cero particiones")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestParticionar_Distribucion(t *testing.T) {
+	const documentos, particiones = 4000, 8
+	conteo := make([]int, particiones)
+	movidos := 0
+	porRUT := ordenJson.Nuevo(ordenJson.WithClaveParticion("tanner:rut-cliente"))
+	for i := 0; i < documentos; i++ {
+		doc := map[string]interface{}{"tanner:rut-cliente": fmt.Sprintf("%d-%d", 10000000+i, i%10)}
+		p, err := porRUT.Particionar(doc, particiones)
+		if err != nil {
+			t.Fatalf("Particionar() error = %v", err)
+		}
+		conteo[p]++
+		// Al agregar una partición, un documento solo puede quedarse o pasar a la nueva.
+		q, _ := porRUT.Particionar(doc, particiones+1)
+		if q != p {
+			movidos++
+			if q != particiones {
+				t.Fatalf("El documento %d pasó de %d a %d", i, p, q)
+			}
+		}
+	}
+	for p, n := range conteo {
+		if n < documentos/particiones*3/4 || n > documentos/particiones*5/4 {
+			t.Errorf("Partición %d desbalanceada: %v", p, conteo)
+		}
+	}
+	if movidos > documentos/(particiones+1)*3/2 {
+		t.Errorf("Se movieron demasiados documentos al agregar una partición: %d", movidos)
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestAplicarPatch(t *testing.T) {
+	doc := `{"x-extra": 1, "cm:title": "viejo", "etiquetas": ["a", "c"], "borrar": true, "datos": {"n": 1}}`
+	patch := `[
+		{"op": "test", "path": "/cm:title", "value": "viejo"},
+		{"op": "replace", "path": "/cm:title", "value": "nuevo"},
+		{"op": "add", "path": "/etiquetas/1", "value": "b"},
+		{"op": "add", "path": "/etiquetas/-", "value": "d"},
+		{"op": "remove", "path": "/borrar"},
+		{"op": "add", "path": "/tanner:tipo-documento", "value": "contrato"},
+		{"op": "copy", "from": "/datos", "path": "/copia"},
+		{"op": "move", "from": "/datos/n", "path": "/datos/m"}
+	]`
+	expected := []string{"tanner:tipo-documento", "cm:title", "x-extra", "etiquetas", "datos", "m", "copia", "n"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, doc)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Aplicando el patch con AplicarPatch")
+	salida, err := ordenJson.AplicarPatch(doc, patch)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("AplicarPatch() error = %v", err)
+	}
+
+	claves := extraerClavesJSON(salida)
+	actual := ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+	status := "Completado"
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	esperado := `{"tanner:tipo-documento": "contrato", "cm:title": "nuevo", "x-extra": 1, "etiquetas": ["a", "b", "c", "d"], "datos": {"m": 1}, "copia": {"n": 1}}`
+	if d, err := ordenJson.Diff(salida, esperado); err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Resultado incorrecto: %v, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando los errores de operaciones que no se pueden aplicar")
+	fallidos := []string{
+		`[{"op": "test", "path": "/cm:title", "value": "otro"}]`,
+		`[{"op": "remove", "path": "/no-existe"}]`,
+		`[{"op": "add", "path": "/etiquetas/5", "value": "z"}]`,
+		`[{"op": "move", "from": "/datos", "path": "/datos/hijo"}]`,
+		`[{"op": "replace", "path": "", "value": [1]}]`,
+		`[{"op": "mezclar", "path": "/a"}]`,
+	}
+	for _, p := range fallidos {
+		_, err := ordenJson.AplicarPatch(doc, p)
+		var errPatch *ordenJson.ErrorPatch
+		if !errors.As(err, &errPatch) || errPatch.Indice != 0 {
+			status = "Fallido"
+			t.Errorf("Se esperaba ErrorPatch para %s, se obtuvo %v", p, err)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestAplicarMergePatch(t *testing.T) {
+	doc := `{"x-extra": 1, "cm:title": "viejo", "borrar": true, "datos": {"n": 1, "m": 2}, "lista": [1, 2]}`
+	patch := `{"cm:title": "nuevo", "borrar": null, "datos": {"m": null, "o": {"p": null, "q": 3}}, "lista": [3], "tanner:tipo-documento": "contrato", "z-nueva": "z"}`
+	expected := []string{"tanner:tipo-documento", "cm:title", "x-extra", "datos", "n", "o", "q", "lista", "z-nueva"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, doc)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Aplicando el merge patch con AplicarMergePatch")
+	salida, err := ordenJson.AplicarMergePatch(doc, patch)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("AplicarMergePatch() error = %v", err)
+	}
+
+	claves := extraerClavesJSON(salida)
+	actual := ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+	status := "Completado"
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	esperado := `{"tanner:tipo-documento": "contrato", "cm:title": "nuevo", "x-extra": 1, "datos": {"n": 1, "o": {"q": 3}}, "lista": [3], "z-nueva": "z"}`
+	if d, err := ordenJson.Diff(salida, esperado); err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Resultado incorrecto: %v, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error ante un patch que no es un objeto")
+	_, err = ordenJson.AplicarMergePatch(doc, `[1]`)
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorJSONInvalido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
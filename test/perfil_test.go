@@ -0,0 +1,100 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestPerfilPersonalizado(t *testing.T) {
+	perfil := ordenJson.NuevoPerfil("factura", []string{
+		"cm:title",
+		"tanner:rut-cliente",
+		"cm:title",
+		"tanner:tipo-documento",
+	})
+	input := `{
+		"tanner:tipo-documento": "factura",
+		"extra": "x",
+		"tanner:rut-cliente": "12345678-9",
+		"cm:title": "Factura 1"
+	}`
+
+	expectedOrder := []string{"cm:title", "tanner:rut-cliente", "tanner:tipo-documento", "extra"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con perfil personalizado")
+	got, err := ordenJson.OrdenarJSON(input, ordenJson.WithPerfil(perfil))
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	keys := extraerClavesJSON(got)
+	actual = ResultadosObtenidos{
+		ClavesOrdenadas: keys,
+		JsonSalida:      got,
+	}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if campos := perfil.Campos(); len(campos) != 3 {
+		status = "Fallido"
+		t.Errorf("Se esperaban 3 campos sin repetir en el perfil, se obtuvieron %v", campos)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestTamanoEsperado_NoAlteraSalida(t *testing.T) {
+	input := jsonConMilesDeClaves(50)
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Salida idéntica para cualquier tamaño esperado"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON sin tamaño esperado")
+	referencia, err := ordenJson.OrdenarJSON(input)
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	actual = ResultadosObtenidos{JsonSalida: referencia}
+
+	status := "Completado"
+	for _, tamano := range []int{-1, 1, len(referencia), 10 * len(referencia)} {
+		registradorGlobal.AgregarProceso(testName, fmt.Sprintf("Ejecutando OrdenarJSON con tamaño esperado %d", tamano))
+		got, err := ordenJson.OrdenarJSON(input, ordenJson.WithTamanoEsperado(tamano))
+		if err != nil {
+			status = "Fallido"
+			t.Errorf("Tamaño %d: error inesperado: %v", tamano, err)
+			continue
+		}
+		if got != referencia {
+			status = "Fallido"
+			t.Errorf("Tamaño %d: la salida difiere de la referencia", tamano)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
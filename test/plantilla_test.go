@@ -0,0 +1,82 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestGenerarPlantilla(t *testing.T) {
+	esquema := `{
+		"title": "plantilla-test",
+		"properties": {
+			"tanner:tipo-documento": {"type": "string", "enum": ["contrato", "factura"]},
+			"monto": {"type": "number"},
+			"vigente": {"type": "boolean"},
+			"etiquetas": {"type": "array"}
+		},
+		"required": ["tanner:tipo-documento"]
+	}`
+	expected := []string{"tanner:tipo-documento", "monto", "vigente", "etiquetas", "tanner:rut-cliente"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, esquema)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Generando la plantilla del perfil por defecto")
+	status := "Completado"
+	plantilla, err := ordenJson.GenerarPlantilla(ordenJson.PerfilPorDefecto.Nombre())
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("GenerarPlantilla() error = %v", err)
+	}
+	if claves := extraerClavesJSON(plantilla); !reflect.DeepEqual(claves, ordenJson.OrdenCampos) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", ordenJson.OrdenCampos, claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Generando la plantilla de un perfil registrado desde un esquema")
+	perfil, err := ordenJson.PerfilDesdeEsquema([]byte(esquema))
+	if err != nil {
+		t.Fatalf("PerfilDesdeEsquema() error = %v", err)
+	}
+	ordenJson.RegistrarPerfil(perfil)
+	if _, ok := ordenJson.BuscarPerfil(perfil.Nombre()); !ok {
+		status = "Fallido"
+		t.Errorf("BuscarPerfil(%q) no encontró el perfil registrado", perfil.Nombre())
+	}
+	plantilla, err = ordenJson.Nuevo(
+		ordenJson.WithPerfil(perfil),
+		ordenJson.WithRequired("tanner:rut-cliente"),
+		ordenJson.WithDefaults(map[string]interface{}{"vigente": true}),
+	).GenerarPlantilla()
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("GenerarPlantilla() error = %v", err)
+	}
+	claves := extraerClavesJSON(plantilla)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	esperado := `{"tanner:tipo-documento": "", "monto": 0, "vigente": true, "etiquetas": [], "tanner:rut-cliente": ""}`
+	if d, err := ordenJson.Diff(plantilla, esperado); err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Valores incorrectos: %v, %v", d, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error ante un perfil desconocido")
+	_, err = ordenJson.GenerarPlantilla("no-existe")
+	var errPerfil *ordenJson.ErrorPerfilDesconocido
+	if !errors.As(err, &errPerfil) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorPerfilDesconocido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: plantilla}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/postgres"
+)
+
+// sqlFalso es un driver de database/sql que responde a cualquier consulta con
+// filasFalsas y registra las consultas recibidas.
+type sqlFalso struct {
+	mu        sync.Mutex
+	consultas []string
+}
+
+var (
+	driverSQLFalso = &sqlFalso{}
+	filasFalsas    [][]driver.Value
+)
+
+func init() {
+	sql.Register("postgresfalso", driverSQLFalso)
+}
+
+func (d *sqlFalso) Open(string) (driver.Conn, error) { return conexionFalsa{d}, nil }
+
+type conexionFalsa struct{ d *sqlFalso }
+
+func (conexionFalsa) Prepare(string) (driver.Stmt, error) { return nil, errors.New("no soportado") }
+func (conexionFalsa) Close() error                        { return nil }
+func (conexionFalsa) Begin() (driver.Tx, error)           { return nil, errors.New("no soportado") }
+
+func (c conexionFalsa) QueryContext(_ context.Context, consulta string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.mu.Lock()
+	c.d.consultas = append(c.d.consultas, consulta)
+	c.d.mu.Unlock()
+	return &filasFalsasSQL{filas: filasFalsas}, nil
+}
+
+type filasFalsasSQL struct{ filas [][]driver.Value }
+
+func (f *filasFalsasSQL) Columns() []string { return []string{"id", "metadata", "metadata_ordenada"} }
+func (f *filasFalsasSQL) Close() error      { return nil }
+
+func (f *filasFalsasSQL) Next(destino []driver.Value) error {
+	if len(f.filas) == 0 {
+		return io.EOF
+	}
+	copy(destino, f.filas[0])
+	f.filas = f.filas[1:]
+	return nil
+}
+
+func TestPostgres_EscribirUpdates(t *testing.T) {
+	// PostgreSQL devuelve jsonb con las claves más cortas primero.
+	filasFalsas = [][]driver.Value{
+		{"1", `{"zzz": "o'brien", "tanner:rut-cliente": "1-9"}`, nil},
+		{"2", `{"zzz": 2, "tanner:tipo-documento": "F"}`, `{"tanner:tipo-documento":"F","zzz":2}`},
+		{"3", `{"zzz": 2, "tanner:tipo-documento": "F"}`, `{"zzz":2,"tanner:tipo-documento":"F"}`},
+	}
+	db, err := sql.Open("postgresfalso", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "3 filas jsonb simuladas")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Se generan UPDATE solo para las filas cuyo destino no tiene el documento ordenado"})
+
+	registradorGlobal.AgregarProceso(testName, "Generando el script de UPDATE")
+	cfg := postgres.Config{Tabla: "public.documentos", Clave: "id", Columna: "metadata", Destino: "metadata_ordenada", Filtro: "id > 0"}
+	var script strings.Builder
+	n, err := postgres.EscribirUpdates(context.Background(), db, cfg, &script)
+
+	status := "Completado"
+	esperado := `UPDATE "public"."documentos" SET "metadata_ordenada" = '{"tanner:rut-cliente":"1-9","zzz":"o''brien"}' WHERE "id" = '1';` + "\n" +
+		`UPDATE "public"."documentos" SET "metadata_ordenada" = '{"tanner:tipo-documento":"F","zzz":2}' WHERE "id" = '3';` + "\n"
+	if err != nil || n != 2 || script.String() != esperado {
+		status = "Fallido"
+		t.Errorf("EscribirUpdates() = %d, %v:\n%s\nesperado:\n%s", n, err, script.String(), esperado)
+	}
+	consulta := driverSQLFalso.consultas[len(driverSQLFalso.consultas)-1]
+	if consulta != `SELECT "id"::text, "metadata"::text, "metadata_ordenada"::text FROM "public"."documentos" WHERE "metadata" IS NOT NULL AND (id > 0) ORDER BY "id"` {
+		status = "Fallido"
+		t.Errorf("consulta = %s", consulta)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando una configuración sin destino")
+	if _, err := postgres.EscribirUpdates(context.Background(), db, postgres.Config{Tabla: "t", Clave: "id", Columna: "j"}, io.Discard); err == nil {
+		status = "Fallido"
+		t.Error("EscribirUpdates() sin Destino no devolvió error")
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: script.String()}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestPresupuesto_Excedido(t *testing.T) {
+	input := jsonConMilesDeClaves(20000)
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "documento de 20000 claves")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorTiempoExcedido"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con presupuesto de 1µs")
+	_, err := ordenJson.OrdenarJSON(input, ordenJson.WithPresupuesto(time.Microsecond))
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	var errTiempo *ordenJson.ErrorTiempoExcedido
+	if !errors.As(err, &errTiempo) {
+		status = "Fallido"
+		t.Fatalf("Se esperaba *ErrorTiempoExcedido, se obtuvo %v", err)
+	}
+	if errTiempo.Presupuesto != time.Microsecond || errTiempo.Etapa == "" || !errTiempo.Timeout() {
+		status = "Fallido"
+		t.Errorf("Error incompleto: %+v", errTiempo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestPresupuesto_Suficiente(t *testing.T) {
+	input := `{"cm:title": "t", "tanner:tipo-documento": "contrato"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: []string{"tanner:tipo-documento", "cm:title"}})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con presupuesto holgado")
+	got, err := ordenJson.OrdenarJSON(input, ordenJson.WithPresupuesto(time.Minute))
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	keys := extraerClavesJSON(got)
+	actual = ResultadosObtenidos{
+		ClavesOrdenadas: keys,
+		JsonSalida:      got,
+	}
+
+	status := "Completado"
+	if len(keys) != 2 || keys[0] != "tanner:tipo-documento" {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto: %v", keys)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestPresupuesto_JSONMalformado(t *testing.T) {
+	_, err := ordenJson.OrdenarJSON(`{"cm:title": `, ordenJson.WithPresupuesto(time.Minute))
+
+	var errTiempo *ordenJson.ErrorTiempoExcedido
+	if err == nil || errors.As(err, &errTiempo) {
+		t.Errorf("Se esperaba un error de sintaxis, se obtuvo %v", err)
+	}
+}
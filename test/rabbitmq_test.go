@@ -0,0 +1,149 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/rabbitmq"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// canalAMQP entrega mensajes fijos, guarda las publicaciones y registra las
+// confirmaciones de cada mensaje por su DeliveryTag.
+type canalAMQP struct {
+	mu            sync.Mutex
+	entregas      chan amqp.Delivery
+	publicaciones map[string][]amqp.Publishing // Por exchange.
+	acks          []uint64
+	nacks         map[uint64]bool // DeliveryTag -> reencolado.
+	errLiquidar   error           // Si no es nil, Ack y Nack lo devuelven después de registrarse.
+}
+
+func nuevoCanalAMQP(documentos []string) *canalAMQP {
+	c := &canalAMQP{
+		entregas:      make(chan amqp.Delivery, len(documentos)),
+		publicaciones: map[string][]amqp.Publishing{},
+		nacks:         map[uint64]bool{},
+	}
+	for i, doc := range documentos {
+		c.entregas <- amqp.Delivery{
+			Acknowledger: c,
+			DeliveryTag:  uint64(i + 1),
+			Exchange:     "entrada",
+			RoutingKey:   "docs",
+			MessageId:    string(rune('a' + i)),
+			Headers:      amqp.Table{"x-app": "prueba"},
+			Body:         []byte(doc),
+		}
+	}
+	close(c.entregas)
+	return c
+}
+
+func (c *canalAMQP) Consume(string, string, bool, bool, bool, bool, amqp.Table) (<-chan amqp.Delivery, error) {
+	return c.entregas, nil
+}
+
+func (c *canalAMQP) PublishWithDeferredConfirmWithContext(_ context.Context, exchange, _ string, _, _ bool, msg amqp.Publishing) (*amqp.DeferredConfirmation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.publicaciones[exchange] = append(c.publicaciones[exchange], msg)
+	return nil, nil
+}
+
+func (c *canalAMQP) Ack(tag uint64, _ bool) error {
+	c.acks = append(c.acks, tag)
+	return c.errLiquidar
+}
+
+func (c *canalAMQP) Nack(tag uint64, _ bool, reencolar bool) error {
+	c.nacks[tag] = reencolar
+	return c.errLiquidar
+}
+
+func (c *canalAMQP) Reject(tag uint64, reencolar bool) error {
+	return c.Nack(tag, false, reencolar)
+}
+
+func TestRabbitMQ_ConsumidorConMensajesMuertos(t *testing.T) {
+	documentos := []string{
+		`{"zzz": 1, "cm:title": "uno"}`,
+		`{"cm:title": "sin cerrar"`,
+		`{"zzz": 2}`,
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, documentos)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Los válidos se publican ordenados, los inválidos van al exchange de mensajes muertos y todos se confirman"})
+
+	registradorGlobal.AgregarProceso(testName, "Consumiendo con un requerido y un JSON inválido")
+	canal := nuevoCanalAMQP(documentos)
+	consumidor, err := rabbitmq.Nuevo(rabbitmq.Config{
+		Canal:           canal,
+		Cola:            "documentos",
+		Exchange:        "ordenados",
+		ExchangeMuertos: "rechazados",
+		Ordenador:       ordenJson.Nuevo(ordenJson.WithRequired("cm:title")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// El canal simulado se cierra al agotar los mensajes.
+	err = consumidor.Ejecutar(context.Background())
+
+	status := "Completado"
+	if err == nil || len(canal.acks) != 3 || len(canal.nacks) != 0 || consumidor.Resumen() != (rabbitmq.Resumen{Publicados: 1, Rechazados: 2}) {
+		status = "Fallido"
+		t.Fatalf("Ejecutar() = %v; acks %v; nacks %v; %+v", err, canal.acks, canal.nacks, consumidor.Resumen())
+	}
+	salida := canal.publicaciones["ordenados"][0]
+	if string(salida.Body) != `{"cm:title":"uno","zzz":1}` || salida.MessageId != "a" || salida.Headers["x-app"] != "prueba" || salida.ContentType != "application/json" {
+		status = "Fallido"
+		t.Errorf("Salida = %s, %+v", salida.Body, salida)
+	}
+	muerto := canal.publicaciones["rechazados"][0]
+	if string(muerto.Body) != documentos[1] || muerto.Headers[rabbitmq.EncabezadoTipo] != cuarentena.TipoJSONInvalido ||
+		muerto.Headers[rabbitmq.EncabezadoOrigen] != "entrada/docs" || muerto.Headers["x-app"] != "prueba" {
+		status = "Fallido"
+		t.Errorf("Mensaje muerto = %s, %v", muerto.Body, muerto.Headers)
+	}
+	if err := muerto.Headers.Validate(); err != nil {
+		status = "Fallido"
+		t.Errorf("Encabezados del mensaje muerto: %v", err)
+	}
+	if tipo := canal.publicaciones["rechazados"][1].Headers[rabbitmq.EncabezadoTipo]; tipo != cuarentena.TipoValidacion {
+		status = "Fallido"
+		t.Errorf("Tipo del segundo mensaje muerto = %v", tipo)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando sin exchange de mensajes muertos")
+	canal = nuevoCanalAMQP(documentos[1:2])
+	consumidor, _ = rabbitmq.Nuevo(rabbitmq.Config{Canal: canal, Cola: "documentos", Exchange: "ordenados"})
+	consumidor.Ejecutar(context.Background())
+	if reencolado, ok := canal.nacks[1]; !ok || reencolado || len(canal.acks) != 0 || len(canal.publicaciones) != 0 {
+		status = "Fallido"
+		t.Errorf("nacks %v; acks %v; publicaciones %v", canal.nacks, canal.acks, canal.publicaciones)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Fallando al confirmar o rechazar, sin volver a rechazar")
+	for _, doc := range []string{documentos[0], documentos[1]} {
+		canal = nuevoCanalAMQP(nil)
+		canal.errLiquidar = errors.New("canal cerrado")
+		consumidor, _ = rabbitmq.Nuevo(rabbitmq.Config{Canal: canal, Cola: "documentos", Exchange: "ordenados"})
+		err := consumidor.Procesar(context.Background(), amqp.Delivery{Acknowledger: canal, DeliveryTag: 1, Body: []byte(doc)})
+		if liquidaciones := len(canal.acks) + len(canal.nacks); !errors.Is(err, canal.errLiquidar) || liquidaciones != 1 || canal.nacks[1] {
+			status = "Fallido"
+			t.Errorf("Procesar(%s) = %v; acks %v; nacks %v", doc, err, canal.acks, canal.nacks)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: string(salida.Body)}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenecho"
+	"github.com/samuel/prueba-orden/ordenJson/ordengin"
+)
+
+func TestOrdenGin_JSON(t *testing.T) {
+	input := map[string]interface{}{"cm:title": "Contrato", "tanner:rut-cliente": "1-9", "tanner:tipo-documento": "contrato"}
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Respondiendo con ordengin.JSON desde un handler de gin")
+	gin.SetMode(gin.TestMode)
+	motor := gin.New()
+	motor.GET("/", func(c *gin.Context) { ordengin.JSON(c, http.StatusCreated, input) })
+	grabador := httptest.NewRecorder()
+	motor.ServeHTTP(grabador, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cuerpo := grabador.Body.String()
+	keys := extraerClavesJSON(cuerpo)
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: cuerpo}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if grabador.Code != http.StatusCreated {
+		status = "Fallido"
+		t.Errorf("Se esperaba el código 201, se obtuvo %d", grabador.Code)
+	}
+	if tipo := grabador.Header().Get("Content-Type"); tipo != "application/json; charset=utf-8" {
+		status = "Fallido"
+		t.Errorf("Content-Type inesperado: %q", tipo)
+	}
+	if strings.Contains(cuerpo, "\n") {
+		status = "Fallido"
+		t.Errorf("Se esperaba una respuesta compacta, se obtuvo %q", cuerpo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestOrdenEcho_Serializador(t *testing.T) {
+	input := map[string]interface{}{"cm:title": "Contrato", "tanner:rut-cliente": "1-9", "tanner:tipo-documento": "contrato"}
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Respondiendo con c.JSON y c.JSONPretty en echo con ordenecho.Serializador")
+	e := echo.New()
+	e.JSONSerializer = ordenecho.Serializador{}
+	e.GET("/", func(c echo.Context) error { return c.JSON(http.StatusOK, input) })
+	e.GET("/pretty", func(c echo.Context) error { return c.JSONPretty(http.StatusOK, input, "\t") })
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+	for ruta, esperado := range map[string]string{
+		"/":       `{"tanner:tipo-documento":"contrato","tanner:rut-cliente":"1-9","cm:title":"Contrato"}` + "\n",
+		"/pretty": "{\n\t\"tanner:tipo-documento\": \"contrato\",\n\t\"tanner:rut-cliente\": \"1-9\",\n\t\"cm:title\": \"Contrato\"\n}\n",
+	} {
+		grabador := httptest.NewRecorder()
+		e.ServeHTTP(grabador, httptest.NewRequest(http.MethodGet, ruta, nil))
+		cuerpo := grabador.Body.String()
+		if ruta == "/" {
+			actual = ResultadosObtenidos{ClavesOrdenadas: extraerClavesJSON(cuerpo), JsonSalida: cuerpo}
+		}
+		if cuerpo != esperado {
+			status = "Fallido"
+			t.Errorf("%s: se esperaba %q, se obtuvo %q", ruta, esperado, cuerpo)
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Leyendo una solicitud inválida con c.Bind")
+	e.POST("/", func(c echo.Context) error {
+		var destino map[string]interface{}
+		return c.Bind(&destino)
+	})
+	solicitud := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"cm:title":`))
+	solicitud.Header.Set("Content-Type", "application/json")
+	grabador := httptest.NewRecorder()
+	e.ServeHTTP(grabador, solicitud)
+	if grabador.Code != http.StatusBadRequest {
+		status = "Fallido"
+		t.Errorf("Se esperaba el código 400 con un cuerpo inválido, se obtuvo %d", grabador.Code)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
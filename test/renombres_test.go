@@ -0,0 +1,71 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestWithRenombres(t *testing.T) {
+	input := `{"x-extra": "1", "titulo": "Contrato", "tanner:rut_cliente": "1-9", "tanner:tipo-documento": "contrato", "tanner:tipo_documento": "viejo"}`
+	renombres := map[string]string{
+		"tanner:rut_cliente":    "tanner:rut-cliente",
+		"titulo":                "cm:title",
+		"tanner:tipo_documento": "tanner:tipo-documento",
+	}
+	expected := []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title", "x-extra"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con WithRenombres")
+	o := ordenJson.Nuevo(ordenJson.WithRenombres(renombres), ordenJson.WithRequired("tanner:rut-cliente"))
+	salida, err := o.OrdenarJSON(input)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	claves := extraerClavesJSON(salida)
+	actual := ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+	status := "Completado"
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	if !strings.Contains(salida, `"tanner:tipo-documento": "contrato"`) {
+		status = "Fallido"
+		t.Errorf("Debía prevalecer el valor de la clave con el nombre actual:\n%s", salida)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Intercambiando dos claves con WithRenombres")
+	intercambio, err := ordenJson.OrdenarJSON(`{"a": 1, "b": 2, "c": 3}`, ordenJson.WithRenombres(map[string]string{"a": "b", "b": "c", "c": "a"}))
+	if iguales, _ := ordenJson.Iguales(intercambio, `{"b": 1, "c": 2, "a": 3}`); err != nil || !iguales {
+		status = "Fallido"
+		t.Errorf("Se esperaban los valores rotados, se obtuvo %s (%v)", intercambio, err)
+	}
+	intercambio, err = ordenJson.OrdenarJSON(`{"a": 1, "b": 2}`, ordenJson.WithRenombres(map[string]string{"a": "b", "b": "a"}))
+	if iguales, _ := ordenJson.Iguales(intercambio, `{"a": 2, "b": 1}`); err != nil || !iguales {
+		status = "Fallido"
+		t.Errorf("Se esperaban los valores intercambiados, se obtuvo %s (%v)", intercambio, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que un mapa de entrada no se modifica")
+	mapa := map[string]interface{}{"titulo": "Contrato"}
+	if _, err := o.OrdenarJSON(mapa); err == nil {
+		status = "Fallido"
+		t.Errorf("Se esperaba el error de campo requerido")
+	}
+	if _, ok := mapa["titulo"]; !ok || len(mapa) != 1 {
+		status = "Fallido"
+		t.Errorf("WithRenombres modificó el mapa recibido: %v", mapa)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
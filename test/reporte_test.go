@@ -0,0 +1,170 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestOrdenarJSONConReporte(t *testing.T) {
+	input := `{
+		"tanner:rut_cliente": "12345678-9",
+		"cm:title": "Contrato",
+		"tanner:fecha-carga": "ayer",
+		"tanner:estado-visado": "listo",
+		"tanner:tipo-documento": "contrato"
+	}`
+
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:estado-visado", "tanner:fecha-carga", "cm:title", "tanner:rut_cliente"}
+	expectedProblemas := []string{
+		ordenJson.ReglaFecha + " tanner:fecha-carga",
+		ordenJson.ReglaRequerido + " tanner:rut-cliente",
+		ordenJson.ReglaClaveDesconocida + " tanner:rut_cliente",
+		ordenJson.ReglaValorPermitido + " tanner:estado-visado",
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder, CustomCheck: expectedProblemas})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSONConReporte con varios problemas")
+	got, problemas, err := ordenJson.OrdenarJSONConReporte(input,
+		ordenJson.WithRequired("tanner:rut-cliente"),
+		ordenJson.WithNormalizarFechas(),
+		ordenJson.WithValidarEstados(),
+	)
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual = ResultadosObtenidos{Error: err.Error()}
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("OrdenarJSONConReporte() error = %v", err)
+	}
+
+	keys := extraerClavesJSON(got)
+	actual = ResultadosObtenidos{
+		ClavesOrdenadas: keys,
+		JsonSalida:      got,
+	}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if !strings.Contains(got, `"tanner:fecha-carga": "ayer"`) {
+		status = "Fallido"
+		t.Errorf("La fecha inválida debía mantenerse sin cambios:\n%s", got)
+	}
+
+	var obtenidos []string
+	for _, p := range problemas {
+		obtenidos = append(obtenidos, p.Regla+" "+p.Campo)
+		if p.Mensaje == "" {
+			status = "Fallido"
+			t.Errorf("Problema sin mensaje: %+v", p)
+		}
+	}
+	if !reflect.DeepEqual(obtenidos, expectedProblemas) {
+		status = "Fallido"
+		t.Errorf("Problemas esperados: %v, obtenidos: %v", expectedProblemas, obtenidos)
+	}
+	// Sin WithStrict, la clave desconocida se informa pero no es un error.
+	if problemas[2].Err != nil || problemas[1].Err == nil {
+		status = "Fallido"
+		t.Errorf("Errores tipados incorrectos: %+v", problemas)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestOrdenarJSONConReporte_SinProblemas(t *testing.T) {
+	got, problemas, err := ordenJson.OrdenarJSONConReporte(`{"cm:title": "t", "tanner:tipo-documento": "contrato"}`, ordenJson.WithStrict())
+	if err != nil {
+		t.Fatalf("OrdenarJSONConReporte() error = %v", err)
+	}
+	if len(problemas) != 0 {
+		t.Errorf("No se esperaban problemas, se obtuvieron %+v", problemas)
+	}
+	if keys := extraerClavesJSON(got); !reflect.DeepEqual(keys, []string{"tanner:tipo-documento", "cm:title"}) {
+		t.Errorf("Orden incorrecto: %v", keys)
+	}
+}
+
+func TestOrdenarJSONConReporte_JSONInvalido(t *testing.T) {
+	got, problemas, err := ordenJson.OrdenarJSONConReporte(`{"cm:title": `)
+	if err == nil || got != "" || problemas != nil {
+		t.Errorf("Se esperaba solo un error, se obtuvo salida=%q problemas=%v error=%v", got, problemas, err)
+	}
+}
+
+func TestReporte_JSON(t *testing.T) {
+	input := `{"tanner:rut_cliente": "x", "tanner:estado-visado": "listo"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Reporte JSON con campo, regla, severidad y mensaje"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSONConReporte y serializando el reporte")
+	_, problemas, err := ordenJson.OrdenarJSONConReporte(input, ordenJson.WithValidarEstados())
+	if err != nil {
+		t.Fatalf("OrdenarJSONConReporte() error = %v", err)
+	}
+	salida, err := ordenJson.NuevoReporte(problemas).JSON()
+	actual := ResultadosObtenidos{JsonSalida: string(salida)}
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	var reporte struct {
+		Valido       bool `json:"valido"`
+		Errores      int  `json:"errores"`
+		Advertencias int  `json:"advertencias"`
+		Problemas    []map[string]string
+	}
+	status := "Completado"
+	if err := json.Unmarshal(salida, &reporte); err != nil {
+		status = "Fallido"
+		t.Fatalf("El reporte no es JSON válido: %v\n%s", err, salida)
+	}
+	if reporte.Valido || reporte.Errores != 1 || reporte.Advertencias != 1 {
+		status = "Fallido"
+		t.Errorf("Resumen incorrecto: %s", salida)
+	}
+	esperados := []map[string]string{
+		{"campo": "tanner:rut_cliente", "regla": ordenJson.ReglaClaveDesconocida, "severidad": "advertencia"},
+		{"campo": "tanner:estado-visado", "regla": ordenJson.ReglaValorPermitido, "severidad": "error"},
+	}
+	if len(reporte.Problemas) != len(esperados) {
+		status = "Fallido"
+		t.Fatalf("Problemas incorrectos: %s", salida)
+	}
+	for i, p := range reporte.Problemas {
+		if p["mensaje"] == "" || len(p) != 4 {
+			status = "Fallido"
+			t.Errorf("Problema %d sin mensaje o con claves de más: %v", i, p)
+		}
+		delete(p, "mensaje")
+		if !reflect.DeepEqual(p, esperados[i]) {
+			status = "Fallido"
+			t.Errorf("Problema %d: esperado %v, obtenido %v", i, esperados[i], p)
+		}
+	}
+
+	if vacio, _ := ordenJson.NuevoReporte(nil).JSON(); !strings.Contains(string(vacio), `"problemas": []`) {
+		status = "Fallido"
+		t.Errorf("Un reporte sin problemas debe serializar una lista vacía: %s", vacio)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
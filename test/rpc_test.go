@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/samuel/prueba-orden/ordenJson/cuarentena"
+	"github.com/samuel/prueba-orden/ordenJson/rpc"
+	"github.com/samuel/prueba-orden/ordenJson/servidor"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// conexionRPC levanta el servicio gRPC en memoria y devuelve una conexión a él.
+func conexionRPC(t *testing.T, cfg servidor.Config) *grpc.ClientConn {
+	t.Helper()
+	perfiles, err := servidor.Nuevo(cfg)
+	if err != nil {
+		t.Fatalf("servidor.Nuevo() error = %v", err)
+	}
+	escucha := bufconn.Listen(1 << 20)
+	srv := rpc.Registrar(perfiles)
+	go srv.Serve(escucha)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return escucha.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestRPC_Ordenar(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:tipo-documento": "contrato"}`
+	expectedOrder := []string{"tanner:tipo-documento", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder, TipoError: "FailedPrecondition para un documento inválido"})
+
+	registradorGlobal.AgregarProceso(testName, "Llamando a Ordenar por gRPC")
+	conn := conexionRPC(t, servidor.Config{Perfiles: map[string]*ordenJson.Ordenador{
+		"general":   ordenJson.Nuevo(),
+		"contratos": ordenJson.Nuevo(ordenJson.WithRequired("tanner:rut-cliente")),
+	}, PerfilPorDefecto: "general"})
+	ordenado, err := rpc.NuevoCliente(conn, "").Ordenar(context.Background(), []byte(input))
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+		registradorGlobal.GuardarResultado(testName, actual, "Fallido")
+		t.Fatalf("Ordenar() error = %v", err)
+	}
+	keys := extraerClavesJSON(string(ordenado))
+	actual = ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: string(ordenado)}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if _, err := rpc.NuevoCliente(conn, "contratos").Ordenar(context.Background(), []byte(input)); statusCode(err) != codes.FailedPrecondition {
+		status = "Fallido"
+		t.Errorf("Se esperaba FailedPrecondition, se obtuvo %v", err)
+	}
+	if _, err := rpc.NuevoCliente(conn, "otro").Ordenar(context.Background(), []byte(input)); statusCode(err) != codes.NotFound {
+		status = "Fallido"
+		t.Errorf("Se esperaba NotFound, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestRPC_OrdenarLote(t *testing.T) {
+	conn := conexionRPC(t, servidor.Config{})
+	documentos := make([][]byte, 500)
+	for i := range documentos {
+		documentos[i] = []byte(`{"cm:title": "t", "tanner:tipo-documento": "x"}`)
+	}
+	documentos[7] = []byte(`{"cm:title": `)
+
+	resultados, err := rpc.NuevoCliente(conn, "").OrdenarLote(context.Background(), documentos)
+	if err != nil {
+		t.Fatalf("OrdenarLote() error = %v", err)
+	}
+	if len(resultados) != len(documentos) {
+		t.Fatalf("Se esperaban %d resultados, se obtuvieron %d", len(documentos), len(resultados))
+	}
+	for i, r := range resultados {
+		if i == 7 {
+			var falla *rpc.Error
+			if !errors.As(r.Err, &falla) || falla.Tipo != cuarentena.TipoJSONInvalido {
+				t.Errorf("Documento 7: se esperaba un error json-invalido, se obtuvo %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil || !reflect.DeepEqual(extraerClavesJSON(string(r.Documento)), []string{"tanner:tipo-documento", "cm:title"}) {
+			t.Errorf("Documento %d: %s, %v", i, r.Documento, r.Err)
+		}
+	}
+}
+
+// statusCode devuelve el código gRPC de err.
+func statusCode(err error) codes.Code {
+	return status.Code(err)
+}
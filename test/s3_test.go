@@ -0,0 +1,231 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/samuel/prueba-orden/ordenJson/s3"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// bucketS3 simula un bucket en memoria, con páginas de dos objetos.
+type bucketS3 struct {
+	mu         sync.Mutex
+	objetos    map[string]string
+	escrituras map[string]string // Clave -> ETag recibido en IfMatch.
+}
+
+func (b *bucketS3) ListObjectsV2(_ context.Context, in *awss3.ListObjectsV2Input, _ ...func(*awss3.Options)) (*awss3.ListObjectsV2Output, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var claves []string
+	for clave := range b.objetos {
+		if strings.HasPrefix(clave, aws.ToString(in.Prefix)) && clave > aws.ToString(in.StartAfter) {
+			claves = append(claves, clave)
+		}
+	}
+	sort.Strings(claves)
+	inicio, _ := strconv.Atoi(aws.ToString(in.ContinuationToken))
+	fin := min(inicio+2, len(claves))
+	salida := &awss3.ListObjectsV2Output{IsTruncated: aws.Bool(fin < len(claves))}
+	for _, clave := range claves[inicio:fin] {
+		salida.Contents = append(salida.Contents, types.Object{Key: aws.String(clave)})
+	}
+	if fin < len(claves) {
+		salida.NextContinuationToken = aws.String(strconv.Itoa(fin))
+	}
+	return salida, nil
+}
+
+func (b *bucketS3) GetObject(_ context.Context, in *awss3.GetObjectInput, _ ...func(*awss3.Options)) (*awss3.GetObjectOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	contenido, ok := b.objetos[aws.ToString(in.Key)]
+	if !ok {
+		return nil, errors.New("NoSuchKey")
+	}
+	return &awss3.GetObjectOutput{
+		Body: io.NopCloser(strings.NewReader(contenido)),
+		ETag: aws.String(`"` + aws.ToString(in.Key) + `"`),
+	}, nil
+}
+
+func (b *bucketS3) PutObject(_ context.Context, in *awss3.PutObjectInput, _ ...func(*awss3.Options)) (*awss3.PutObjectOutput, error) {
+	contenido, _ := io.ReadAll(in.Body)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objetos[aws.ToString(in.Key)] = string(contenido)
+	b.escrituras[aws.ToString(in.Key)] = aws.ToString(in.IfMatch)
+	return &awss3.PutObjectOutput{}, nil
+}
+
+func nuevoBucketS3() *bucketS3 {
+	ordenado, _ := ordenJson.OrdenarJSON(`{"b": 1, "a": 2}`)
+	return &bucketS3{
+		objetos: map[string]string{
+			"entrada/1.json":     `{"zzz": 1, "documentName": "uno"}`,
+			"entrada/2.json":     ordenado,
+			"entrada/3.json":     `{"documentName": `,
+			"entrada/4.txt":      "no es JSON",
+			"entrada/5.json":     `{"b": true, "a": false}`,
+			"otro/ignorado.json": `{"b": 1, "a": 2}`,
+		},
+		escrituras: map[string]string{},
+	}
+}
+
+func TestS3_NormalizarADestino(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "bucket simulado con 5 objetos bajo entrada/")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Los .json válidos se escriben ordenados bajo ordenados/; el inválido y el .txt se cuentan aparte"})
+
+	registradorGlobal.AgregarProceso(testName, "Normalizando entrada/ hacia ordenados/")
+	bucket := nuevoBucketS3()
+	var fallidos []string
+	var progresos []string
+	resumen, err := s3.Normalizar(context.Background(), s3.Config{
+		Cliente:      bucket,
+		Bucket:       "documentos",
+		Prefijo:      "entrada/",
+		Destino:      "ordenados/",
+		Concurrencia: 3,
+		Progreso:     func(ultima string) { progresos = append(progresos, ultima) },
+		AlFallar:     func(clave string, _ error) { fallidos = append(fallidos, clave) },
+	})
+
+	status := "Completado"
+	esperado := s3.Resumen{Escritos: 3, Fallidos: 1, Omitidos: 1, Ultima: "entrada/5.json"}
+	if err != nil || resumen != esperado {
+		status = "Fallido"
+		t.Errorf("Normalizar() = %+v, %v; esperado %+v", resumen, err, esperado)
+	}
+	uno, _ := ordenJson.OrdenarJSON(bucket.objetos["entrada/1.json"])
+	if bucket.objetos["ordenados/1.json"] != uno || bucket.objetos["entrada/1.json"] == uno {
+		status = "Fallido"
+		t.Errorf("ordenados/1.json = %q", bucket.objetos["ordenados/1.json"])
+	}
+	if _, ok := bucket.objetos["ordenados/ignorado.json"]; ok || len(fallidos) != 1 || fallidos[0] != "entrada/3.json" {
+		status = "Fallido"
+		t.Errorf("objetos fallidos = %v", fallidos)
+	}
+	if len(progresos) != 1 || progresos[0] != "entrada/5.json" {
+		status = "Fallido"
+		t.Errorf("progresos = %v", progresos)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Rechazando un destino dentro del prefijo")
+	if _, err := s3.Normalizar(context.Background(), s3.Config{Cliente: bucket, Bucket: "documentos", Prefijo: "entrada/", Destino: "entrada/ordenados/"}); err == nil {
+		status = "Fallido"
+		t.Error("Normalizar() con el destino dentro del prefijo no devolvió error")
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: bucket.objetos["ordenados/1.json"]}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestS3_NormalizarEnElLugarDesde(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "bucket simulado, retomando después de entrada/1.json")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Solo se reescriben, con If-Match, los objetos posteriores a Desde que cambiaron"})
+
+	registradorGlobal.AgregarProceso(testName, "Normalizando entrada/ en el lugar desde entrada/1.json")
+	bucket := nuevoBucketS3()
+	original := bucket.objetos["entrada/1.json"]
+	resumen, err := s3.Normalizar(context.Background(), s3.Config{
+		Cliente: bucket,
+		Bucket:  "documentos",
+		Prefijo: "entrada/",
+		Desde:   "entrada/1.json",
+	})
+
+	status := "Completado"
+	esperado := s3.Resumen{Escritos: 1, SinCambios: 1, Fallidos: 1, Omitidos: 1, Ultima: "entrada/5.json"}
+	if err != nil || resumen != esperado {
+		status = "Fallido"
+		t.Errorf("Normalizar() = %+v, %v; esperado %+v", resumen, err, esperado)
+	}
+	escrituras := map[string]string{"entrada/5.json": `"entrada/5.json"`}
+	if len(bucket.escrituras) != 1 || bucket.escrituras["entrada/5.json"] != escrituras["entrada/5.json"] {
+		status = "Fallido"
+		t.Errorf("escrituras = %v; esperado %v", bucket.escrituras, escrituras)
+	}
+	if bucket.objetos["entrada/1.json"] != original {
+		status = "Fallido"
+		t.Error("se reescribió un objeto anterior a Desde")
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: bucket.objetos["entrada/5.json"]}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+// bucketS3Cancelable cancela la ejecución al leer la clave indicada.
+type bucketS3Cancelable struct {
+	*bucketS3
+	clave    string
+	cancelar context.CancelFunc
+}
+
+func (b *bucketS3Cancelable) GetObject(ctx context.Context, in *awss3.GetObjectInput, optFns ...func(*awss3.Options)) (*awss3.GetObjectOutput, error) {
+	if aws.ToString(in.Key) == b.clave {
+		b.cancelar()
+	}
+	return b.bucketS3.GetObject(ctx, in, optFns...)
+}
+
+func TestS3_NormalizarCanceladoYRetomado(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "bucket simulado, cancelando al leer entrada/2.json")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Un objeto cancelado no avanza Resumen.Ultima ni se cuenta como fallido, y se procesa al retomar"})
+
+	registradorGlobal.AgregarProceso(testName, "Normalizando hasta cancelar en entrada/2.json")
+	bucket := nuevoBucketS3()
+	ctx, cancelar := context.WithCancel(context.Background())
+	defer cancelar()
+	var fallidos []string
+	cfg := s3.Config{
+		Cliente:      &bucketS3Cancelable{bucketS3: bucket, clave: "entrada/2.json", cancelar: cancelar},
+		Bucket:       "documentos",
+		Prefijo:      "entrada/",
+		Destino:      "ordenados/",
+		Concurrencia: 1,
+		AlFallar:     func(clave string, _ error) { fallidos = append(fallidos, clave) },
+	}
+	resumen, err := s3.Normalizar(ctx, cfg)
+
+	status := "Completado"
+	if !errors.Is(err, context.Canceled) || resumen.Ultima != "entrada/1.json" || resumen.Fallidos != 0 || len(fallidos) != 0 {
+		status = "Fallido"
+		t.Errorf("Normalizar() = %+v, %v; fallidos %v", resumen, err, fallidos)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Retomando desde Resumen.Ultima")
+	cfg.Cliente = bucket
+	cfg.Desde = resumen.Ultima
+	resumen, err = s3.Normalizar(context.Background(), cfg)
+	esperado := s3.Resumen{Escritos: 2, Fallidos: 1, Omitidos: 1, Ultima: "entrada/5.json"}
+	if err != nil || resumen != esperado {
+		status = "Fallido"
+		t.Errorf("Normalizar() al retomar = %+v, %v; esperado %+v", resumen, err, esperado)
+	}
+	if _, ok := bucket.objetos["ordenados/2.json"]; !ok {
+		status = "Fallido"
+		t.Error("entrada/2.json no se escribió al retomar")
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: bucket.objetos["ordenados/2.json"]}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
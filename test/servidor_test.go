@@ -0,0 +1,182 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/servidor"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestServidor_Capacidades(t *testing.T) {
+	perfiles := map[string]*ordenJson.Ordenador{
+		"contratos": ordenJson.Nuevo(
+			ordenJson.WithPerfil(ordenJson.NuevoPerfil("contratos", []string{"tanner:rut-cliente", "cm:title"})),
+			ordenJson.WithRequired("tanner:rut-cliente"),
+			ordenJson.WithStrict(),
+			ordenJson.WithMaxBytes(2048),
+			ordenJson.WithMaxDepth(8),
+			ordenJson.WithPresupuesto(50*time.Millisecond),
+			ordenJson.WithClavesUnicas(),
+		),
+		"basico": ordenJson.Nuevo(),
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, map[string]interface{}{"perfiles": []string{"contratos", "basico"}})
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "GET /capacidades lista formatos, perfiles, validadores y límites"})
+
+	registradorGlobal.AgregarProceso(testName, "Consultando GET /capacidades")
+	s, err := servidor.Nuevo(servidor.Config{Perfiles: perfiles, PerfilPorDefecto: "contratos", TamanoMaximo: 4096})
+	if err != nil {
+		t.Fatalf("Nuevo() error = %v", err)
+	}
+	grabador := httptest.NewRecorder()
+	s.ServeHTTP(grabador, httptest.NewRequest(http.MethodGet, "/capacidades", nil))
+
+	actual := ResultadosObtenidos{JsonSalida: grabador.Body.String()}
+	status := "Completado"
+	if grabador.Code != http.StatusOK {
+		status = "Fallido"
+		t.Fatalf("Código %d: %s", grabador.Code, grabador.Body)
+	}
+
+	var c servidor.Capacidades
+	if err := json.Unmarshal(grabador.Body.Bytes(), &c); err != nil {
+		status = "Fallido"
+		t.Fatalf("Respuesta inválida: %v", err)
+	}
+	esperados := []servidor.PerfilDisponible{
+		{Nombre: "basico", Campos: ordenJson.PerfilPorDefecto.Campos(), Reglas: []string{}},
+		{Nombre: "contratos", PorDefecto: true, Campos: []string{"tanner:rut-cliente", "cm:title"},
+			Reglas:  []string{ordenJson.ReglaClaveDesconocida, ordenJson.ReglaRequerido},
+			Limites: servidor.LimitesPerfil{Bytes: 2048, Profundidad: 8, Presupuesto: 50 * time.Millisecond, ClavesUnicas: true}},
+	}
+	if !reflect.DeepEqual(c.Perfiles, esperados) {
+		status = "Fallido"
+		t.Errorf("Perfiles esperados %+v, obtenidos %+v", esperados, c.Perfiles)
+	}
+	if len(c.Formatos) == 0 || len(c.Validadores) == 0 || c.Limites.TamanoMaximo != 4096 || c.Version.Modulo == "" {
+		status = "Fallido"
+		t.Errorf("Capacidades incompletas: %+v", c)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestServidor_Info(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, "GET /info")
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "GET /info devuelve ordenJson.Version()"})
+
+	registradorGlobal.AgregarProceso(testName, "Consultando GET /info")
+	s, err := servidor.Nuevo(servidor.Config{Perfiles: map[string]*ordenJson.Ordenador{"basico": ordenJson.Nuevo()}})
+	if err != nil {
+		t.Fatalf("Nuevo() error = %v", err)
+	}
+	grabador := httptest.NewRecorder()
+	s.ServeHTTP(grabador, httptest.NewRequest(http.MethodGet, "/info", nil))
+
+	actual := ResultadosObtenidos{JsonSalida: grabador.Body.String()}
+	status := "Completado"
+	var info ordenJson.InfoVersion
+	if err := json.Unmarshal(grabador.Body.Bytes(), &info); err != nil || grabador.Code != http.StatusOK || info != ordenJson.Version() {
+		status = "Fallido"
+		t.Errorf("Respuesta inesperada (código %d, %v): %s", grabador.Code, err, grabador.Body)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestServidor_PerfilPorDefectoInvalido(t *testing.T) {
+	perfiles := map[string]*ordenJson.Ordenador{"a": ordenJson.Nuevo(), "b": ordenJson.Nuevo()}
+	if _, err := servidor.Nuevo(servidor.Config{Perfiles: perfiles}); err == nil {
+		t.Error("Con varios perfiles se debía exigir PerfilPorDefecto")
+	}
+	if _, err := servidor.Nuevo(servidor.Config{Perfiles: perfiles, PerfilPorDefecto: "c"}); err == nil {
+		t.Error("Se esperaba error con un perfil por defecto inexistente")
+	}
+}
+
+func TestServidor_Ordenar(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:tipo-documento": "contrato"}`
+	expectedOrder := []string{"tanner:tipo-documento", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Enviando POST /ordenar")
+	s, err := servidor.Nuevo(servidor.Config{})
+	if err != nil {
+		t.Fatalf("Nuevo() error = %v", err)
+	}
+	servidorHTTP := httptest.NewServer(s)
+	defer servidorHTTP.Close()
+	respuesta, err := http.Post(servidorHTTP.URL+"/ordenar", "application/json", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer respuesta.Body.Close()
+	cuerpo, _ := io.ReadAll(respuesta.Body)
+
+	keys := extraerClavesJSON(string(cuerpo))
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: string(cuerpo)}
+	status := "Completado"
+	if respuesta.StatusCode != http.StatusOK || !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Respuesta inesperada %d: %s", respuesta.StatusCode, cuerpo)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestServidor_Errores(t *testing.T) {
+	perfiles := map[string]*ordenJson.Ordenador{
+		"contratos": ordenJson.Nuevo(ordenJson.WithRequired("tanner:rut-cliente")),
+	}
+	s, err := servidor.Nuevo(servidor.Config{Perfiles: perfiles, TamanoMaximo: 64})
+	if err != nil {
+		t.Fatalf("Nuevo() error = %v", err)
+	}
+	casos := []struct {
+		ruta, cuerpo string
+		estado       int
+		tipo         string
+	}{
+		{"/ordenar", `{"cm:title": `, http.StatusBadRequest, "json-invalido"},
+		{"/ordenar/contratos", `{"cm:title": "t"}`, http.StatusUnprocessableEntity, "validacion"},
+		{"/ordenar/otro", `{}`, http.StatusNotFound, ""},
+		{"/ordenar?formato=xml", `{}`, http.StatusBadRequest, ""},
+		{"/ordenar", `{"cm:title": "` + strings.Repeat("x", 100) + `"}`, http.StatusRequestEntityTooLarge, ""},
+	}
+	for _, c := range casos {
+		grabador := httptest.NewRecorder()
+		s.ServeHTTP(grabador, httptest.NewRequest(http.MethodPost, c.ruta, strings.NewReader(c.cuerpo)))
+		var r servidor.RespuestaError
+		json.Unmarshal(grabador.Body.Bytes(), &r)
+		if grabador.Code != c.estado || r.Tipo != c.tipo || r.Error == "" {
+			t.Errorf("POST %s: se esperaba %d %q, se obtuvo %d %s", c.ruta, c.estado, c.tipo, grabador.Code, grabador.Body)
+		}
+	}
+
+	// /validar responde el reporte aunque el documento no sea válido.
+	grabador := httptest.NewRecorder()
+	s.ServeHTTP(grabador, httptest.NewRequest(http.MethodPost, "/validar", strings.NewReader(`{"cm:title": "t"}`)))
+	var reporte ordenJson.Reporte
+	if err := json.Unmarshal(grabador.Body.Bytes(), &reporte); err != nil || grabador.Code != http.StatusOK || reporte.Valido || reporte.Errores != 1 {
+		t.Errorf("Reporte inesperado %d: %s", grabador.Code, grabador.Body)
+	}
+}
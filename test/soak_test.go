@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/soak"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestSoak_SinFugas(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, map[string]interface{}{"duracion": "500ms", "intervalo": "50ms"})
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Sin crecimiento sostenido"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando soak.Ejecutar con OrdenarJSON")
+	reporte, err := soak.Ejecutar(context.Background(), soak.Config{
+		Duracion:          500 * time.Millisecond,
+		IntervaloMuestreo: 50 * time.Millisecond,
+		Trabajadores:      2,
+		MuestrasTendencia: 5,
+		CrecimientoMinimo: 0.5,
+	})
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	if err != nil {
+		status = "Fallido"
+		t.Errorf("Error inesperado: %v", err)
+	}
+	if reporte.Documentos == 0 || reporte.Errores != 0 || len(reporte.Muestras) == 0 {
+		status = "Fallido"
+		t.Errorf("Reporte inesperado: documentos=%d errores=%d muestras=%d", reporte.Documentos, reporte.Errores, len(reporte.Muestras))
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestSoak_DetectaFugaDeGoroutines(t *testing.T) {
+	liberar := make(chan struct{})
+	defer close(liberar)
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, map[string]interface{}{"duracion": "10s", "intervalo": "20ms"})
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "soak.ErrFuga"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando soak.Ejecutar con una función que deja goroutines bloqueadas")
+	_, err := soak.Ejecutar(context.Background(), soak.Config{
+		Duracion:          10 * time.Second,
+		IntervaloMuestreo: 20 * time.Millisecond,
+		Trabajadores:      1,
+		MuestrasTendencia: 5,
+		Ordenar: func(documento string) (string, error) {
+			go func() { <-liberar }()
+			time.Sleep(time.Millisecond)
+			return ordenJson.OrdenarJSON(documento)
+		},
+	})
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	if !errors.Is(err, soak.ErrFuga) {
+		status = "Fallido"
+		t.Errorf("Se esperaba soak.ErrFuga, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
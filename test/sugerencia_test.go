@@ -0,0 +1,56 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestSugerirOrden(t *testing.T) {
+	corpus := []string{
+		`{"tanner:tipo-documento": "contrato", "tanner:canal": "web", "tanner:sucursal": "centro", "cm:title": "a"}`,
+		`{"tanner:tipo-documento": "factura", "tanner:canal": "api", "cm:title": "b", "tanner:lote": "7"}`,
+		`{"tanner:sucursal": "norte", "tanner:tipo-documento": "contrato", "tanner:canal": "web"}`,
+		`{"id-externo": "x1", "tanner:tipo-documento": "contrato", "tanner:sucursal": "sur"}`,
+	}
+	perfil := ordenJson.NuevoPerfil("base", []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title"})
+	expected := []string{
+		"id-externo",
+		"tanner:tipo-documento", "tanner:canal", "tanner:sucursal",
+		"tanner:rut-cliente",
+		"cm:title", "tanner:lote",
+	}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, corpus)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando SugerirOrden sobre el corpus")
+	got, err := ordenJson.SugerirOrden(corpus, ordenJson.WithPerfil(perfil))
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("SugerirOrden() error = %v", err)
+	}
+
+	actual := ResultadosObtenidos{ClavesOrdenadas: got}
+	status := "Completado"
+	if !reflect.DeepEqual(got, expected) {
+		status = "Fallido"
+		t.Errorf("Orden sugerido incorrecto.\nEsperado: %v\nObtenido: %v", expected, got)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando el error ante un documento inválido")
+	_, err = ordenJson.SugerirOrden([]string{`{}`, `{"a": `})
+	var errJSON *ordenJson.ErrorJSONInvalido
+	if !errors.As(err, &errJSON) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorJSONInvalido, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// pagareMetadata son los metadatos de un pagaré, una familia con campos
+// propios además de los comunes.
+type pagareMetadata struct {
+	TipoDocumento string  `json:"tanner:tipo-documento"`
+	RUTDeudor     string  `json:"tanner:rut-deudor"`
+	Monto         float64 `json:"monto"`
+	Vencimiento   string  `json:"vencimiento" orden:"0"`
+}
+
+func init() {
+	perfil, err := ordenJson.PerfilDesdeEsquema([]byte(`{
+		"required": ["tanner:rut-deudor"],
+		"properties": {
+			"tanner:tipo-documento": {},
+			"tanner:rut-deudor": {"type": "string"},
+			"monto": {"type": "number"}
+		}
+	}`))
+	if err != nil {
+		panic(err)
+	}
+	ordenJson.RegisterTipoDocumento("pagare-test", pagareMetadata{}, perfil)
+}
+
+func TestTiposDocumento_Registro(t *testing.T) {
+	input := `{"monto": 1500.5, "cm:title": "P-1", "tanner:rut-deudor": "1-9", "tanner:tipo-documento": "pagare-test"}`
+	expected := []string{"tanner:tipo-documento", "tanner:rut-deudor", "monto", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un pagaré con WithTiposDocumento")
+	salida, err := ordenJson.OrdenarJSON(input, ordenJson.WithTiposDocumento())
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+	status := "Completado"
+	claves := extraerClavesJSON(salida)
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Claves incorrectas.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que sin la opción se usa el perfil por defecto")
+	if sinTipos, _ := ordenJson.OrdenarJSON(input); reflect.DeepEqual(extraerClavesJSON(sinTipos), expected) {
+		status = "Fallido"
+		t.Errorf("Sin WithTiposDocumento no se esperaba el orden del pagaré:\n%s", sinTipos)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando las reglas del perfil de la familia")
+	_, err = ordenJson.OrdenarJSON(`{"tanner:tipo-documento": "pagare-test", "monto": 1}`, ordenJson.WithTiposDocumento())
+	if !errors.Is(err, ordenJson.ErrCamposFaltantes) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrCamposFaltantes, se obtuvo %v", err)
+	}
+	_, err = ordenJson.OrdenarJSON(`{"tanner:tipo-documento": "pagare-test", "tanner:rut-deudor": "1-9", "monto": "mil"}`, ordenJson.WithTiposDocumento())
+	if !errors.Is(err, ordenJson.ErrRegla) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrRegla, se obtuvo %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando el struct de la familia con su etiqueta orden")
+	structSalida, err := ordenJson.OrdenarStruct(pagareMetadata{TipoDocumento: "pagare-test", RUTDeudor: "1-9", Monto: 10, Vencimiento: "2025-01-01"}, ordenJson.WithTiposDocumento())
+	esperadasStruct := []string{"vencimiento", "tanner:tipo-documento", "tanner:rut-deudor", "monto"}
+	if err != nil || !reflect.DeepEqual(extraerClavesJSON(structSalida), esperadasStruct) {
+		status = "Fallido"
+		t.Errorf("Orden del struct incorrecto, se esperaba %v:\n%s (%v)", esperadasStruct, structSalida, err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida, ClavesOrdenadas: claves}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestDecodificarDocumento(t *testing.T) {
+	input := `{"monto": 1500.5, "tanner:rut-deudor": "1-9", "tanner:tipo-documento": "pagare-test"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*pagareMetadata, *DocumentMetadata, *ordenJson.ErrorTipoDocumentoDesconocido y *ordenJson.ErrorRegla"})
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando un pagaré")
+	doc, err := ordenJson.DecodificarDocumento(input)
+	esperado := &pagareMetadata{TipoDocumento: "pagare-test", RUTDeudor: "1-9", Monto: 1500.5}
+	if err != nil || !reflect.DeepEqual(doc, esperado) {
+		status = "Fallido"
+		t.Errorf("Se esperaba %+v, se obtuvo %+v (%v)", esperado, doc, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando un contrato, registrado desde el inicio")
+	doc, err = ordenJson.DecodificarDocumento(map[string]interface{}{"tanner:tipo-documento": "contrato", "cm:title": "C-1"})
+	if contrato, ok := doc.(*ordenJson.DocumentMetadata); err != nil || !ok || contrato.CmTitle != "C-1" {
+		status = "Fallido"
+		t.Errorf("Se esperaba un *DocumentMetadata, se obtuvo %+v (%v)", doc, err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando documentos sin un tipo registrado")
+	for _, desconocido := range []string{`{"tanner:tipo-documento": "boleta"}`, `{"cm:title": "sin tipo"}`} {
+		_, err := ordenJson.DecodificarDocumento(desconocido)
+		var errTipo *ordenJson.ErrorTipoDocumentoDesconocido
+		if !errors.As(err, &errTipo) || !errors.Is(err, ordenJson.ErrTipoDocumentoDesconocido) {
+			status = "Fallido"
+			t.Errorf("Se esperaba *ErrorTipoDocumentoDesconocido para %s, se obtuvo %v", desconocido, err)
+		}
+		if err != nil {
+			actual.Error = err.Error()
+		}
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando un valor de tipo distinto al del campo")
+	_, err = ordenJson.DecodificarDocumento(`{"tanner:tipo-documento": "contrato", "cm:title": 5}`)
+	var errRegla *ordenJson.ErrorRegla
+	if !errors.As(err, &errRegla) || errRegla.Campo != "cm:title" || errRegla.Regla != "type" {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ErrorRegla en cm:title, se obtuvo %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Registrando un prototipo que no es un struct")
+	func() {
+		defer func() {
+			if recover() == nil {
+				status = "Fallido"
+				t.Errorf("Se esperaba un pánico al registrar un prototipo que no es un struct")
+			}
+		}()
+		ordenJson.RegisterTipoDocumento("invalido", "no es un struct", nil)
+	}()
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestDecodificarDocumento_CacheYFiltros(t *testing.T) {
+	input := `{"monto": 10, "tanner:rut-deudor": "1-9", "tanner:tipo-documento": "pagare-test"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "DecodificarDocumento no comparte la caché con OrdenarJSON y elige la familia antes de filtrar"})
+
+	status := "Completado"
+	var actual ResultadosObtenidos
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando y ordenando el mismo documento con caché")
+	porDefecto, _ := ordenJson.OrdenarJSON(input)
+	o := ordenJson.Nuevo(ordenJson.WithCache(10))
+	if _, err := o.DecodificarDocumento(input); err != nil {
+		status = "Fallido"
+		t.Errorf("DecodificarDocumento() error = %v", err)
+	}
+	if salida, _ := o.OrdenarJSON(input); salida != porDefecto {
+		status = "Fallido"
+		t.Errorf("OrdenarJSON devolvió la salida de la familia desde la caché:\n%s", salida)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando después de ordenar con caché")
+	invalido := `{"monto": 10, "tanner:tipo-documento": "pagare-test"}`
+	o = ordenJson.Nuevo(ordenJson.WithCache(10))
+	if _, err := o.OrdenarJSON(invalido); err != nil {
+		status = "Fallido"
+		t.Errorf("OrdenarJSON() error = %v", err)
+	}
+	if _, err := o.DecodificarDocumento(invalido); !errors.Is(err, ordenJson.ErrCamposFaltantes) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrCamposFaltantes de la familia, se obtuvo %v", err)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Decodificando con opciones que quitan o cambian el tipo de documento")
+	for _, opt := range []ordenJson.Option{
+		ordenJson.WithOnly("monto"),
+		ordenJson.WithExclude("tanner:tipo-documento"),
+		ordenJson.WithMask("tanner:tipo-documento", ordenJson.MaskAllButLast4),
+	} {
+		doc, err := ordenJson.DecodificarDocumento(input, opt)
+		pagare, ok := doc.(*pagareMetadata)
+		if err != nil || !ok || pagare.Monto != 10 {
+			status = "Fallido"
+			t.Errorf("Se esperaba un *pagareMetadata, se obtuvo %+v (%v)", doc, err)
+		}
+		if err != nil {
+			actual.Error = err.Error()
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestWithTransform(t *testing.T) {
+	input := `{"cm:title": "  contrato marco  ", "tanner:rut-cliente": " 12345678-k ", "tanner:fecha-carga": "15/03/2024", "tanner:observaciones": "   ", "monto": 10}`
+	expected := []string{"tanner:rut-cliente", "tanner:fecha-carga", "cm:title", "monto"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expected})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con transformaciones por campo")
+	salida, err := ordenJson.OrdenarJSON(input,
+		ordenJson.WithTransform("tanner:rut-cliente", ordenJson.Recortar),
+		ordenJson.WithTransform("tanner:rut-cliente", ordenJson.Mayusculas),
+		ordenJson.WithTransform("cm:title", ordenJson.Recortar),
+		ordenJson.WithTransform("tanner:observaciones", ordenJson.Recortar),
+		ordenJson.WithTransform("tanner:fecha-carga", ordenJson.ReformatearFecha("2006-01-02", "02/01/2006")),
+		ordenJson.WithTransform("monto", func(v interface{}) interface{} { return v.(float64) * 2 }),
+		ordenJson.WithTransform("no-existe", func(v interface{}) interface{} { panic("no debía llamarse") }),
+		ordenJson.WithVacio(ordenJson.MarcadoresVacios()),
+	)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	claves := extraerClavesJSON(salida)
+	actual := ResultadosObtenidos{ClavesOrdenadas: claves, JsonSalida: salida}
+	status := "Completado"
+	if !reflect.DeepEqual(claves, expected) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto.\nEsperado: %v\nObtenido: %v", expected, claves)
+	}
+	for _, fragmento := range []string{`"12345678-K"`, `"2024-03-15"`, `"contrato marco"`, `"monto": 20`} {
+		if !strings.Contains(salida, fragmento) {
+			status = "Fallido"
+			t.Errorf("La salida no contiene %s:\n%s", fragmento, salida)
+		}
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestWithOrdenarLista(t *testing.T) {
+	categorias := []interface{}{"legal", "comercial", "Anexos", "comercial"}
+	input := map[string]interface{}{
+		"tanner:categorias": categorias,
+		"montos":            []interface{}{10.0, 2.0, "x", true},
+		"prioridades":       []interface{}{"baja", "alta", "media"},
+		"cm:title":          "Contrato",
+	}
+	rango := map[string]int{"alta": 0, "media": 1, "baja": 2}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "listas ordenadas"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando con WithOrdenarLista")
+	salida, err := ordenJson.OrdenarJSON(input,
+		ordenJson.WithOrdenarLista("tanner:categorias", nil),
+		ordenJson.WithOrdenarLista("montos", nil),
+		ordenJson.WithOrdenarLista("prioridades", func(a, b interface{}) int {
+			return rango[a.(string)] - rango[b.(string)]
+		}),
+		ordenJson.WithOrdenarLista("cm:title", nil),
+	)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	status := "Completado"
+	esperado := `{"tanner:categorias": ["Anexos", "comercial", "comercial", "legal"], "cm:title": "Contrato", "montos": [true, 2, 10, "x"], "prioridades": ["alta", "media", "baja"]}`
+	if d, err := ordenJson.Diff(salida, esperado); err != nil || !d.Iguales() {
+		status = "Fallido"
+		t.Errorf("Listas mal ordenadas: %v, %v\n%s", d, err, salida)
+	}
+	if !reflect.DeepEqual(categorias, []interface{}{"legal", "comercial", "Anexos", "comercial"}) {
+		status = "Fallido"
+		t.Errorf("WithOrdenarLista modificó el arreglo de entrada: %v", categorias)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// servidorEco devuelve un servidor que responde con el cuerpo recibido y
+// guarda en largo el Content-Length de la solicitud.
+func servidorEco(t *testing.T, largo *int64) *httptest.Server {
+	t.Helper()
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*largo = r.ContentLength
+		io.Copy(w, r.Body)
+	}))
+	t.Cleanup(servidor.Close)
+	return servidor
+}
+
+func TestTransporte_OrdenaCuerposSalientes(t *testing.T) {
+	input := `{"cm:title": "Contrato", "tanner:rut-cliente": "1-9", "tanner:tipo-documento": "contrato"}`
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:rut-cliente", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Enviando un POST application/json a través de Transporte")
+	var largo int64
+	servidor := servidorEco(t, &largo)
+	cliente := &http.Client{Transport: ordenJson.Transporte(nil)}
+	respuesta, err := cliente.Post(servidor.URL, "application/json", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	recibido, _ := io.ReadAll(respuesta.Body)
+	respuesta.Body.Close()
+
+	cuerpo := string(recibido)
+	keys := extraerClavesJSON(cuerpo)
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: cuerpo}
+
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if largo != int64(len(recibido)) {
+		status = "Fallido"
+		t.Errorf("Content-Length %d no coincide con el cuerpo de %d bytes", largo, len(recibido))
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Enviando texto plano, que no debe modificarse")
+	respuesta, err = cliente.Post(servidor.URL, "text/plain", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	recibido, _ = io.ReadAll(respuesta.Body)
+	respuesta.Body.Close()
+	if string(recibido) != input {
+		status = "Fallido"
+		t.Errorf("El cuerpo text/plain cambió: %q", recibido)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestTransporte_NoEnviaDocumentosInvalidos(t *testing.T) {
+	input := `{"cm:title": "Contrato"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorCamposFaltantes"})
+
+	registradorGlobal.AgregarProceso(testName, "Enviando un documento sin un campo requerido")
+	var largo int64 = -1
+	servidor := servidorEco(t, &largo)
+	ordenador := ordenJson.Nuevo(ordenJson.WithRequired("tanner:tipo-documento"))
+	cliente := &http.Client{Transport: ordenador.Transporte(nil)}
+	_, err := cliente.Post(servidor.URL, "application/json", strings.NewReader(input))
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	var faltantes *ordenJson.ErrorCamposFaltantes
+	if !errors.As(err, &faltantes) {
+		status = "Fallido"
+		t.Errorf("Se esperaba *ordenJson.ErrorCamposFaltantes, se obtuvo %v", err)
+	}
+	if largo != -1 {
+		status = "Fallido"
+		t.Errorf("La solicitud no debía llegar al servidor")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/samuel/prueba-orden/ordenJson/ordenotel"
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+// atributosSpan devuelve los atributos de un span indexados por clave.
+func atributosSpan(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	atributos := map[attribute.Key]attribute.Value{}
+	for _, a := range span.Attributes() {
+		atributos[a.Key] = a.Value
+	}
+	return atributos
+}
+
+func TestTrazas_OrdenarYValidar(t *testing.T) {
+	input := `{"zzz": {"a": 1}, "cm:title": "Contrato"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Un span ordenar hijo del span del llamador, con un span validar dentro y sus atributos"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando dentro de un span del llamador")
+	grabador := tracetest.NewSpanRecorder()
+	proveedor := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(grabador))
+	ctx, padre := proveedor.Tracer("prueba").Start(context.Background(), "solicitud")
+	ordenador := ordenJson.Nuevo(ordenotel.WithTrazas(proveedor), ordenJson.WithRequired("cm:title"))
+	salida, err := ordenador.OrdenarJSONCtx(ctx, input)
+	padre.End()
+
+	status := "Completado"
+	spans := grabador.Ended()
+	if err != nil || len(spans) != 3 {
+		status = "Fallido"
+		t.Fatalf("OrdenarJSONCtx() = %v; %d spans", err, len(spans))
+	}
+	validar, ordenar := spans[0], spans[1]
+	if ordenar.Name() != ordenJson.TramoOrdenar || ordenar.Parent().SpanID() != padre.SpanContext().SpanID() ||
+		validar.Name() != ordenJson.TramoValidar || validar.Parent().SpanID() != ordenar.SpanContext().SpanID() {
+		status = "Fallido"
+		t.Errorf("Jerarquía de spans incorrecta: %s < %s", validar.Name(), ordenar.Name())
+	}
+	atributos := atributosSpan(ordenar)
+	if atributos[ordenJson.AtributoBytesEntrada].AsInt64() != int64(len(input)) || atributos[ordenJson.AtributoBytesSalida].AsInt64() != int64(len(salida)) ||
+		atributos[ordenJson.AtributoClaves].AsInt64() != 2 || atributos[ordenJson.AtributoPerfil].AsString() != ordenJson.PerfilPorDefecto.Nombre() {
+		status = "Fallido"
+		t.Errorf("Atributos de %s: %v", ordenar.Name(), atributos)
+	}
+	if reglas := atributosSpan(validar)[ordenJson.AtributoReglas].AsString(); reglas != ordenJson.ReglaRequerido {
+		status = "Fallido"
+		t.Errorf("Reglas = %q", reglas)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un documento que no cumple la validación")
+	grabador = tracetest.NewSpanRecorder()
+	proveedor.RegisterSpanProcessor(grabador)
+	if _, err := ordenador.OrdenarJSON(`{"zzz": 1}`); err == nil {
+		t.Fatal("OrdenarJSON() sin el campo requerido no devolvió error")
+	}
+	spans = grabador.Ended()
+	if len(spans) != 2 || spans[0].Status().Code != codes.Error || spans[1].Status().Code != codes.Error || spans[1].Parent().IsValid() {
+		status = "Fallido"
+		t.Errorf("Spans del documento inválido: %d", len(spans))
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{JsonSalida: salida}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestTrazas_Lote(t *testing.T) {
+	inputs := []string{`{"b": 1, "a": 2}`, `{"sin cerrar": `, `{"c": 3}`}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, inputs)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Un span lote con un span ordenar por documento"})
+
+	registradorGlobal.AgregarProceso(testName, "Ordenando un lote trazado")
+	grabador := tracetest.NewSpanRecorder()
+	proveedor := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(grabador))
+	_, err := ordenJson.OrdenarLote(context.Background(), inputs, ordenotel.WithTrazas(proveedor), ordenJson.WithTrabajadores(2))
+
+	status := "Completado"
+	spans := grabador.Ended()
+	lote := spans[len(spans)-1]
+	hijos := 0
+	for _, span := range spans[:len(spans)-1] {
+		if span.Name() == ordenJson.TramoOrdenar && span.Parent().SpanID() == lote.SpanContext().SpanID() {
+			hijos++
+		}
+	}
+	atributos := atributosSpan(lote)
+	if err != nil || lote.Name() != ordenJson.TramoLote || hijos != 3 ||
+		atributos[ordenJson.AtributoDocumentos].AsInt64() != 3 || atributos[ordenJson.AtributoFallidos].AsInt64() != 1 || atributos[ordenJson.AtributoTrabajadores].AsInt64() != 2 {
+		status = "Fallido"
+		t.Errorf("OrdenarLote() = %v; span %s con %d hijos y atributos %v", err, lote.Name(), hijos, atributos)
+	}
+
+	registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{}, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
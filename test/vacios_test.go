@@ -0,0 +1,97 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestWithVacio_DocumentoMetadata(t *testing.T) {
+	doc := ordenJson.DocumentMetadata{
+		TipoDocumento: "contrato",
+		RUTCliente:    "-",
+		EstadoVisado:  "N/A",
+		CmTitle:       "Contrato",
+	}
+	expectedOrder := []string{"tanner:tipo-documento", "cm:title"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, doc)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarDocumentoMetadata con marcadores vacíos")
+	got, err := ordenJson.OrdenarDocumentoMetadata(doc, ordenJson.WithVacio(ordenJson.MarcadoresVacios("-", "N/A")))
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarDocumentoMetadata() error = %v", err)
+	}
+
+	keys := extraerClavesJSON(got)
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: got}
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+
+	// Sin la opción, los marcadores se conservan como valores normales.
+	sinOpcion, _ := ordenJson.OrdenarDocumentoMetadata(doc)
+	if !strings.Contains(sinOpcion, `"tanner:rut-cliente": "-"`) {
+		status = "Fallido"
+		t.Errorf("Sin WithVacio el marcador debía conservarse:\n%s", sinOpcion)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
+
+func TestWithVacio_OrdenarJSON(t *testing.T) {
+	input := map[string]interface{}{
+		"cm:title":              "N/A",
+		"tanner:tipo-documento": "contrato",
+		"tanner:observaciones":  "",
+		"tanner:origen":         nil,
+	}
+	expectedOrder := []string{"tanner:tipo-documento", "tanner:origen"}
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{ClavesOrdenadas: expectedOrder})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con un criterio de vacío propio")
+	vacio := ordenJson.WithVacio(ordenJson.MarcadoresVacios("N/A"))
+	got, err := ordenJson.OrdenarJSON(input, vacio)
+	if err != nil {
+		registradorGlobal.GuardarResultado(testName, ResultadosObtenidos{Error: err.Error()}, "Fallido")
+		t.Fatalf("OrdenarJSON() error = %v", err)
+	}
+
+	keys := extraerClavesJSON(got)
+	actual := ResultadosObtenidos{ClavesOrdenadas: keys, JsonSalida: got}
+	status := "Completado"
+	if !reflect.DeepEqual(keys, expectedOrder) {
+		status = "Fallido"
+		t.Errorf("Orden incorrecto. Esperado: %v, Obtenido: %v", expectedOrder, keys)
+	}
+	if len(input) != 4 {
+		status = "Fallido"
+		t.Errorf("El mapa de entrada no debe modificarse: %v", input)
+	}
+
+	registradorGlobal.AgregarProceso(testName, "Validando que un marcador cuenta como campo faltante")
+	_, err = ordenJson.OrdenarJSON(`{"cm:title": "N/A"}`, vacio, ordenJson.WithRequired("cm:title"))
+	var faltantes *ordenJson.ErrorCamposFaltantes
+	if !errors.As(err, &faltantes) {
+		status = "Fallido"
+		t.Errorf("Se esperaba ErrorCamposFaltantes, se obtuvo %v", err)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
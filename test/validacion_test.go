@@ -0,0 +1,138 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestValidacion(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		opts      []ordenJson.Option
+		wantError string
+	}{
+		{
+			name:  "requeridos presentes",
+			input: `{"tanner:rut-cliente": "1-9", "tanner:tipo-documento": "contrato"}`,
+			opts:  []ordenJson.Option{ordenJson.WithRequired("tanner:tipo-documento", "tanner:rut-cliente")},
+		},
+		{
+			name:      "requerido ausente",
+			input:     `{"tanner:tipo-documento": "contrato"}`,
+			opts:      []ordenJson.Option{ordenJson.WithRequired("tanner:tipo-documento", "tanner:rut-cliente")},
+			wantError: "tanner:rut-cliente",
+		},
+		{
+			name:      "requeridos vacíos o nulos",
+			input:     `{"tanner:tipo-documento": "", "tanner:rut-cliente": null}`,
+			opts:      []ordenJson.Option{ordenJson.WithRequired("tanner:tipo-documento"), ordenJson.WithRequired("tanner:rut-cliente")},
+			wantError: "tanner:tipo-documento, tanner:rut-cliente",
+		},
+		{
+			name:  "estricto con claves conocidas",
+			input: `{"cm:title": "t", "tanner:rut-cliente": "1-9"}`,
+			opts:  []ordenJson.Option{ordenJson.WithStrict()},
+		},
+		{
+			name:      "estricto con claves desconocidas",
+			input:     `{"tanner:rut_cliente": "1-9", "cm:title": "t", "titulo": "x"}`,
+			opts:      []ordenJson.Option{ordenJson.WithStrict()},
+			wantError: "tanner:rut_cliente, titulo",
+		},
+		{
+			name:      "estricto respeta el perfil configurado",
+			input:     `{"cm:title": "t", "tanner:rut-cliente": "1-9"}`,
+			opts:      []ordenJson.Option{ordenJson.WithStrict(), ordenJson.WithPerfil(ordenJson.NuevoPerfil("solo-titulo", []string{"cm:title"}))},
+			wantError: "tanner:rut-cliente",
+		},
+		{
+			name:  "estados permitidos",
+			input: `{"tanner:estado-visado": "pendiente", "tanner:estado-vigencia": "vigente"}`,
+			opts:  []ordenJson.Option{ordenJson.WithValidarEstados()},
+		},
+		{
+			name:      "estado de visado no permitido",
+			input:     `{"tanner:estado-visado": "Aprobado", "tanner:estado-vigencia": "vigente"}`,
+			opts:      []ordenJson.Option{ordenJson.WithValidarEstados()},
+			wantError: "Aprobado",
+		},
+		{
+			name:  "valores permitidos personalizados",
+			input: `{"tanner:estado-vigencia": "suspendido", "tanner:estado-visado": ""}`,
+			opts: []ordenJson.Option{
+				ordenJson.WithValidarEstados(),
+				ordenJson.WithValoresPermitidos("tanner:estado-vigencia", "vigente", "suspendido"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testName := t.Name()
+			startTime := time.Now()
+			registradorGlobal.IniciadorTest(testName, tt.input)
+			registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: tt.wantError})
+
+			registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con reglas de validación")
+			got, err := ordenJson.OrdenarJSON(tt.input, tt.opts...)
+
+			actual := ResultadosObtenidos{JsonSalida: got}
+			if err != nil {
+				actual.Error = err.Error()
+			}
+
+			status := "Completado"
+			switch {
+			case tt.wantError == "" && err != nil:
+				status = "Fallido"
+				t.Errorf("Error inesperado: %v", err)
+			case tt.wantError != "" && err == nil:
+				status = "Fallido"
+				t.Errorf("Se esperaba un error que mencione %q, pero no se produjo ninguno", tt.wantError)
+			case tt.wantError != "" && !strings.Contains(err.Error(), tt.wantError):
+				status = "Fallido"
+				t.Errorf("Se esperaba un error que mencione %q, se obtuvo %v", tt.wantError, err)
+			}
+
+			registradorGlobal.GuardarResultado(testName, actual, status)
+			registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+		})
+	}
+}
+
+func TestValoresPermitidos_ErrorTipado(t *testing.T) {
+	input := `{"tanner:estado-vigencia": "caducado"}`
+
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, input)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{TipoError: "*ordenJson.ErrorValorNoPermitido"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando OrdenarJSON con estado de vigencia no permitido")
+	_, err := ordenJson.OrdenarJSON(input, ordenJson.WithValidarEstados())
+
+	var actual ResultadosObtenidos
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	var errValor *ordenJson.ErrorValorNoPermitido
+	if !errors.As(err, &errValor) {
+		status = "Fallido"
+		t.Fatalf("Se esperaba *ErrorValorNoPermitido, se obtuvo %v", err)
+	}
+	if errValor.Campo != "tanner:estado-vigencia" || errValor.Valor != "caducado" || len(errValor.Permitidos) != len(ordenJson.ValoresEstadoVigencia) {
+		status = "Fallido"
+		t.Errorf("Error incompleto: %+v", errValor)
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuel/prueba-orden/ordenJson/v2"
+)
+
+func TestVersion(t *testing.T) {
+	testName := t.Name()
+	startTime := time.Now()
+	registradorGlobal.IniciadorTest(testName, nil)
+	registradorGlobal.ConfigResultadoEsperado(testName, ResultadosEsperados{CustomCheck: "Versión de módulo, perfil y Go informadas"})
+
+	registradorGlobal.AgregarProceso(testName, "Ejecutando Version")
+	info := ordenJson.Version()
+
+	salida, err := json.Marshal(info)
+	actual := ResultadosObtenidos{JsonSalida: string(salida)}
+	if err != nil {
+		actual.Error = err.Error()
+	}
+
+	status := "Completado"
+	if info.Modulo != "github.com/samuel/prueba-orden" || info.Version == "" {
+		status = "Fallido"
+		t.Errorf("Módulo o versión incorrectos: %+v", info)
+	}
+	if info.VersionPerfil != ordenJson.VersionPerfil || len(info.HuellaPerfil) != 12 {
+		status = "Fallido"
+		t.Errorf("Versión de perfil incorrecta: %+v", info)
+	}
+	if info.VersionGo != runtime.Version() {
+		status = "Fallido"
+		t.Errorf("Versión de Go incorrecta: %+v", info)
+	}
+	if !strings.Contains(info.String(), info.HuellaPerfil) {
+		status = "Fallido"
+		t.Errorf("String() no incluye la huella del perfil: %s", info)
+	}
+	if ordenJson.Version() != info {
+		status = "Fallido"
+		t.Errorf("Version() debe ser estable entre llamadas")
+	}
+
+	registradorGlobal.GuardarResultado(testName, actual, status)
+	registradorGlobal.logs[testName].TiempoDeEjecucion = time.Since(startTime).String()
+}